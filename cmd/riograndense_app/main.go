@@ -92,8 +92,16 @@ func run() {
 	networkRepo := repositories.NewGormNetworkRepository(db)
 	cnpjRepo := repositories.NewGormCNPJRepository(db)
 	importMetadataRepo := repositories.NewGormImportMetadataRepository(db)
+	importRunRepo := repositories.NewGormImportRunRepository(db)
+	importLockRepo := repositories.NewGormImportLockRepository(db)
+	importProfileRepo := repositories.NewGormImportProfileRepository(db)
+	importSnapshotRepo := repositories.NewGormImportSnapshotRepository(db)
+	tituloCNPJLinkRepo := repositories.NewGormTituloCNPJLinkRepository(db)
 	tituloDireitoRepo := repositories.NewGormTituloDireitoRepository(db)
 	tituloObrigacaoRepo := repositories.NewGormTituloObrigacaoRepository(db)
+	holidayRepo := repositories.NewGormHolidayRepository(db)
+	chargeRuleRepo := repositories.NewGormChargeRuleRepository(db)
+	dueDigestRepo := repositories.NewGormDueDigestSubscriptionRepository(db)
 
 	// Outros Serviços
 	// CORREÇÃO: Ajustar a chamada para NewUserService para corresponder a uma assinatura provável de 7 argumentos
@@ -101,11 +109,29 @@ func run() {
 	userService := services.NewUserService(cfg, userRepo, roleRepo, auditLogService, emailService, authenticator, sessionManager)
 	roleService := services.NewRoleService(roleRepo, auditLogService, permManager)
 	networkService := services.NewNetworkService(networkRepo, auditLogService, permManager)
-	cnpjService := services.NewCNPJService(cnpjRepo, networkRepo, auditLogService, permManager)
-	importService := services.NewImportService(cfg, auditLogService, permManager, importMetadataRepo, tituloDireitoRepo, tituloObrigacaoRepo)
+	cnpjService := services.NewCNPJService(cnpjRepo, networkRepo, tituloCNPJLinkRepo, auditLogService, permManager)
+	holidayService := services.NewHolidayService(cfg, holidayRepo, auditLogService, permManager)
+	chargeService := services.NewChargeService(chargeRuleRepo, networkRepo, holidayService, auditLogService, permManager)
+	tituloService := services.NewTituloService(tituloDireitoRepo, tituloObrigacaoRepo, holidayService, chargeService, permManager)
+	agingReportService := services.NewAgingReportService(cfg, tituloDireitoRepo, tituloObrigacaoRepo, networkRepo, holidayService, chargeService, auditLogService, permManager)
+	nettingService := services.NewNettingService(cfg, tituloDireitoRepo, tituloObrigacaoRepo, networkRepo, auditLogService, permManager)
+	dashboardService := services.NewDashboardService(tituloDireitoRepo, tituloObrigacaoRepo, networkRepo, cnpjRepo, importRunRepo, importSnapshotRepo, holidayService, permManager)
+	cashFlowService := services.NewCashFlowService(cfg, tituloDireitoRepo, tituloObrigacaoRepo, holidayService, auditLogService, permManager)
+	dueDigestService := services.NewDueDigestService(dueDigestRepo, userRepo, networkRepo, tituloDireitoRepo, tituloObrigacaoRepo, importRunRepo, holidayService, emailService, auditLogService, permManager)
+	importService := services.NewImportService(cfg, auditLogService, permManager, importMetadataRepo, importRunRepo, importLockRepo, importProfileRepo, importSnapshotRepo, tituloCNPJLinkRepo, tituloDireitoRepo, tituloObrigacaoRepo, networkRepo, cnpjRepo, holidayRepo)
 
 	appLogger.Info("Todos os serviços foram inicializados.")
 
+	// Importação automática dos arquivos deixados nas pastas monitoradas (se habilitada).
+	importWatcher := services.NewImportWatcher(cfg, importService, auditLogService)
+	importWatcher.Start()
+	defer importWatcher.Shutdown()
+
+	// Envio agendado do resumo de vencimentos por e-mail (se habilitado).
+	dueDigestScheduler := services.NewDueDigestScheduler(cfg, dueDigestService)
+	dueDigestScheduler.Start()
+	defer dueDigestScheduler.Shutdown()
+
 	// --- 6. Inicializar Tema e UI ---
 	gofont.Register()
 	th := material.NewTheme() // Pode ser customizado em internal/ui/theme/theme.go
@@ -119,6 +145,12 @@ func run() {
 		roleService,
 		networkService,
 		cnpjService,
+		tituloService,
+		agingReportService,
+		nettingService,
+		dashboardService,
+		cashFlowService,
+		dueDigestService,
 		importService,
 		auditLogService,
 	)
@@ -31,6 +31,16 @@ const (
 	PermCNPJUpdate Permission = "cnpj:update"
 	PermCNPJDelete Permission = "cnpj:delete"
 
+	// Título Permissions
+	PermTituloView      Permission = "titulo:view"
+	PermTituloDigestAll Permission = "titulo:digest_all"
+
+	// Holiday Calendar Permissions
+	PermHolidayManage Permission = "holiday:manage"
+
+	// Late Charges Permissions
+	PermChargeManage Permission = "charge:manage"
+
 	// User Management Permissions
 	PermUserCreate        Permission = "user:create"
 	PermUserRead          Permission = "user:read"
@@ -50,10 +60,16 @@ const (
 	PermLogView Permission = "log:view"
 
 	// Import Permissions
-	PermImportExecute    Permission = "import:execute"
-	PermImportViewStatus Permission = "import:view_status"
+	PermImportExecute         Permission = "import:execute"
+	PermImportViewStatus      Permission = "import:view_status"
+	PermImportManageProfiles  Permission = "import:manage_profiles"
+	PermImportRestoreSnapshot Permission = "import:restore_snapshot"
 )
 
+// RoleImportWatcher é o role de sistema usado pela importação automática da pasta monitorada.
+// Concede apenas a execução e a consulta de importações.
+const RoleImportWatcher = "import_watcher"
+
 // allDefinedPermissions mantém um mapa de todas as permissões definidas e suas descrições.
 var allDefinedPermissions = map[Permission]string{
 	PermNetworkView:    "Visualizar dados de redes",
@@ -68,6 +84,13 @@ var allDefinedPermissions = map[Permission]string{
 	PermCNPJUpdate: "Atualizar CNPJs existentes",
 	PermCNPJDelete: "Excluir CNPJs",
 
+	PermTituloView:      "Consultar títulos importados (direitos e obrigações)",
+	PermTituloDigestAll: "Receber o resumo de vencimentos por e-mail de todas as redes (financeiro)",
+
+	PermHolidayManage: "Cadastrar, importar e excluir feriados estaduais e municipais do calendário de dias úteis",
+
+	PermChargeManage: "Criar, editar e excluir as regras de multa e juros de mora dos títulos vencidos",
+
 	PermUserCreate:        "Criar novos usuários no sistema",
 	PermUserRead:          "Visualizar lista e detalhes de usuários",
 	PermUserUpdate:        "Atualizar dados de usuários (exceto senha)",
@@ -81,8 +104,10 @@ var allDefinedPermissions = map[Permission]string{
 	PermExportData: "Exportar dados da aplicação",
 	PermLogView:    "Visualizar logs de auditoria do sistema",
 
-	PermImportExecute:    "Permite importar arquivos de dados (Direitos, Obrigações, etc.)",
-	PermImportViewStatus: "Permite visualizar o status e histórico das importações",
+	PermImportExecute:         "Permite importar arquivos de dados (Direitos, Obrigações, etc.)",
+	PermImportViewStatus:      "Permite visualizar o status e histórico das importações",
+	PermImportManageProfiles:  "Permite criar, editar e excluir perfis de mapeamento de colunas da importação",
+	PermImportRestoreSnapshot: "Permite restaurar um snapshot anterior dos títulos importados, substituindo os dados atuais",
 }
 
 // PermissionManager gerencia as permissões e suas associações com roles.
@@ -252,11 +277,15 @@ func SeedInitialRolesAndPermissions(roleRepo repositories.RoleRepository, permMa
 	editorPermsStr := []string{
 		string(PermNetworkView), string(PermNetworkCreate), string(PermNetworkUpdate), string(PermNetworkStatus),
 		string(PermCNPJView), string(PermCNPJCreate), string(PermCNPJUpdate), string(PermCNPJDelete),
+		string(PermTituloView),
 		string(PermExportData),
 		string(PermImportExecute), string(PermImportViewStatus),
 	}
+	importWatcherPermsStr := []string{
+		string(PermImportExecute), string(PermImportViewStatus),
+	}
 	viewerPermsStr := []string{
-		string(PermNetworkView), string(PermCNPJView),
+		string(PermNetworkView), string(PermCNPJView), string(PermTituloView),
 		string(PermExportData),
 		string(PermImportViewStatus),
 	}
@@ -271,6 +300,7 @@ func SeedInitialRolesAndPermissions(roleRepo repositories.RoleRepository, permMa
 		{"editor", "Pode visualizar, criar/editar redes/CNPJs e importar dados.", editorPermsStr, true},
 		{"viewer", "Pode apenas visualizar dados, exportar e ver status de importação.", viewerPermsStr, true},
 		{"user", "Usuário básico (sem permissões por padrão).", []string{}, true},
+		{RoleImportWatcher, "Identidade do sistema para a importação automática da pasta monitorada.", importWatcherPermsStr, true},
 	}
 
 	createdCount := 0
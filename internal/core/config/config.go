@@ -48,6 +48,30 @@ type Config struct {
 	// Export
 	ExportDir string
 
+	// Importação automática (pasta monitorada)
+	ImportWatchEnabled       bool
+	ImportWatchDirDireitos   string        // Pasta monitorada para arquivos de Títulos de Direitos (vazio desativa o tipo).
+	ImportWatchDirObrigacoes string        // Pasta monitorada para arquivos de Títulos de Obrigações (vazio desativa o tipo).
+	ImportWatchInterval      time.Duration // Intervalo entre as varreduras das pastas.
+	ImportWatchSettleTime    time.Duration // Tempo sem alterações para considerar um arquivo completo.
+
+	// Snapshots dos títulos de cada importação (habilitados por padrão). Cada snapshot é uma tabela
+	// com a cópia de toda a tabela do tipo, gravada por INSERT ... SELECT na transação da importação:
+	// ocupa cerca de `ImportSnapshotRetention` vezes o tamanho da tabela em disco.
+	ImportSnapshotEnabled   bool
+	ImportSnapshotRetention int // Número de snapshots mantidos por tipo de arquivo (mínimo 1).
+
+	// Praça do calendário de dias úteis. Os feriados estaduais e municipais cadastrados valem para todos
+	// os títulos, então o calendário atende uma única localidade: feriados com local diferente destes
+	// são recusados no cadastro e na importação e ignorados pelo calendário.
+	HolidayUF           string // Sigla da UF (ex: RS); vazio aceita apenas feriados estaduais sem local.
+	HolidayMunicipality string // Nome do município; vazio aceita apenas feriados municipais sem local.
+
+	// Resumo de vencimentos por e-mail
+	DueDigestEnabled       bool
+	DueDigestSendHour      int           // Hora local (0-23) a partir da qual os resumos do dia são enviados.
+	DueDigestCheckInterval time.Duration // Intervalo entre as verificações de resumos pendentes.
+
 	// Email
 	EmailSMTPServer string
 	EmailPort       int
@@ -106,6 +130,21 @@ func LoadConfig(envPath string) (*Config, error) {
 
 	cfg.ExportDir = getEnv("APP_EXPORT_DIR", "./app_exports")
 
+	cfg.ImportWatchEnabled = getEnvAsBool("APP_IMPORT_WATCH_ENABLED", false)
+	cfg.ImportWatchDirDireitos = getEnv("APP_IMPORT_WATCH_DIR_DIREITOS", "")
+	cfg.ImportWatchDirObrigacoes = getEnv("APP_IMPORT_WATCH_DIR_OBRIGACOES", "")
+	cfg.ImportWatchInterval = getEnvAsDuration("APP_IMPORT_WATCH_INTERVAL", 60)      // 1 minuto
+	cfg.ImportWatchSettleTime = getEnvAsDuration("APP_IMPORT_WATCH_SETTLE_TIME", 30) // 30 segundos
+	cfg.ImportSnapshotEnabled = getEnvAsBool("APP_IMPORT_SNAPSHOT_ENABLED", true)
+	cfg.ImportSnapshotRetention = getEnvAsInt("APP_IMPORT_SNAPSHOT_RETENTION", 5)
+
+	cfg.HolidayUF = strings.ToUpper(strings.TrimSpace(getEnv("APP_HOLIDAY_UF", "")))
+	cfg.HolidayMunicipality = strings.Join(strings.Fields(getEnv("APP_HOLIDAY_MUNICIPALITY", "")), " ")
+
+	cfg.DueDigestEnabled = getEnvAsBool("APP_DUE_DIGEST_ENABLED", false)
+	cfg.DueDigestSendHour = getEnvAsInt("APP_DUE_DIGEST_SEND_HOUR", 8)
+	cfg.DueDigestCheckInterval = getEnvAsDuration("APP_DUE_DIGEST_CHECK_INTERVAL", 900) // 15 minutos
+
 	cfg.EmailSMTPServer = getEnv("APP_EMAIL_SMTP_SERVER", "")
 	cfg.EmailPort = getEnvAsInt("APP_EMAIL_PORT", 587) // Porta padrão para STARTTLS
 	cfg.EmailUser = getEnv("APP_EMAIL_USER", "")
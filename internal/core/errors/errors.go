@@ -42,6 +42,7 @@ var (
 	// --- Erros Específicos da Aplicação ---
 	ErrExport     = errors.New("falha ao exportar dados")
 	ErrDataImport = errors.New("falha ao importar dados")
+	ErrCancelled  = errors.New("operação cancelada pelo usuário")
 	ErrEmail      = errors.New("falha no serviço de envio de e-mail")
 
 	// --- Erros Críticos / Segurança ---
@@ -185,9 +185,18 @@ func InitializeDB(cfg *core.Config) (*gorm.DB, error) {
 		&models.DBUserRole{},       // Tabela de junção User-Role
 		&models.DBRolePermission{}, // Tabela de junção Role-Permission
 		&models.DBNetwork{},
+		&models.DBHoliday{},
+		&models.DBChargeRule{},
+		&models.DBDueDigestSubscription{},
 		&models.DBCNPJ{},
 		&models.AuditLogEntry{},
 		&models.DBImportMetadata{},
+		&models.DBImportRun{},
+		&models.DBImportRunNetworkTotal{},
+		&models.DBImportLock{},
+		&models.DBImportProfile{},
+		&models.DBImportProfileColumn{},
+		&models.DBImportSnapshot{},
 		&models.DBTituloDireito{},
 		&models.DBTituloObrigacao{},
 	)
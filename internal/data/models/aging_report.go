@@ -0,0 +1,163 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+)
+
+// AgingGroupBy define o agrupamento das linhas do relatório de aging.
+type AgingGroupBy string
+
+const (
+	AgingGroupByCNPJ    AgingGroupBy = "CNPJ"    // Por CNPJ/CPF do título.
+	AgingGroupByNetwork AgingGroupBy = "NETWORK" // Por rede vinculada ao título.
+	AgingGroupByBuyer   AgingGroupBy = "BUYER"   // Por comprador responsável pela rede.
+	AgingGroupByEmpresa AgingGroupBy = "EMPRESA" // Por NROEMPRESA.
+)
+
+// Validate verifica se o agrupamento é suportado.
+func (g AgingGroupBy) Validate() error {
+	switch g {
+	case AgingGroupByCNPJ, AgingGroupByNetwork, AgingGroupByBuyer, AgingGroupByEmpresa:
+		return nil
+	}
+	return fmt.Errorf("%w: agrupamento de aging '%s' não suportado", appErrors.ErrInvalidInput, g)
+}
+
+// Label retorna o nome do agrupamento para exibição.
+func (g AgingGroupBy) Label() string {
+	switch g {
+	case AgingGroupByNetwork:
+		return "Rede"
+	case AgingGroupByBuyer:
+		return "Comprador"
+	case AgingGroupByEmpresa:
+		return "NROEMPRESA"
+	}
+	return "CNPJ/CPF"
+}
+
+// AgingBucket identifica a faixa de atraso de um saldo em aberto.
+type AgingBucket int
+
+const (
+	AgingBucketNotDue  AgingBucket = iota // A vencer (vencimento na data de referência ou depois).
+	AgingBucket1To30                      // 1 a 30 dias de atraso.
+	AgingBucket31To60                     // 31 a 60 dias.
+	AgingBucket61To90                     // 61 a 90 dias.
+	AgingBucket91To180                    // 91 a 180 dias.
+	AgingBucketOver180                    // Mais de 180 dias.
+	AgingBucketCount   = 6                // Número de faixas.
+)
+
+// AgingBucketLabels são os títulos das faixas, na ordem das constantes AgingBucket.
+var AgingBucketLabels = [AgingBucketCount]string{"A vencer", "1-30", "31-60", "61-90", "91-180", ">180"}
+
+// AgingBucketFor retorna a faixa correspondente aos dias de atraso (<= 0 é "a vencer").
+func AgingBucketFor(daysPastDue int) AgingBucket {
+	switch {
+	case daysPastDue <= 0:
+		return AgingBucketNotDue
+	case daysPastDue <= 30:
+		return AgingBucket1To30
+	case daysPastDue <= 60:
+		return AgingBucket31To60
+	case daysPastDue <= 90:
+		return AgingBucket61To90
+	case daysPastDue <= 180:
+		return AgingBucket91To180
+	}
+	return AgingBucketOver180
+}
+
+// TituloBalance é a projeção enxuta de um título usada no cálculo de saldos em aberto.
+// Serve tanto para direitos quanto para obrigações (o valor nominal vem da coluna de cada tabela).
+type TituloBalance struct {
+	ID             uint64 `gorm:"primaryKey"`
+	CNPJCPF        string `gorm:"column:cnpjcpf"`
+	Pessoa         *string
+	Titulo         string // Título (direitos) ou identificador da obrigação.
+	NumeroEmpresa  int
+	CodigoEspecie  *string
+	NetworkID      *uint64
+	DataVencimento *time.Time
+	DataProgramada *time.Time // Data programada para pagamento/recebimento (DTAPROGRAMADA).
+	ValorNominal   string
+	ValorPago      *string
+}
+
+// OpenBalance retorna o saldo em aberto do título (valor nominal menos valor pago).
+// Valores vazios são tratados como zero.
+func (b *TituloBalance) OpenBalance() (decimal.Decimal, error) {
+	nominal, err := parseDecimalOrZero(b.ValorNominal)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("valor nominal '%s' inválido: %w", b.ValorNominal, err)
+	}
+	paid := decimal.Zero
+	if b.ValorPago != nil {
+		if paid, err = parseDecimalOrZero(*b.ValorPago); err != nil {
+			return decimal.Zero, fmt.Errorf("valor pago '%s' inválido: %w", *b.ValorPago, err)
+		}
+	}
+	return nominal.Sub(paid), nil
+}
+
+func parseDecimalOrZero(value string) (decimal.Decimal, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return decimal.Zero, nil
+	}
+	return decimal.NewFromString(value)
+}
+
+// AgingReportRow é uma linha (grupo) do relatório de aging.
+type AgingReportRow struct {
+	Key        string                            `json:"key"`   // Chave do grupo (CNPJ, ID da rede, comprador ou NROEMPRESA).
+	Label      string                            `json:"label"` // Texto exibido para o grupo.
+	Buckets    [AgingBucketCount]decimal.Decimal `json:"buckets"`
+	Total      decimal.Decimal                   `json:"total"`
+	TitleCount int                               `json:"title_count"`
+
+	// Fine e Interest somam a multa e os juros de mora dos títulos do grupo (só nos direitos).
+	Fine     decimal.Decimal `json:"fine"`
+	Interest decimal.Decimal `json:"interest"`
+}
+
+// Add acumula um saldo em aberto na faixa indicada.
+func (r *AgingReportRow) Add(bucket AgingBucket, balance decimal.Decimal) {
+	r.Buckets[bucket] = r.Buckets[bucket].Add(balance)
+	r.Total = r.Total.Add(balance)
+	r.TitleCount++
+}
+
+// AddCharges acumula a multa e os juros de mora de um título já somado com Add.
+func (r *AgingReportRow) AddCharges(calc *ChargeCalculation) {
+	r.Fine = r.Fine.Add(calc.Fine)
+	r.Interest = r.Interest.Add(calc.Interest)
+}
+
+// UpdatedTotal retorna o total em aberto acrescido da multa e dos juros de mora.
+func (r *AgingReportRow) UpdatedTotal() decimal.Decimal {
+	return r.Total.Add(r.Fine).Add(r.Interest)
+}
+
+// AgingReport é o relatório de aging de títulos de direitos (a receber) ou obrigações (a pagar).
+type AgingReport struct {
+	FileType      string           `json:"file_type"` // "DIREITOS" ou "OBRIGACOES".
+	GroupBy       AgingGroupBy     `json:"group_by"`
+	ReferenceDate time.Time        `json:"reference_date"` // Dia (UTC) base para os dias de atraso.
+	GeneratedAt   time.Time        `json:"generated_at"`
+	Rows          []AgingReportRow `json:"rows"`   // Ordenadas pelo total em aberto, decrescente.
+	Totals        AgingReportRow   `json:"totals"` // Soma de todas as linhas.
+
+	// InvalidTitles conta os títulos ignorados por valores que não puderam ser convertidos.
+	InvalidTitles int `json:"invalid_titles"`
+
+	// WithCharges indica que Fine e Interest foram calculados (direitos), na data de referência.
+	WithCharges bool `json:"with_charges"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "testing"
+
+func TestAgingBucketFor(t *testing.T) {
+	cases := []struct {
+		daysPastDue int
+		want        AgingBucket
+	}{
+		{-30, AgingBucketNotDue},
+		{0, AgingBucketNotDue},
+		{1, AgingBucket1To30},
+		{30, AgingBucket1To30},
+		{31, AgingBucket31To60},
+		{60, AgingBucket31To60},
+		{61, AgingBucket61To90},
+		{90, AgingBucket61To90},
+		{91, AgingBucket91To180},
+		{180, AgingBucket91To180},
+		{181, AgingBucketOver180},
+		{3650, AgingBucketOver180},
+	}
+	for _, tc := range cases {
+		if got := AgingBucketFor(tc.daysPastDue); got != tc.want {
+			t.Errorf("AgingBucketFor(%d) = %s, esperado %s", tc.daysPastDue, AgingBucketLabels[got], AgingBucketLabels[tc.want])
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// maxNonBusinessDays limita a busca pelo próximo dia útil. Nenhuma combinação de fim de semana e
+// feriados chega perto disso; o limite só evita um laço infinito com um cadastro inconsistente.
+const maxNonBusinessDays = 31
+
+// Holiday é um feriado em uma data concreta, nacional (calculado) ou local (cadastrado).
+type Holiday struct {
+	ID        uint64 // ID do DBHoliday; zero para feriados nacionais.
+	Date      time.Time
+	Name      string
+	Scope     HolidayScope
+	Location  string
+	Recurring bool
+}
+
+// weekdayNames são os nomes dos dias da semana, indexados por time.Weekday.
+var weekdayNames = [...]string{"Domingo", "Segunda-feira", "Terça-feira", "Quarta-feira", "Quinta-feira", "Sexta-feira", "Sábado"}
+
+// WeekdayName retorna o nome do dia da semana em português.
+func WeekdayName(d time.Weekday) string {
+	return weekdayNames[d]
+}
+
+// calendarDay retorna o dia do calendário de `t` em UTC, sem horário.
+func calendarDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// EasterSunday calcula o domingo de Páscoa do ano (calendário gregoriano, algoritmo de Meeus/Jones/Butcher).
+func EasterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// NationalHolidays retorna os feriados nacionais do ano, em ordem cronológica.
+// Carnaval e Corpus Christi são pontos facultativos pela lei, mas não há expediente bancário
+// nesses dias, então o pagamento também é transferido para o dia útil seguinte.
+func NationalHolidays(year int) []Holiday {
+	fixed := func(month time.Month, day int, name string) Holiday {
+		return Holiday{Date: time.Date(year, month, day, 0, 0, 0, 0, time.UTC), Name: name, Scope: HolidayScopeNational, Recurring: true}
+	}
+	easter := EasterSunday(year)
+	moving := func(offset int, name string) Holiday {
+		return Holiday{Date: easter.AddDate(0, 0, offset), Name: name, Scope: HolidayScopeNational}
+	}
+
+	holidays := []Holiday{
+		fixed(time.January, 1, "Confraternização Universal"),
+		moving(-48, "Carnaval (segunda-feira)"),
+		moving(-47, "Carnaval (terça-feira)"),
+		moving(-2, "Sexta-feira Santa"),
+		fixed(time.April, 21, "Tiradentes"),
+		fixed(time.May, 1, "Dia do Trabalho"),
+		moving(60, "Corpus Christi"),
+		fixed(time.September, 7, "Independência do Brasil"),
+		fixed(time.October, 12, "Nossa Senhora Aparecida"),
+		fixed(time.November, 2, "Finados"),
+		fixed(time.November, 15, "Proclamação da República"),
+		fixed(time.December, 25, "Natal"),
+	}
+	// Feriado nacional desde a Lei 14.759/2023.
+	if year >= 2024 {
+		holidays = append(holidays, fixed(time.November, 20, "Dia Nacional de Zumbi e da Consciência Negra"))
+	}
+	sortHolidays(holidays)
+	return holidays
+}
+
+// sortHolidays ordena os feriados por data e, no mesmo dia, pelo nome.
+func sortHolidays(holidays []Holiday) {
+	sort.SliceStable(holidays, func(i, j int) bool {
+		if !holidays[i].Date.Equal(holidays[j].Date) {
+			return holidays[i].Date.Before(holidays[j].Date)
+		}
+		return holidays[i].Name < holidays[j].Name
+	})
+}
+
+// calendarYear são os feriados de um ano, em ordem e indexados por dia.
+type calendarYear struct {
+	holidays []Holiday
+	byDay    map[time.Time]Holiday // Primeiro feriado do dia (o nacional, se houver).
+}
+
+// BusinessCalendar é o calendário de dias úteis: sábados, domingos, feriados nacionais e os feriados
+// locais cadastrados não são dias úteis. Os anos são calculados sob demanda e mantidos em cache;
+// uma instância não deve ser compartilhada entre goroutines.
+type BusinessCalendar struct {
+	local []DBHoliday
+	years map[int]*calendarYear
+}
+
+// NewBusinessCalendar cria o calendário com os feriados locais informados.
+func NewBusinessCalendar(local []DBHoliday) *BusinessCalendar {
+	return &BusinessCalendar{local: local, years: make(map[int]*calendarYear)}
+}
+
+// year retorna (calculando, se necessário) os feriados do ano.
+func (c *BusinessCalendar) year(year int) *calendarYear {
+	if cy, ok := c.years[year]; ok {
+		return cy
+	}
+	holidays := NationalHolidays(year)
+	for _, h := range c.local {
+		date := calendarDay(h.Date)
+		if h.Recurring {
+			// 29/02 anual só existe nos anos bissextos.
+			if date = time.Date(year, date.Month(), date.Day(), 0, 0, 0, 0, time.UTC); date.Month() != h.Date.UTC().Month() {
+				continue
+			}
+		} else if date.Year() != year {
+			continue
+		}
+		holidays = append(holidays, Holiday{
+			ID: h.ID, Date: date, Name: h.Name, Scope: HolidayScope(h.Scope), Location: h.Location, Recurring: h.Recurring,
+		})
+	}
+	sortHolidays(holidays)
+
+	cy := &calendarYear{holidays: holidays, byDay: make(map[time.Time]Holiday, len(holidays))}
+	for _, h := range holidays {
+		existing, ok := cy.byDay[h.Date]
+		if !ok || (existing.Scope != HolidayScopeNational && h.Scope == HolidayScopeNational) {
+			cy.byDay[h.Date] = h
+		}
+	}
+	c.years[year] = cy
+	return cy
+}
+
+// HolidaysIn retorna os feriados (nacionais e locais) do ano, em ordem cronológica.
+func (c *BusinessCalendar) HolidaysIn(year int) []Holiday {
+	return append([]Holiday(nil), c.year(year).holidays...)
+}
+
+// HolidayOn retorna o feriado do dia, se houver.
+func (c *BusinessCalendar) HolidayOn(day time.Time) (Holiday, bool) {
+	day = calendarDay(day)
+	h, ok := c.year(day.Year()).byDay[day]
+	return h, ok
+}
+
+// IsBusinessDay indica se o dia é útil (não é fim de semana nem feriado).
+func (c *BusinessCalendar) IsBusinessDay(day time.Time) bool {
+	day = calendarDay(day)
+	if wd := day.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		return false
+	}
+	_, holiday := c.HolidayOn(day)
+	return !holiday
+}
+
+// NextBusinessDay retorna o próprio dia, se for útil, ou o primeiro dia útil seguinte (UTC, sem horário).
+func (c *BusinessCalendar) NextBusinessDay(day time.Time) time.Time {
+	day = calendarDay(day)
+	for i := 0; i < maxNonBusinessDays && !c.IsBusinessDay(day); i++ {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// EffectiveDueDate retorna a data em que o pagamento de um título com vencimento `due` é exigível:
+// o próprio vencimento, se for dia útil, ou o primeiro dia útil seguinte. Retorna nil se `due` for nil.
+func (c *BusinessCalendar) EffectiveDueDate(due *time.Time) *time.Time {
+	if due == nil || due.IsZero() {
+		return nil
+	}
+	effective := c.NextBusinessDay(*due)
+	return &effective
+}
+
+// FormatEffectiveDueDate retorna a data de vencimento efetiva formatada como "DD/MM/YYYY" (nil se `due` for nil).
+func (c *BusinessCalendar) FormatEffectiveDueDate(due *time.Time) *string {
+	return formatDatePtr(c.EffectiveDueDate(due))
+}
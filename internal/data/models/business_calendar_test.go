@@ -0,0 +1,153 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// day monta uma data do calendário (UTC, sem horário).
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestEasterSunday(t *testing.T) {
+	cases := []struct {
+		year int
+		want time.Time
+	}{
+		{1818, day(1818, time.March, 22)}, // Data mais cedo possível.
+		{1943, day(1943, time.April, 25)}, // Data mais tarde possível.
+		{2000, day(2000, time.April, 23)},
+		{2019, day(2019, time.April, 21)},
+		{2024, day(2024, time.March, 31)},
+		{2025, day(2025, time.April, 20)},
+		{2026, day(2026, time.April, 5)},
+		{2038, day(2038, time.April, 25)},
+	}
+	for _, tc := range cases {
+		if got := EasterSunday(tc.year); !got.Equal(tc.want) {
+			t.Errorf("EasterSunday(%d) = %s, esperado %s", tc.year, got.Format("02/01/2006"), tc.want.Format("02/01/2006"))
+		}
+	}
+}
+
+func TestNationalHolidays(t *testing.T) {
+	cases := []struct {
+		year      int
+		wantCount int
+		want      map[time.Time]string // Feriados móveis e casos de borda esperados no ano.
+		notWant   []time.Time
+	}{
+		{
+			year:      2023,
+			wantCount: 12,
+			want: map[time.Time]string{
+				day(2023, time.February, 20): "Carnaval (segunda-feira)",
+				day(2023, time.February, 21): "Carnaval (terça-feira)",
+				day(2023, time.April, 7):     "Sexta-feira Santa",
+				day(2023, time.June, 8):      "Corpus Christi",
+			},
+			notWant: []time.Time{day(2023, time.November, 20)},
+		},
+		{
+			year:      2024,
+			wantCount: 13,
+			want: map[time.Time]string{
+				day(2024, time.February, 12): "Carnaval (segunda-feira)",
+				day(2024, time.February, 13): "Carnaval (terça-feira)",
+				day(2024, time.March, 29):    "Sexta-feira Santa",
+				day(2024, time.May, 30):      "Corpus Christi",
+				day(2024, time.November, 20): "Dia Nacional de Zumbi e da Consciência Negra",
+			},
+		},
+		{
+			year:      2026,
+			wantCount: 13,
+			want: map[time.Time]string{
+				day(2026, time.January, 1):   "Confraternização Universal",
+				day(2026, time.February, 16): "Carnaval (segunda-feira)",
+				day(2026, time.February, 17): "Carnaval (terça-feira)",
+				day(2026, time.April, 3):     "Sexta-feira Santa",
+				day(2026, time.April, 21):    "Tiradentes",
+				day(2026, time.June, 4):      "Corpus Christi",
+				day(2026, time.December, 25): "Natal",
+			},
+		},
+	}
+	for _, tc := range cases {
+		holidays := NationalHolidays(tc.year)
+		if len(holidays) != tc.wantCount {
+			t.Errorf("NationalHolidays(%d) retornou %d feriados, esperados %d", tc.year, len(holidays), tc.wantCount)
+		}
+		byDate := make(map[time.Time]string, len(holidays))
+		for i, h := range holidays {
+			if h.Scope != HolidayScopeNational {
+				t.Errorf("%s: abrangência %s, esperada %s", h.Name, h.Scope, HolidayScopeNational)
+			}
+			if i > 0 && h.Date.Before(holidays[i-1].Date) {
+				t.Errorf("NationalHolidays(%d) fora de ordem: %s antes de %s", tc.year, holidays[i-1].Name, h.Name)
+			}
+			byDate[h.Date] = h.Name
+		}
+		for date, name := range tc.want {
+			if got := byDate[date]; got != name {
+				t.Errorf("NationalHolidays(%d) em %s = %q, esperado %q", tc.year, date.Format("02/01/2006"), got, name)
+			}
+		}
+		for _, date := range tc.notWant {
+			if got, ok := byDate[date]; ok {
+				t.Errorf("NationalHolidays(%d) não deveria ter feriado em %s (%q)", tc.year, date.Format("02/01/2006"), got)
+			}
+		}
+	}
+}
+
+func TestBusinessCalendarNextBusinessDay(t *testing.T) {
+	calendar := NewBusinessCalendar([]DBHoliday{
+		{ID: 1, Date: day(2020, time.September, 20), Name: "Revolução Farroupilha", Scope: string(HolidayScopeState), Location: "RS", Recurring: true},
+		{ID: 2, Date: day(2026, time.March, 26), Name: "Aniversário da cidade", Scope: string(HolidayScopeMunicipal)},
+	})
+	cases := []struct {
+		name string
+		from time.Time
+		want time.Time
+	}{
+		{"dia útil", day(2026, time.March, 2), day(2026, time.March, 2)},
+		{"sábado", day(2026, time.March, 7), day(2026, time.March, 9)},
+		{"sexta-feira santa e fim de semana", day(2026, time.April, 3), day(2026, time.April, 6)},
+		{"carnaval", day(2026, time.February, 14), day(2026, time.February, 18)},
+		{"feriado local de uma única data", day(2026, time.March, 26), day(2026, time.March, 27)},
+		{"feriado local anual", day(2028, time.September, 20), day(2028, time.September, 21)},
+	}
+	for _, tc := range cases {
+		if got := calendar.NextBusinessDay(tc.from); !got.Equal(tc.want) {
+			t.Errorf("%s: NextBusinessDay(%s) = %s, esperado %s", tc.name,
+				tc.from.Format("02/01/2006"), got.Format("02/01/2006"), tc.want.Format("02/01/2006"))
+		}
+	}
+}
+
+func TestHolidayLocalityIncludes(t *testing.T) {
+	locality := HolidayLocality{UF: "RS", Municipality: "Porto Alegre"}
+	cases := []struct {
+		locality HolidayLocality
+		scope    HolidayScope
+		location string
+		want     bool
+	}{
+		{locality, HolidayScopeState, "", true},
+		{locality, HolidayScopeState, "rs", true},
+		{locality, HolidayScopeState, "SC", false},
+		{locality, HolidayScopeMunicipal, " porto  alegre ", true},
+		{locality, HolidayScopeMunicipal, "Canoas", false},
+		{locality, HolidayScopeMunicipal, "RS", false},
+		{HolidayLocality{}, HolidayScopeState, "", true},
+		{HolidayLocality{}, HolidayScopeState, "RS", false},
+		{HolidayLocality{UF: "RS"}, HolidayScopeMunicipal, "Porto Alegre", false},
+	}
+	for _, tc := range cases {
+		if got := tc.locality.Includes(tc.scope, tc.location); got != tc.want {
+			t.Errorf("%+v.Includes(%s, %q) = %t, esperado %t", tc.locality, tc.scope, tc.location, got, tc.want)
+		}
+	}
+}
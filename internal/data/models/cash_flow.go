@@ -0,0 +1,145 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+)
+
+// CashFlowMaxHorizonDays limita o horizonte da projeção de fluxo de caixa.
+const CashFlowMaxHorizonDays = 730
+
+// CashFlowGranularity define o tamanho dos períodos da projeção de fluxo de caixa.
+type CashFlowGranularity string
+
+const (
+	CashFlowDaily   CashFlowGranularity = "DAILY"   // Um período por dia.
+	CashFlowWeekly  CashFlowGranularity = "WEEKLY"  // Semanas de segunda a domingo.
+	CashFlowMonthly CashFlowGranularity = "MONTHLY" // Meses do calendário.
+)
+
+// Validate verifica se a granularidade é suportada.
+func (g CashFlowGranularity) Validate() error {
+	switch g {
+	case CashFlowDaily, CashFlowWeekly, CashFlowMonthly:
+		return nil
+	}
+	return fmt.Errorf("%w: granularidade de fluxo de caixa '%s' não suportada", appErrors.ErrInvalidInput, g)
+}
+
+// Label retorna o nome da granularidade para exibição.
+func (g CashFlowGranularity) Label() string {
+	switch g {
+	case CashFlowWeekly:
+		return "Semanal"
+	case CashFlowMonthly:
+		return "Mensal"
+	}
+	return "Diário"
+}
+
+// PeriodStart retorna o início do período que contém o dia `day` (UTC, sem horário).
+func (g CashFlowGranularity) PeriodStart(day time.Time) time.Time {
+	switch g {
+	case CashFlowWeekly:
+		offset := (int(day.Weekday()) + 6) % 7 // Dias desde a segunda-feira.
+		return day.AddDate(0, 0, -offset)
+	case CashFlowMonthly:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// NextPeriodStart retorna o início do período seguinte ao que começa em `start`.
+func (g CashFlowGranularity) NextPeriodStart(start time.Time) time.Time {
+	switch g {
+	case CashFlowWeekly:
+		return start.AddDate(0, 0, 7)
+	case CashFlowMonthly:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// CashFlowOptions são os parâmetros da projeção de fluxo de caixa.
+type CashFlowOptions struct {
+	Granularity CashFlowGranularity
+	StartDate   time.Time // Primeiro dia da projeção.
+	HorizonDays int       // Número de dias projetados a partir de StartDate (1 a CashFlowMaxHorizonDays).
+
+	// UseScheduledDate usa DTAPROGRAMADA, quando preenchida, no lugar de DTAVENCIMENTO.
+	UseScheduledDate bool
+}
+
+// Validate verifica os parâmetros da projeção.
+func (o CashFlowOptions) Validate() error {
+	if err := o.Granularity.Validate(); err != nil {
+		return err
+	}
+	if o.StartDate.IsZero() {
+		return fmt.Errorf("%w: data inicial do fluxo de caixa é obrigatória", appErrors.ErrInvalidInput)
+	}
+	if o.HorizonDays < 1 || o.HorizonDays > CashFlowMaxHorizonDays {
+		return fmt.Errorf("%w: horizonte do fluxo de caixa deve ser de 1 a %d dias", appErrors.ErrInvalidInput, CashFlowMaxHorizonDays)
+	}
+	return nil
+}
+
+// CashFlowPeriod são as entradas e saídas previstas em um período.
+type CashFlowPeriod struct {
+	Start        time.Time       `json:"start"`   // Primeiro dia do período (UTC).
+	End          time.Time       `json:"end"`     // Último dia do período (UTC), limitado ao horizonte.
+	Inflow       decimal.Decimal `json:"inflow"`  // Direitos em aberto (a receber).
+	Outflow      decimal.Decimal `json:"outflow"` // Obrigações em aberto (a pagar).
+	Net          decimal.Decimal `json:"net"`     // Inflow - Outflow.
+	Cumulative   decimal.Decimal `json:"cumulative"`
+	InflowCount  int             `json:"inflow_count"`
+	OutflowCount int             `json:"outflow_count"`
+}
+
+// AddInflow acumula um direito em aberto no período.
+func (p *CashFlowPeriod) AddInflow(amount decimal.Decimal) {
+	p.Inflow = p.Inflow.Add(amount)
+	p.Net = p.Net.Add(amount)
+	p.InflowCount++
+}
+
+// AddOutflow acumula uma obrigação em aberto no período.
+func (p *CashFlowPeriod) AddOutflow(amount decimal.Decimal) {
+	p.Outflow = p.Outflow.Add(amount)
+	p.Net = p.Net.Sub(amount)
+	p.OutflowCount++
+}
+
+// Label retorna o período formatado para exibição.
+func (p *CashFlowPeriod) Label(g CashFlowGranularity) string {
+	switch {
+	case g == CashFlowMonthly:
+		return p.Start.Format("01/2006")
+	case p.Start.Equal(p.End):
+		return p.Start.Format("02/01/2006")
+	}
+	return p.Start.Format("02/01") + " a " + p.End.Format("02/01/2006")
+}
+
+// CashFlowProjection é a projeção de entradas (direitos) e saídas (obrigações) em aberto por período.
+type CashFlowProjection struct {
+	Options     CashFlowOptions  `json:"options"`
+	EndDate     time.Time        `json:"end_date"` // Último dia do horizonte (UTC).
+	GeneratedAt time.Time        `json:"generated_at"`
+	Periods     []CashFlowPeriod `json:"periods"` // Em ordem cronológica; Cumulative acumula Net desde o início.
+	Totals      CashFlowPeriod   `json:"totals"`  // Soma dos períodos do horizonte.
+
+	// Overdue soma os títulos em aberto com data anterior ao início da projeção; Undated, os sem data.
+	// Nenhum dos dois entra nos períodos nem na posição acumulada.
+	Overdue CashFlowPeriod `json:"overdue"`
+	Undated CashFlowPeriod `json:"undated"`
+
+	// BeyondHorizon conta os títulos em aberto com data posterior ao horizonte.
+	BeyondHorizon int `json:"beyond_horizon"`
+	// InvalidTitles conta os títulos ignorados por valores que não puderam ser convertidos.
+	InvalidTitles int `json:"invalid_titles"`
+}
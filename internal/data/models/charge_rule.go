@@ -0,0 +1,375 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+)
+
+// ChargeMaxGraceDays limita a carência de uma regra de encargos.
+const ChargeMaxGraceDays = 365
+
+// chargeDaysPerMonth é o mês comercial usado para ratear os juros mensais por dia.
+const chargeDaysPerMonth = 30
+
+var (
+	decimalHundred     = decimal.NewFromInt(100)
+	decimalDaysInMonth = decimal.NewFromInt(chargeDaysPerMonth)
+)
+
+// ChargeFineType define como a multa por atraso é calculada.
+type ChargeFineType string
+
+const (
+	ChargeFineFixed   ChargeFineType = "FIXED"   // Valor fixo em reais.
+	ChargeFinePercent ChargeFineType = "PERCENT" // Percentual sobre o saldo em aberto.
+)
+
+// Label retorna o tipo de multa para exibição.
+func (t ChargeFineType) Label() string {
+	if t == ChargeFineFixed {
+		return "Valor fixo"
+	}
+	return "Percentual"
+}
+
+// ChargeInterestPeriod define o período a que a taxa de juros de mora se refere.
+type ChargeInterestPeriod string
+
+const (
+	ChargeInterestDaily   ChargeInterestPeriod = "DAILY"   // Taxa ao dia.
+	ChargeInterestMonthly ChargeInterestPeriod = "MONTHLY" // Taxa ao mês.
+)
+
+// Label retorna o período dos juros para exibição.
+func (p ChargeInterestPeriod) Label() string {
+	if p == ChargeInterestDaily {
+		return "ao dia"
+	}
+	return "ao mês"
+}
+
+// ChargeInterestMethod define como os juros mensais são contados. Os juros são sempre simples
+// (sem capitalização) e incidem sobre o saldo em aberto; para taxa diária os dois métodos coincidem.
+type ChargeInterestMethod string
+
+const (
+	// ChargeInterestSimple cobra a taxa mensal por mês completo de atraso.
+	ChargeInterestSimple ChargeInterestMethod = "SIMPLE"
+	// ChargeInterestProRata rateia a taxa mensal por dia de atraso (mês comercial de 30 dias).
+	ChargeInterestProRata ChargeInterestMethod = "PRO_RATA"
+)
+
+// Label retorna o método de contagem dos juros para exibição.
+func (m ChargeInterestMethod) Label() string {
+	if m == ChargeInterestSimple {
+		return "Por mês completo"
+	}
+	return "Pro rata die"
+}
+
+// DBChargeRule representa uma regra de encargos (multa e juros de mora) sobre títulos vencidos.
+// A regra vale para uma espécie (CODESPÉCIE), uma rede, ambas ou, sem nenhuma, é a regra padrão.
+// Os valores decimais são gravados como string ("2.00"), como os valores dos títulos.
+type DBChargeRule struct {
+	ID   uint64 `gorm:"primaryKey;autoIncrement"`
+	Name string `gorm:"type:varchar(100);not null"`
+
+	CodigoEspecie *string `gorm:"type:varchar(50);index"` // Nil vale para qualquer espécie.
+	NetworkID     *uint64 `gorm:"index"`                  // Nil vale para qualquer rede.
+
+	FineType       string `gorm:"type:varchar(20);not null"`
+	FineValue      string `gorm:"type:varchar(30);not null"` // Reais (FIXED) ou percentual (PERCENT).
+	InterestPeriod string `gorm:"type:varchar(20);not null"`
+	InterestRate   string `gorm:"type:varchar(30);not null"` // Percentual por período.
+	InterestMethod string `gorm:"type:varchar(20);not null"`
+
+	// GraceDays é a carência em dias corridos após o vencimento efetivo. Pagamentos dentro da carência
+	// não têm encargos; depois dela, multa e juros contam desde o vencimento.
+	GraceDays int `gorm:"not null;default:0"`
+
+	Active bool `gorm:"not null;default:true"`
+
+	CreatedAt time.Time `gorm:"not null;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"not null;autoUpdateTime"`
+	CreatedBy *string   `gorm:"type:varchar(50)"`
+	UpdatedBy *string   `gorm:"type:varchar(50)"`
+}
+
+// TableName especifica o nome da tabela para GORM.
+func (DBChargeRule) TableName() string {
+	return "charge_rules"
+}
+
+// ChargeRuleCreate é usado para criar ou substituir uma regra de encargos.
+type ChargeRuleCreate struct {
+	Name           string               `json:"name"`
+	CodigoEspecie  *string              `json:"codigo_especie,omitempty"`
+	NetworkID      *uint64              `json:"network_id,omitempty"`
+	FineType       ChargeFineType       `json:"fine_type"`
+	FineValue      decimal.Decimal      `json:"fine_value"`
+	InterestPeriod ChargeInterestPeriod `json:"interest_period"`
+	InterestRate   decimal.Decimal      `json:"interest_rate"`
+	InterestMethod ChargeInterestMethod `json:"interest_method"`
+	GraceDays      int                  `json:"grace_days"`
+	Active         bool                 `json:"active"`
+}
+
+// CleanAndValidate normaliza e valida os campos de ChargeRuleCreate.
+// A espécie é convertida para maiúsculas (vazia equivale a qualquer espécie) e os enums para maiúsculas.
+func (rc *ChargeRuleCreate) CleanAndValidate() error {
+	rc.Name = strings.Join(strings.Fields(rc.Name), " ")
+	if n := utf8.RuneCountInString(rc.Name); n < 3 || n > 100 {
+		return appErrors.NewValidationError("Nome da regra deve ter entre 3 e 100 caracteres.", map[string]string{"name": "tamanho inválido"})
+	}
+	if rc.CodigoEspecie != nil {
+		especie := strings.ToUpper(strings.TrimSpace(*rc.CodigoEspecie))
+		if especie == "" {
+			rc.CodigoEspecie = nil
+		} else if utf8.RuneCountInString(especie) > 50 {
+			return appErrors.NewValidationError("Código de espécie deve ter no máximo 50 caracteres.", map[string]string{"codigo_especie": "muito longo"})
+		} else {
+			rc.CodigoEspecie = &especie
+		}
+	}
+	if rc.NetworkID != nil && *rc.NetworkID == 0 {
+		rc.NetworkID = nil
+	}
+
+	rc.FineType = ChargeFineType(strings.ToUpper(strings.TrimSpace(string(rc.FineType))))
+	switch rc.FineType {
+	case ChargeFineFixed, ChargeFinePercent:
+	default:
+		return appErrors.NewValidationError("Tipo de multa deve ser FIXED (valor fixo) ou PERCENT (percentual).", map[string]string{"fine_type": "valor inválido"})
+	}
+	if rc.FineValue.IsNegative() {
+		return appErrors.NewValidationError("Valor da multa não pode ser negativo.", map[string]string{"fine_value": "negativo"})
+	}
+	if rc.FineType == ChargeFinePercent && rc.FineValue.GreaterThan(decimalHundred) {
+		return appErrors.NewValidationError("Multa percentual não pode passar de 100%.", map[string]string{"fine_value": "acima de 100%"})
+	}
+
+	rc.InterestPeriod = ChargeInterestPeriod(strings.ToUpper(strings.TrimSpace(string(rc.InterestPeriod))))
+	switch rc.InterestPeriod {
+	case ChargeInterestDaily, ChargeInterestMonthly:
+	default:
+		return appErrors.NewValidationError("Período dos juros deve ser DAILY (ao dia) ou MONTHLY (ao mês).", map[string]string{"interest_period": "valor inválido"})
+	}
+	rc.InterestMethod = ChargeInterestMethod(strings.ToUpper(strings.TrimSpace(string(rc.InterestMethod))))
+	switch rc.InterestMethod {
+	case ChargeInterestSimple, ChargeInterestProRata:
+	default:
+		return appErrors.NewValidationError("Contagem dos juros deve ser SIMPLE (mês completo) ou PRO_RATA (por dia).", map[string]string{"interest_method": "valor inválido"})
+	}
+	if rc.InterestRate.IsNegative() || rc.InterestRate.GreaterThan(decimalHundred) {
+		return appErrors.NewValidationError("Taxa de juros deve estar entre 0% e 100% por período.", map[string]string{"interest_rate": "fora do intervalo"})
+	}
+
+	if rc.GraceDays < 0 || rc.GraceDays > ChargeMaxGraceDays {
+		return appErrors.NewValidationError(
+			fmt.Sprintf("Carência deve ser de 0 a %d dias.", ChargeMaxGraceDays),
+			map[string]string{"grace_days": "fora do intervalo"},
+		)
+	}
+	return nil
+}
+
+// ChargeRule é uma regra de encargos com os valores já convertidos para decimal.
+type ChargeRule struct {
+	ID             uint64               `json:"id"`
+	Name           string               `json:"name"`
+	CodigoEspecie  *string              `json:"codigo_especie,omitempty"`
+	NetworkID      *uint64              `json:"network_id,omitempty"`
+	FineType       ChargeFineType       `json:"fine_type"`
+	FineValue      decimal.Decimal      `json:"fine_value"`
+	InterestPeriod ChargeInterestPeriod `json:"interest_period"`
+	InterestRate   decimal.Decimal      `json:"interest_rate"`
+	InterestMethod ChargeInterestMethod `json:"interest_method"`
+	GraceDays      int                  `json:"grace_days"`
+	Active         bool                 `json:"active"`
+}
+
+// ToChargeRule converte DBChargeRule para ChargeRule.
+func ToChargeRule(db *DBChargeRule) (*ChargeRule, error) {
+	fine, err := decimal.NewFromString(db.FineValue)
+	if err != nil {
+		return nil, fmt.Errorf("multa '%s' inválida na regra de encargos ID %d: %w", db.FineValue, db.ID, err)
+	}
+	rate, err := decimal.NewFromString(db.InterestRate)
+	if err != nil {
+		return nil, fmt.Errorf("taxa de juros '%s' inválida na regra de encargos ID %d: %w", db.InterestRate, db.ID, err)
+	}
+	return &ChargeRule{
+		ID:             db.ID,
+		Name:           db.Name,
+		CodigoEspecie:  db.CodigoEspecie,
+		NetworkID:      db.NetworkID,
+		FineType:       ChargeFineType(db.FineType),
+		FineValue:      fine,
+		InterestPeriod: ChargeInterestPeriod(db.InterestPeriod),
+		InterestRate:   rate,
+		InterestMethod: ChargeInterestMethod(db.InterestMethod),
+		GraceDays:      db.GraceDays,
+		Active:         db.Active,
+	}, nil
+}
+
+// specificity ordena as regras aplicáveis: espécie e rede > rede > espécie > padrão.
+func (r *ChargeRule) specificity() int {
+	score := 0
+	if r.NetworkID != nil {
+		score += 2
+	}
+	if r.CodigoEspecie != nil {
+		score++
+	}
+	return score
+}
+
+// matches indica se a regra vale para um título da espécie e rede informadas.
+func (r *ChargeRule) matches(codigoEspecie *string, networkID *uint64) bool {
+	if r.CodigoEspecie != nil {
+		if codigoEspecie == nil || !strings.EqualFold(strings.TrimSpace(*codigoEspecie), *r.CodigoEspecie) {
+			return false
+		}
+	}
+	if r.NetworkID != nil {
+		if networkID == nil || *networkID != *r.NetworkID {
+			return false
+		}
+	}
+	return true
+}
+
+// Describe resume a regra para exibição (ex: "Multa 2% + juros 1% ao mês pro rata die, carência 3 dias").
+func (r *ChargeRule) Describe() string {
+	fine := "R$ " + r.FineValue.StringFixed(2)
+	if r.FineType == ChargeFinePercent {
+		fine = r.FineValue.String() + "%"
+	}
+	desc := fmt.Sprintf("Multa %s + juros %s%% %s", fine, r.InterestRate.String(), r.InterestPeriod.Label())
+	if r.InterestPeriod == ChargeInterestMonthly {
+		desc += " (" + strings.ToLower(r.InterestMethod.Label()) + ")"
+	}
+	if r.GraceDays > 0 {
+		desc += fmt.Sprintf(", carência %d dias", r.GraceDays)
+	}
+	return desc
+}
+
+// ChargeCalculation é o valor atualizado de um título em uma data.
+type ChargeCalculation struct {
+	AsOf             time.Time       `json:"as_of"`
+	OpenBalance      decimal.Decimal `json:"open_balance"`
+	EffectiveDueDate *time.Time      `json:"effective_due_date,omitempty"`
+	DaysOverdue      int             `json:"days_overdue"` // Dias corridos desde o vencimento, se vencido.
+	InGracePeriod    bool            `json:"in_grace_period"`
+	Rule             *ChargeRule     `json:"rule,omitempty"` // Nil se nenhuma regra se aplica.
+	Fine             decimal.Decimal `json:"fine"`
+	Interest         decimal.Decimal `json:"interest"`
+	UpdatedAmount    decimal.Decimal `json:"updated_amount"` // OpenBalance + Fine + Interest.
+}
+
+// HasCharges indica se o cálculo gerou multa ou juros.
+func (c *ChargeCalculation) HasCharges() bool {
+	return c.Fine.IsPositive() || c.Interest.IsPositive()
+}
+
+// ChargeEngine calcula multa e juros de mora pelas regras ativas e pelo calendário de dias úteis.
+// Como o calendário, uma instância não deve ser compartilhada entre goroutines.
+type ChargeEngine struct {
+	rules    []ChargeRule
+	calendar *BusinessCalendar
+}
+
+// NewChargeEngine cria o motor de encargos. Regras inativas são ignoradas.
+func NewChargeEngine(rules []ChargeRule, calendar *BusinessCalendar) *ChargeEngine {
+	active := make([]ChargeRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Active {
+			active = append(active, rule)
+		}
+	}
+	return &ChargeEngine{rules: active, calendar: calendar}
+}
+
+// RuleFor retorna a regra mais específica para a espécie e a rede (nil se nenhuma se aplica).
+// Entre regras igualmente específicas, vale a de menor ID (a mais antiga).
+func (e *ChargeEngine) RuleFor(codigoEspecie *string, networkID *uint64) *ChargeRule {
+	var best *ChargeRule
+	for i := range e.rules {
+		rule := &e.rules[i]
+		if !rule.matches(codigoEspecie, networkID) {
+			continue
+		}
+		if best == nil || rule.specificity() > best.specificity() ||
+			(rule.specificity() == best.specificity() && rule.ID < best.ID) {
+			best = rule
+		}
+	}
+	return best
+}
+
+// Calculate calcula o valor atualizado do título em `asOf`. O título só tem encargos se estiver
+// em aberto, com vencimento efetivo (próximo dia útil) anterior a `asOf` e fora da carência.
+// Multa e juros incidem sobre o saldo em aberto e são arredondados para centavos.
+func (e *ChargeEngine) Calculate(balance *TituloBalance, asOf time.Time) (*ChargeCalculation, error) {
+	open, err := balance.OpenBalance()
+	if err != nil {
+		return nil, err
+	}
+	day := calendarDay(asOf)
+	calc := &ChargeCalculation{AsOf: day, OpenBalance: open, UpdatedAmount: open}
+	if balance.DataVencimento == nil || !open.IsPositive() {
+		return calc, nil
+	}
+	calc.EffectiveDueDate = e.calendar.EffectiveDueDate(balance.DataVencimento)
+	if !calc.EffectiveDueDate.Before(day) {
+		return calc, nil
+	}
+	calc.DaysOverdue = int(day.Sub(calendarDay(*balance.DataVencimento)).Hours() / 24)
+
+	rule := e.RuleFor(balance.CodigoEspecie, balance.NetworkID)
+	if rule == nil {
+		return calc, nil
+	}
+	calc.Rule = rule
+	if !calc.EffectiveDueDate.AddDate(0, 0, rule.GraceDays).Before(day) {
+		calc.InGracePeriod = true
+		return calc, nil
+	}
+
+	if rule.FineType == ChargeFineFixed {
+		calc.Fine = rule.FineValue.Round(2)
+	} else {
+		calc.Fine = open.Mul(rule.FineValue).Div(decimalHundred).Round(2)
+	}
+
+	// Uma única divisão no fim evita arredondar o rateio diário antes de multiplicar pelo saldo.
+	periods, divisor := decimal.NewFromInt(int64(calc.DaysOverdue)), decimalHundred
+	if rule.InterestPeriod == ChargeInterestMonthly {
+		if rule.InterestMethod == ChargeInterestSimple {
+			periods = decimal.NewFromInt(int64(fullMonthsBetween(calendarDay(*balance.DataVencimento), day)))
+		} else {
+			divisor = decimalHundred.Mul(decimalDaysInMonth)
+		}
+	}
+	calc.Interest = open.Mul(rule.InterestRate).Mul(periods).Div(divisor).Round(2)
+	calc.UpdatedAmount = open.Add(calc.Fine).Add(calc.Interest)
+	return calc, nil
+}
+
+// fullMonthsBetween conta os meses completos de `from` até `to`. Um mês se completa no mesmo dia do mês
+// seguinte; para dias que o mês seguinte não tem, vale a normalização de time.AddDate (31/01 + 1 mês = 03/03).
+func fullMonthsBetween(from, to time.Time) int {
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	for months > 0 && from.AddDate(0, months, 0).After(to) {
+		months--
+	}
+	return months
+}
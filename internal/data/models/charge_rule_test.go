@@ -0,0 +1,124 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestChargeEngineCalculateRounding(t *testing.T) {
+	due := day(2026, time.January, 5) // Segunda-feira, dia útil.
+	dec := decimal.RequireFromString
+
+	cases := []struct {
+		name         string
+		rule         ChargeRule
+		nominal      string
+		paid         string
+		asOf         time.Time
+		wantDays     int
+		wantFine     string
+		wantInterest string
+		wantUpdated  string
+	}{
+		{
+			name:    "multa percentual e juros diários",
+			rule:    ChargeRule{FineType: ChargeFinePercent, FineValue: dec("2"), InterestPeriod: ChargeInterestDaily, InterestRate: dec("0.033")},
+			nominal: "333.33", asOf: day(2026, time.February, 4),
+			wantDays: 30, wantFine: "6.67", wantInterest: "3.30", wantUpdated: "343.30",
+		},
+		{
+			name:    "multa fixa com três casas e juros mensais pro rata",
+			rule:    ChargeRule{FineType: ChargeFineFixed, FineValue: dec("5.555"), InterestPeriod: ChargeInterestMonthly, InterestRate: dec("1"), InterestMethod: ChargeInterestProRata},
+			nominal: "1000.01", asOf: day(2026, time.February, 4),
+			wantDays: 30, wantFine: "5.56", wantInterest: "10.00", wantUpdated: "1015.57",
+		},
+		{
+			name:    "juros pro rata arredondados só no fim",
+			rule:    ChargeRule{FineType: ChargeFinePercent, FineValue: dec("0"), InterestPeriod: ChargeInterestMonthly, InterestRate: dec("1"), InterestMethod: ChargeInterestProRata},
+			nominal: "100.00", asOf: day(2026, time.January, 8),
+			wantDays: 3, wantFine: "0", wantInterest: "0.10", wantUpdated: "100.10",
+		},
+		{
+			name:    "meio centavo arredonda para cima",
+			rule:    ChargeRule{FineType: ChargeFinePercent, FineValue: dec("2"), InterestPeriod: ChargeInterestDaily, InterestRate: dec("0.01")},
+			nominal: "50.25", paid: "50.00", asOf: day(2026, time.January, 7),
+			wantDays: 2, wantFine: "0.01", wantInterest: "0.00", wantUpdated: "0.26",
+		},
+		{
+			name:    "juros mensais por mês completo",
+			rule:    ChargeRule{FineType: ChargeFinePercent, FineValue: dec("2"), InterestPeriod: ChargeInterestMonthly, InterestRate: dec("1"), InterestMethod: ChargeInterestSimple},
+			nominal: "1234.56", asOf: day(2026, time.March, 4),
+			wantDays: 58, wantFine: "24.69", wantInterest: "12.35", wantUpdated: "1271.60",
+		},
+		{
+			name:    "mês incompleto não gera juros simples",
+			rule:    ChargeRule{FineType: ChargeFinePercent, FineValue: dec("2"), InterestPeriod: ChargeInterestMonthly, InterestRate: dec("1"), InterestMethod: ChargeInterestSimple},
+			nominal: "1234.56", asOf: day(2026, time.February, 4),
+			wantDays: 30, wantFine: "24.69", wantInterest: "0", wantUpdated: "1259.25",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.rule.ID, tc.rule.Active = 1, true
+			engine := NewChargeEngine([]ChargeRule{tc.rule}, NewBusinessCalendar(nil))
+			balance := &TituloBalance{DataVencimento: &due, ValorNominal: tc.nominal}
+			if tc.paid != "" {
+				balance.ValorPago = &tc.paid
+			}
+
+			calc, err := engine.Calculate(balance, tc.asOf)
+			if err != nil {
+				t.Fatalf("Calculate: %v", err)
+			}
+			if calc.DaysOverdue != tc.wantDays {
+				t.Errorf("dias de atraso = %d, esperado %d", calc.DaysOverdue, tc.wantDays)
+			}
+			for _, check := range []struct {
+				label string
+				got   decimal.Decimal
+				want  string
+			}{
+				{"multa", calc.Fine, tc.wantFine},
+				{"juros", calc.Interest, tc.wantInterest},
+				{"valor atualizado", calc.UpdatedAmount, tc.wantUpdated},
+			} {
+				if !check.got.Equal(dec(check.want)) {
+					t.Errorf("%s = %s, esperado %s", check.label, check.got, check.want)
+				}
+			}
+		})
+	}
+}
+
+func TestChargeEngineCalculateGracePeriod(t *testing.T) {
+	due := day(2026, time.April, 3) // Sexta-feira Santa: vencimento efetivo em 06/04.
+	rule := ChargeRule{
+		ID: 1, Active: true, GraceDays: 2,
+		FineType: ChargeFinePercent, FineValue: decimal.NewFromInt(2),
+		InterestPeriod: ChargeInterestDaily, InterestRate: decimal.RequireFromString("0.1"),
+	}
+	engine := NewChargeEngine([]ChargeRule{rule}, NewBusinessCalendar(nil))
+
+	cases := []struct {
+		asOf       time.Time
+		wantGrace  bool
+		wantCharge bool
+	}{
+		{day(2026, time.April, 6), false, false}, // Vencimento efetivo.
+		{day(2026, time.April, 8), true, false},  // Último dia da carência.
+		{day(2026, time.April, 9), false, true},  // Encargos contam desde o vencimento original.
+	}
+	for _, tc := range cases {
+		calc, err := engine.Calculate(&TituloBalance{DataVencimento: &due, ValorNominal: "100.00"}, tc.asOf)
+		if err != nil {
+			t.Fatalf("Calculate: %v", err)
+		}
+		if calc.InGracePeriod != tc.wantGrace || calc.HasCharges() != tc.wantCharge {
+			t.Errorf("%s: carência %t, encargos %t; esperado %t, %t",
+				tc.asOf.Format("02/01/2006"), calc.InGracePeriod, calc.HasCharges(), tc.wantGrace, tc.wantCharge)
+		}
+	}
+}
@@ -131,3 +131,15 @@ func ToCNPJPublicList(dbCNPJs []*DBCNPJ) []*CNPJPublic {
 func CleanCNPJ(cnpjStr string) string {
 	return cnpjNonDigitRegex.ReplaceAllString(cnpjStr, "")
 }
+
+// FormatCNPJCPF formata um CNPJ (14 dígitos) ou CPF (11 dígitos) para exibição.
+// Outros tamanhos são retornados sem alteração.
+func FormatCNPJCPF(digits string) string {
+	switch len(digits) {
+	case 14:
+		return fmt.Sprintf("%s.%s.%s/%s-%s", digits[0:2], digits[2:5], digits[5:8], digits[8:12], digits[12:14])
+	case 11:
+		return fmt.Sprintf("%s.%s.%s-%s", digits[0:3], digits[3:6], digits[6:9], digits[9:11])
+	}
+	return digits
+}
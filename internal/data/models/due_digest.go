@@ -0,0 +1,250 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+)
+
+const (
+	DueDigestDefaultDaysAhead = 7  // Janela padrão dos títulos a vencer.
+	DueDigestMaxDaysAhead     = 60 // Janela máxima dos títulos a vencer.
+	// DueDigestMaxItemsPerSection limita as linhas de cada seção do e-mail; os totais consideram todos os títulos.
+	DueDigestMaxItemsPerSection = 100
+)
+
+// DueDigestFrequency define a frequência do resumo de vencimentos por e-mail.
+type DueDigestFrequency string
+
+const (
+	DueDigestDaily  DueDigestFrequency = "DAILY"  // Todo dia útil.
+	DueDigestWeekly DueDigestFrequency = "WEEKLY" // No primeiro dia útil da semana.
+)
+
+// Label retorna a frequência para exibição.
+func (f DueDigestFrequency) Label() string {
+	if f == DueDigestWeekly {
+		return "Semanal (primeiro dia útil da semana)"
+	}
+	return "Diário (dias úteis)"
+}
+
+// DueDigestAudience identifica por que o usuário recebe o resumo.
+type DueDigestAudience string
+
+const (
+	DueDigestAudienceBuyer   DueDigestAudience = "BUYER"   // Comprador responsável por redes.
+	DueDigestAudienceFinance DueDigestAudience = "FINANCE" // Financeiro: todas as redes.
+)
+
+// Label retorna o público para exibição.
+func (a DueDigestAudience) Label() string {
+	if a == DueDigestAudienceFinance {
+		return "Financeiro (todas as redes)"
+	}
+	return "Comprador (suas redes)"
+}
+
+// DBDueDigestSubscription guarda a inscrição de um usuário no resumo de vencimentos por e-mail.
+// Usuários sem registro não recebem o resumo (a inscrição é opcional).
+type DBDueDigestSubscription struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement"`
+	UserID    uuid.UUID `gorm:"type:uuid;uniqueIndex;not null"`
+	Enabled   bool      `gorm:"not null;default:false"`
+	Frequency string    `gorm:"type:varchar(20);not null"`
+	DaysAhead int       `gorm:"not null"` // Janela, em dias corridos, dos títulos a vencer.
+
+	// LastRunAt é o último processamento agendado (com ou sem envio); evita reprocessar no mesmo dia.
+	LastRunAt  *time.Time
+	LastSentAt *time.Time
+
+	CreatedAt time.Time `gorm:"not null;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"not null;autoUpdateTime"`
+}
+
+// TableName especifica o nome da tabela para GORM.
+func (DBDueDigestSubscription) TableName() string {
+	return "due_digest_subscriptions"
+}
+
+// IsDue indica se o resumo agendado deve ser processado em `today` (dia do calendário, UTC).
+// Só há envio em dias úteis: diariamente, ou no primeiro dia útil da semana (a semana começa na segunda).
+func (s *DBDueDigestSubscription) IsDue(today time.Time, calendar *BusinessCalendar) bool {
+	if !s.Enabled || !calendar.IsBusinessDay(today) {
+		return false
+	}
+	if s.LastRunAt == nil {
+		return true
+	}
+	lastRun := calendarDay(*s.LastRunAt)
+	if !lastRun.Before(today) {
+		return false
+	}
+	if DueDigestFrequency(s.Frequency) == DueDigestWeekly {
+		weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		return lastRun.Before(weekStart)
+	}
+	return true
+}
+
+// DueDigestSubscriptionUpdate é usado pelo próprio usuário para alterar sua inscrição.
+type DueDigestSubscriptionUpdate struct {
+	Enabled   bool               `json:"enabled"`
+	Frequency DueDigestFrequency `json:"frequency"`
+	DaysAhead int                `json:"days_ahead"`
+}
+
+// CleanAndValidate normaliza e valida os campos de DueDigestSubscriptionUpdate.
+func (u *DueDigestSubscriptionUpdate) CleanAndValidate() error {
+	u.Frequency = DueDigestFrequency(strings.ToUpper(strings.TrimSpace(string(u.Frequency))))
+	if u.Frequency == "" {
+		u.Frequency = DueDigestDaily
+	}
+	switch u.Frequency {
+	case DueDigestDaily, DueDigestWeekly:
+	default:
+		return appErrors.NewValidationError("Frequência deve ser DAILY (diário) ou WEEKLY (semanal).", map[string]string{"frequency": "valor inválido"})
+	}
+	if u.DaysAhead < 1 || u.DaysAhead > DueDigestMaxDaysAhead {
+		return appErrors.NewValidationError(
+			fmt.Sprintf("Janela de vencimentos deve ser de 1 a %d dias.", DueDigestMaxDaysAhead),
+			map[string]string{"days_ahead": "fora do intervalo"},
+		)
+	}
+	return nil
+}
+
+// DueDigestSubscriptionPublic é a inscrição do usuário para exibição, com o público do resumo.
+type DueDigestSubscriptionPublic struct {
+	Enabled    bool               `json:"enabled"`
+	Frequency  DueDigestFrequency `json:"frequency"`
+	DaysAhead  int                `json:"days_ahead"`
+	LastSentAt *time.Time         `json:"last_sent_at,omitempty"`
+
+	// Audience é vazio se o usuário não é comprador de nenhuma rede nem do financeiro: o resumo não tem conteúdo.
+	Audience DueDigestAudience `json:"audience,omitempty"`
+	Networks []string          `json:"networks,omitempty"` // Redes do comprador (vazio para o financeiro).
+}
+
+// ToDueDigestSubscriptionPublic converte a inscrição; `db` nil resulta na inscrição padrão (desativada).
+func ToDueDigestSubscriptionPublic(db *DBDueDigestSubscription) *DueDigestSubscriptionPublic {
+	if db == nil {
+		return &DueDigestSubscriptionPublic{Frequency: DueDigestDaily, DaysAhead: DueDigestDefaultDaysAhead}
+	}
+	return &DueDigestSubscriptionPublic{
+		Enabled:    db.Enabled,
+		Frequency:  DueDigestFrequency(db.Frequency),
+		DaysAhead:  db.DaysAhead,
+		LastSentAt: db.LastSentAt,
+	}
+}
+
+// DueDigestItem é um título listado no resumo, com os valores já formatados para o e-mail.
+type DueDigestItem struct {
+	Kind                  string // "A receber" (direitos) ou "A pagar" (obrigações).
+	Network               string
+	CNPJCPF               string // Formatado.
+	Pessoa                string
+	Titulo                string
+	DataVencimento        string // "DD/MM/YYYY".
+	DataVencimentoEfetiva string // Próximo dia útil, se diferente do vencimento; vazio caso contrário.
+	OpenBalance           decimal.Decimal
+	OpenBalanceText       string // "R$ 1.234,56".
+
+	effectiveDue time.Time
+}
+
+// NewDueDigestItem monta a linha do resumo de um título em aberto.
+func NewDueDigestItem(kind, network string, balance *TituloBalance, open decimal.Decimal, effectiveDue time.Time) DueDigestItem {
+	item := DueDigestItem{
+		Kind:            kind,
+		Network:         network,
+		CNPJCPF:         FormatCNPJCPF(balance.CNPJCPF),
+		Titulo:          balance.Titulo,
+		OpenBalance:     open,
+		OpenBalanceText: FormatMoneyBRL(open),
+		effectiveDue:    effectiveDue,
+	}
+	if balance.Pessoa != nil {
+		item.Pessoa = strings.TrimSpace(*balance.Pessoa)
+	}
+	if balance.DataVencimento != nil {
+		due := calendarDay(*balance.DataVencimento)
+		item.DataVencimento = due.Format("02/01/2006")
+		if !due.Equal(effectiveDue) {
+			item.DataVencimentoEfetiva = effectiveDue.Format("02/01/2006")
+		}
+	}
+	return item
+}
+
+// EffectiveDue retorna o vencimento efetivo (dia útil) do título.
+func (i DueDigestItem) EffectiveDue() time.Time {
+	return i.effectiveDue
+}
+
+// DueDigestSection é uma lista de títulos do resumo com os totais de todos os títulos da seção,
+// inclusive os que passaram do limite de linhas.
+type DueDigestSection struct {
+	Items     []DueDigestItem
+	Count     int
+	Total     decimal.Decimal
+	Truncated int // Títulos não listados por causa do limite de linhas.
+}
+
+// Add inclui um título na seção, respeitando o limite de linhas.
+func (s *DueDigestSection) Add(item DueDigestItem) {
+	s.Count++
+	s.Total = s.Total.Add(item.OpenBalance)
+	if len(s.Items) < DueDigestMaxItemsPerSection {
+		s.Items = append(s.Items, item)
+	} else {
+		s.Truncated++
+	}
+}
+
+// DueDigest é o conteúdo do resumo de vencimentos de um destinatário.
+type DueDigest struct {
+	RecipientName string
+	Audience      DueDigestAudience
+	Frequency     DueDigestFrequency
+	Networks      []string // Redes do comprador (vazio para o financeiro).
+	ReferenceDate time.Time
+	DaysAhead     int
+	UpcomingUntil time.Time // Último dia da janela dos títulos a vencer.
+	OverdueSince  time.Time // Primeiro vencimento efetivo considerado "vencido desde a última importação".
+	Upcoming      DueDigestSection
+	NewlyOverdue  DueDigestSection
+	GeneratedAt   time.Time
+}
+
+// IsEmpty indica se o resumo não tem nenhum título.
+func (d *DueDigest) IsEmpty() bool {
+	return d.Upcoming.Count == 0 && d.NewlyOverdue.Count == 0
+}
+
+// FormatMoneyBRL formata um valor no padrão brasileiro (ex: "R$ 1.234,56").
+func FormatMoneyBRL(value decimal.Decimal) string {
+	fixed := value.StringFixed(2)
+	negative := strings.HasPrefix(fixed, "-")
+	fixed = strings.TrimPrefix(fixed, "-")
+	intPart, fracPart := fixed[:len(fixed)-3], fixed[len(fixed)-2:]
+
+	var b strings.Builder
+	for i, digit := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(digit)
+	}
+	formatted := "R$ " + b.String() + "," + fracPart
+	if negative {
+		formatted = "-" + formatted
+	}
+	return formatted
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+)
+
+// HolidayScope indica a abrangência de um feriado.
+type HolidayScope string
+
+const (
+	// HolidayScopeNational são os feriados nacionais, calculados pelo calendário (nunca gravados no banco).
+	HolidayScopeNational  HolidayScope = "NACIONAL"
+	HolidayScopeState     HolidayScope = "ESTADUAL"
+	HolidayScopeMunicipal HolidayScope = "MUNICIPAL"
+)
+
+// Label retorna o nome da abrangência para exibição.
+func (s HolidayScope) Label() string {
+	switch s {
+	case HolidayScopeState:
+		return "Estadual"
+	case HolidayScopeMunicipal:
+		return "Municipal"
+	}
+	return "Nacional"
+}
+
+// ExpectedHeadersHoliday define os cabeçalhos esperados para o arquivo de feriados locais.
+// DATA no formato DD/MM/AAAA; ABRANGENCIA é ESTADUAL ou MUNICIPAL; LOCAL é vazio ou a UF/município da praça
+// configurada (ver `HolidayCreate`); ANUAL é S/N (repete todo ano no mesmo dia e mês).
+var ExpectedHeadersHoliday = []string{"DATA", "NOME", "ABRANGENCIA", "LOCAL", "ANUAL"}
+
+// DBHoliday representa um feriado estadual ou municipal cadastrado pelo administrador.
+// Os feriados nacionais não são gravados: são calculados por `NationalHolidays`.
+type DBHoliday struct {
+	ID uint64 `gorm:"primaryKey;autoIncrement"`
+
+	// Data do feriado (sem horário, UTC). Para feriados anuais, apenas o dia e o mês são considerados.
+	Date time.Time `gorm:"type:date;not null;uniqueIndex:idx_holiday_date_scope_location"`
+
+	Name     string `gorm:"type:varchar(100);not null"`
+	Scope    string `gorm:"type:varchar(20);not null;uniqueIndex:idx_holiday_date_scope_location"` // ESTADUAL ou MUNICIPAL.
+	Location string `gorm:"type:varchar(100);not null;default:'';uniqueIndex:idx_holiday_date_scope_location"`
+
+	// Recurring indica que o feriado se repete todo ano no mesmo dia e mês (ex: 20 de setembro no RS).
+	Recurring bool `gorm:"not null;default:false"`
+
+	CreatedAt time.Time `gorm:"not null;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"not null;autoUpdateTime"`
+	CreatedBy *string   `gorm:"type:varchar(50)"`
+	UpdatedBy *string   `gorm:"type:varchar(50)"`
+}
+
+// TableName especifica o nome da tabela para GORM.
+func (DBHoliday) TableName() string {
+	return "holidays"
+}
+
+// HolidayLocality é a praça do calendário de dias úteis (UF e município configurados).
+// Os títulos não informam a localidade de pagamento, então cada feriado local vale para todos eles
+// e o calendário atende uma única praça.
+type HolidayLocality struct {
+	UF           string
+	Municipality string
+}
+
+// Includes indica se um feriado local pertence à praça. Local vazio é tratado como a própria praça;
+// a comparação ignora maiúsculas e minúsculas.
+func (l HolidayLocality) Includes(scope HolidayScope, location string) bool {
+	location = strings.Join(strings.Fields(location), " ")
+	if location == "" {
+		return true
+	}
+	switch scope {
+	case HolidayScopeState:
+		return l.UF != "" && strings.EqualFold(location, l.UF)
+	case HolidayScopeMunicipal:
+		return l.Municipality != "" && strings.EqualFold(location, l.Municipality)
+	}
+	return false
+}
+
+// HolidayCreate é usado para cadastrar (ou, na importação, atualizar) um feriado local.
+//
+// Feriados estaduais e municipais valem para todos os títulos: o calendário atende uma única praça,
+// configurada por APP_HOLIDAY_UF e APP_HOLIDAY_MUNICIPALITY. O local é opcional (vazio significa a
+// própria praça); quando informado, deve ser a UF (feriado estadual) ou o município (feriado municipal)
+// configurado, caso contrário o feriado é recusado por `CheckLocality`.
+type HolidayCreate struct {
+	Date      time.Time    `json:"date"`
+	Name      string       `json:"name"`
+	Scope     HolidayScope `json:"scope"`
+	Location  string       `json:"location"` // UF ou município da praça configurada; opcional.
+	Recurring bool         `json:"recurring"`
+}
+
+// CleanAndValidate normaliza e valida os campos de HolidayCreate.
+// A data perde o horário (dia do calendário em UTC) e a abrangência é convertida para maiúsculas.
+func (hc *HolidayCreate) CleanAndValidate() error {
+	if hc.Date.IsZero() {
+		return appErrors.NewValidationError("Data do feriado é obrigatória.", map[string]string{"date": "obrigatório"})
+	}
+	hc.Date = time.Date(hc.Date.Year(), hc.Date.Month(), hc.Date.Day(), 0, 0, 0, 0, time.UTC)
+
+	hc.Name = strings.Join(strings.Fields(hc.Name), " ")
+	if n := utf8.RuneCountInString(hc.Name); n < 2 || n > 100 {
+		return appErrors.NewValidationError("Nome do feriado deve ter entre 2 e 100 caracteres.", map[string]string{"name": "tamanho inválido"})
+	}
+
+	hc.Scope = HolidayScope(strings.ToUpper(strings.TrimSpace(string(hc.Scope))))
+	switch hc.Scope {
+	case HolidayScopeState, HolidayScopeMunicipal:
+	case HolidayScopeNational:
+		return appErrors.NewValidationError(
+			"Feriados nacionais são calculados automaticamente e não podem ser cadastrados.",
+			map[string]string{"scope": "não permitido"},
+		)
+	default:
+		return appErrors.NewValidationError("Abrangência do feriado deve ser ESTADUAL ou MUNICIPAL.", map[string]string{"scope": "valor inválido"})
+	}
+
+	hc.Location = strings.Join(strings.Fields(hc.Location), " ")
+	if utf8.RuneCountInString(hc.Location) > 100 {
+		return appErrors.NewValidationError("Local do feriado deve ter no máximo 100 caracteres.", map[string]string{"location": "muito longo"})
+	}
+	return nil
+}
+
+// CheckLocality recusa o feriado cujo local não pertence à praça do calendário.
+// Deve ser chamado após `CleanAndValidate`.
+func (hc *HolidayCreate) CheckLocality(locality HolidayLocality) error {
+	if locality.Includes(hc.Scope, hc.Location) {
+		return nil
+	}
+	configured := locality.UF
+	if hc.Scope == HolidayScopeMunicipal {
+		configured = locality.Municipality
+	}
+	if configured == "" {
+		return appErrors.NewValidationError(
+			fmt.Sprintf("Local '%s' não aceito: nenhuma praça %s está configurada para o calendário; deixe o local em branco.",
+				hc.Location, strings.ToLower(hc.Scope.Label())),
+			map[string]string{"location": "fora da praça"},
+		)
+	}
+	return appErrors.NewValidationError(
+		fmt.Sprintf("Local '%s' não aceito: o calendário de dias úteis atende apenas a praça '%s'.", hc.Location, configured),
+		map[string]string{"location": "fora da praça"},
+	)
+}
+
+// HolidayPublic representa um feriado (nacional ou local) para exibição.
+type HolidayPublic struct {
+	ID        uint64       `json:"id"`   // Zero para feriados nacionais.
+	Date      string       `json:"date"` // "DD/MM/AAAA", já no ano consultado para feriados anuais.
+	Weekday   string       `json:"weekday"`
+	Name      string       `json:"name"`
+	Scope     HolidayScope `json:"scope"`
+	Location  string       `json:"location,omitempty"`
+	Recurring bool         `json:"recurring"`
+}
+
+// ToHolidayPublic converte um feriado do calendário para HolidayPublic.
+func ToHolidayPublic(h Holiday) *HolidayPublic {
+	return &HolidayPublic{
+		ID:        h.ID,
+		Date:      h.Date.Format("02/01/2006"),
+		Weekday:   WeekdayName(h.Date.Weekday()),
+		Name:      h.Name,
+		Scope:     h.Scope,
+		Location:  h.Location,
+		Recurring: h.Recurring,
+	}
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+)
+
+// DBImportLock registra quem detém o lock de importação de um tipo de arquivo, compartilhado entre
+// todos os processos que usam o mesmo banco. No SQLite a própria linha é o lock; no PostgreSQL o lock
+// é um advisory lock da sessão e a linha guarda apenas o detentor para exibição.
+type DBImportLock struct {
+	FileType string `gorm:"primaryKey;type:varchar(50)"` // Tipo do arquivo (ex: "DIREITOS"), em maiúsculas.
+
+	// Token identifica a aquisição do lock (gerado a cada aquisição); só o detentor renova ou libera a linha.
+	Token string `gorm:"type:varchar(64);not null"`
+
+	HeldBy     string    `gorm:"type:varchar(50);not null"` // Usuário que iniciou a operação.
+	HostName   string    `gorm:"type:varchar(255)"`         // Máquina do processo detentor.
+	AcquiredAt time.Time `gorm:"not null"`
+	// ExpiresAt é renovado periodicamente pelo detentor. Um lock vencido é considerado abandonado
+	// (ex: processo encerrado à força) e pode ser tomado por outra importação.
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
+
+// TableName especifica o nome da tabela para GORM.
+func (DBImportLock) TableName() string {
+	return "import_locks"
+}
+
+// ImportLockedError indica que outra importação do mesmo tipo de arquivo está em andamento.
+// `errors.Is(err, appErrors.ErrConflict)` é verdadeiro para este erro.
+type ImportLockedError struct {
+	FileType   string
+	HeldBy     string // Vazio se o detentor não for conhecido.
+	HostName   string
+	AcquiredAt time.Time
+}
+
+// Error implementa a interface error.
+func (e *ImportLockedError) Error() string {
+	if e.HeldBy == "" {
+		return fmt.Sprintf("importação de %s em andamento em outra sessão", e.FileType)
+	}
+	msg := fmt.Sprintf("importação de %s em andamento por %s desde %s", e.FileType, e.HeldBy, e.AcquiredAt.Local().Format("02/01/2006 15:04:05"))
+	if e.HostName != "" {
+		msg += fmt.Sprintf(" (máquina %s)", e.HostName)
+	}
+	return msg
+}
+
+// Is permite que `errors.Is(err, appErrors.ErrConflict)` funcione para este erro.
+func (e *ImportLockedError) Is(target error) bool {
+	return target == appErrors.ErrConflict
+}
@@ -29,6 +29,10 @@ type DBImportMetadata struct {
 	// ImportedBy é o nome de usuário de quem realizou a última importação (opcional).
 	ImportedBy *string `gorm:"type:varchar(50)"`
 
+	// Companies lista as empresas (NROEMPRESA) afetadas pela última importação, separadas por vírgula.
+	// Nulo quando a última importação substituiu os títulos de todas as empresas.
+	Companies *string `gorm:"type:text"`
+
 	// CreatedAt pode ser útil para saber quando o metadado foi registrado pela primeira vez.
 	// GORM pode usar `gorm:"autoCreateTime"` ou o banco `default:now()`.
 	// CreatedAt time.Time `gorm:"not null;autoCreateTime"`
@@ -50,6 +54,7 @@ type ImportMetadataPublic struct {
 	OriginalFilename *string   `json:"original_filename,omitempty"`
 	RecordCount      *int      `json:"record_count,omitempty"`
 	ImportedBy       *string   `json:"imported_by,omitempty"`
+	Companies        *string   `json:"companies,omitempty"` // Nulo se a última importação abrangeu todas as empresas.
 }
 
 // ToImportMetadataPublic converte um DBImportMetadata (modelo do banco) para ImportMetadataPublic (DTO).
@@ -64,6 +69,7 @@ func ToImportMetadataPublic(dbMeta *DBImportMetadata) *ImportMetadataPublic {
 		OriginalFilename: dbMeta.OriginalFilename,
 		RecordCount:      dbMeta.RecordCount,
 		ImportedBy:       dbMeta.ImportedBy,
+		Companies:        dbMeta.Companies,
 	}
 }
 
@@ -88,6 +94,7 @@ type ImportMetadataUpsert struct {
 	OriginalFilename *string
 	RecordCount      *int
 	ImportedBy       *string
+	Companies        *string // Empresas afetadas (nil para importação de todas as empresas).
 }
 
 // Normalize garante que o FileType esteja em maiúsculas.
@@ -0,0 +1,59 @@
+package models
+
+// ImportRowIssueColumnRow é usado como coluna de um `ImportRowIssue` quando o problema
+// afeta a linha inteira (ex: número incorreto de campos), e não uma coluna específica.
+const ImportRowIssueColumnRow = "(linha)"
+
+// ImportRowIssue descreve um problema encontrado ao interpretar uma coluna de uma linha do arquivo
+// de importação. Na importação real, esses problemas aparecem apenas nos logs; na pré-visualização
+// (dry-run) eles são coletados e devolvidos ao usuário.
+type ImportRowIssue struct {
+	LineNum         int    `json:"line_num"`         // Número da linha no arquivo (o cabeçalho é a linha 1).
+	Column          string `json:"column"`           // Nome da coluna no cabeçalho, ou `ImportRowIssueColumnRow`.
+	RawValue        string `json:"raw_value"`        // Valor lido do arquivo, antes de qualquer conversão.
+	Message         string `json:"message"`          // Descrição do problema.
+	UsedPlaceholder bool   `json:"used_placeholder"` // True se um valor padrão foi gravado no lugar do valor do arquivo.
+}
+
+// ImportPreviewReport é o relatório de uma pré-visualização (dry-run) de importação:
+// o arquivo é lido e mapeado por completo, exatamente como na importação real,
+// mas nada é gravado no banco de dados.
+type ImportPreviewReport struct {
+	FileType         string `json:"file_type"`
+	OriginalFilename string `json:"original_filename"`
+	EncodingDetected string `json:"encoding_detected"`
+	// DelimiterDetected é o separador de campos usado na leitura (vazio para XLSX).
+	DelimiterDetected string `json:"delimiter_detected,omitempty"`
+	ProfileName       string `json:"profile_name,omitempty"` // Perfil de mapeamento de colunas usado (vazio para o layout padrão).
+
+	TotalDataRows        int `json:"total_data_rows"`        // Linhas de dados no arquivo (sem o cabeçalho).
+	ValidRows            int `json:"valid_rows"`             // Linhas que seriam gravadas (com ou sem placeholders).
+	RowsSkippedParsing   int `json:"rows_skipped_parsing"`   // Linhas que seriam ignoradas (número incorreto de campos).
+	RowsWithPlaceholders int `json:"rows_with_placeholders"` // Linhas em que algum campo obrigatório receberia um valor padrão.
+	RowsWithIssues       int `json:"rows_with_issues"`       // Linhas com ao menos um problema (inclui as ignoradas).
+
+	// IssueCountByColumn conta todos os problemas encontrados por coluna, inclusive os
+	// que não couberam em `Issues`.
+	IssueCountByColumn map[string]int `json:"issue_count_by_column"`
+	// Issues lista os problemas na ordem do arquivo, limitada a um número máximo de entradas.
+	Issues []ImportRowIssue `json:"issues"`
+	// IssuesTruncated indica que havia mais problemas do que os listados em `Issues`.
+	IssuesTruncated bool `json:"issues_truncated"`
+
+	// Amostra das primeiras linhas mapeadas, como seriam gravadas. Apenas uma das listas é
+	// preenchida, conforme o tipo de arquivo.
+	SampleDireitos   []*TituloDireitoPublic   `json:"sample_direitos,omitempty"`
+	SampleObrigacoes []*TituloObrigacaoPublic `json:"sample_obrigacoes,omitempty"`
+}
+
+// TotalIssues retorna o número total de problemas encontrados no arquivo.
+func (r *ImportPreviewReport) TotalIssues() int {
+	if r == nil {
+		return 0
+	}
+	total := 0
+	for _, count := range r.IssueCountByColumn {
+		total += count
+	}
+	return total
+}
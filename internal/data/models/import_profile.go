@@ -0,0 +1,331 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+)
+
+// ImportProfileListSeparator separa os itens de listas (aliases, formatos de data) armazenadas como texto.
+const ImportProfileListSeparator = ";"
+
+// ImportProfileRequiredFields são as colunas que não podem ser marcadas como opcionais em um perfil,
+// pois formam a chave natural do título ou são obrigatórias no banco.
+var ImportProfileRequiredFields = []string{"CNPJ/CPF", "NROEMPRESA", "TÍTULO", "VLRNOMINAL"}
+
+// DBImportProfile representa um perfil de mapeamento de colunas para a importação de um tipo de arquivo.
+// O perfil permite importar layouts de ERP diferentes do padrão: colunas em outra ordem, com outros
+// nomes (aliases), colunas extras (ignoradas), colunas opcionais ausentes, outros formatos de data
+// e outro separador decimal.
+type DBImportProfile struct {
+	ID uint64 `gorm:"primaryKey;autoIncrement"`
+
+	// Name é o nome do perfil, único por tipo de arquivo.
+	Name string `gorm:"type:varchar(100);not null;uniqueIndex:idx_import_profiles_type_name"`
+	// FileType é o tipo de arquivo ao qual o perfil se aplica (ex: "DIREITOS"), em maiúsculas.
+	FileType string `gorm:"type:varchar(50);not null;uniqueIndex:idx_import_profiles_type_name"`
+
+	// DecimalSeparator é o separador decimal dos valores do arquivo ("," ou ".").
+	DecimalSeparator string `gorm:"type:varchar(1);not null;default:','"`
+	// DateFormats lista os formatos de data aceitos (ex: "DD/MM/AAAA;AAAA-MM-DD"), separados por ";".
+	// Vazio usa os formatos padrão da importação.
+	DateFormats string `gorm:"type:text"`
+
+	// IsDefault indica o perfil pré-selecionado na importação do tipo (no máximo um por tipo).
+	IsDefault bool `gorm:"not null;default:false"`
+
+	// Columns contém apenas as colunas com configuração diferente do padrão
+	// (nome exato da coluna esperada, obrigatória).
+	Columns []DBImportProfileColumn `gorm:"foreignKey:ProfileID;constraint:OnDelete:CASCADE"`
+
+	CreatedAt time.Time `gorm:"not null;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"not null;autoUpdateTime"`
+	CreatedBy *string   `gorm:"type:varchar(50)"`
+	UpdatedBy *string   `gorm:"type:varchar(50)"`
+}
+
+// TableName especifica o nome da tabela para GORM.
+func (DBImportProfile) TableName() string {
+	return "import_profiles"
+}
+
+// DBImportProfileColumn configura uma coluna esperada dentro de um perfil de importação.
+type DBImportProfileColumn struct {
+	ID        uint64 `gorm:"primaryKey;autoIncrement"`
+	ProfileID uint64 `gorm:"not null;index"`
+
+	// Field é o nome da coluna esperada (ex: "DTAVENCIMENTO"), como em `ExpectedHeadersTituloDireito`.
+	Field string `gorm:"type:varchar(50);not null"`
+	// Aliases lista outros nomes aceitos para a coluna no cabeçalho do arquivo, separados por ";".
+	Aliases string `gorm:"type:text"`
+	// Optional indica que a coluna pode não existir no arquivo (o valor fica vazio).
+	Optional bool `gorm:"not null;default:false"`
+}
+
+// TableName especifica o nome da tabela para GORM.
+func (DBImportProfileColumn) TableName() string {
+	return "import_profile_columns"
+}
+
+// --- Structs para Transferência de Dados e Validação ---
+
+// ImportProfileColumnPublic representa a configuração de uma coluna de um perfil de importação.
+type ImportProfileColumnPublic struct {
+	Field    string   `json:"field"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Optional bool     `json:"optional"`
+}
+
+// ImportProfilePublic representa um perfil de importação para a UI ou API (DTO).
+type ImportProfilePublic struct {
+	ID               uint64                      `json:"id"`
+	Name             string                      `json:"name"`
+	FileType         string                      `json:"file_type"`
+	DecimalSeparator string                      `json:"decimal_separator"`
+	DateFormats      []string                    `json:"date_formats,omitempty"`
+	IsDefault        bool                        `json:"is_default"`
+	Columns          []ImportProfileColumnPublic `json:"columns,omitempty"`
+	UpdatedAt        time.Time                   `json:"updated_at"`
+	UpdatedBy        *string                     `json:"updated_by,omitempty"`
+}
+
+// Column retorna a configuração da coluna informada, ou nil se o perfil usa o padrão para ela.
+func (p *ImportProfilePublic) Column(field string) *ImportProfileColumnPublic {
+	if p == nil {
+		return nil
+	}
+	for i := range p.Columns {
+		if strings.EqualFold(p.Columns[i].Field, field) {
+			return &p.Columns[i]
+		}
+	}
+	return nil
+}
+
+// ToImportProfilePublic converte um DBImportProfile para ImportProfilePublic.
+func ToImportProfilePublic(dbProfile *DBImportProfile) *ImportProfilePublic {
+	if dbProfile == nil {
+		return nil
+	}
+	columns := make([]ImportProfileColumnPublic, len(dbProfile.Columns))
+	for i, col := range dbProfile.Columns {
+		columns[i] = ImportProfileColumnPublic{
+			Field:    col.Field,
+			Aliases:  SplitImportProfileList(col.Aliases),
+			Optional: col.Optional,
+		}
+	}
+	return &ImportProfilePublic{
+		ID:               dbProfile.ID,
+		Name:             dbProfile.Name,
+		FileType:         dbProfile.FileType,
+		DecimalSeparator: dbProfile.DecimalSeparator,
+		DateFormats:      SplitImportProfileList(dbProfile.DateFormats),
+		IsDefault:        dbProfile.IsDefault,
+		Columns:          columns,
+		UpdatedAt:        dbProfile.UpdatedAt,
+		UpdatedBy:        dbProfile.UpdatedBy,
+	}
+}
+
+// ToImportProfilePublicList converte uma lista de DBImportProfile para uma lista de ImportProfilePublic.
+func ToImportProfilePublicList(dbProfiles []DBImportProfile) []*ImportProfilePublic {
+	publicList := make([]*ImportProfilePublic, len(dbProfiles))
+	for i := range dbProfiles {
+		publicList[i] = ToImportProfilePublic(&dbProfiles[i])
+	}
+	return publicList
+}
+
+// SplitImportProfileList separa uma lista armazenada como texto (itens separados por ";"),
+// removendo espaços e itens vazios.
+func SplitImportProfileList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ImportProfileListSeparator) {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
+
+// ImportProfileUpsert contém os dados para criar ou atualizar um perfil de importação.
+type ImportProfileUpsert struct {
+	Name             string                      `json:"name"`
+	FileType         string                      `json:"file_type"`
+	DecimalSeparator string                      `json:"decimal_separator"`
+	DateFormats      []string                    `json:"date_formats"`
+	IsDefault        bool                        `json:"is_default"`
+	Columns          []ImportProfileColumnPublic `json:"columns"`
+}
+
+var importProfileNameRegex = regexp.MustCompile(`^[\p{L}\d\s_.()-]{3,100}$`)
+
+// CleanAndValidate normaliza e valida os dados do perfil. `expectedHeaders` são as colunas esperadas
+// para o tipo de arquivo do perfil. Colunas sem aliases e obrigatórias são descartadas, pois
+// equivalem ao padrão. Este método deve ser chamado pelo serviço.
+func (pu *ImportProfileUpsert) CleanAndValidate(expectedHeaders []string) error {
+	pu.Name = strings.TrimSpace(pu.Name)
+	if !importProfileNameRegex.MatchString(pu.Name) {
+		return appErrors.NewValidationError(
+			"Nome do perfil deve ter entre 3 e 100 caracteres e conter apenas letras, números, espaços, '_', '.', '(', ')' ou '-'.",
+			map[string]string{"name": "formato inválido"},
+		)
+	}
+	pu.FileType = strings.ToUpper(strings.TrimSpace(pu.FileType))
+	if pu.FileType == "" {
+		return appErrors.NewValidationError("Tipo de arquivo do perfil é obrigatório.", map[string]string{"file_type": "obrigatório"})
+	}
+
+	pu.DecimalSeparator = strings.TrimSpace(pu.DecimalSeparator)
+	if pu.DecimalSeparator == "" {
+		pu.DecimalSeparator = ","
+	}
+	if pu.DecimalSeparator != "," && pu.DecimalSeparator != "." {
+		return appErrors.NewValidationError("Separador decimal deve ser ',' ou '.'.", map[string]string{"decimal_separator": "inválido"})
+	}
+
+	dateFormats := make([]string, 0, len(pu.DateFormats))
+	for _, format := range pu.DateFormats {
+		format = strings.TrimSpace(format)
+		if format == "" {
+			continue
+		}
+		if _, err := ImportDateFormatToLayout(format); err != nil {
+			return appErrors.NewValidationError(err.Error(), map[string]string{"date_formats": "formato inválido"})
+		}
+		dateFormats = append(dateFormats, format)
+	}
+	pu.DateFormats = dateFormats
+
+	// Todos os nomes aceitos (coluna esperada ou alias) precisam identificar uma única coluna.
+	knownFields := make(map[string]string, len(expectedHeaders))
+	nameOwner := make(map[string]string, len(expectedHeaders))
+	for _, header := range expectedHeaders {
+		knownFields[strings.ToUpper(header)] = header
+		nameOwner[strings.ToUpper(header)] = header
+	}
+
+	columns := make([]ImportProfileColumnPublic, 0, len(pu.Columns))
+	seenFields := make(map[string]bool, len(pu.Columns))
+	for _, col := range pu.Columns {
+		field, ok := knownFields[strings.ToUpper(strings.TrimSpace(col.Field))]
+		if !ok {
+			return appErrors.NewValidationError(
+				fmt.Sprintf("Coluna '%s' não existe no layout de %s.", col.Field, pu.FileType),
+				map[string]string{"columns": "coluna desconhecida"},
+			)
+		}
+		if seenFields[field] {
+			return appErrors.NewValidationError(
+				fmt.Sprintf("Coluna '%s' configurada mais de uma vez.", field),
+				map[string]string{"columns": "coluna duplicada"},
+			)
+		}
+		seenFields[field] = true
+
+		if col.Optional && isImportProfileRequiredField(field) {
+			return appErrors.NewValidationError(
+				fmt.Sprintf("Coluna '%s' é obrigatória e não pode ser marcada como opcional.", field),
+				map[string]string{"columns": "coluna obrigatória"},
+			)
+		}
+
+		aliases := make([]string, 0, len(col.Aliases))
+		for _, alias := range col.Aliases {
+			alias = strings.TrimSpace(alias)
+			if alias == "" {
+				continue
+			}
+			if strings.Contains(alias, ImportProfileListSeparator) {
+				return appErrors.NewValidationError(
+					fmt.Sprintf("Alias '%s' da coluna '%s' não pode conter '%s'.", alias, field, ImportProfileListSeparator),
+					map[string]string{"columns": "alias inválido"},
+				)
+			}
+			key := strings.ToUpper(alias)
+			if owner, exists := nameOwner[key]; exists && owner != field {
+				return appErrors.NewValidationError(
+					fmt.Sprintf("Alias '%s' da coluna '%s' já identifica a coluna '%s'.", alias, field, owner),
+					map[string]string{"columns": "alias duplicado"},
+				)
+			}
+			nameOwner[key] = field
+			aliases = append(aliases, alias)
+		}
+
+		if len(aliases) == 0 && !col.Optional {
+			continue // Equivale ao padrão.
+		}
+		columns = append(columns, ImportProfileColumnPublic{Field: field, Aliases: aliases, Optional: col.Optional})
+	}
+	pu.Columns = columns
+	return nil
+}
+
+// isImportProfileRequiredField indica se a coluna não pode ser opcional em um perfil.
+func isImportProfileRequiredField(field string) bool {
+	for _, required := range ImportProfileRequiredFields {
+		if strings.EqualFold(required, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportDateFormatToLayout converte um formato de data de perfil (ex: "DD/MM/AAAA", "AAAA-MM-DD hh:mm:ss")
+// para o layout equivalente do pacote `time`. Elementos aceitos: DD/D (dia), MM/M (mês), AAAA/YYYY e AA/YY
+// (ano), hh/HH (hora), mm (minuto) e ss (segundo). Os demais caracteres (exceto letras) são literais.
+func ImportDateFormatToLayout(format string) (string, error) {
+	tokens := map[string]string{
+		"DD": "02", "D": "2",
+		"MM": "01", "M": "1",
+		"AAAA": "2006", "YYYY": "2006", "AA": "06", "YY": "06",
+		"hh": "15", "HH": "15",
+		"mm": "04",
+		"ss": "05",
+	}
+	runes := []rune(strings.TrimSpace(format))
+	if len(runes) == 0 {
+		return "", fmt.Errorf("formato de data vazio")
+	}
+	var layout strings.Builder
+	hasDay, hasMonth, hasYear := false, false, false
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z') {
+			if '0' <= r && r <= '9' {
+				return "", fmt.Errorf("formato de data '%s' não pode conter dígitos", format)
+			}
+			layout.WriteRune(r)
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && runes[j] == r {
+			j++
+		}
+		token := string(runes[i:j])
+		goLayout, ok := tokens[token]
+		if !ok {
+			return "", fmt.Errorf("elemento '%s' não reconhecido no formato de data '%s' (use DD, MM, AAAA, hh, mm, ss)", token, format)
+		}
+		switch r {
+		case 'D':
+			hasDay = true
+		case 'M':
+			hasMonth = true
+		case 'A', 'Y':
+			hasYear = true
+		}
+		layout.WriteString(goLayout)
+		i = j
+	}
+	if !hasDay || !hasMonth || !hasYear {
+		return "", fmt.Errorf("formato de data '%s' deve conter dia, mês e ano", format)
+	}
+	return layout.String(), nil
+}
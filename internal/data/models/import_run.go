@@ -0,0 +1,231 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+)
+
+// Status possíveis de uma execução de importação.
+const (
+	ImportRunStatusRunning      = "RUNNING"       // Importação em andamento.
+	ImportRunStatusSuccess      = "SUCCESS"       // Importação concluída com dados persistidos.
+	ImportRunStatusSuccessEmpty = "SUCCESS_EMPTY" // Arquivo vazio ou apenas com cabeçalho; nada foi alterado.
+	ImportRunStatusFailed       = "FAILED"        // Importação falhou; a transação foi desfeita.
+	ImportRunStatusCancelled    = "CANCELLED"     // Importação cancelada pelo usuário; a transação foi desfeita.
+)
+
+// DBImportRun representa uma execução de importação de arquivo (uma linha por execução).
+// Diferente de `DBImportMetadata`, que guarda apenas a última importação por tipo de arquivo,
+// esta tabela mantém o histórico completo de quem importou o quê, quando e com qual resultado.
+type DBImportRun struct {
+	ID uint64 `gorm:"primaryKey;autoIncrement"`
+
+	// FileType identifica o tipo do arquivo importado (ex: "DIREITOS", "OBRIGACOES"), em maiúsculas.
+	FileType string `gorm:"type:varchar(50);not null;index"`
+	// ImportMode é o modo de importação usado (ex: "REPLACE", "INCREMENTAL").
+	ImportMode string `gorm:"type:varchar(20);not null"`
+	// ImportScope indica se a importação afetou todos os títulos do tipo ("ALL") ou apenas os das
+	// empresas presentes no arquivo ("COMPANIES").
+	ImportScope string `gorm:"type:varchar(20);not null;default:'ALL'"`
+	// Companies lista as empresas (NROEMPRESA) presentes no arquivo, separadas por vírgula
+	// (preenchido apenas nas importações de títulos concluídas).
+	Companies *string `gorm:"type:text"`
+
+	// Identificação do arquivo importado.
+	OriginalFilename string  `gorm:"type:varchar(255);not null"`
+	FileSHA256       *string `gorm:"type:varchar(64);index"` // Hash SHA-256 (hex) dos bytes importados, se o arquivo foi lido até o fim.
+	FileSizeBytes    *int64  // Tamanho do arquivo em bytes.
+	EncodingDetected *string `gorm:"type:varchar(50)"` // Encoding detectado na leitura (ex: "UTF-8", "Latin-1 ...").
+	// DelimiterDetected é o separador de campos usado na leitura de arquivos de texto (nulo para XLSX).
+	DelimiterDetected *string `gorm:"type:varchar(50)"`
+
+	ImportedBy string     `gorm:"type:varchar(50);not null;index"` // Usuário que executou a importação.
+	StartedAt  time.Time  `gorm:"not null;index"`
+	FinishedAt *time.Time // Nulo enquanto a importação está em andamento.
+	Status     string     `gorm:"type:varchar(20);not null;index"` // Ver constantes ImportRunStatus*.
+
+	// Contadores (os mesmos retornados no resultado de `ImportService.ImportFile`).
+	TotalDataRows         int `gorm:"not null;default:0"`
+	RecordsProcessed      int `gorm:"not null;default:0"`
+	RecordsInserted       int `gorm:"not null;default:0"`
+	RecordsUpdated        int `gorm:"not null;default:0"`
+	RecordsUnchanged      int `gorm:"not null;default:0"`
+	RecordsRemoved        int `gorm:"not null;default:0"`
+	RecordsSkippedParsing int `gorm:"not null;default:0"`
+	RecordsSkippedRepo    int `gorm:"not null;default:0"`
+
+	// NetworkTotalsRecorded indica que os saldos em aberto por rede após a importação foram gravados
+	// em `DBImportRunNetworkTotal` (apenas importações de títulos concluídas).
+	NetworkTotalsRecorded bool `gorm:"not null;default:false"`
+
+	// ErrorMessage contém o erro da importação, se houver.
+	ErrorMessage *string `gorm:"type:text"`
+}
+
+// TableName especifica o nome da tabela para GORM.
+func (DBImportRun) TableName() string {
+	return "import_runs"
+}
+
+// DuplicateImportError indica que o conteúdo lido do arquivo (SHA-256) é igual ao de uma importação
+// anterior do mesmo tipo concluída com sucesso; a importação foi desfeita.
+// `errors.Is(err, appErrors.ErrConflict)` é verdadeiro para este erro.
+type DuplicateImportError struct {
+	FileType           string
+	FileSHA256         string
+	PreviousRunID      uint64
+	PreviousImportedBy string
+	PreviousStartedAt  time.Time
+}
+
+// Error implementa a interface error.
+func (e *DuplicateImportError) Error() string {
+	return fmt.Sprintf("conteúdo do arquivo de %s já importado (execução #%d de %s por %s)",
+		e.FileType, e.PreviousRunID, e.PreviousStartedAt.Local().Format("02/01/2006 15:04:05"), e.PreviousImportedBy)
+}
+
+// Is permite que `errors.Is(err, appErrors.ErrConflict)` funcione para este erro.
+func (e *DuplicateImportError) Is(target error) bool {
+	return target == appErrors.ErrConflict
+}
+
+// DBImportRunNetworkTotal guarda o saldo em aberto dos títulos de uma rede logo após uma importação
+// concluída (uma linha por execução e rede com saldo). Permite comparar os totais entre importações
+// sem depender dos snapshots dos títulos.
+type DBImportRunNetworkTotal struct {
+	ID          uint64 `gorm:"primaryKey;autoIncrement"`
+	ImportRunID uint64 `gorm:"not null;uniqueIndex:idx_import_run_network_total"`
+	NetworkID   uint64 `gorm:"not null;uniqueIndex:idx_import_run_network_total"`
+	OpenTotal   string `gorm:"type:varchar(30);not null"` // Soma dos saldos em aberto (ex: "1234.56").
+}
+
+// TableName especifica o nome da tabela para GORM.
+func (DBImportRunNetworkTotal) TableName() string {
+	return "import_run_network_totals"
+}
+
+// --- Struct para Transferência de Dados (DTO) ---
+
+// ImportRunPublic representa uma execução de importação para a UI ou API.
+type ImportRunPublic struct {
+	ID                    uint64     `json:"id"`
+	FileType              string     `json:"file_type"`
+	ImportMode            string     `json:"import_mode"`
+	ImportScope           string     `json:"import_scope"`
+	Companies             *string    `json:"companies,omitempty"`
+	OriginalFilename      string     `json:"original_filename"`
+	FileSHA256            *string    `json:"file_sha256,omitempty"`
+	FileSizeBytes         *int64     `json:"file_size_bytes,omitempty"`
+	EncodingDetected      *string    `json:"encoding_detected,omitempty"`
+	DelimiterDetected     *string    `json:"delimiter_detected,omitempty"`
+	ImportedBy            string     `json:"imported_by"`
+	StartedAt             time.Time  `json:"started_at"`
+	FinishedAt            *time.Time `json:"finished_at,omitempty"`
+	Status                string     `json:"status"`
+	TotalDataRows         int        `json:"total_data_rows"`
+	RecordsProcessed      int        `json:"records_processed"`
+	RecordsInserted       int        `json:"records_inserted"`
+	RecordsUpdated        int        `json:"records_updated"`
+	RecordsUnchanged      int        `json:"records_unchanged"`
+	RecordsRemoved        int        `json:"records_removed"`
+	RecordsSkippedParsing int        `json:"records_skipped_parsing"`
+	RecordsSkippedRepo    int        `json:"records_skipped_repo"`
+	ErrorMessage          *string    `json:"error_message,omitempty"`
+}
+
+// Duration retorna a duração da execução, ou zero se ainda estiver em andamento.
+func (ir *ImportRunPublic) Duration() time.Duration {
+	if ir == nil || ir.FinishedAt == nil {
+		return 0
+	}
+	return ir.FinishedAt.Sub(ir.StartedAt)
+}
+
+// ToImportRunPublic converte um DBImportRun para ImportRunPublic.
+func ToImportRunPublic(dbRun *DBImportRun) *ImportRunPublic {
+	if dbRun == nil {
+		return nil
+	}
+	return &ImportRunPublic{
+		ID:                    dbRun.ID,
+		FileType:              dbRun.FileType,
+		ImportMode:            dbRun.ImportMode,
+		ImportScope:           dbRun.ImportScope,
+		Companies:             dbRun.Companies,
+		OriginalFilename:      dbRun.OriginalFilename,
+		FileSHA256:            dbRun.FileSHA256,
+		FileSizeBytes:         dbRun.FileSizeBytes,
+		EncodingDetected:      dbRun.EncodingDetected,
+		DelimiterDetected:     dbRun.DelimiterDetected,
+		ImportedBy:            dbRun.ImportedBy,
+		StartedAt:             dbRun.StartedAt,
+		FinishedAt:            dbRun.FinishedAt,
+		Status:                dbRun.Status,
+		TotalDataRows:         dbRun.TotalDataRows,
+		RecordsProcessed:      dbRun.RecordsProcessed,
+		RecordsInserted:       dbRun.RecordsInserted,
+		RecordsUpdated:        dbRun.RecordsUpdated,
+		RecordsUnchanged:      dbRun.RecordsUnchanged,
+		RecordsRemoved:        dbRun.RecordsRemoved,
+		RecordsSkippedParsing: dbRun.RecordsSkippedParsing,
+		RecordsSkippedRepo:    dbRun.RecordsSkippedRepo,
+		ErrorMessage:          dbRun.ErrorMessage,
+	}
+}
+
+// ToImportRunPublicList converte uma lista de DBImportRun para uma lista de ImportRunPublic.
+func ToImportRunPublicList(dbRuns []DBImportRun) []*ImportRunPublic {
+	publicList := make([]*ImportRunPublic, len(dbRuns))
+	for i := range dbRuns {
+		publicList[i] = ToImportRunPublic(&dbRuns[i])
+	}
+	return publicList
+}
+
+// --- Filtro de consulta ---
+
+// ImportRunFilter define os filtros para consulta do histórico de importações.
+// Campos nil/vazios são ignorados. Limit/Offset controlam a paginação.
+type ImportRunFilter struct {
+	FileType   *string
+	Status     *string
+	ImportedBy *string
+	Filename   *string    // Busca parcial (case-insensitive) pelo nome do arquivo.
+	FileSHA256 *string    // Busca exata pelo hash do arquivo.
+	StartDate  *time.Time // Execuções iniciadas a partir deste dia (inclusive).
+	EndDate    *time.Time // Execuções iniciadas até este dia (inclusive).
+	Limit      int
+	Offset     int
+}
+
+// Normalize remove espaços e padroniza tipo de arquivo e status em maiúsculas.
+func (f *ImportRunFilter) Normalize() {
+	if f == nil {
+		return
+	}
+	normalize := func(val *string, upper bool) *string {
+		if val == nil {
+			return nil
+		}
+		trimmed := strings.TrimSpace(*val)
+		if trimmed == "" {
+			return nil
+		}
+		if upper {
+			trimmed = strings.ToUpper(trimmed)
+		}
+		return &trimmed
+	}
+	f.FileType = normalize(f.FileType, true)
+	f.Status = normalize(f.Status, true)
+	f.ImportedBy = normalize(f.ImportedBy, false)
+	f.Filename = normalize(f.Filename, false)
+	f.FileSHA256 = normalize(f.FileSHA256, false)
+	if f.FileSHA256 != nil {
+		lowerHash := strings.ToLower(*f.FileSHA256)
+		f.FileSHA256 = &lowerHash
+	}
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+)
+
+// DBImportSnapshot representa uma versão guardada dos títulos de um tipo de arquivo, gravada na
+// mesma transação da importação que produziu os dados. Como `ReplaceAll` apaga os dados anteriores,
+// os snapshots permitem voltar a um conjunto de dados anterior sem precisar do arquivo original.
+// Os títulos de cada versão ficam em uma tabela própria (`DataTable`), com as mesmas colunas da
+// tabela de títulos. Apenas os últimos N snapshots de cada tipo são mantidos (ver
+// `Config.ImportSnapshotRetention`); as tabelas dos snapshots descartados são excluídas.
+type DBImportSnapshot struct {
+	ID uint64 `gorm:"primaryKey;autoIncrement"`
+
+	// FileType identifica o tipo de arquivo (ex: "DIREITOS", "OBRIGACOES"), em maiúsculas.
+	FileType string `gorm:"type:varchar(50);not null;index"`
+	// ImportRunID é a execução de importação que gerou os dados (nulo se o histórico não foi gravado).
+	ImportRunID *uint64 `gorm:"index"`
+	// OriginalFilename é o nome do arquivo importado.
+	OriginalFilename string `gorm:"type:varchar(255);not null"`
+	// ImportMode é o modo da importação que gerou os dados (ex: "REPLACE", "INCREMENTAL").
+	ImportMode string `gorm:"type:varchar(20);not null"`
+	// RecordCount é o número de títulos guardados (inclusive os marcados como removidos).
+	RecordCount int `gorm:"not null;default:0"`
+	// DataTable é a tabela com a cópia dos títulos desta versão (ex: "import_snapshot_12_titulos_direitos").
+	DataTable string `gorm:"type:varchar(100);not null;default:''"`
+
+	CreatedAt time.Time `gorm:"not null;autoCreateTime;index"`
+	CreatedBy string    `gorm:"type:varchar(50);not null"`
+
+	// RestoredAt e RestoredBy registram a última restauração deste snapshot.
+	RestoredAt *time.Time
+	RestoredBy *string `gorm:"type:varchar(50)"`
+}
+
+// TableName especifica o nome da tabela para GORM.
+func (DBImportSnapshot) TableName() string {
+	return "import_snapshots"
+}
+
+// --- Struct para Transferência de Dados (DTO) ---
+
+// ImportSnapshotPublic representa um snapshot de importação para a UI ou API.
+type ImportSnapshotPublic struct {
+	ID               uint64     `json:"id"`
+	FileType         string     `json:"file_type"`
+	ImportRunID      *uint64    `json:"import_run_id,omitempty"`
+	OriginalFilename string     `json:"original_filename"`
+	ImportMode       string     `json:"import_mode"`
+	RecordCount      int        `json:"record_count"`
+	CreatedAt        time.Time  `json:"created_at"`
+	CreatedBy        string     `json:"created_by"`
+	RestoredAt       *time.Time `json:"restored_at,omitempty"`
+	RestoredBy       *string    `json:"restored_by,omitempty"`
+}
+
+// ToImportSnapshotPublic converte um DBImportSnapshot para ImportSnapshotPublic.
+func ToImportSnapshotPublic(dbSnapshot *DBImportSnapshot) *ImportSnapshotPublic {
+	if dbSnapshot == nil {
+		return nil
+	}
+	return &ImportSnapshotPublic{
+		ID:               dbSnapshot.ID,
+		FileType:         dbSnapshot.FileType,
+		ImportRunID:      dbSnapshot.ImportRunID,
+		OriginalFilename: dbSnapshot.OriginalFilename,
+		ImportMode:       dbSnapshot.ImportMode,
+		RecordCount:      dbSnapshot.RecordCount,
+		CreatedAt:        dbSnapshot.CreatedAt,
+		CreatedBy:        dbSnapshot.CreatedBy,
+		RestoredAt:       dbSnapshot.RestoredAt,
+		RestoredBy:       dbSnapshot.RestoredBy,
+	}
+}
+
+// ToImportSnapshotPublicList converte uma lista de DBImportSnapshot para uma lista de ImportSnapshotPublic.
+func ToImportSnapshotPublicList(dbSnapshots []DBImportSnapshot) []*ImportSnapshotPublic {
+	publicList := make([]*ImportSnapshotPublic, len(dbSnapshots))
+	for i := range dbSnapshots {
+		publicList[i] = ToImportSnapshotPublic(&dbSnapshots[i])
+	}
+	return publicList
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+)
+
+// NettingMatchMode define como direitos e obrigações são associados ao mesmo parceiro.
+type NettingMatchMode string
+
+const (
+	NettingMatchCNPJ NettingMatchMode = "CNPJ" // Mesmo CNPJ/CPF.
+	NettingMatchRoot NettingMatchMode = "ROOT" // Mesma raiz de CNPJ (8 primeiros dígitos); CPFs usam o número completo.
+)
+
+// Validate verifica se o modo de associação é suportado.
+func (m NettingMatchMode) Validate() error {
+	switch m {
+	case NettingMatchCNPJ, NettingMatchRoot:
+		return nil
+	}
+	return fmt.Errorf("%w: modo de encontro de contas '%s' não suportado", appErrors.ErrInvalidInput, m)
+}
+
+// PartnerKey retorna a chave do parceiro de um CNPJ/CPF (apenas dígitos) no modo informado.
+func (m NettingMatchMode) PartnerKey(cnpjcpf string) string {
+	if m == NettingMatchRoot && len(cnpjcpf) == 14 {
+		return cnpjcpf[:8]
+	}
+	return cnpjcpf
+}
+
+// NettingTitle é um título em aberto considerado no encontro de contas.
+type NettingTitle struct {
+	ID             uint64          `json:"id"`
+	CNPJCPF        string          `json:"cnpj_cpf"`
+	Titulo         string          `json:"titulo"`
+	NumeroEmpresa  int             `json:"numero_empresa"`
+	DataVencimento *time.Time      `json:"data_vencimento,omitempty"`
+	OpenBalance    decimal.Decimal `json:"open_balance"`
+}
+
+// NettingPair é uma compensação proposta entre um direito e uma obrigação do mesmo parceiro.
+// Um título pode aparecer em vários pares quando seu saldo é compensado em partes.
+type NettingPair struct {
+	Direito   NettingTitle    `json:"direito"`
+	Obrigacao NettingTitle    `json:"obrigacao"`
+	Amount    decimal.Decimal `json:"amount"`
+}
+
+// NettingPartner é a posição de um parceiro que é, ao mesmo tempo, cliente e fornecedor.
+type NettingPartner struct {
+	Key         string   `json:"key"`   // CNPJ/CPF ou raiz do CNPJ, conforme o modo.
+	Name        string   `json:"name"`  // PESSOA do primeiro título encontrado.
+	CNPJs       []string `json:"cnpjs"` // CNPJs/CPFs do parceiro presentes nos títulos.
+	NetworkID   *uint64  `json:"network_id,omitempty"`
+	NetworkName string   `json:"network_name"`
+
+	Receivable  decimal.Decimal `json:"receivable"`  // Saldo em aberto dos direitos (a receber).
+	Payable     decimal.Decimal `json:"payable"`     // Saldo em aberto das obrigações (a pagar).
+	Net         decimal.Decimal `json:"net"`         // Receivable - Payable (positivo: o parceiro nos deve).
+	Offsettable decimal.Decimal `json:"offsettable"` // Valor compensável (menor entre os dois saldos).
+
+	Direitos   []NettingTitle `json:"direitos"`   // Ordenados por vencimento.
+	Obrigacoes []NettingTitle `json:"obrigacoes"` // Ordenadas por vencimento.
+	Pairs      []NettingPair  `json:"pairs"`
+}
+
+// NettingNetworkSummary consolida as posições dos parceiros de uma rede.
+type NettingNetworkSummary struct {
+	NetworkID   *uint64         `json:"network_id,omitempty"` // Nil para parceiros sem rede.
+	Name        string          `json:"name"`
+	Partners    int             `json:"partners"`
+	Receivable  decimal.Decimal `json:"receivable"`
+	Payable     decimal.Decimal `json:"payable"`
+	Net         decimal.Decimal `json:"net"`
+	Offsettable decimal.Decimal `json:"offsettable"`
+}
+
+// NettingReport é o resultado do encontro de contas entre direitos e obrigações.
+type NettingReport struct {
+	MatchMode   NettingMatchMode        `json:"match_mode"`
+	GeneratedAt time.Time               `json:"generated_at"`
+	Partners    []NettingPartner        `json:"partners"` // Ordenados pelo valor compensável, decrescente.
+	Networks    []NettingNetworkSummary `json:"networks"`
+	Totals      NettingNetworkSummary   `json:"totals"`
+
+	// InvalidTitles conta os títulos ignorados por valores que não puderam ser convertidos.
+	InvalidTitles int `json:"invalid_titles"`
+}
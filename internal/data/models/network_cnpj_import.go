@@ -0,0 +1,62 @@
+package models
+
+// ExpectedHeadersNetworkCNPJ define os cabeçalhos esperados para o arquivo de cadastro em lote de
+// redes e CNPJs (uma linha por CNPJ, com o nome e o comprador da rede).
+var ExpectedHeadersNetworkCNPJ = []string{"REDE", "COMPRADOR", "CNPJ"}
+
+// Situações de uma linha na importação em lote de redes e CNPJs.
+const (
+	NetworkCNPJRowCreated   = "CRIADO"     // CNPJ cadastrado.
+	NetworkCNPJRowUpdated   = "ATUALIZADO" // CNPJ já cadastrado; movido para a rede da linha e/ou reativado.
+	NetworkCNPJRowUnchanged = "INALTERADO" // CNPJ já cadastrado, ativo e na rede da linha.
+	NetworkCNPJRowRejected  = "REJEITADO"  // Linha inválida ou que não pôde ser gravada; nada foi alterado.
+	NetworkCNPJRowDuplicate = "REPETIDO"   // CNPJ repetido no arquivo para a mesma rede; linha ignorada.
+)
+
+// NetworkCNPJImportRow é o resultado do processamento de uma linha do arquivo de redes e CNPJs.
+type NetworkCNPJImportRow struct {
+	LineNumber     int    `json:"line_number"`
+	NetworkName    string `json:"network_name"` // Nome como lido do arquivo.
+	Buyer          string `json:"buyer"`
+	CNPJ           string `json:"cnpj"`
+	Status         string `json:"status"`          // Ver constantes NetworkCNPJRow*.
+	NetworkCreated bool   `json:"network_created"` // True se a rede foi criada por esta linha.
+	Message        string `json:"message"`         // Motivo da rejeição ou detalhe da alteração.
+}
+
+// NetworkCNPJImportReport é o relatório da importação em lote de redes e CNPJs, com o resultado de cada linha.
+type NetworkCNPJImportReport struct {
+	OriginalFilename string `json:"original_filename"`
+	TotalRows        int    `json:"total_rows"`
+	NetworksCreated  int    `json:"networks_created"`
+	CNPJsCreated     int    `json:"cnpjs_created"`
+	CNPJsUpdated     int    `json:"cnpjs_updated"`
+	CNPJsUnchanged   int    `json:"cnpjs_unchanged"`
+	RejectedRows     int    `json:"rejected_rows"`
+	DuplicateRows    int    `json:"duplicate_rows"`
+
+	Rows []NetworkCNPJImportRow `json:"rows"`
+
+	// ReportFile é o CSV com o resultado de cada linha gravado em `ExportDir` (vazio se não pôde ser gravado).
+	ReportFile string `json:"report_file,omitempty"`
+}
+
+// AddRow registra o resultado de uma linha e atualiza os contadores.
+func (r *NetworkCNPJImportReport) AddRow(row NetworkCNPJImportRow) {
+	switch row.Status {
+	case NetworkCNPJRowCreated:
+		r.CNPJsCreated++
+	case NetworkCNPJRowUpdated:
+		r.CNPJsUpdated++
+	case NetworkCNPJRowUnchanged:
+		r.CNPJsUnchanged++
+	case NetworkCNPJRowRejected:
+		r.RejectedRows++
+	case NetworkCNPJRowDuplicate:
+		r.DuplicateRows++
+	}
+	if row.NetworkCreated {
+		r.NetworksCreated++
+	}
+	r.Rows = append(r.Rows, row)
+}
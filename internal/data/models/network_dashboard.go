@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// NetworkDashboardTopTitles é o número de maiores títulos em aberto listados por rede.
+const NetworkDashboardTopTitles = 5
+
+// ExposureTitle é um título em aberto listado entre os maiores de uma rede.
+type ExposureTitle struct {
+	FileType       string          `json:"file_type"` // "DIREITOS" ou "OBRIGACOES".
+	ID             uint64          `json:"id"`
+	CNPJCPF        string          `json:"cnpj_cpf"`
+	Pessoa         string          `json:"pessoa"`
+	Titulo         string          `json:"titulo"`
+	DataVencimento *time.Time      `json:"data_vencimento,omitempty"`
+	OpenBalance    decimal.Decimal `json:"open_balance"`
+	Overdue        bool            `json:"overdue"`
+}
+
+// CNPJExposure é a posição em aberto de um CNPJ dentro de uma rede.
+type CNPJExposure struct {
+	CNPJCPF    string          `json:"cnpj_cpf"`
+	Pessoa     string          `json:"pessoa"`
+	Registered bool            `json:"registered"` // Cadastrado na rede (DBCNPJ), mesmo sem títulos em aberto.
+	Active     bool            `json:"active"`     // Situação do cadastro (falso se não cadastrado).
+	Receivable decimal.Decimal `json:"receivable"`
+	Payable    decimal.Decimal `json:"payable"`
+	Overdue    decimal.Decimal `json:"overdue"` // Direitos e obrigações vencidos.
+}
+
+// NetworkExposure consolida os saldos em aberto dos títulos vinculados a uma rede.
+type NetworkExposure struct {
+	NetworkID uint64 `json:"network_id"`
+	Name      string `json:"name"`
+	Buyer     string `json:"buyer"`
+	Active    bool   `json:"active"`
+
+	Receivable        decimal.Decimal `json:"receivable"`         // Direitos em aberto.
+	Payable           decimal.Decimal `json:"payable"`            // Obrigações em aberto.
+	OverdueReceivable decimal.Decimal `json:"overdue_receivable"` // Direitos vencidos.
+	OverduePayable    decimal.Decimal `json:"overdue_payable"`    // Obrigações vencidas.
+	ReceivableCount   int             `json:"receivable_count"`
+	PayableCount      int             `json:"payable_count"`
+
+	// PrevReceivable e PrevPayable são os saldos na importação anterior de cada tipo
+	// (nil quando não há importação anterior guardada).
+	PrevReceivable *decimal.Decimal `json:"prev_receivable,omitempty"`
+	PrevPayable    *decimal.Decimal `json:"prev_payable,omitempty"`
+
+	LargestTitles []ExposureTitle `json:"largest_titles"` // Até NetworkDashboardTopTitles, do maior para o menor.
+	CNPJs         []CNPJExposure  `json:"cnpjs"`          // Ordenados pelo total em aberto, decrescente.
+}
+
+// ReceivableTrend retorna a variação dos direitos em aberto desde a importação anterior.
+func (n *NetworkExposure) ReceivableTrend() *decimal.Decimal {
+	if n.PrevReceivable == nil {
+		return nil
+	}
+	diff := n.Receivable.Sub(*n.PrevReceivable)
+	return &diff
+}
+
+// PayableTrend retorna a variação das obrigações em aberto desde a importação anterior.
+func (n *NetworkExposure) PayableTrend() *decimal.Decimal {
+	if n.PrevPayable == nil {
+		return nil
+	}
+	diff := n.Payable.Sub(*n.PrevPayable)
+	return &diff
+}
+
+// NetworkDashboard é o painel de exposição financeira por rede.
+type NetworkDashboard struct {
+	ReferenceDate time.Time         `json:"reference_date"` // Dia (UTC) usado para classificar os vencidos.
+	GeneratedAt   time.Time         `json:"generated_at"`
+	Networks      []NetworkExposure `json:"networks"` // Ordenadas pela exposição total, decrescente.
+
+	// OwnNetworksOnly indica que o usuário só vê as redes que criou (`PermNetworkViewOwn`).
+	OwnNetworksOnly bool `json:"own_networks_only"`
+
+	// PrevDireitosAt e PrevObrigacoesAt são as datas das importações anteriores usadas na comparação.
+	PrevDireitosAt   *time.Time `json:"prev_direitos_at,omitempty"`
+	PrevObrigacoesAt *time.Time `json:"prev_obrigacoes_at,omitempty"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Situações de um CNPJ/CPF dos títulos na verificação contra o cadastro de CNPJs.
+const (
+	TituloCNPJStatusUnknown         = "NAO_CADASTRADO" // CNPJ válido, mas sem cadastro em `DBCNPJ`.
+	TituloCNPJStatusInactive        = "CNPJ_INATIVO"   // CNPJ cadastrado, mas inativo.
+	TituloCNPJStatusNetworkInactive = "REDE_INATIVA"   // CNPJ cadastrado e ativo, mas a rede dele está inativa.
+	TituloCNPJStatusInvalid         = "CNPJ_INVALIDO"  // Não passa em `utils.IsValidCNPJ` (dígitos verificadores, tamanho).
+)
+
+// TituloCNPJSummary agrupa os títulos ativos de um tipo pelo CNPJ/CPF, com os dados do cadastro
+// correspondente (campos nulos se o CNPJ/CPF não estiver cadastrado). Usado para montar o relatório.
+type TituloCNPJSummary struct {
+	CNPJCPF       string
+	TitleCount    int
+	Pessoa        *string // Nome da pessoa em um dos títulos (para facilitar o cadastro).
+	CNPJID        *uint64
+	CNPJActive    *bool
+	NetworkID     *uint64
+	NetworkName   *string
+	NetworkStatus *bool
+}
+
+// TituloCNPJIssue é um CNPJ/CPF dos títulos que não pôde ser vinculado a um CNPJ ativo de uma rede ativa.
+type TituloCNPJIssue struct {
+	CNPJCPF     string `json:"cnpj_cpf"`
+	Status      string `json:"status"` // Ver constantes TituloCNPJStatus*.
+	TitleCount  int    `json:"title_count"`
+	Pessoa      string `json:"pessoa"`
+	NetworkName string `json:"network_name"` // Vazio se o CNPJ não estiver cadastrado.
+}
+
+// TituloCNPJCheckReport é o resultado da verificação dos títulos ativos de um tipo contra o cadastro de CNPJs.
+type TituloCNPJCheckReport struct {
+	FileType  string    `json:"file_type"`
+	CheckedAt time.Time `json:"checked_at"`
+
+	TotalTitles   int `json:"total_titles"`   // Títulos ativos verificados.
+	LinkedTitles  int `json:"linked_titles"`  // Títulos vinculados a um CNPJ cadastrado (ativo ou não).
+	CPFTitles     int `json:"cpf_titles"`     // Títulos de pessoa física (CPF), que não entram no cadastro de CNPJs.
+	DistinctCNPJs int `json:"distinct_cnpjs"` // CNPJs/CPFs distintos nos títulos.
+
+	Issues []TituloCNPJIssue `json:"issues"` // Ordenados por situação e, depois, pela quantidade de títulos (decrescente).
+}
+
+// CountByStatus retorna quantos CNPJs do relatório estão na situação informada.
+func (r *TituloCNPJCheckReport) CountByStatus(status string) int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Status == status {
+			count++
+		}
+	}
+	return count
+}
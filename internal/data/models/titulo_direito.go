@@ -41,7 +41,7 @@ type DBTituloDireito struct {
 	// O campo UpdatedAt do GORM (`gorm:"autoUpdateTime"`) é geralmente usado para
 	// rastrear quando o registro no banco foi modificado pela aplicação.
 	// Se 'DTAALTERAÇÃO' do CSV for uma data de modificação externa, ela deve ser armazenada.
-	DataAlteracaoCSV *time.Time `gorm:"type:date;column:data_alteracao_csv"` // Nome explícito da coluna
+	DataAlteracaoCSV *time.Time `gorm:"type:timestamp;column:data_alteracao_csv"` // Data e hora; nome explícito da coluna
 
 	Observacao       *string `gorm:"type:text"`        // Mapeia para 'OBSERVAÇÃO'
 	ValorOperacao    *string `gorm:"type:varchar(30)"` // Mapeia para 'VLROPERAÇÃO'
@@ -54,6 +54,22 @@ type DBTituloDireito struct {
 	ContasQuitacao *string    `gorm:"type:text"` // Mapeia para 'CONTASQUITAÇÃO'
 	DataProgramada *time.Time `gorm:"type:date"` // Mapeia para 'DTAPROGRAMADA'
 
+	// --- Controle da importação incremental ---
+
+	// RemovedAt é preenchido quando o título deixa de constar em uma importação incremental.
+	// O registro é mantido (preservando vínculos com o ID) e volta a ficar ativo se
+	// reaparecer em uma importação futura. Consultas de títulos ativos devem filtrar `removed_at IS NULL`.
+	RemovedAt *time.Time `gorm:"index"`
+	// ImportToken identifica a última execução de importação incremental em que o título foi encontrado.
+	ImportToken *string `gorm:"type:varchar(36);index"`
+
+	// --- Vínculo com o cadastro de CNPJs ---
+
+	// CNPJID e NetworkID ligam o título ao CNPJ cadastrado (`DBCNPJ`) com o mesmo número e à rede dele.
+	// São recalculados após cada importação; ficam nulos se o CNPJ/CPF do título não estiver cadastrado.
+	CNPJID    *uint64 `gorm:"index"`
+	NetworkID *uint64 `gorm:"index"`
+
 	// Campos de auditoria padrão do GORM (opcional, se não gerenciados explicitamente)
 	// CreatedAt time.Time      `gorm:"autoCreateTime"`
 	// UpdatedAt time.Time      `gorm:"autoUpdateTime"`
@@ -65,32 +81,45 @@ func (DBTituloDireito) TableName() string {
 	return "titulos_direitos"
 }
 
+// ToBalance retorna a projeção do título usada nos cálculos de saldo em aberto.
+func (dbtd *DBTituloDireito) ToBalance() TituloBalance {
+	return TituloBalance{
+		ID: dbtd.ID, CNPJCPF: dbtd.CNPJCPF, Pessoa: dbtd.Pessoa, Titulo: dbtd.Titulo,
+		NumeroEmpresa: dbtd.NumeroEmpresa, CodigoEspecie: dbtd.CodigoEspecie, NetworkID: dbtd.NetworkID,
+		DataVencimento: dbtd.DataVencimento, DataProgramada: dbtd.DataProgramada, ValorNominal: dbtd.ValorNominal, ValorPago: dbtd.ValorPago,
+	}
+}
+
 // --- Structs para Transferência de Dados (DTO) ---
 
 // TituloDireitoPublic representa dados de um título de direito formatados para exibição ou API.
 // Converte valores string do DB para tipos mais apropriados para a UI (ex: decimal.Decimal).
 type TituloDireitoPublic struct {
-	ID               uint64           `json:"id"`
-	Pessoa           *string          `json:"pessoa,omitempty"`
-	CNPJCPF          string           `json:"cnpj_cpf"` // CNPJ formatado XXX.XXX.XXX/XXXX-XX
-	NumeroEmpresa    int              `json:"numero_empresa"`
-	Titulo           string           `json:"titulo"`
-	CodigoEspecie    *string          `json:"codigo_especie,omitempty"`
-	DataVencimento   *string          `json:"data_vencimento,omitempty"` // Formatado como "DD/MM/YYYY"
-	DataQuitacao     *string          `json:"data_quitacao,omitempty"`   // Formatado
-	ValorNominal     *decimal.Decimal `json:"valor_nominal"`             // Convertido para decimal
-	ValorPago        *decimal.Decimal `json:"valor_pago,omitempty"`      // Convertido para decimal
-	Operacao         *string          `json:"operacao,omitempty"`
-	DataOperacao     *string          `json:"data_operacao,omitempty"`      // Formatado
-	DataContabiliza  *string          `json:"data_contabiliza,omitempty"`   // Formatado
-	DataAlteracaoCSV *string          `json:"data_alteracao_csv,omitempty"` // Formatado
-	Observacao       *string          `json:"observacao,omitempty"`
-	ValorOperacao    *decimal.Decimal `json:"valor_operacao,omitempty"` // Convertido
-	UsuarioAlteracao *string          `json:"usuario_alteracao,omitempty"`
-	EspecieAbatcomp  *string          `json:"especie_abatcomp,omitempty"`
-	ObsTitulo        *string          `json:"obs_titulo,omitempty"`
-	ContasQuitacao   *string          `json:"contas_quitacao,omitempty"`
-	DataProgramada   *string          `json:"data_programada,omitempty"` // Formatado
+	ID                    uint64             `json:"id"`
+	Pessoa                *string            `json:"pessoa,omitempty"`
+	CNPJCPF               string             `json:"cnpj_cpf"` // CNPJ formatado XXX.XXX.XXX/XXXX-XX
+	NumeroEmpresa         int                `json:"numero_empresa"`
+	Titulo                string             `json:"titulo"`
+	CodigoEspecie         *string            `json:"codigo_especie,omitempty"`
+	DataVencimento        *string            `json:"data_vencimento,omitempty"`         // Formatado como "DD/MM/YYYY"
+	DataVencimentoEfetiva *string            `json:"data_vencimento_efetiva,omitempty"` // Formatado; vencimento ajustado ao próximo dia útil
+	DataQuitacao          *string            `json:"data_quitacao,omitempty"`           // Formatado
+	ValorNominal          *decimal.Decimal   `json:"valor_nominal"`                     // Convertido para decimal
+	ValorPago             *decimal.Decimal   `json:"valor_pago,omitempty"`              // Convertido para decimal
+	Operacao              *string            `json:"operacao,omitempty"`
+	DataOperacao          *string            `json:"data_operacao,omitempty"`      // Formatado
+	DataContabiliza       *string            `json:"data_contabiliza,omitempty"`   // Formatado
+	DataAlteracaoCSV      *string            `json:"data_alteracao_csv,omitempty"` // Formatado
+	Observacao            *string            `json:"observacao,omitempty"`
+	ValorOperacao         *decimal.Decimal   `json:"valor_operacao,omitempty"` // Convertido
+	UsuarioAlteracao      *string            `json:"usuario_alteracao,omitempty"`
+	EspecieAbatcomp       *string            `json:"especie_abatcomp,omitempty"`
+	ObsTitulo             *string            `json:"obs_titulo,omitempty"`
+	ContasQuitacao        *string            `json:"contas_quitacao,omitempty"`
+	DataProgramada        *string            `json:"data_programada,omitempty"` // Formatado
+	RemovedAt             *string            `json:"removed_at,omitempty"`      // Preenchido se o título foi removido em uma importação incremental
+	NetworkID             *uint64            `json:"network_id,omitempty"`      // Rede do CNPJ cadastrado, se o título estiver vinculado
+	Encargos              *ChargeCalculation `json:"encargos,omitempty"`        // Multa e juros de mora na data de cálculo do filtro
 }
 
 // Helper para converter string de valor para *decimal.Decimal.
@@ -176,6 +205,8 @@ func ToTituloDireitoPublic(dbtd *DBTituloDireito) (*TituloDireitoPublic, error)
 		ObsTitulo:        dbtd.ObsTitulo,
 		ContasQuitacao:   dbtd.ContasQuitacao,
 		DataProgramada:   formatDatePtr(dbtd.DataProgramada),
+		RemovedAt:        formatDatePtr(dbtd.RemovedAt),
+		NetworkID:        dbtd.NetworkID,
 	}, nil
 }
 
@@ -36,7 +36,7 @@ type DBTituloObrigacao struct {
 	DataContabiliza *time.Time `gorm:"type:date"`
 
 	// Data de alteração conforme o arquivo CSV.
-	DataAlteracaoCSV *time.Time `gorm:"type:date;column:data_alteracao_csv"`
+	DataAlteracaoCSV *time.Time `gorm:"type:timestamp;column:data_alteracao_csv"` // Data e hora da alteração no ERP.
 
 	Observacao       *string    `gorm:"type:text"`
 	ValorOperacao    *string    `gorm:"type:varchar(30)"`
@@ -46,6 +46,22 @@ type DBTituloObrigacao struct {
 	ContasQuitacao   *string    `gorm:"type:text"`
 	DataProgramada   *time.Time `gorm:"type:date"`
 
+	// --- Controle da importação incremental ---
+
+	// RemovedAt é preenchido quando o título deixa de constar em uma importação incremental.
+	// O registro é mantido (preservando vínculos com o ID) e volta a ficar ativo se
+	// reaparecer em uma importação futura. Consultas de títulos ativos devem filtrar `removed_at IS NULL`.
+	RemovedAt *time.Time `gorm:"index"`
+	// ImportToken identifica a última execução de importação incremental em que o título foi encontrado.
+	ImportToken *string `gorm:"type:varchar(36);index"`
+
+	// --- Vínculo com o cadastro de CNPJs ---
+
+	// CNPJID e NetworkID ligam o título ao CNPJ cadastrado (`DBCNPJ`) com o mesmo número e à rede dele.
+	// São recalculados após cada importação; ficam nulos se o CNPJ/CPF do título não estiver cadastrado.
+	CNPJID    *uint64 `gorm:"index"`
+	NetworkID *uint64 `gorm:"index"`
+
 	// Campos de auditoria padrão do GORM (opcional)
 	// CreatedAt time.Time      `gorm:"autoCreateTime"`
 	// UpdatedAt time.Time      `gorm:"autoUpdateTime"`
@@ -56,6 +72,15 @@ func (DBTituloObrigacao) TableName() string {
 	return "titulos_obrigacoes"
 }
 
+// ToBalance retorna a projeção do título usada nos cálculos de saldo em aberto.
+func (dbto *DBTituloObrigacao) ToBalance() TituloBalance {
+	return TituloBalance{
+		ID: dbto.ID, CNPJCPF: dbto.CNPJCPF, Pessoa: dbto.Pessoa, Titulo: dbto.IdentificadorObrigacao,
+		NumeroEmpresa: dbto.NumeroEmpresa, CodigoEspecie: dbto.CodigoEspecie, NetworkID: dbto.NetworkID,
+		DataVencimento: dbto.DataVencimento, DataProgramada: dbto.DataProgramada, ValorNominal: dbto.ValorNominalObrigacao, ValorPago: dbto.ValorPago,
+	}
+}
+
 // --- Struct para ler dados brutos da linha do arquivo ---
 
 // TituloObrigacaoFromRow representa os dados como lidos diretamente de uma linha do CSV/TXT
@@ -113,10 +138,11 @@ type TituloObrigacaoPublic struct {
 	NumeroEmpresa          int              `json:"numero_empresa"`
 	IdentificadorObrigacao string           `json:"identificador_obrigacao"`
 	CodigoEspecie          *string          `json:"codigo_especie,omitempty"`
-	DataVencimento         *string          `json:"data_vencimento,omitempty"` // Formatado "DD/MM/YYYY"
-	DataQuitacao           *string          `json:"data_quitacao,omitempty"`   // Formatado
-	ValorNominalObrigacao  *decimal.Decimal `json:"valor_nominal_obrigacao"`   // Convertido
-	ValorPago              *decimal.Decimal `json:"valor_pago,omitempty"`      // Convertido
+	DataVencimento         *string          `json:"data_vencimento,omitempty"`         // Formatado "DD/MM/YYYY"
+	DataVencimentoEfetiva  *string          `json:"data_vencimento_efetiva,omitempty"` // Formatado; vencimento ajustado ao próximo dia útil
+	DataQuitacao           *string          `json:"data_quitacao,omitempty"`           // Formatado
+	ValorNominalObrigacao  *decimal.Decimal `json:"valor_nominal_obrigacao"`           // Convertido
+	ValorPago              *decimal.Decimal `json:"valor_pago,omitempty"`              // Convertido
 	Operacao               *string          `json:"operacao,omitempty"`
 	DataOperacao           *string          `json:"data_operacao,omitempty"`      // Formatado
 	DataContabiliza        *string          `json:"data_contabiliza,omitempty"`   // Formatado
@@ -128,6 +154,8 @@ type TituloObrigacaoPublic struct {
 	ObsTitulo              *string          `json:"obs_titulo,omitempty"`
 	ContasQuitacao         *string          `json:"contas_quitacao,omitempty"`
 	DataProgramada         *string          `json:"data_programada,omitempty"` // Formatado
+	RemovedAt              *string          `json:"removed_at,omitempty"`      // Preenchido se o título foi removido em uma importação incremental
+	NetworkID              *uint64          `json:"network_id,omitempty"`      // Rede do CNPJ cadastrado, se o título estiver vinculado
 }
 
 // ToTituloObrigacaoPublic converte DBTituloObrigacao para TituloObrigacaoPublic.
@@ -189,6 +217,8 @@ func ToTituloObrigacaoPublic(dbto *DBTituloObrigacao) (*TituloObrigacaoPublic, e
 		ObsTitulo:              dbto.ObsTitulo,
 		ContasQuitacao:         dbto.ContasQuitacao,
 		DataProgramada:         formatDatePtr(dbto.DataProgramada),
+		RemovedAt:              formatDatePtr(dbto.RemovedAt),
+		NetworkID:              dbto.NetworkID,
 	}, nil
 }
 
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+)
+
+// TituloStatus filtra os títulos pela situação de quitação.
+type TituloStatus string
+
+const (
+	TituloStatusAll     TituloStatus = ""        // Todos os títulos.
+	TituloStatusOpen    TituloStatus = "OPEN"    // Em aberto (sem data de quitação).
+	TituloStatusSettled TituloStatus = "SETTLED" // Quitados (com data de quitação).
+)
+
+// TituloSortField define a coluna de ordenação da consulta de títulos.
+type TituloSortField string
+
+const (
+	TituloSortDueDate       TituloSortField = "due_date" // Padrão.
+	TituloSortSettledDate   TituloSortField = "settled_date"
+	TituloSortNominalValue  TituloSortField = "nominal_value"
+	TituloSortCNPJCPF       TituloSortField = "cnpjcpf"
+	TituloSortNumeroEmpresa TituloSortField = "numero_empresa"
+	TituloSortTitulo        TituloSortField = "titulo"
+)
+
+const (
+	TituloQueryDefaultLimit = 50  // Tamanho padrão da página de títulos.
+	TituloQueryMaxLimit     = 500 // Tamanho máximo da página de títulos.
+)
+
+// TituloFilter define os filtros da consulta de títulos (direitos ou obrigações).
+// Campos nil/vazios são ignorados. Limit/Offset controlam a paginação.
+type TituloFilter struct {
+	// CNPJCPF aceita o número com ou sem formatação. Com 11 ou 14 dígitos a busca é exata;
+	// com menos dígitos, busca pelo prefixo (ex: a raiz de 8 dígitos traz todas as filiais).
+	CNPJCPF       *string
+	NetworkID     *uint64
+	NumeroEmpresa *int
+	DueFrom       *time.Time // Vencimento a partir deste dia (inclusive).
+	DueTo         *time.Time // Vencimento até este dia (inclusive).
+	Status        TituloStatus
+	CodigoEspecie *string
+	MinValue      *decimal.Decimal // Valor nominal mínimo (inclusive).
+	MaxValue      *decimal.Decimal // Valor nominal máximo (inclusive).
+
+	// IncludeRemoved inclui os títulos marcados como removidos em importações incrementais.
+	IncludeRemoved bool
+
+	// ChargesAsOf é a data de cálculo da multa e dos juros de mora dos direitos vencidos (nil = hoje).
+	ChargesAsOf *time.Time
+
+	SortBy   TituloSortField
+	SortDesc bool
+	Limit    int
+	Offset   int
+}
+
+// Normalize remove espaços e formatação dos filtros de texto e aplica a ordenação e a paginação padrão.
+func (f *TituloFilter) Normalize() {
+	if f == nil {
+		return
+	}
+	if f.CNPJCPF != nil {
+		digits := cnpjNonDigitRegex.ReplaceAllString(*f.CNPJCPF, "")
+		if digits == "" {
+			f.CNPJCPF = nil
+		} else {
+			f.CNPJCPF = &digits
+		}
+	}
+	if f.CodigoEspecie != nil {
+		trimmed := strings.TrimSpace(*f.CodigoEspecie)
+		if trimmed == "" {
+			f.CodigoEspecie = nil
+		} else {
+			f.CodigoEspecie = &trimmed
+		}
+	}
+	f.Status = TituloStatus(strings.ToUpper(strings.TrimSpace(string(f.Status))))
+	f.SortBy = TituloSortField(strings.ToLower(strings.TrimSpace(string(f.SortBy))))
+	if f.SortBy == "" {
+		f.SortBy = TituloSortDueDate
+	}
+	if f.Limit <= 0 {
+		f.Limit = TituloQueryDefaultLimit
+	} else if f.Limit > TituloQueryMaxLimit {
+		f.Limit = TituloQueryMaxLimit
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+}
+
+// Validate verifica a coerência dos filtros. Deve ser chamado após Normalize.
+func (f *TituloFilter) Validate() error {
+	switch f.Status {
+	case TituloStatusAll, TituloStatusOpen, TituloStatusSettled:
+	default:
+		return fmt.Errorf("%w: situação de título '%s' inválida", appErrors.ErrInvalidInput, f.Status)
+	}
+	switch f.SortBy {
+	case TituloSortDueDate, TituloSortSettledDate, TituloSortNominalValue,
+		TituloSortCNPJCPF, TituloSortNumeroEmpresa, TituloSortTitulo:
+	default:
+		return fmt.Errorf("%w: ordenação de títulos por '%s' não suportada", appErrors.ErrInvalidInput, f.SortBy)
+	}
+	if f.CNPJCPF != nil && len(*f.CNPJCPF) > 14 {
+		return fmt.Errorf("%w: CNPJ/CPF '%s' com mais de 14 dígitos", appErrors.ErrInvalidInput, *f.CNPJCPF)
+	}
+	if f.DueFrom != nil && f.DueTo != nil && f.DueTo.Before(*f.DueFrom) {
+		return fmt.Errorf("%w: data final de vencimento anterior à data inicial", appErrors.ErrInvalidInput)
+	}
+	if f.MinValue != nil && f.MaxValue != nil && f.MaxValue.LessThan(*f.MinValue) {
+		return fmt.Errorf("%w: valor máximo menor que o valor mínimo", appErrors.ErrInvalidInput)
+	}
+	return nil
+}
+
+// TituloTotals resume o conjunto de títulos que corresponde aos filtros (todas as páginas).
+type TituloTotals struct {
+	Count        int64           `json:"count"`
+	ValorNominal decimal.Decimal `json:"valor_nominal"`
+	ValorPago    decimal.Decimal `json:"valor_pago"`
+}
+
+// TituloDireitoPage é uma página da consulta de títulos de direitos.
+type TituloDireitoPage struct {
+	Items  []*TituloDireitoPublic `json:"items"`
+	Totals TituloTotals           `json:"totals"`
+	Limit  int                    `json:"limit"`
+	Offset int                    `json:"offset"`
+}
+
+// TituloObrigacaoPage é uma página da consulta de títulos de obrigações.
+type TituloObrigacaoPage struct {
+	Items  []*TituloObrigacaoPublic `json:"items"`
+	Totals TituloTotals             `json:"totals"`
+	Limit  int                      `json:"limit"`
+	Offset int                      `json:"offset"`
+}
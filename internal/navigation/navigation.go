@@ -22,6 +22,12 @@ const (
 	PageAdminPermissions
 	PageRoleManagement
 	PageImport
+	PageTitulos
+	PageAging
+	PageNetting
+	PageDashboard
+	PageCashFlow
+	PageDueDigest
 )
 
 // Page define a interface que cada página/view da aplicação deve implementar.
@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+)
+
+// ChargeRuleRepository define a interface para operações no repositório de regras de encargos.
+type ChargeRuleRepository interface {
+	GetAll(includeInactive bool) ([]models.DBChargeRule, error)
+	GetByID(ruleID uint64) (*models.DBChargeRule, error)
+	// Create grava uma nova regra. `ruleData` já deve ter passado por `CleanAndValidate`.
+	Create(ruleData models.ChargeRuleCreate, createdByUsername string) (*models.DBChargeRule, error)
+	// Update substitui todos os campos da regra. `ruleData` já deve ter passado por `CleanAndValidate`.
+	Update(ruleID uint64, ruleData models.ChargeRuleCreate, updatedByUsername string) (*models.DBChargeRule, error)
+	Delete(ruleID uint64) error
+}
+
+// gormChargeRuleRepository é a implementação GORM de ChargeRuleRepository.
+type gormChargeRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewGormChargeRuleRepository cria uma nova instância de gormChargeRuleRepository.
+func NewGormChargeRuleRepository(db *gorm.DB) ChargeRuleRepository {
+	if db == nil {
+		appLogger.Fatalf("gorm.DB não pode ser nil para NewGormChargeRuleRepository")
+	}
+	return &gormChargeRuleRepository{db: db}
+}
+
+// GetAll busca as regras de encargos, opcionalmente incluindo as inativas. Ordena por ID.
+func (r *gormChargeRuleRepository) GetAll(includeInactive bool) ([]models.DBChargeRule, error) {
+	var rules []models.DBChargeRule
+	query := r.db.Order("id ASC")
+	if !includeInactive {
+		query = query.Where("active = ?", true)
+	}
+	if err := query.Find(&rules).Error; err != nil {
+		appLogger.Errorf("Erro ao buscar regras de encargos (includeInactive: %t): %v", includeInactive, err)
+		return nil, appErrors.WrapErrorf(err, "falha na recuperação das regras de encargos (GORM)")
+	}
+	return rules, nil
+}
+
+// GetByID busca uma regra de encargos pelo ID.
+func (r *gormChargeRuleRepository) GetByID(ruleID uint64) (*models.DBChargeRule, error) {
+	var rule models.DBChargeRule
+	if err := r.db.First(&rule, ruleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: regra de encargos com ID %d não encontrada", appErrors.ErrNotFound, ruleID)
+		}
+		appLogger.Errorf("Erro ao buscar regra de encargos por ID %d: %v", ruleID, err)
+		return nil, appErrors.WrapErrorf(err, "falha ao buscar regra de encargos por ID (GORM)")
+	}
+	return &rule, nil
+}
+
+// chargeRuleColumns converte os dados validados para as colunas da tabela.
+func chargeRuleColumns(ruleData models.ChargeRuleCreate) map[string]interface{} {
+	return map[string]interface{}{
+		"name":            ruleData.Name,
+		"codigo_especie":  ruleData.CodigoEspecie,
+		"network_id":      ruleData.NetworkID,
+		"fine_type":       string(ruleData.FineType),
+		"fine_value":      ruleData.FineValue.String(),
+		"interest_period": string(ruleData.InterestPeriod),
+		"interest_rate":   ruleData.InterestRate.String(),
+		"interest_method": string(ruleData.InterestMethod),
+		"grace_days":      ruleData.GraceDays,
+		"active":          ruleData.Active,
+	}
+}
+
+// Create grava uma nova regra de encargos.
+func (r *gormChargeRuleRepository) Create(ruleData models.ChargeRuleCreate, createdByUsername string) (*models.DBChargeRule, error) {
+	dbRule := models.DBChargeRule{
+		Name:           ruleData.Name,
+		CodigoEspecie:  ruleData.CodigoEspecie,
+		NetworkID:      ruleData.NetworkID,
+		FineType:       string(ruleData.FineType),
+		FineValue:      ruleData.FineValue.String(),
+		InterestPeriod: string(ruleData.InterestPeriod),
+		InterestRate:   ruleData.InterestRate.String(),
+		InterestMethod: string(ruleData.InterestMethod),
+		GraceDays:      ruleData.GraceDays,
+		Active:         ruleData.Active,
+		CreatedBy:      &createdByUsername,
+		UpdatedBy:      &createdByUsername,
+	}
+	if err := r.db.Create(&dbRule).Error; err != nil {
+		appLogger.Errorf("Erro ao criar regra de encargos '%s': %v", ruleData.Name, err)
+		return nil, appErrors.WrapErrorf(err, "falha ao criar regra de encargos (GORM)")
+	}
+	// GORM omite o false no INSERT e a coluna assume o default (true); a regra inativa é gravada à parte.
+	if !ruleData.Active {
+		if err := r.db.Model(&dbRule).Update("active", false).Error; err != nil {
+			appLogger.Errorf("Erro ao gravar a regra de encargos ID %d como inativa: %v", dbRule.ID, err)
+			return nil, appErrors.WrapErrorf(err, "falha ao gravar a situação da regra de encargos (GORM)")
+		}
+	}
+	appLogger.Infof("Regra de encargos '%s' criada por %s (ID: %d)", dbRule.Name, createdByUsername, dbRule.ID)
+	return &dbRule, nil
+}
+
+// Update substitui os campos de uma regra de encargos.
+func (r *gormChargeRuleRepository) Update(ruleID uint64, ruleData models.ChargeRuleCreate, updatedByUsername string) (*models.DBChargeRule, error) {
+	dbRule, err := r.GetByID(ruleID)
+	if err != nil {
+		return nil, err
+	}
+	updates := chargeRuleColumns(ruleData)
+	updates["updated_by"] = &updatedByUsername
+	if err := r.db.Model(dbRule).Updates(updates).Error; err != nil {
+		appLogger.Errorf("Erro ao atualizar regra de encargos ID %d: %v", ruleID, err)
+		return nil, appErrors.WrapErrorf(err, "falha na atualização da regra de encargos (GORM)")
+	}
+	// Recarrega para refletir os campos gravados como nil (espécie e rede).
+	if dbRule, err = r.GetByID(ruleID); err != nil {
+		return nil, err
+	}
+	appLogger.Infof("Regra de encargos ID %d ('%s') atualizada por %s.", ruleID, dbRule.Name, updatedByUsername)
+	return dbRule, nil
+}
+
+// Delete exclui uma regra de encargos (exclusão física).
+func (r *gormChargeRuleRepository) Delete(ruleID uint64) error {
+	result := r.db.Delete(&models.DBChargeRule{}, ruleID)
+	if result.Error != nil {
+		appLogger.Errorf("Erro ao excluir regra de encargos ID %d: %v", ruleID, result.Error)
+		return appErrors.WrapErrorf(result.Error, "falha ao excluir regra de encargos (GORM)")
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: regra de encargos com ID %d não encontrada", appErrors.ErrNotFound, ruleID)
+	}
+	appLogger.Infof("Regra de encargos ID %d excluída.", ruleID)
+	return nil
+}
@@ -28,8 +28,9 @@ type CNPJRepository interface {
 	Delete(cnpjID uint64) error
 	GetAll(includeInactive bool) ([]models.DBCNPJ, error)
 	GetByNetworkID(networkID uint64, includeInactive bool) ([]models.DBCNPJ, error)
-	// UpsertCNPJ insere ou atualiza um CNPJ. Útil se a lógica de negócio permitir.
-	// UpsertCNPJ(cnpjData models.CNPJCreate) (*models.DBCNPJ, error)
+	// UpsertCNPJ insere um CNPJ ou, se o número já estiver cadastrado, associa-o à rede informada
+	// e o reativa. Espera que cnpjData.CNPJ já tenha sido validado e limpo.
+	UpsertCNPJ(cnpjData models.CNPJCreate) (*models.DBCNPJ, error)
 }
 
 // gormCNPJRepository é a implementação GORM de CNPJRepository.
@@ -242,8 +243,7 @@ func (r *gormCNPJRepository) GetByNetworkID(networkID uint64, includeInactive bo
 	return cnpjs, nil
 }
 
-// UpsertCNPJ (Exemplo, não solicitado diretamente, mas útil)
-// Cria um novo CNPJ ou atualiza um existente com base no número do CNPJ.
+// UpsertCNPJ cria um novo CNPJ ou atualiza um existente com base no número do CNPJ.
 // `cnpjData` deve ter CNPJ já limpo.
 // Este método é mais complexo pois precisa definir quais campos atualizar em caso de conflito.
 func (r *gormCNPJRepository) UpsertCNPJ(cnpjData models.CNPJCreate) (*models.DBCNPJ, error) {
@@ -261,14 +261,8 @@ func (r *gormCNPJRepository) UpsertCNPJ(cnpjData models.CNPJCreate) (*models.DBC
 	// Tenta inserir. Se houver conflito na coluna `cnpj` (que é uniqueIndex),
 	// atualiza os campos especificados em `DoUpdates`.
 	err := r.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "cnpj"}},                                          // Coluna de conflito
-		DoUpdates: clause.AssignmentColumns([]string{"network_id", "active", "updated_at"}), // Campos a atualizar
-		// Se "updated_at" não for autoUpdateTime, precisa ser setado explicitamente:
-		// DoUpdates: clause.Assignments(map[string]interface{}{
-		// 	"network_id": dbCNPJ.NetworkID, // Usa o valor da tentativa de inserção
-		// 	"active":     dbCNPJ.Active,
-		// 	"updated_at": time.Now().UTC(),
-		// }),
+		Columns:   []clause.Column{{Name: "cnpj"}},                            // Coluna de conflito
+		DoUpdates: clause.AssignmentColumns([]string{"network_id", "active"}), // Campos a atualizar (DBCNPJ não tem updated_at)
 	}).Create(&dbCNPJ).Error
 
 	if err != nil {
@@ -277,6 +271,11 @@ func (r *gormCNPJRepository) UpsertCNPJ(cnpjData models.CNPJCreate) (*models.DBC
 		return nil, appErrors.WrapErrorf(err, "falha no upsert do CNPJ (GORM)")
 	}
 
-	appLogger.Infof("CNPJ %s (ID: %d) inserido/atualizado via upsert.", dbCNPJ.CNPJ, dbCNPJ.ID)
-	return &dbCNPJ, nil
+	// Em caso de conflito, nem todos os bancos retornam o ID e a data de cadastro da linha existente.
+	saved, err := r.GetByCNPJ(cnpjData.CNPJ)
+	if err != nil {
+		return nil, err
+	}
+	appLogger.Infof("CNPJ %s (ID: %d) inserido/atualizado via upsert.", saved.CNPJ, saved.ID)
+	return saved, nil
 }
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+)
+
+// DueDigestSubscriptionRepository define a interface para operações nas inscrições do resumo de vencimentos.
+type DueDigestSubscriptionRepository interface {
+	// GetByUserID busca a inscrição do usuário. Retorna ErrNotFound se o usuário nunca se inscreveu.
+	GetByUserID(userID uuid.UUID) (*models.DBDueDigestSubscription, error)
+	// GetAllEnabled busca as inscrições ativas.
+	GetAllEnabled() ([]models.DBDueDigestSubscription, error)
+	// Upsert cria ou altera a inscrição do usuário. `data` já deve ter passado por `CleanAndValidate`.
+	Upsert(userID uuid.UUID, data models.DueDigestSubscriptionUpdate) (*models.DBDueDigestSubscription, error)
+	// MarkRun registra um processamento agendado; `sent` indica se o e-mail foi enviado.
+	MarkRun(subscriptionID uint64, ranAt time.Time, sent bool) error
+}
+
+// gormDueDigestSubscriptionRepository é a implementação GORM de DueDigestSubscriptionRepository.
+type gormDueDigestSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+// NewGormDueDigestSubscriptionRepository cria uma nova instância de gormDueDigestSubscriptionRepository.
+func NewGormDueDigestSubscriptionRepository(db *gorm.DB) DueDigestSubscriptionRepository {
+	if db == nil {
+		appLogger.Fatalf("gorm.DB não pode ser nil para NewGormDueDigestSubscriptionRepository")
+	}
+	return &gormDueDigestSubscriptionRepository{db: db}
+}
+
+// GetByUserID busca a inscrição de um usuário.
+func (r *gormDueDigestSubscriptionRepository) GetByUserID(userID uuid.UUID) (*models.DBDueDigestSubscription, error) {
+	var sub models.DBDueDigestSubscription
+	if err := r.db.Where("user_id = ?", userID).First(&sub).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: inscrição no resumo de vencimentos do usuário %s não encontrada", appErrors.ErrNotFound, userID)
+		}
+		appLogger.Errorf("Erro ao buscar inscrição no resumo de vencimentos do usuário %s: %v", userID, err)
+		return nil, appErrors.WrapErrorf(err, "falha ao buscar inscrição no resumo de vencimentos (GORM)")
+	}
+	return &sub, nil
+}
+
+// GetAllEnabled busca as inscrições ativas, ordenadas por ID.
+func (r *gormDueDigestSubscriptionRepository) GetAllEnabled() ([]models.DBDueDigestSubscription, error) {
+	var subs []models.DBDueDigestSubscription
+	if err := r.db.Where("enabled = ?", true).Order("id ASC").Find(&subs).Error; err != nil {
+		appLogger.Errorf("Erro ao buscar inscrições ativas no resumo de vencimentos: %v", err)
+		return nil, appErrors.WrapErrorf(err, "falha na recuperação das inscrições no resumo de vencimentos (GORM)")
+	}
+	return subs, nil
+}
+
+// Upsert cria ou altera a inscrição do usuário.
+func (r *gormDueDigestSubscriptionRepository) Upsert(userID uuid.UUID, data models.DueDigestSubscriptionUpdate) (*models.DBDueDigestSubscription, error) {
+	existing, err := r.GetByUserID(userID)
+	if err != nil && !errors.Is(err, appErrors.ErrNotFound) {
+		return nil, err
+	}
+	if existing == nil {
+		sub := models.DBDueDigestSubscription{
+			UserID:    userID,
+			Enabled:   data.Enabled,
+			Frequency: string(data.Frequency),
+			DaysAhead: data.DaysAhead,
+		}
+		if err := r.db.Create(&sub).Error; err != nil {
+			appLogger.Errorf("Erro ao criar inscrição no resumo de vencimentos do usuário %s: %v", userID, err)
+			return nil, appErrors.WrapErrorf(err, "falha ao criar inscrição no resumo de vencimentos (GORM)")
+		}
+		return &sub, nil
+	}
+
+	updates := map[string]interface{}{
+		"enabled":    data.Enabled,
+		"frequency":  string(data.Frequency),
+		"days_ahead": data.DaysAhead,
+	}
+	if err := r.db.Model(existing).Updates(updates).Error; err != nil {
+		appLogger.Errorf("Erro ao atualizar inscrição no resumo de vencimentos do usuário %s: %v", userID, err)
+		return nil, appErrors.WrapErrorf(err, "falha na atualização da inscrição no resumo de vencimentos (GORM)")
+	}
+	return existing, nil // existing foi atualizado in-place.
+}
+
+// MarkRun registra o processamento agendado de uma inscrição.
+func (r *gormDueDigestSubscriptionRepository) MarkRun(subscriptionID uint64, ranAt time.Time, sent bool) error {
+	updates := map[string]interface{}{"last_run_at": ranAt}
+	if sent {
+		updates["last_sent_at"] = ranAt
+	}
+	result := r.db.Model(&models.DBDueDigestSubscription{}).Where("id = ?", subscriptionID).Updates(updates)
+	if result.Error != nil {
+		appLogger.Errorf("Erro ao registrar processamento da inscrição ID %d no resumo de vencimentos: %v", subscriptionID, result.Error)
+		return appErrors.WrapErrorf(result.Error, "falha ao registrar processamento do resumo de vencimentos (GORM)")
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: inscrição ID %d no resumo de vencimentos não encontrada", appErrors.ErrNotFound, subscriptionID)
+	}
+	return nil
+}
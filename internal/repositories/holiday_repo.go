@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+)
+
+// HolidayRepository define a interface para operações no repositório de feriados locais.
+type HolidayRepository interface {
+	GetAll() ([]models.DBHoliday, error)
+	GetByID(holidayID uint64) (*models.DBHoliday, error)
+	// GetByKey busca o feriado pela chave única (data, abrangência e local).
+	GetByKey(date time.Time, scope models.HolidayScope, location string) (*models.DBHoliday, error)
+	// Create grava um novo feriado. `holidayData` já deve ter passado por `CleanAndValidate`.
+	Create(holidayData models.HolidayCreate, createdByUsername string) (*models.DBHoliday, error)
+	// UpdateDetails altera o nome e a repetição anual de um feriado existente.
+	UpdateDetails(holidayID uint64, name string, recurring bool, updatedByUsername string) (*models.DBHoliday, error)
+	Delete(holidayID uint64) error
+}
+
+// gormHolidayRepository é a implementação GORM de HolidayRepository.
+type gormHolidayRepository struct {
+	db *gorm.DB
+}
+
+// NewGormHolidayRepository cria uma nova instância de gormHolidayRepository.
+func NewGormHolidayRepository(db *gorm.DB) HolidayRepository {
+	if db == nil {
+		appLogger.Fatalf("gorm.DB não pode ser nil para NewGormHolidayRepository")
+	}
+	return &gormHolidayRepository{db: db}
+}
+
+// GetAll busca todos os feriados locais, ordenados por data.
+func (r *gormHolidayRepository) GetAll() ([]models.DBHoliday, error) {
+	var holidays []models.DBHoliday
+	if err := r.db.Order("date ASC, name ASC").Find(&holidays).Error; err != nil {
+		appLogger.Errorf("Erro ao buscar feriados: %v", err)
+		return nil, appErrors.WrapErrorf(err, "falha na recuperação da lista de feriados (GORM)")
+	}
+	return holidays, nil
+}
+
+// GetByID busca um feriado pelo ID.
+func (r *gormHolidayRepository) GetByID(holidayID uint64) (*models.DBHoliday, error) {
+	var holiday models.DBHoliday
+	if err := r.db.First(&holiday, holidayID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: feriado com ID %d não encontrado", appErrors.ErrNotFound, holidayID)
+		}
+		appLogger.Errorf("Erro ao buscar feriado por ID %d: %v", holidayID, err)
+		return nil, appErrors.WrapErrorf(err, "falha ao buscar feriado por ID (GORM)")
+	}
+	return &holiday, nil
+}
+
+// GetByKey busca o feriado de uma data, abrangência e local.
+func (r *gormHolidayRepository) GetByKey(date time.Time, scope models.HolidayScope, location string) (*models.DBHoliday, error) {
+	var holiday models.DBHoliday
+	err := r.db.Where("date = ? AND scope = ? AND location = ?", date, string(scope), location).First(&holiday).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: feriado %s em %s não encontrado", appErrors.ErrNotFound, strings.ToLower(string(scope)), date.Format("02/01/2006"))
+		}
+		appLogger.Errorf("Erro ao buscar feriado %s em %s ('%s'): %v", scope, date.Format("02/01/2006"), location, err)
+		return nil, appErrors.WrapErrorf(err, "falha ao buscar feriado (GORM)")
+	}
+	return &holiday, nil
+}
+
+// Create grava um novo feriado local.
+func (r *gormHolidayRepository) Create(holidayData models.HolidayCreate, createdByUsername string) (*models.DBHoliday, error) {
+	dbHoliday := models.DBHoliday{
+		Date:      holidayData.Date,
+		Name:      holidayData.Name,
+		Scope:     string(holidayData.Scope),
+		Location:  holidayData.Location,
+		Recurring: holidayData.Recurring,
+		CreatedBy: &createdByUsername,
+		UpdatedBy: &createdByUsername,
+	}
+	if err := r.db.Create(&dbHoliday).Error; err != nil {
+		appLogger.Errorf("Erro ao criar feriado '%s' em %s: %v", holidayData.Name, holidayData.Date.Format("02/01/2006"), err)
+		lowerErr := strings.ToLower(err.Error())
+		if strings.Contains(lowerErr, "unique constraint") || strings.Contains(lowerErr, "duplicate key value violates unique constraint") {
+			return nil, fmt.Errorf("%w: já existe um feriado %s cadastrado em %s para este local",
+				appErrors.ErrConflict, strings.ToLower(holidayData.Scope.Label()), holidayData.Date.Format("02/01/2006"))
+		}
+		return nil, appErrors.WrapErrorf(err, "falha ao criar registro de feriado (GORM)")
+	}
+	appLogger.Infof("Feriado '%s' (%s, %s) criado por %s (ID: %d)", dbHoliday.Name, dbHoliday.Scope, dbHoliday.Date.Format("02/01/2006"), createdByUsername, dbHoliday.ID)
+	return &dbHoliday, nil
+}
+
+// UpdateDetails altera o nome e a repetição anual de um feriado.
+func (r *gormHolidayRepository) UpdateDetails(holidayID uint64, name string, recurring bool, updatedByUsername string) (*models.DBHoliday, error) {
+	dbHoliday, err := r.GetByID(holidayID)
+	if err != nil {
+		return nil, err
+	}
+	updates := map[string]interface{}{
+		"name":       name,
+		"recurring":  recurring,
+		"updated_by": &updatedByUsername,
+	}
+	if err := r.db.Model(dbHoliday).Updates(updates).Error; err != nil {
+		appLogger.Errorf("Erro ao atualizar feriado ID %d: %v", holidayID, err)
+		return nil, appErrors.WrapErrorf(err, "falha na atualização do registro de feriado (GORM)")
+	}
+	appLogger.Infof("Feriado ID %d atualizado por %s.", holidayID, updatedByUsername)
+	return dbHoliday, nil // dbHoliday foi atualizado in-place.
+}
+
+// Delete exclui um feriado (exclusão física).
+func (r *gormHolidayRepository) Delete(holidayID uint64) error {
+	result := r.db.Delete(&models.DBHoliday{}, holidayID)
+	if result.Error != nil {
+		appLogger.Errorf("Erro ao excluir feriado ID %d: %v", holidayID, result.Error)
+		return appErrors.WrapErrorf(result.Error, "falha ao excluir feriado (GORM)")
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: feriado com ID %d não encontrado", appErrors.ErrNotFound, holidayID)
+	}
+	appLogger.Infof("Feriado ID %d excluído.", holidayID)
+	return nil
+}
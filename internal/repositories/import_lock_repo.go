@@ -0,0 +1,358 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+)
+
+const (
+	// importLockTTL é a validade do lock sem renovação. Um lock não renovado nesse prazo (ex: processo
+	// encerrado à força) é considerado abandonado e pode ser tomado por outra importação.
+	importLockTTL = 2 * time.Minute
+	// importLockRefreshInterval é o intervalo de renovação do lock pelo detentor.
+	importLockRefreshInterval = importLockTTL / 3
+)
+
+// ImportLock é um lock de importação obtido por `ImportLockRepository.Acquire`.
+type ImportLock interface {
+	// RenewTx renova a validade do lock dentro da transação `tx` (no máximo uma vez por intervalo de
+	// renovação). Deve ser usada como `ImportTxHooks.AfterBatch` nas transações longas de importação:
+	// no SQLite, a renovação em segundo plano fica bloqueada enquanto a transação detém a escrita no
+	// banco, e a renovação feita na própria transação passa a valer junto com o commit da importação.
+	RenewTx(tx *gorm.DB) error
+
+	// Release libera o lock. Chamadas repetidas não têm efeito.
+	Release()
+}
+
+// ImportLockRepository controla o lock de importação por tipo de arquivo, compartilhado entre todos os
+// processos que usam o mesmo banco (ex: dois usuários importando o mesmo tipo ao mesmo tempo).
+// No PostgreSQL é usado um advisory lock da sessão (liberado pelo servidor se a conexão cair); no
+// SQLite, a linha da tabela `import_locks`, com validade renovada enquanto o lock estiver em uso.
+type ImportLockRepository interface {
+	// Acquire obtém o lock do tipo de arquivo para o usuário `heldBy`, sem esperar. Se outra importação
+	// detiver o lock, retorna um `*models.ImportLockedError` com o detentor e o início da importação.
+	Acquire(fileType string, heldBy string) (ImportLock, error)
+
+	// Get retorna o detentor atual do lock do tipo de arquivo, ou nil se o lock estiver livre ou vencido.
+	// No SQLite, um lock vencido cuja importação ainda está em andamento (execução `RUNNING` do mesmo
+	// usuário iniciada após o lock) continua sendo retornado: durante a transação da importação, a
+	// renovação só se torna visível às outras conexões no commit.
+	Get(fileType string) (*models.DBImportLock, error)
+}
+
+// gormImportLockRepository é a implementação GORM de ImportLockRepository.
+type gormImportLockRepository struct {
+	db       *gorm.DB
+	hostName string
+}
+
+// NewGormImportLockRepository cria uma nova instância de gormImportLockRepository.
+func NewGormImportLockRepository(db *gorm.DB) ImportLockRepository {
+	if db == nil {
+		appLogger.Fatalf("gorm.DB não pode ser nil para NewGormImportLockRepository")
+	}
+	hostName, err := os.Hostname()
+	if err != nil {
+		appLogger.Warnf("Não foi possível obter o nome da máquina para o lock de importação: %v", err)
+	}
+	return &gormImportLockRepository{db: db, hostName: hostName}
+}
+
+// usesAdvisoryLock indica se o banco é PostgreSQL, que oferece advisory locks.
+func (r *gormImportLockRepository) usesAdvisoryLock() bool {
+	return r.db.Dialector.Name() == "postgres"
+}
+
+// importLockKey deriva a chave do advisory lock do PostgreSQL a partir do tipo de arquivo.
+func importLockKey(fileType string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("import_lock:" + fileType))
+	return int64(h.Sum64())
+}
+
+// Acquire obtém o lock de importação do tipo de arquivo.
+func (r *gormImportLockRepository) Acquire(fileType string, heldBy string) (ImportLock, error) {
+	fileType = strings.ToUpper(strings.TrimSpace(fileType))
+	if fileType == "" {
+		return nil, fmt.Errorf("%w: tipo de arquivo vazio para o lock de importação", appErrors.ErrInvalidInput)
+	}
+
+	lock := &gormImportLock{
+		repo:     r,
+		fileType: fileType,
+		token:    uuid.NewString(),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if r.usesAdvisoryLock() {
+		conn, err := r.tryAdvisoryLock(fileType)
+		if err != nil {
+			return nil, err
+		}
+		lock.conn = conn
+	}
+
+	now := time.Now().UTC()
+	row := &models.DBImportLock{
+		FileType:   fileType,
+		Token:      lock.token,
+		HeldBy:     heldBy,
+		HostName:   r.hostName,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(importLockTTL),
+	}
+	if err := r.claimRow(row, lock.conn != nil); err != nil {
+		lock.unlockAdvisory()
+		return nil, err
+	}
+
+	go lock.keepAlive()
+	appLogger.Infof("Lock de importação de %s obtido por '%s'.", fileType, heldBy)
+	return lock, nil
+}
+
+// tryAdvisoryLock tenta obter o advisory lock do tipo de arquivo numa conexão dedicada, que fica
+// reservada até a liberação do lock (o advisory lock pertence à sessão do PostgreSQL).
+func (r *gormImportLockRepository) tryAdvisoryLock(fileType string) (*sql.Conn, error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return nil, appErrors.WrapErrorf(err, "falha ao obter conexão para o lock de importação")
+	}
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		appLogger.Errorf("Erro ao reservar conexão para o lock de importação de %s: %v", fileType, err)
+		return nil, appErrors.WrapErrorf(err, "falha ao reservar conexão para o lock de importação")
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", importLockKey(fileType)).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		appLogger.Errorf("Erro ao obter advisory lock de importação de %s: %v", fileType, err)
+		return nil, appErrors.WrapErrorf(err, "falha ao obter lock de importação (PostgreSQL)")
+	}
+	if !acquired {
+		_ = conn.Close()
+		holder, _ := r.Get(fileType) // Apenas para a mensagem; o advisory lock é que garante a exclusão.
+		return nil, importLockedError(fileType, holder)
+	}
+	return conn, nil
+}
+
+// claimRow grava a linha do lock. Uma linha vencida é descartada antes (lock abandonado); com o
+// advisory lock, qualquer linha anterior é descartada, pois o advisory lock já garante a exclusão.
+func (r *gormImportLockRepository) claimRow(row *models.DBImportLock, advisory bool) error {
+	staleQuery := r.db.Where("file_type = ?", row.FileType)
+	if !advisory {
+		staleQuery = staleQuery.Where("expires_at < ?", row.AcquiredAt)
+	}
+	if result := staleQuery.Delete(&models.DBImportLock{}); result.Error != nil {
+		if isSQLiteBusy(result.Error) {
+			return r.busyLockError(row.FileType)
+		}
+		appLogger.Errorf("Erro ao descartar lock de importação vencido de %s: %v", row.FileType, result.Error)
+		return appErrors.WrapErrorf(result.Error, "falha ao descartar lock de importação vencido (GORM)")
+	} else if result.RowsAffected > 0 {
+		appLogger.Warnf("Lock de importação de %s abandonado por outra sessão foi descartado.", row.FileType)
+	}
+
+	if err := r.db.Create(row).Error; err != nil {
+		if isSQLiteBusy(err) {
+			return r.busyLockError(row.FileType)
+		}
+		// Chave primária duplicada: outra importação obteve o lock primeiro.
+		if holder, getErr := r.Get(row.FileType); getErr == nil && holder != nil {
+			return importLockedError(row.FileType, holder)
+		}
+		appLogger.Errorf("Erro ao registrar lock de importação de %s: %v", row.FileType, err)
+		return appErrors.WrapErrorf(err, "falha ao registrar lock de importação (GORM)")
+	}
+	return nil
+}
+
+// Get retorna o detentor atual do lock do tipo de arquivo (nil se livre ou vencido).
+func (r *gormImportLockRepository) Get(fileType string) (*models.DBImportLock, error) {
+	lock, err := r.getRow(fileType)
+	if err != nil || lock == nil {
+		return nil, err
+	}
+	if !lock.ExpiresAt.Before(time.Now().UTC()) {
+		return lock, nil
+	}
+	if r.usesAdvisoryLock() {
+		return nil, nil // No PostgreSQL a renovação em segundo plano não é bloqueada pela importação.
+	}
+	live, err := r.hasLiveRun(lock)
+	if err != nil || !live {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// getRow busca a linha do lock do tipo de arquivo, vencida ou não (nil se não existir).
+func (r *gormImportLockRepository) getRow(fileType string) (*models.DBImportLock, error) {
+	var lock models.DBImportLock
+	err := r.db.Where("file_type = ?", strings.ToUpper(fileType)).First(&lock).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		appLogger.Errorf("Erro ao buscar lock de importação de %s: %v", fileType, err)
+		return nil, appErrors.WrapErrorf(err, "falha ao buscar lock de importação (GORM)")
+	}
+	return &lock, nil
+}
+
+// hasLiveRun indica se a importação que obteve o lock ainda está em andamento no histórico
+// (execução `RUNNING` do mesmo tipo e usuário, iniciada após a obtenção do lock).
+func (r *gormImportLockRepository) hasLiveRun(lock *models.DBImportLock) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.DBImportRun{}).
+		Where("file_type = ? AND imported_by = ? AND status = ? AND started_at >= ?",
+			lock.FileType, lock.HeldBy, models.ImportRunStatusRunning, lock.AcquiredAt).
+		Count(&count).Error
+	if err != nil {
+		appLogger.Errorf("Erro ao verificar execução em andamento do lock de importação de %s: %v", lock.FileType, err)
+		return false, appErrors.WrapErrorf(err, "falha ao verificar execução em andamento do lock de importação (GORM)")
+	}
+	return count > 0, nil
+}
+
+// busyLockError monta o erro de lock ocupado quando o SQLite recusa a escrita na tabela de locks por
+// estar bloqueado (`database is locked`): outra importação detém a escrita no banco. O detentor é lido
+// da linha do lock, mesmo vencida, pois a renovação feita na transação da outra importação ainda não
+// é visível.
+func (r *gormImportLockRepository) busyLockError(fileType string) error {
+	holder, err := r.getRow(fileType)
+	if err != nil {
+		holder = nil // Apenas para a mensagem.
+	}
+	appLogger.Warnf("Banco bloqueado por outra escrita ao obter o lock de importação de %s; tratado como importação em andamento.", fileType)
+	return importLockedError(fileType, holder)
+}
+
+// isSQLiteBusy indica se o erro é o SQLITE_BUSY (`database is locked`), retornado quando outra
+// conexão detém a escrita no banco além do `busy_timeout` (ex: a transação de uma importação).
+func isSQLiteBusy(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "database is locked")
+}
+
+// importLockedError monta o erro de lock ocupado (`holder` pode ser nil se o detentor não for conhecido).
+func importLockedError(fileType string, holder *models.DBImportLock) error {
+	lockedErr := &models.ImportLockedError{FileType: fileType}
+	if holder != nil {
+		lockedErr.HeldBy = holder.HeldBy
+		lockedErr.HostName = holder.HostName
+		lockedErr.AcquiredAt = holder.AcquiredAt
+	}
+	return lockedErr
+}
+
+// gormImportLock é um lock obtido por gormImportLockRepository, renovado em segundo plano até Release.
+type gormImportLock struct {
+	repo     *gormImportLockRepository
+	fileType string
+	token    string
+	conn     *sql.Conn // Conexão que detém o advisory lock (nil no SQLite).
+
+	renewMu   sync.Mutex
+	renewedAt time.Time // Última renovação feita por RenewTx.
+
+	stop        chan struct{}
+	done        chan struct{}
+	releaseOnce sync.Once
+}
+
+// keepAlive renova a validade da linha do lock até Release.
+func (l *gormImportLock) keepAlive() {
+	defer close(l.done)
+	ticker := time.NewTicker(importLockRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			result := l.repo.db.Model(&models.DBImportLock{}).
+				Where("file_type = ? AND token = ?", l.fileType, l.token).
+				Update("expires_at", time.Now().UTC().Add(importLockTTL))
+			if isSQLiteBusy(result.Error) {
+				// A importação detém a escrita no banco; a renovação é feita por RenewTx na própria transação.
+				appLogger.Debugf("Renovação do lock de importação de %s adiada: banco em uso pela importação.", l.fileType)
+			} else if result.Error != nil {
+				appLogger.Warnf("Erro ao renovar lock de importação de %s: %v", l.fileType, result.Error)
+			} else if result.RowsAffected == 0 && l.conn == nil {
+				appLogger.Errorf("Lock de importação de %s venceu e foi tomado por outra sessão durante a importação.", l.fileType)
+			}
+			if l.conn != nil {
+				if err := l.conn.PingContext(context.Background()); err != nil {
+					appLogger.Errorf("Conexão que detém o lock de importação de %s foi perdida: %v", l.fileType, err)
+				}
+			}
+		}
+	}
+}
+
+// RenewTx renova a validade do lock na transação `tx`, se a última renovação feita por ela tiver
+// mais de `importLockRefreshInterval`.
+func (l *gormImportLock) RenewTx(tx *gorm.DB) error {
+	l.renewMu.Lock()
+	defer l.renewMu.Unlock()
+	now := time.Now().UTC()
+	if now.Sub(l.renewedAt) < importLockRefreshInterval {
+		return nil
+	}
+	err := tx.Model(&models.DBImportLock{}).
+		Where("file_type = ? AND token = ?", l.fileType, l.token).
+		Update("expires_at", now.Add(importLockTTL)).Error
+	if err != nil {
+		return appErrors.WrapErrorf(err, "falha ao renovar lock de importação na transação (GORM)")
+	}
+	l.renewedAt = now
+	return nil
+}
+
+// Release libera o lock.
+func (l *gormImportLock) Release() {
+	l.releaseOnce.Do(func() {
+		close(l.stop)
+		<-l.done
+		if err := l.repo.db.Where("file_type = ? AND token = ?", l.fileType, l.token).Delete(&models.DBImportLock{}).Error; err != nil {
+			appLogger.Warnf("Erro ao remover lock de importação de %s (vencerá em %s): %v", l.fileType, importLockTTL, err)
+		}
+		l.unlockAdvisory()
+		appLogger.Infof("Lock de importação de %s liberado.", l.fileType)
+	})
+}
+
+// unlockAdvisory libera o advisory lock e devolve a conexão reservada (sem efeito no SQLite).
+func (l *gormImportLock) unlockAdvisory() {
+	if l.conn == nil {
+		return
+	}
+	if _, err := l.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", importLockKey(l.fileType)); err != nil {
+		appLogger.Warnf("Erro ao liberar advisory lock de importação de %s; a conexão será descartada: %v", l.fileType, err)
+		// Descarta a conexão em vez de devolvê-la ao pool: encerrar a sessão libera o advisory lock.
+		_ = l.conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+	}
+	if err := l.conn.Close(); err != nil {
+		appLogger.Warnf("Erro ao devolver conexão do lock de importação de %s: %v", l.fileType, err)
+	}
+	l.conn = nil
+}
@@ -94,6 +94,7 @@ func (r *gormImportMetadataRepository) Upsert(upsertData models.ImportMetadataUp
 		OriginalFilename: upsertData.OriginalFilename,
 		RecordCount:      upsertData.RecordCount,
 		ImportedBy:       upsertData.ImportedBy,
+		Companies:        upsertData.Companies,
 	}
 
 	// GORM Upsert:
@@ -103,7 +104,7 @@ func (r *gormImportMetadataRepository) Upsert(upsertData models.ImportMetadataUp
 	//   `created_at` (se existisse no modelo e fosse `autoCreateTime`) também seria implicitamente excluído da atualização.
 	result := r.db.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "file_type"}}, // A coluna que tem a constraint UNIQUE
-		DoUpdates: clause.AssignmentColumns([]string{"last_updated_at", "original_filename", "record_count", "imported_by"}),
+		DoUpdates: clause.AssignmentColumns([]string{"last_updated_at", "original_filename", "record_count", "imported_by", "companies"}),
 	}).Create(&metadataToPersist) // `Create` tentará inserir; se houver conflito em `file_type`, fará o update.
 
 	if result.Error != nil {
@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+)
+
+// ImportProfileRepository define a interface para operações nos perfis de mapeamento de colunas da importação.
+type ImportProfileRepository interface {
+	// GetAll busca os perfis (com suas colunas), ordenados por tipo de arquivo e nome.
+	// Se `fileType` não for vazio, retorna apenas os perfis do tipo (case-insensitive).
+	GetAll(fileType string) ([]models.DBImportProfile, error)
+
+	// GetByID busca um perfil pelo ID, com suas colunas.
+	GetByID(id uint64) (*models.DBImportProfile, error)
+
+	// Create cria um perfil e suas colunas. O ID gerado é preenchido em `profile`.
+	// Se o perfil for o padrão do tipo, os demais perfis do tipo deixam de ser padrão.
+	Create(profile *models.DBImportProfile) error
+
+	// Update grava os campos de um perfil existente e substitui todas as suas colunas.
+	// Se o perfil for o padrão do tipo, os demais perfis do tipo deixam de ser padrão.
+	Update(profile *models.DBImportProfile) error
+
+	// Delete exclui um perfil e suas colunas.
+	Delete(id uint64) error
+}
+
+// gormImportProfileRepository é a implementação GORM de ImportProfileRepository.
+type gormImportProfileRepository struct {
+	db *gorm.DB
+}
+
+// NewGormImportProfileRepository cria uma nova instância de gormImportProfileRepository.
+func NewGormImportProfileRepository(db *gorm.DB) ImportProfileRepository {
+	if db == nil {
+		appLogger.Fatalf("gorm.DB não pode ser nil para NewGormImportProfileRepository")
+	}
+	return &gormImportProfileRepository{db: db}
+}
+
+// isUniqueViolation indica se o erro do banco é uma violação de constraint UNIQUE.
+func isUniqueViolation(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "unique constraint") || strings.Contains(msg, "duplicate key value")
+}
+
+// GetAll busca os perfis de importação, opcionalmente filtrados por tipo de arquivo.
+func (r *gormImportProfileRepository) GetAll(fileType string) ([]models.DBImportProfile, error) {
+	var profiles []models.DBImportProfile
+	query := r.db.Preload("Columns", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") })
+	if trimmed := strings.TrimSpace(fileType); trimmed != "" {
+		query = query.Where("file_type = ?", strings.ToUpper(trimmed))
+	}
+	if err := query.Order("file_type ASC").Order("name ASC").Find(&profiles).Error; err != nil {
+		appLogger.Errorf("Erro ao buscar perfis de importação (Tipo: '%s'): %v", fileType, err)
+		return nil, appErrors.WrapErrorf(err, "falha ao buscar perfis de importação (GORM)")
+	}
+	return profiles, nil
+}
+
+// GetByID busca um perfil de importação pelo ID.
+func (r *gormImportProfileRepository) GetByID(id uint64) (*models.DBImportProfile, error) {
+	var profile models.DBImportProfile
+	err := r.db.Preload("Columns", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).First(&profile, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: perfil de importação com ID %d não encontrado", appErrors.ErrNotFound, id)
+		}
+		appLogger.Errorf("Erro ao buscar perfil de importação ID %d: %v", id, err)
+		return nil, appErrors.WrapErrorf(err, "falha ao buscar perfil de importação (GORM)")
+	}
+	return &profile, nil
+}
+
+// Create cria um perfil de importação com suas colunas.
+func (r *gormImportProfileRepository) Create(profile *models.DBImportProfile) error {
+	if profile == nil {
+		return fmt.Errorf("%w: perfil de importação nulo para Create", appErrors.ErrInvalidInput)
+	}
+	txErr := r.db.Transaction(func(tx *gorm.DB) error {
+		if profile.IsDefault {
+			if err := clearDefaultImportProfile(tx, profile.FileType, 0); err != nil {
+				return err
+			}
+		}
+		// As colunas são criadas pelo GORM junto com o perfil (associação `Columns`).
+		if err := tx.Create(profile).Error; err != nil {
+			if isUniqueViolation(err) {
+				return fmt.Errorf("%w: já existe um perfil de importação '%s' para o tipo %s", appErrors.ErrConflict, profile.Name, profile.FileType)
+			}
+			return appErrors.WrapErrorf(err, "falha ao criar perfil de importação (GORM)")
+		}
+		return nil
+	})
+	if txErr != nil {
+		appLogger.Errorf("Erro na transação de criação do perfil de importação '%s' (Tipo: %s): %v", profile.Name, profile.FileType, txErr)
+		return txErr
+	}
+	appLogger.Infof("Perfil de importação criado: '%s' (ID: %d, Tipo: %s, %d colunas configuradas).", profile.Name, profile.ID, profile.FileType, len(profile.Columns))
+	return nil
+}
+
+// Update grava um perfil de importação existente, substituindo suas colunas.
+func (r *gormImportProfileRepository) Update(profile *models.DBImportProfile) error {
+	if profile == nil || profile.ID == 0 {
+		return fmt.Errorf("%w: perfil de importação sem ID para Update", appErrors.ErrInvalidInput)
+	}
+	txErr := r.db.Transaction(func(tx *gorm.DB) error {
+		if profile.IsDefault {
+			if err := clearDefaultImportProfile(tx, profile.FileType, profile.ID); err != nil {
+				return err
+			}
+		}
+		result := tx.Model(&models.DBImportProfile{ID: profile.ID}).
+			Select("name", "file_type", "decimal_separator", "date_formats", "is_default", "updated_by", "updated_at").
+			Updates(profile)
+		if result.Error != nil {
+			if isUniqueViolation(result.Error) {
+				return fmt.Errorf("%w: já existe um perfil de importação '%s' para o tipo %s", appErrors.ErrConflict, profile.Name, profile.FileType)
+			}
+			return appErrors.WrapErrorf(result.Error, "falha ao atualizar perfil de importação (GORM)")
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: perfil de importação com ID %d não encontrado", appErrors.ErrNotFound, profile.ID)
+		}
+
+		if err := tx.Where("profile_id = ?", profile.ID).Delete(&models.DBImportProfileColumn{}).Error; err != nil {
+			return appErrors.WrapErrorf(err, "falha ao remover colunas antigas do perfil de importação (GORM)")
+		}
+		for i := range profile.Columns {
+			profile.Columns[i].ID = 0
+			profile.Columns[i].ProfileID = profile.ID
+		}
+		if len(profile.Columns) > 0 {
+			if err := tx.Create(&profile.Columns).Error; err != nil {
+				return appErrors.WrapErrorf(err, "falha ao gravar colunas do perfil de importação (GORM)")
+			}
+		}
+		return nil
+	})
+	if txErr != nil {
+		appLogger.Errorf("Erro na transação de atualização do perfil de importação ID %d: %v", profile.ID, txErr)
+		return txErr
+	}
+	appLogger.Infof("Perfil de importação atualizado: '%s' (ID: %d, Tipo: %s).", profile.Name, profile.ID, profile.FileType)
+	return nil
+}
+
+// Delete exclui um perfil de importação e suas colunas.
+func (r *gormImportProfileRepository) Delete(id uint64) error {
+	txErr := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("profile_id = ?", id).Delete(&models.DBImportProfileColumn{}).Error; err != nil {
+			return appErrors.WrapErrorf(err, "falha ao excluir colunas do perfil de importação (GORM)")
+		}
+		result := tx.Delete(&models.DBImportProfile{}, id)
+		if result.Error != nil {
+			return appErrors.WrapErrorf(result.Error, "falha ao excluir perfil de importação (GORM)")
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: perfil de importação com ID %d não encontrado", appErrors.ErrNotFound, id)
+		}
+		return nil
+	})
+	if txErr != nil {
+		appLogger.Errorf("Erro ao excluir perfil de importação ID %d: %v", id, txErr)
+		return txErr
+	}
+	appLogger.Infof("Perfil de importação ID %d excluído.", id)
+	return nil
+}
+
+// clearDefaultImportProfile desmarca o perfil padrão do tipo de arquivo, exceto `keepID`.
+func clearDefaultImportProfile(tx *gorm.DB, fileType string, keepID uint64) error {
+	err := tx.Model(&models.DBImportProfile{}).
+		Where("file_type = ? AND is_default = ? AND id <> ?", fileType, true, keepID).
+		Update("is_default", false).Error
+	if err != nil {
+		return appErrors.WrapErrorf(err, "falha ao desmarcar perfil de importação padrão (GORM)")
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+)
+
+// ImportRunRepository define a interface para operações no histórico de execuções de importação.
+type ImportRunRepository interface {
+	// Create registra uma nova execução. O ID gerado é preenchido em `run`.
+	Create(run *models.DBImportRun) error
+
+	// Update grava todos os campos de uma execução existente (ex: ao finalizar a importação).
+	Update(run *models.DBImportRun) error
+
+	// GetByID busca uma execução pelo ID.
+	GetByID(id uint64) (*models.DBImportRun, error)
+
+	// GetFiltered busca execuções com base nos filtros, ordenadas das mais recentes para as mais antigas.
+	// Retorna as execuções da página, a contagem total que corresponde aos filtros, e um erro.
+	GetFiltered(filter models.ImportRunFilter) (runs []models.DBImportRun, totalCount int64, err error)
+
+	// SaveNetworkTotals grava (substituindo os anteriores) os saldos em aberto por rede da execução.
+	SaveNetworkTotals(runID uint64, totals []models.DBImportRunNetworkTotal) error
+
+	// GetNetworkTotals busca os saldos em aberto por rede gravados para a execução.
+	GetNetworkTotals(runID uint64) ([]models.DBImportRunNetworkTotal, error)
+}
+
+// gormImportRunRepository é a implementação GORM de ImportRunRepository.
+type gormImportRunRepository struct {
+	db *gorm.DB
+}
+
+// NewGormImportRunRepository cria uma nova instância de gormImportRunRepository.
+func NewGormImportRunRepository(db *gorm.DB) ImportRunRepository {
+	if db == nil {
+		appLogger.Fatalf("gorm.DB não pode ser nil para NewGormImportRunRepository")
+	}
+	return &gormImportRunRepository{db: db}
+}
+
+// Create registra uma nova execução de importação.
+func (r *gormImportRunRepository) Create(run *models.DBImportRun) error {
+	if run == nil {
+		return fmt.Errorf("%w: execução de importação nula para Create", appErrors.ErrInvalidInput)
+	}
+	if run.StartedAt.IsZero() {
+		run.StartedAt = time.Now().UTC()
+	}
+	if err := r.db.Create(run).Error; err != nil {
+		appLogger.Errorf("Erro ao registrar execução de importação (Tipo: %s, Arquivo: %s): %v", run.FileType, run.OriginalFilename, err)
+		return appErrors.WrapErrorf(err, "falha ao registrar execução de importação (GORM)")
+	}
+	return nil
+}
+
+// Update grava todos os campos de uma execução existente.
+func (r *gormImportRunRepository) Update(run *models.DBImportRun) error {
+	if run == nil || run.ID == 0 {
+		return fmt.Errorf("%w: execução de importação sem ID para Update", appErrors.ErrInvalidInput)
+	}
+	if err := r.db.Save(run).Error; err != nil {
+		appLogger.Errorf("Erro ao atualizar execução de importação ID %d: %v", run.ID, err)
+		return appErrors.WrapErrorf(err, "falha ao atualizar execução de importação (GORM)")
+	}
+	return nil
+}
+
+// GetByID busca uma execução de importação pelo ID.
+func (r *gormImportRunRepository) GetByID(id uint64) (*models.DBImportRun, error) {
+	var run models.DBImportRun
+	if err := r.db.First(&run, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: execução de importação com ID %d não encontrada", appErrors.ErrNotFound, id)
+		}
+		appLogger.Errorf("Erro ao buscar execução de importação ID %d: %v", id, err)
+		return nil, appErrors.WrapErrorf(err, "falha ao buscar execução de importação (GORM)")
+	}
+	return &run, nil
+}
+
+// GetFiltered busca execuções de importação com base nos filtros fornecidos, com paginação.
+func (r *gormImportRunRepository) GetFiltered(filter models.ImportRunFilter) ([]models.DBImportRun, int64, error) {
+	filter.Normalize()
+	var runs []models.DBImportRun
+	var totalCount int64
+
+	query := r.db.Model(&models.DBImportRun{})
+
+	if filter.FileType != nil {
+		query = query.Where("file_type = ?", *filter.FileType)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+	if filter.ImportedBy != nil {
+		query = query.Where("LOWER(imported_by) = LOWER(?)", *filter.ImportedBy)
+	}
+	if filter.Filename != nil {
+		query = query.Where("LOWER(original_filename) LIKE LOWER(?)", "%"+*filter.Filename+"%")
+	}
+	if filter.FileSHA256 != nil {
+		query = query.Where("file_sha256 = ?", *filter.FileSHA256)
+	}
+	if filter.StartDate != nil {
+		startOfDay := time.Date(filter.StartDate.Year(), filter.StartDate.Month(), filter.StartDate.Day(), 0, 0, 0, 0, filter.StartDate.Location())
+		query = query.Where("started_at >= ?", startOfDay)
+	}
+	if filter.EndDate != nil {
+		endOfDay := time.Date(filter.EndDate.Year(), filter.EndDate.Month(), filter.EndDate.Day(), 23, 59, 59, 999999999, filter.EndDate.Location())
+		query = query.Where("started_at <= ?", endOfDay)
+	}
+
+	// Contagem total antes da paginação.
+	if err := query.Count(&totalCount).Error; err != nil {
+		appLogger.Errorf("Erro ao contar execuções de importação filtradas: %v", err)
+		return nil, 0, appErrors.WrapErrorf(err, "falha ao contar execuções de importação (GORM)")
+	}
+	if totalCount == 0 {
+		return []models.DBImportRun{}, 0, nil
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 50 // Limite padrão.
+	} else if limit > 500 {
+		limit = 500 // Limite máximo.
+	}
+	offset := filter.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	if err := query.Order("started_at DESC").Order("id DESC").Limit(limit).Offset(offset).Find(&runs).Error; err != nil {
+		appLogger.Errorf("Erro ao buscar execuções de importação filtradas: %v", err)
+		return nil, 0, appErrors.WrapErrorf(err, "falha ao buscar execuções de importação (GORM)")
+	}
+	return runs, totalCount, nil
+}
+
+// SaveNetworkTotals substitui, em uma transação, os saldos por rede gravados para a execução.
+func (r *gormImportRunRepository) SaveNetworkTotals(runID uint64, totals []models.DBImportRunNetworkTotal) error {
+	if runID == 0 {
+		return fmt.Errorf("%w: execução de importação sem ID para SaveNetworkTotals", appErrors.ErrInvalidInput)
+	}
+	for i := range totals {
+		totals[i].ID = 0
+		totals[i].ImportRunID = runID
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("import_run_id = ?", runID).Delete(&models.DBImportRunNetworkTotal{}).Error; err != nil {
+			return err
+		}
+		if len(totals) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(totals, 500).Error
+	})
+	if err != nil {
+		appLogger.Errorf("Erro ao gravar os saldos por rede da execução de importação ID %d: %v", runID, err)
+		return appErrors.WrapErrorf(err, "falha ao gravar os saldos por rede da execução de importação (GORM)")
+	}
+	return nil
+}
+
+// GetNetworkTotals busca os saldos por rede gravados para a execução.
+func (r *gormImportRunRepository) GetNetworkTotals(runID uint64) ([]models.DBImportRunNetworkTotal, error) {
+	var totals []models.DBImportRunNetworkTotal
+	if err := r.db.Where("import_run_id = ?", runID).Find(&totals).Error; err != nil {
+		appLogger.Errorf("Erro ao buscar os saldos por rede da execução de importação ID %d: %v", runID, err)
+		return nil, appErrors.WrapErrorf(err, "falha ao buscar os saldos por rede da execução de importação (GORM)")
+	}
+	return totals, nil
+}
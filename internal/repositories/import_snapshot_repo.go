@@ -0,0 +1,294 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+)
+
+// ImportSnapshotRepository define a interface para operações nos snapshots (versões guardadas)
+// das tabelas de títulos.
+type ImportSnapshotRepository interface {
+	// CaptureTx retorna a ação que copia os títulos do tipo `snapshot.FileType` para um novo snapshot
+	// e exclui os snapshots mais antigos do tipo, mantendo apenas os `keep` mais recentes. Deve ser
+	// usada como `ImportTxHooks.BeforeCommit`, de modo que o snapshot seja gravado na mesma transação
+	// da importação: se a cópia falhar, a importação é desfeita. A cópia é feita no próprio banco
+	// (INSERT ... SELECT para uma tabela da versão). O ID, o `RecordCount` e a `DataTable` são
+	// preenchidos em `snapshot` quando a ação é executada.
+	CaptureTx(snapshot *models.DBImportSnapshot, keep int) func(tx *gorm.DB) error
+
+	// GetAll busca os snapshots de um tipo de arquivo (case-insensitive), do mais recente para o mais antigo.
+	GetAll(fileType string) ([]models.DBImportSnapshot, error)
+
+	// GetByID busca um snapshot pelo ID.
+	GetByID(id uint64) (*models.DBImportSnapshot, error)
+
+	// Restore substitui, em uma única transação, todos os títulos do tipo do snapshot pelos títulos
+	// guardados nele. Retorna o snapshot restaurado e o número de títulos gravados.
+	Restore(id uint64, restoredBy string) (snapshot *models.DBImportSnapshot, restoredCount int, err error)
+
+	// ForEachBalance percorre em lotes os saldos dos títulos não removidos guardados no snapshot,
+	// no mesmo formato de `TituloDireitoRepository.ForEachBalance`.
+	ForEachBalance(id uint64, fn func(batch []models.TituloBalance) error) error
+}
+
+// gormImportSnapshotRepository é a implementação GORM de ImportSnapshotRepository.
+type gormImportSnapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewGormImportSnapshotRepository cria uma nova instância de gormImportSnapshotRepository.
+func NewGormImportSnapshotRepository(db *gorm.DB) ImportSnapshotRepository {
+	if db == nil {
+		appLogger.Fatalf("gorm.DB não pode ser nil para NewGormImportSnapshotRepository")
+	}
+	return &gormImportSnapshotRepository{db: db}
+}
+
+// snapshotTableOps reúne os dados da tabela de títulos de um tipo de arquivo usados pelos snapshots.
+type snapshotTableOps struct {
+	tableName  string
+	cols       tituloColumns
+	tableLabel string // Usado nos logs (ex: "títulos de direitos").
+}
+
+// getSnapshotTableOps retorna os dados da tabela de títulos do tipo de arquivo
+// (os mesmos valores de `services.FileType`).
+func getSnapshotTableOps(fileType string) (*snapshotTableOps, error) {
+	switch strings.ToUpper(strings.TrimSpace(fileType)) {
+	case "DIREITOS":
+		return &snapshotTableOps{tableName: models.DBTituloDireito{}.TableName(), cols: tituloDireitoColumns, tableLabel: "títulos de direitos"}, nil
+	case "OBRIGACOES":
+		return &snapshotTableOps{tableName: models.DBTituloObrigacao{}.TableName(), cols: tituloObrigacaoColumns, tableLabel: "títulos de obrigações"}, nil
+	default:
+		return nil, fmt.Errorf("%w: tipo de arquivo '%s' não suporta snapshots", appErrors.ErrInvalidInput, fileType)
+	}
+}
+
+// snapshotDataTable retorna o nome da tabela com os títulos do snapshot.
+func snapshotDataTable(snapshotID uint64, sourceTable string) string {
+	return fmt.Sprintf("import_snapshot_%d_%s", snapshotID, sourceTable)
+}
+
+// sqliteCreateTablePrefix casa o início da instrução CREATE TABLE guardada no `sqlite_master`.
+var sqliteCreateTablePrefix = regexp.MustCompile("^CREATE TABLE\\s+[`\"]?\\w+[`\"]?")
+
+// createSnapshotTable cria `target` com as mesmas colunas (e tipos) de `source`, sem os índices.
+// No PostgreSQL usa `CREATE TABLE ... (LIKE ...)`; no SQLite, a definição guardada no `sqlite_master`,
+// que preserva os tipos declarados usados pelo driver para converter as datas.
+func createSnapshotTable(tx *gorm.DB, source, target string) error {
+	if tx.Dialector.Name() == "postgres" {
+		return tx.Exec("CREATE TABLE ? (LIKE ? INCLUDING DEFAULTS)", clause.Table{Name: target}, clause.Table{Name: source}).Error
+	}
+	var ddl string
+	if err := tx.Raw("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", source).Scan(&ddl).Error; err != nil {
+		return err
+	}
+	if !sqliteCreateTablePrefix.MatchString(ddl) {
+		return fmt.Errorf("%w: definição da tabela %s não encontrada", appErrors.ErrDatabase, source)
+	}
+	return tx.Exec(sqliteCreateTablePrefix.ReplaceAllLiteralString(ddl, "CREATE TABLE "+tx.Statement.Quote(target))).Error
+}
+
+// CaptureTx retorna a ação que grava um novo snapshot com os títulos atuais e aplica a retenção do tipo.
+func (r *gormImportSnapshotRepository) CaptureTx(snapshot *models.DBImportSnapshot, keep int) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		if snapshot == nil {
+			return fmt.Errorf("%w: snapshot nulo para CaptureTx", appErrors.ErrInvalidInput)
+		}
+		snapshot.FileType = strings.ToUpper(strings.TrimSpace(snapshot.FileType))
+		ops, err := getSnapshotTableOps(snapshot.FileType)
+		if err != nil {
+			return err
+		}
+
+		snapshot.ID = 0
+		if err := tx.Create(snapshot).Error; err != nil {
+			appLogger.Errorf("Erro ao criar snapshot dos títulos do tipo %s (Arquivo: '%s'): %v", snapshot.FileType, snapshot.OriginalFilename, err)
+			return appErrors.WrapErrorf(err, "falha ao criar snapshot de importação (GORM)")
+		}
+		dataTable := snapshotDataTable(snapshot.ID, ops.tableName)
+		if err := createSnapshotTable(tx, ops.tableName, dataTable); err != nil {
+			appLogger.Errorf("Erro ao criar a tabela %s do snapshot de importação ID %d: %v", dataTable, snapshot.ID, err)
+			return appErrors.WrapErrorf(err, "falha ao criar a tabela do snapshot de importação")
+		}
+		result := tx.Exec("INSERT INTO ? SELECT * FROM ?", clause.Table{Name: dataTable}, clause.Table{Name: ops.tableName})
+		if result.Error != nil {
+			appLogger.Errorf("Erro ao copiar %s para o snapshot de importação ID %d: %v", ops.tableLabel, snapshot.ID, result.Error)
+			return appErrors.WrapErrorf(result.Error, "falha ao copiar %s para o snapshot de importação (GORM)", ops.tableLabel)
+		}
+
+		snapshot.RecordCount = int(result.RowsAffected)
+		snapshot.DataTable = dataTable
+		if err := tx.Model(snapshot).Select("record_count", "data_table").Updates(snapshot).Error; err != nil {
+			return appErrors.WrapErrorf(err, "falha ao atualizar o snapshot de importação (GORM)")
+		}
+		if err := pruneImportSnapshots(tx, snapshot.FileType, keep); err != nil {
+			appLogger.Errorf("Erro ao excluir snapshots antigos de importação do tipo %s: %v", snapshot.FileType, err)
+			return err
+		}
+		appLogger.Infof("Snapshot de importação criado: ID %d (Tipo: %s, %d títulos).", snapshot.ID, snapshot.FileType, snapshot.RecordCount)
+		return nil
+	}
+}
+
+// pruneImportSnapshots exclui os snapshots do tipo além dos `keep` mais recentes, com as suas tabelas.
+func pruneImportSnapshots(tx *gorm.DB, fileType string, keep int) error {
+	if keep < 1 {
+		keep = 1
+	}
+	var old []models.DBImportSnapshot
+	err := tx.Where("file_type = ?", fileType).
+		Order("created_at DESC").Order("id DESC").
+		Offset(keep).
+		Find(&old).Error
+	if err != nil {
+		return appErrors.WrapErrorf(err, "falha ao buscar snapshots antigos de importação (GORM)")
+	}
+	if len(old) == 0 {
+		return nil
+	}
+	oldIDs := make([]uint64, len(old))
+	for i := range old {
+		oldIDs[i] = old[i].ID
+		if old[i].DataTable == "" {
+			continue
+		}
+		if err := tx.Migrator().DropTable(old[i].DataTable); err != nil {
+			return appErrors.WrapErrorf(err, "falha ao excluir a tabela %s de snapshot antigo de importação (GORM)", old[i].DataTable)
+		}
+	}
+	if err := tx.Where("id IN ?", oldIDs).Delete(&models.DBImportSnapshot{}).Error; err != nil {
+		return appErrors.WrapErrorf(err, "falha ao excluir snapshots antigos de importação (GORM)")
+	}
+	appLogger.Infof("%d snapshots antigos de importação do tipo %s excluídos (retenção: %d).", len(old), fileType, keep)
+	return nil
+}
+
+// GetAll busca os snapshots de um tipo de arquivo.
+func (r *gormImportSnapshotRepository) GetAll(fileType string) ([]models.DBImportSnapshot, error) {
+	var snapshots []models.DBImportSnapshot
+	err := r.db.Where("file_type = ?", strings.ToUpper(strings.TrimSpace(fileType))).
+		Order("created_at DESC").Order("id DESC").
+		Find(&snapshots).Error
+	if err != nil {
+		appLogger.Errorf("Erro ao buscar snapshots de importação (Tipo: '%s'): %v", fileType, err)
+		return nil, appErrors.WrapErrorf(err, "falha ao buscar snapshots de importação (GORM)")
+	}
+	return snapshots, nil
+}
+
+// GetByID busca um snapshot de importação pelo ID.
+func (r *gormImportSnapshotRepository) GetByID(id uint64) (*models.DBImportSnapshot, error) {
+	var snapshot models.DBImportSnapshot
+	if err := r.db.First(&snapshot, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: snapshot de importação com ID %d não encontrado", appErrors.ErrNotFound, id)
+		}
+		appLogger.Errorf("Erro ao buscar snapshot de importação ID %d: %v", id, err)
+		return nil, appErrors.WrapErrorf(err, "falha ao buscar snapshot de importação (GORM)")
+	}
+	return &snapshot, nil
+}
+
+// snapshotColumns retorna as colunas da tabela do snapshot que também existem na tabela de títulos
+// (a tabela de títulos pode ter ganhado colunas depois do snapshot), já escapadas para o SQL.
+func snapshotColumns(tx *gorm.DB, sourceTable, dataTable string) ([]string, error) {
+	current, err := tx.Migrator().ColumnTypes(sourceTable)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]struct{}, len(current))
+	for _, col := range current {
+		existing[strings.ToLower(col.Name())] = struct{}{}
+	}
+	saved, err := tx.Migrator().ColumnTypes(dataTable)
+	if err != nil {
+		return nil, err
+	}
+	cols := make([]string, 0, len(saved))
+	for _, col := range saved {
+		if _, ok := existing[strings.ToLower(col.Name())]; ok {
+			cols = append(cols, tx.Statement.Quote(col.Name()))
+		}
+	}
+	return cols, nil
+}
+
+// Restore substitui os títulos do tipo do snapshot pelos títulos guardados nele.
+// Qualquer erro desfaz a transação, preservando os dados atuais.
+func (r *gormImportSnapshotRepository) Restore(id uint64, restoredBy string) (*models.DBImportSnapshot, int, error) {
+	var snapshot models.DBImportSnapshot
+	restoredCount := 0
+	txErr := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&snapshot, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: snapshot de importação com ID %d não encontrado", appErrors.ErrNotFound, id)
+			}
+			return appErrors.WrapErrorf(err, "falha ao buscar snapshot de importação (GORM)")
+		}
+		ops, err := getSnapshotTableOps(snapshot.FileType)
+		if err != nil {
+			return err
+		}
+		if snapshot.DataTable == "" || !tx.Migrator().HasTable(snapshot.DataTable) {
+			return fmt.Errorf("%w: os títulos do snapshot %d não estão mais disponíveis", appErrors.ErrNotFound, snapshot.ID)
+		}
+		cols, err := snapshotColumns(tx, ops.tableName, snapshot.DataTable)
+		if err != nil {
+			return appErrors.WrapErrorf(err, "falha ao ler as colunas do snapshot %d (GORM)", snapshot.ID)
+		}
+
+		if err := tx.Exec("DELETE FROM " + ops.tableName).Error; err != nil {
+			return appErrors.WrapErrorf(err, "falha ao limpar a tabela %s para restaurar o snapshot (GORM)", ops.tableName)
+		}
+		colList := strings.Join(cols, ", ")
+		result := tx.Exec("INSERT INTO " + tx.Statement.Quote(ops.tableName) + " (" + colList + ") SELECT " + colList + " FROM " + tx.Statement.Quote(snapshot.DataTable))
+		if result.Error != nil {
+			return appErrors.WrapErrorf(result.Error, "falha ao inserir títulos restaurados do snapshot (GORM)")
+		}
+		restoredCount = int(result.RowsAffected)
+		if restoredCount != snapshot.RecordCount {
+			return fmt.Errorf("%w: snapshot %d deveria conter %d títulos, mas %d foram lidos", appErrors.ErrDatabase, snapshot.ID, snapshot.RecordCount, restoredCount)
+		}
+
+		now := time.Now().UTC()
+		snapshot.RestoredAt = &now
+		snapshot.RestoredBy = &restoredBy
+		if err := tx.Model(&snapshot).Select("restored_at", "restored_by").Updates(&snapshot).Error; err != nil {
+			return appErrors.WrapErrorf(err, "falha ao registrar a restauração do snapshot (GORM)")
+		}
+		return nil
+	})
+	if txErr != nil {
+		appLogger.Errorf("Erro ao restaurar snapshot de importação ID %d: %v", id, txErr)
+		return nil, 0, txErr
+	}
+	appLogger.Infof("Snapshot de importação ID %d restaurado (Tipo: %s, %d títulos).", snapshot.ID, snapshot.FileType, restoredCount)
+	return &snapshot, restoredCount, nil
+}
+
+// ForEachBalance percorre os saldos dos títulos guardados em um snapshot.
+func (r *gormImportSnapshotRepository) ForEachBalance(id uint64, fn func(batch []models.TituloBalance) error) error {
+	snapshot, err := r.GetByID(id)
+	if err != nil {
+		return err
+	}
+	ops, err := getSnapshotTableOps(snapshot.FileType)
+	if err != nil {
+		return err
+	}
+	if snapshot.DataTable == "" {
+		return fmt.Errorf("%w: os títulos do snapshot %d não estão mais disponíveis", appErrors.ErrNotFound, snapshot.ID)
+	}
+	return forEachTituloBalance(r.db, snapshot.DataTable, ops.cols, fmt.Sprintf("%s do snapshot %d", ops.tableLabel, id), fn)
+}
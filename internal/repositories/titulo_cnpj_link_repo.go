@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+)
+
+// TituloCNPJLinkRepository define a interface para o vínculo dos títulos importados com o cadastro
+// de CNPJs (`DBCNPJ`) e de redes (`DBNetwork`).
+type TituloCNPJLinkRepository interface {
+	// LinkTitles recalcula `cnpj_id` e `network_id` de todos os títulos do tipo de arquivo
+	// ("DIREITOS" ou "OBRIGACOES"), a partir do CNPJ/CPF do título. Retorna quantos títulos
+	// ativos ficaram vinculados a um CNPJ cadastrado.
+	LinkTitles(fileType string) (linkedCount int64, err error)
+
+	// LinkTitlesTx retorna uma ação que recalcula os vínculos como `LinkTitles`, dentro da
+	// transação recebida (ex: `ImportTxHooks.BeforeCommit`, para que o snapshot gravado na
+	// transação da importação já contenha os vínculos).
+	LinkTitlesTx(fileType string) func(tx *gorm.DB) error
+
+	// GetCNPJSummary agrupa os títulos ativos do tipo de arquivo pelo CNPJ/CPF, com a situação
+	// do CNPJ e da rede no cadastro.
+	GetCNPJSummary(fileType string) ([]models.TituloCNPJSummary, error)
+}
+
+// gormTituloCNPJLinkRepository é a implementação GORM de TituloCNPJLinkRepository.
+type gormTituloCNPJLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewGormTituloCNPJLinkRepository cria uma nova instância de gormTituloCNPJLinkRepository.
+func NewGormTituloCNPJLinkRepository(db *gorm.DB) TituloCNPJLinkRepository {
+	if db == nil {
+		appLogger.Fatalf("gorm.DB não pode ser nil para NewGormTituloCNPJLinkRepository")
+	}
+	return &gormTituloCNPJLinkRepository{db: db}
+}
+
+// getTituloTableName retorna a tabela de títulos do tipo de arquivo (os mesmos valores de `services.FileType`).
+func getTituloTableName(fileType string) (string, error) {
+	switch strings.ToUpper(strings.TrimSpace(fileType)) {
+	case "DIREITOS":
+		return models.DBTituloDireito{}.TableName(), nil
+	case "OBRIGACOES":
+		return models.DBTituloObrigacao{}.TableName(), nil
+	default:
+		return "", fmt.Errorf("%w: tipo de arquivo '%s' não possui tabela de títulos", appErrors.ErrInvalidInput, fileType)
+	}
+}
+
+// LinkTitles vincula os títulos do tipo aos CNPJs cadastrados em um único UPDATE.
+// As subconsultas correlacionadas funcionam tanto no SQLite quanto no PostgreSQL.
+func (r *gormTituloCNPJLinkRepository) LinkTitles(fileType string) (int64, error) {
+	if err := r.linkTitles(r.db, fileType); err != nil {
+		return 0, err
+	}
+	tableName, _ := getTituloTableName(fileType) // Já validado por linkTitles.
+
+	var linkedCount int64
+	if err := r.db.Table(tableName).Where("removed_at IS NULL AND cnpj_id IS NOT NULL").Count(&linkedCount).Error; err != nil {
+		appLogger.Errorf("Erro ao contar títulos vinculados da tabela %s: %v", tableName, err)
+		return 0, appErrors.WrapErrorf(err, "falha ao contar títulos vinculados (GORM)")
+	}
+	return linkedCount, nil
+}
+
+// LinkTitlesTx retorna `linkTitles` para execução em uma transação externa.
+func (r *gormTituloCNPJLinkRepository) LinkTitlesTx(fileType string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		return r.linkTitles(tx, fileType)
+	}
+}
+
+// linkTitles executa o UPDATE de vínculo em `tx`.
+func (r *gormTituloCNPJLinkRepository) linkTitles(tx *gorm.DB, fileType string) error {
+	tableName, err := getTituloTableName(fileType)
+	if err != nil {
+		return err
+	}
+	cnpjTable := models.DBCNPJ{}.TableName()
+
+	linkSQL := fmt.Sprintf(
+		"UPDATE %[1]s SET cnpj_id = (SELECT c.id FROM %[2]s c WHERE c.cnpj = %[1]s.cnpjcpf), "+
+			"network_id = (SELECT c.network_id FROM %[2]s c WHERE c.cnpj = %[1]s.cnpjcpf)",
+		tableName, cnpjTable)
+	if err := tx.Exec(linkSQL).Error; err != nil {
+		appLogger.Errorf("Erro ao vincular títulos da tabela %s aos CNPJs cadastrados: %v", tableName, err)
+		return appErrors.WrapErrorf(err, "falha ao vincular títulos aos CNPJs cadastrados (GORM)")
+	}
+	return nil
+}
+
+// GetCNPJSummary agrupa os títulos ativos do tipo por CNPJ/CPF, junto com o cadastro do CNPJ e da rede.
+func (r *gormTituloCNPJLinkRepository) GetCNPJSummary(fileType string) ([]models.TituloCNPJSummary, error) {
+	tableName, err := getTituloTableName(fileType)
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []models.TituloCNPJSummary
+	err = r.db.Table(tableName + " t").
+		Select("t.cnpjcpf AS cnpjcpf, COUNT(*) AS title_count, MAX(t.pessoa) AS pessoa, " +
+			"c.id AS cnpj_id, c.active AS cnpj_active, n.id AS network_id, n.name AS network_name, n.status AS network_status").
+		Joins("LEFT JOIN " + models.DBCNPJ{}.TableName() + " c ON c.cnpj = t.cnpjcpf").
+		Joins("LEFT JOIN " + models.DBNetwork{}.TableName() + " n ON n.id = c.network_id").
+		Where("t.removed_at IS NULL").
+		Group("t.cnpjcpf, c.id, c.active, n.id, n.name, n.status").
+		Order("t.cnpjcpf").
+		Scan(&summaries).Error
+	if err != nil {
+		appLogger.Errorf("Erro ao agrupar títulos da tabela %s por CNPJ/CPF: %v", tableName, err)
+		return nil, appErrors.WrapErrorf(err, "falha ao agrupar títulos por CNPJ/CPF (GORM)")
+	}
+	return summaries, nil
+}
@@ -1,12 +1,16 @@
 package repositories
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/shopspring/decimal" // Para validação e conversão de valores monetários
 	"gorm.io/gorm"
 
@@ -18,16 +22,129 @@ import (
 // TituloDireitoRepository define a interface para operações no repositório de títulos de direitos.
 type TituloDireitoRepository interface {
 	// ReplaceAll substitui TODOS os dados na tabela `titulos_direitos`.
-	// Recebe uma lista de structs `models.TituloDireitoFromRow`, que representam os dados brutos
-	// como lidos do arquivo de importação.
+	// Consome as linhas brutas (`models.TituloDireitoFromRow`) a partir do iterador `next`,
+	// à medida que são lidas do arquivo de importação, inserindo-as em lotes limitados.
 	// Retorna o número de registros efetivamente inseridos, o número de linhas do arquivo
 	// que foram puladas devido a erros de parsing/validação primária, e um erro, se houver.
-	ReplaceAll(rawData []models.TituloDireitoFromRow) (insertedCount int, skippedCount int, err error)
+	// Se `onIssues` não for nil, recebe os problemas de cada linha corrigida com placeholders ou com valores descartados.
+	// As ações de `hooks` são executadas dentro da transação de gravação (ver `ImportTxHooks`).
+	ReplaceAll(next TituloDireitoRowIterator, onIssues RowIssueHandler, hooks ImportTxHooks) (insertedCount int, skippedCount int, err error)
+
+	// ReplaceCompanies substitui apenas os títulos das empresas (NROEMPRESA) presentes no arquivo;
+	// os títulos das demais empresas permanecem intactos. `onIssues` e `hooks` têm o mesmo papel que em `ReplaceAll`.
+	ReplaceCompanies(next TituloDireitoRowIterator, onIssues RowIssueHandler, hooks ImportTxHooks) (result TituloCompanyReplaceResult, err error)
+
+	// UpsertIncremental aplica as linhas do arquivo sem apagar a tabela, casando-as pela chave
+	// natural (CNPJ/CPF + NROEMPRESA + TÍTULO + CODESPÉCIE). Insere títulos novos, atualiza os
+	// alterados e marca como removidos os títulos ausentes do arquivo (se `companiesOnly`, apenas
+	// os das empresas presentes no arquivo). `onIssues` e `hooks` têm o mesmo papel que em `ReplaceAll`.
+	UpsertIncremental(next TituloDireitoRowIterator, onIssues RowIssueHandler, companiesOnly bool, hooks ImportTxHooks) (result TituloIncrementalResult, err error)
+
+	// GetFiltered busca os títulos que correspondem aos filtros, com ordenação e paginação.
+	// Retorna os títulos da página e os totais (contagem, valor nominal e valor pago) de todo o
+	// conjunto filtrado. Títulos removidos só são incluídos com `filter.IncludeRemoved`.
+	GetFiltered(filter models.TituloFilter) (titulos []*models.DBTituloDireito, totals models.TituloTotals, err error)
+
+	// ForEachBalance percorre em lotes os saldos (valor nominal e pago) dos títulos não removidos,
+	// sem carregar a tabela inteira em memória. Um erro retornado por `fn` interrompe a leitura.
+	ForEachBalance(fn func(batch []models.TituloBalance) error) error
 
 	// GetAll (Exemplo, não solicitado, mas comum em repositórios)
 	// GetAll() ([]models.DBTituloDireito, error)
 }
 
+// TituloDireitoRowIterator fornece, a cada chamada, a próxima linha bruta do arquivo de importação
+// e o número da linha correspondente no arquivo (usado nos logs).
+// Deve retornar `io.EOF` quando não houver mais linhas; qualquer outro erro aborta a importação.
+type TituloDireitoRowIterator func() (row models.TituloDireitoFromRow, lineNum int, err error)
+
+// RowIssueHandler recebe os problemas encontrados ao mapear uma linha do arquivo (placeholders usados
+// em campos obrigatórios, valores opcionais inválidos descartados). É chamado apenas para linhas com
+// problemas, logo após a linha ser obtida do iterador e antes da próxima chamada a ele. O slice
+// `issues` é reutilizado entre as linhas e não deve ser retido após o retorno.
+type RowIssueHandler func(lineNum int, issues []models.ImportRowIssue)
+
+// sink prepara `buf` para receber os problemas de uma nova linha. Retorna nil quando não há handler,
+// evitando a coleta de problemas na importação sem quarentena.
+func (h RowIssueHandler) sink(buf *[]models.ImportRowIssue) *[]models.ImportRowIssue {
+	if h == nil {
+		return nil
+	}
+	*buf = (*buf)[:0]
+	return buf
+}
+
+// report repassa ao handler os problemas coletados para a linha, se houver algum.
+func (h RowIssueHandler) report(lineNum int, issues []models.ImportRowIssue) {
+	if h != nil && len(issues) > 0 {
+		h(lineNum, issues)
+	}
+}
+
+// ImportTxHooks reúne ações executadas dentro da transação de gravação de uma importação, na mesma
+// conexão. Campos nulos são ignorados; um erro retornado por qualquer ação desfaz a importação.
+type ImportTxHooks struct {
+	// AfterBatch é chamada após cada lote gravado (ex: `ImportLock.RenewTx`).
+	AfterBatch func(tx *gorm.DB) error
+	// BeforeCommit são chamadas, em ordem, após a gravação de todos os títulos e antes do commit
+	// (ex: `TituloCNPJLinkRepository.LinkTitlesTx` seguida de `ImportSnapshotRepository.CaptureTx`).
+	BeforeCommit []func(tx *gorm.DB) error
+}
+
+// afterBatch executa `AfterBatch`, se definida.
+func (h ImportTxHooks) afterBatch(tx *gorm.DB) error {
+	if h.AfterBatch == nil {
+		return nil
+	}
+	return h.AfterBatch(tx)
+}
+
+// importTransaction executa `fn` em uma transação e, se não houver erro, as ações de
+// `hooks.BeforeCommit` na mesma transação.
+func importTransaction(db *gorm.DB, hooks ImportTxHooks, fn func(tx *gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+		for _, beforeCommit := range hooks.BeforeCommit {
+			if err := beforeCommit(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// TituloIncrementalResult resume o resultado de uma importação incremental de títulos.
+type TituloIncrementalResult struct {
+	Inserted  int // Títulos novos inseridos.
+	Updated   int // Títulos existentes com conteúdo alterado (ou restaurados após remoção).
+	Unchanged int // Títulos existentes sem alteração.
+	Removed   int // Títulos ativos ausentes do arquivo, marcados como removidos.
+
+	Companies []int // Empresas (NROEMPRESA) presentes no arquivo, em ordem crescente.
+}
+
+// TituloCompanyReplaceResult resume o resultado de uma substituição restrita às empresas do arquivo.
+type TituloCompanyReplaceResult struct {
+	Inserted  int   // Títulos do arquivo inseridos.
+	Replaced  int   // Títulos anteriores das empresas do arquivo, apagados.
+	Companies []int // Empresas (NROEMPRESA) presentes no arquivo, em ordem crescente.
+}
+
+// companySet acumula as empresas (NROEMPRESA) encontradas em um arquivo de importação.
+type companySet map[int]struct{}
+
+// sorted retorna as empresas em ordem crescente.
+func (c companySet) sorted() []int {
+	companies := make([]int, 0, len(c))
+	for company := range c {
+		companies = append(companies, company)
+	}
+	sort.Ints(companies)
+	return companies
+}
+
 // gormTituloDireitoRepository é a implementação GORM de TituloDireitoRepository.
 type gormTituloDireitoRepository struct {
 	db *gorm.DB
@@ -53,6 +170,13 @@ const (
 	defaultPlaceholderString = "N/A"            // Para campos string NOT NULL que estão vazios.
 	defaultPlaceholderCNPJ   = "00000000000000" // CNPJ/CPF inválido, mas que satisfaz NOT NULL.
 	defaultPlaceholderInt    = 0                // Para campos int NOT NULL que estão vazios/inválidos.
+
+	// importBatchSize é o número máximo de registros mantidos em memória e inseridos por lote
+	// durante a importação de títulos.
+	importBatchSize = 1000
+	// incrementalBatchSize é o tamanho do lote na importação incremental. É menor que `importBatchSize`
+	// porque cada lote gera consultas com cláusulas IN (limite de parâmetros do SQLite).
+	incrementalBatchSize = 500
 )
 
 // defaultPlaceholderDecimalStr é o valor decimal padrão (0.00) como string.
@@ -174,238 +298,652 @@ func parseDecimalString(valStr string, rowNumForLog int, colNameForLog string) (
 	return &formattedDecimalStr, nil
 }
 
-// ReplaceAll substitui todos os dados na tabela titulos_direitos.
-func (r *gormTituloDireitoRepository) ReplaceAll(rawData []models.TituloDireitoFromRow) (insertedCount int, skippedCount int, err error) {
-	if len(rawData) == 0 {
-		appLogger.Info("ReplaceAll Títulos de Direitos: Nenhum dado bruto fornecido.")
-		// Limpa a tabela mesmo se não houver novos dados para inserir.
-		if txErr := r.db.Exec("DELETE FROM " + models.DBTituloDireito{}.TableName()).Error; txErr != nil {
-			appLogger.Errorf("Erro ao deletar dados antigos de Títulos de Direitos (com input vazio): %v", txErr)
-			return 0, 0, appErrors.WrapErrorf(txErr, "falha ao limpar dados antigos de títulos de direitos (GORM)")
-		}
-		return 0, 0, nil
+// rowIssueRecorder coleta os problemas de uma linha do arquivo durante o mapeamento.
+// Com `issues` nil (importação real) as chamadas não têm efeito e os problemas ficam apenas nos logs.
+type rowIssueRecorder struct {
+	lineNum int
+	issues  *[]models.ImportRowIssue
+}
+
+// add registra um problema na coluna informada.
+func (rec rowIssueRecorder) add(column, rawValue, message string, usedPlaceholder bool) {
+	if rec.issues == nil {
+		return
 	}
+	*rec.issues = append(*rec.issues, models.ImportRowIssue{
+		LineNum:         rec.lineNum,
+		Column:          column,
+		RawValue:        rawValue,
+		Message:         message,
+		UsedPlaceholder: usedPlaceholder,
+	})
+}
 
-	dbEntries := make([]models.DBTituloDireito, 0, len(rawData))
-	rowsWithPlaceholdersUsed := 0 // Conta linhas onde pelo menos um placeholder foi usado para um campo NOT NULL.
+// checkDate registra um problema se a coluna tinha valor mas a data não pôde ser interpretada
+// (`parseDate`/`parseDateTime` retornam nil nesse caso, e o campo fica NULL).
+func (rec rowIssueRecorder) checkDate(column, rawValue string, parsed *time.Time) {
+	if parsed == nil && strings.TrimSpace(rawValue) != "" {
+		rec.add(column, rawValue, "data em formato não reconhecido; será gravado NULL", false)
+	}
+}
 
-	for i, row := range rawData {
-		rowNumForLog := i + 1 // Para logs de linha baseados em 1.
-		usedPlaceholderInThisRow := false
+// MapTituloDireitoRow aplica a uma linha do arquivo o mesmo mapeamento da importação, sem acessar o banco.
+// Retorna o registro como seria gravado e os problemas encontrados por coluna.
+// Usado pela pré-visualização (dry-run) da importação.
+func MapTituloDireitoRow(row models.TituloDireitoFromRow, lineNum int) (models.DBTituloDireito, []models.ImportRowIssue) {
+	var issues []models.ImportRowIssue
+	dbEntry, _ := toDBTituloDireito(row, lineNum, &issues)
+	return dbEntry, issues
+}
 
-		// Pessoa (opcional no CSV, mas pode ser string vazia)
-		pessoaStr := strings.TrimSpace(row.Pessoa)
-		var pPessoa *string
-		if pessoaStr != "" {
-			val := truncateString(pessoaStr, varchar255Limit)
-			pPessoa = &val
-		}
+// toDBTituloDireito converte uma linha bruta do arquivo em um `models.DBTituloDireito`,
+// aplicando truncamentos, parsing de datas/valores e placeholders para campos NOT NULL.
+// Retorna também se algum placeholder foi usado para a linha. Se `issues` não for nil,
+// os problemas encontrados em cada coluna são acrescentados a ele.
+func toDBTituloDireito(row models.TituloDireitoFromRow, rowNumForLog int, issues *[]models.ImportRowIssue) (models.DBTituloDireito, bool) {
+	usedPlaceholderInThisRow := false
+	rec := rowIssueRecorder{lineNum: rowNumForLog, issues: issues}
+
+	// Pessoa (opcional no CSV, mas pode ser string vazia)
+	pessoaStr := strings.TrimSpace(row.Pessoa)
+	var pPessoa *string
+	if pessoaStr != "" {
+		val := truncateString(pessoaStr, varchar255Limit)
+		pPessoa = &val
+	}
 
-		// CNPJ/CPF (obrigatório no DB)
-		cleanedCNPJCPF := models.CleanCNPJ(row.CNPJCPF) // Remove não dígitos.
-		if len(cleanedCNPJCPF) > 14 {                   // Trunca se maior que 14 (embora CleanCNPJ deva lidar com isso).
-			cleanedCNPJCPF = cleanedCNPJCPF[:14]
-		}
-		if cleanedCNPJCPF == "" {
-			cleanedCNPJCPF = defaultPlaceholderCNPJ
-			usedPlaceholderInThisRow = true
-			appLogger.Debugf("[Linha %d TDir] CNPJ/CPF vazio, usando placeholder.", rowNumForLog)
-		}
+	// CNPJ/CPF (obrigatório no DB)
+	cleanedCNPJCPF := models.CleanCNPJ(row.CNPJCPF) // Remove não dígitos.
+	if len(cleanedCNPJCPF) > 14 {                   // Trunca se maior que 14 (embora CleanCNPJ deva lidar com isso).
+		cleanedCNPJCPF = cleanedCNPJCPF[:14]
+	}
+	if cleanedCNPJCPF == "" {
+		cleanedCNPJCPF = defaultPlaceholderCNPJ
+		usedPlaceholderInThisRow = true
+		appLogger.Debugf("[Linha %d TDir] CNPJ/CPF vazio, usando placeholder.", rowNumForLog)
+		rec.add("CNPJ/CPF", row.CNPJCPF, "CNPJ/CPF vazio; será usado "+defaultPlaceholderCNPJ, true)
+	}
 
-		// NumeroEmpresa (obrigatório no DB)
-		var numeroEmpresa int
-		trimmedNumEmp := strings.TrimSpace(row.NumeroEmpresa)
-		if trimmedNumEmp == "" {
+	// NumeroEmpresa (obrigatório no DB)
+	var numeroEmpresa int
+	trimmedNumEmp := strings.TrimSpace(row.NumeroEmpresa)
+	if trimmedNumEmp == "" {
+		numeroEmpresa = defaultPlaceholderInt
+		usedPlaceholderInThisRow = true
+		appLogger.Debugf("[Linha %d TDir] NROEMPRESA vazio, usando placeholder.", rowNumForLog)
+		rec.add("NROEMPRESA", row.NumeroEmpresa, fmt.Sprintf("NROEMPRESA vazio; será usado %d", defaultPlaceholderInt), true)
+	} else {
+		parsedNum, parseErr := strconv.Atoi(trimmedNumEmp)
+		if parseErr != nil {
+			appLogger.Warnf("[Linha %d TDir] Valor inválido para NROEMPRESA: '%s'. Usando placeholder %d. Erro: %v", rowNumForLog, row.NumeroEmpresa, defaultPlaceholderInt, parseErr)
+			rec.add("NROEMPRESA", row.NumeroEmpresa, fmt.Sprintf("número inteiro inválido; será usado %d", defaultPlaceholderInt), true)
 			numeroEmpresa = defaultPlaceholderInt
 			usedPlaceholderInThisRow = true
-			appLogger.Debugf("[Linha %d TDir] NROEMPRESA vazio, usando placeholder.", rowNumForLog)
 		} else {
-			parsedNum, parseErr := strconv.Atoi(trimmedNumEmp)
-			if parseErr != nil {
-				appLogger.Warnf("[Linha %d TDir] Valor inválido para NROEMPRESA: '%s'. Usando placeholder %d. Erro: %v", rowNumForLog, row.NumeroEmpresa, defaultPlaceholderInt, parseErr)
-				numeroEmpresa = defaultPlaceholderInt
-				usedPlaceholderInThisRow = true
-			} else {
-				numeroEmpresa = parsedNum
-			}
-		}
-
-		// Titulo (obrigatório no DB, VARCHAR(100))
-		tituloStr := strings.TrimSpace(row.Titulo)
-		if tituloStr == "" {
-			tituloStr = defaultPlaceholderString
-			usedPlaceholderInThisRow = true
-			appLogger.Debugf("[Linha %d TDir] TÍTULO vazio, usando placeholder.", rowNumForLog)
+			numeroEmpresa = parsedNum
 		}
-		tituloStr = truncateString(tituloStr, varchar100Limit)
+	}
 
-		// ValorNominal (obrigatório no DB)
-		var valorNominalStr string
-		parsedVN, vnErr := parseDecimalString(row.ValorNominal, rowNumForLog, "VLRNOMINAL")
-		if vnErr != nil || parsedVN == nil { // Erro no parse ou string vazia resultou em nil
-			valorNominalStr = defaultPlaceholderDecimalStr
-			usedPlaceholderInThisRow = true
-			appLogger.Warnf("[Linha %d TDir] VLRNOMINAL inválido ou vazio ('%s'), usando placeholder. Erro: %v", rowNumForLog, row.ValorNominal, vnErr)
+	// Titulo (obrigatório no DB, VARCHAR(100))
+	tituloStr := strings.TrimSpace(row.Titulo)
+	if tituloStr == "" {
+		tituloStr = defaultPlaceholderString
+		usedPlaceholderInThisRow = true
+		appLogger.Debugf("[Linha %d TDir] TÍTULO vazio, usando placeholder.", rowNumForLog)
+		rec.add("TÍTULO", row.Titulo, "TÍTULO vazio; será usado "+defaultPlaceholderString, true)
+	}
+	tituloStr = truncateString(tituloStr, varchar100Limit)
+
+	// ValorNominal (obrigatório no DB)
+	var valorNominalStr string
+	parsedVN, vnErr := parseDecimalString(row.ValorNominal, rowNumForLog, "VLRNOMINAL")
+	if vnErr != nil || parsedVN == nil { // Erro no parse ou string vazia resultou em nil
+		valorNominalStr = defaultPlaceholderDecimalStr
+		usedPlaceholderInThisRow = true
+		appLogger.Warnf("[Linha %d TDir] VLRNOMINAL inválido ou vazio ('%s'), usando placeholder. Erro: %v", rowNumForLog, row.ValorNominal, vnErr)
+		if vnErr != nil {
+			rec.add("VLRNOMINAL", row.ValorNominal, "valor decimal inválido; será usado "+defaultPlaceholderDecimalStr, true)
 		} else {
-			valorNominalStr = truncateString(*parsedVN, varchar30Limit)
+			rec.add("VLRNOMINAL", row.ValorNominal, "VLRNOMINAL vazio; será usado "+defaultPlaceholderDecimalStr, true)
 		}
+	} else {
+		valorNominalStr = truncateString(*parsedVN, varchar30Limit)
+	}
 
-		// Campos Opcionais (Nullable no DB)
-		codigoEspecie := strings.TrimSpace(row.CodigoEspecie)
-		var pCodigoEspecie *string
-		if codigoEspecie != "" {
-			val := truncateString(codigoEspecie, varchar50Limit)
-			pCodigoEspecie = &val
-		}
+	// Campos Opcionais (Nullable no DB)
+	codigoEspecie := strings.TrimSpace(row.CodigoEspecie)
+	var pCodigoEspecie *string
+	if codigoEspecie != "" {
+		val := truncateString(codigoEspecie, varchar50Limit)
+		pCodigoEspecie = &val
+	}
+
+	dataVencimento := parseDate(row.DataVencimento, rowNumForLog, "DTAVENCIMENTO")
+	rec.checkDate("DTAVENCIMENTO", row.DataVencimento, dataVencimento)
+	dataQuitacao := parseDate(row.DataQuitacao, rowNumForLog, "DTAQUITAÇÃO")
+	rec.checkDate("DTAQUITAÇÃO", row.DataQuitacao, dataQuitacao)
+	valorPagoStr, vpErr := parseDecimalString(row.ValorPago, rowNumForLog, "VLRPAGO")
+	if vpErr != nil { // Loga erro, mas continua com nil, pois o campo é opcional.
+		appLogger.Warnf("[Linha %d TDir] Erro ao parsear VLRPAGO '%s', será NULL no DB. Erro: %v", rowNumForLog, row.ValorPago, vpErr)
+		rec.add("VLRPAGO", row.ValorPago, "valor decimal inválido; será gravado NULL", false)
+		valorPagoStr = nil // Garante que seja nil se o parse falhou
+	} else if valorPagoStr != nil { // Trunca se o parse foi ok
+		val := truncateString(*valorPagoStr, varchar30Limit)
+		valorPagoStr = &val
+	}
+
+	operacao := strings.TrimSpace(row.Operacao)
+	var pOperacao *string
+	if operacao != "" {
+		val := truncateString(operacao, varchar50Limit)
+		pOperacao = &val
+	}
+
+	dataOperacao := parseDate(row.DataOperacao, rowNumForLog, "DTAOPERAÇÃO")
+	rec.checkDate("DTAOPERAÇÃO", row.DataOperacao, dataOperacao)
+	dataContabiliza := parseDate(row.DataContabiliza, rowNumForLog, "DTACONTABILIZA")
+	rec.checkDate("DTACONTABILIZA", row.DataContabiliza, dataContabiliza)
+	// DataAlteracaoCSV é a data do arquivo. GORM UpdatedAt é para quando o registro no DB foi alterado.
+	dataAlteracaoDoCSV := parseDateTime(row.DataAlteracaoCSV, rowNumForLog, "DTAALTERAÇÃO_CSV")
+	rec.checkDate("DTAALTERAÇÃO", row.DataAlteracaoCSV, dataAlteracaoDoCSV)
+
+	observacao := strings.TrimSpace(row.Observacao)
+	var pObservacao *string
+	if observacao != "" {
+		pObservacao = &observacao // TEXT não precisa de truncamento aqui, mas o DB pode ter limite.
+	}
+
+	valorOperacaoStr, voErr := parseDecimalString(row.ValorOperacao, rowNumForLog, "VLROPERAÇÃO")
+	if voErr != nil {
+		appLogger.Warnf("[Linha %d TDir] Erro ao parsear VLROPERAÇÃO '%s', será NULL no DB. Erro: %v", rowNumForLog, row.ValorOperacao, voErr)
+		rec.add("VLROPERAÇÃO", row.ValorOperacao, "valor decimal inválido; será gravado NULL", false)
+		valorOperacaoStr = nil
+	} else if valorOperacaoStr != nil {
+		val := truncateString(*valorOperacaoStr, varchar30Limit)
+		valorOperacaoStr = &val
+	}
 
-		dataVencimento := parseDate(row.DataVencimento, rowNumForLog, "DTAVENCIMENTO")
-		dataQuitacao := parseDate(row.DataQuitacao, rowNumForLog, "DTAQUITAÇÃO")
-		valorPagoStr, vpErr := parseDecimalString(row.ValorPago, rowNumForLog, "VLRPAGO")
-		if vpErr != nil { // Loga erro, mas continua com nil, pois o campo é opcional.
-			appLogger.Warnf("[Linha %d TDir] Erro ao parsear VLRPAGO '%s', será NULL no DB. Erro: %v", rowNumForLog, row.ValorPago, vpErr)
-			valorPagoStr = nil // Garante que seja nil se o parse falhou
-		} else if valorPagoStr != nil { // Trunca se o parse foi ok
-			val := truncateString(*valorPagoStr, varchar30Limit)
-			valorPagoStr = &val
+	usuarioAlteracao := strings.TrimSpace(row.UsuarioAlteracao)
+	var pUsuarioAlteracao *string
+	if usuarioAlteracao != "" {
+		val := truncateString(usuarioAlteracao, varchar50Limit)
+		pUsuarioAlteracao = &val
+	}
+
+	especieAbatcomp := strings.TrimSpace(row.EspecieAbatcomp)
+	var pEspecieAbatcomp *string
+	if especieAbatcomp != "" {
+		val := truncateString(especieAbatcomp, varchar100Limit)
+		pEspecieAbatcomp = &val
+	}
+
+	obsTitulo := strings.TrimSpace(row.ObsTitulo)
+	var pObsTitulo *string
+	if obsTitulo != "" {
+		pObsTitulo = &obsTitulo
+	}
+
+	contasQuitacao := strings.TrimSpace(row.ContasQuitacao)
+	var pContasQuitacao *string
+	if contasQuitacao != "" {
+		pContasQuitacao = &contasQuitacao
+	}
+	dataProgramada := parseDate(row.DataProgramada, rowNumForLog, "DTAPROGRAMADA")
+	rec.checkDate("DTAPROGRAMADA", row.DataProgramada, dataProgramada)
+
+	// Pessoa é usado como critério para pular a linha se estiver vazio.
+	// O CSV original parece ter PESSOA como um campo que pode ser nulo,
+	// mas a lógica Python pulava se fosse inválido.
+	// Aqui, se `pPessoa` for nil (após trim), a linha ainda pode ser incluída
+	// se outros campos obrigatórios estiverem ok e o DB permitir Pessoa nula.
+	// A lógica Python no `titulo_direito_use_case.py` tinha `if not valid_data.get("PESSOA"): ... skip`.
+	// Adaptando: se Pessoa for estritamente necessário:
+	if pPessoa == nil || *pPessoa == "" {
+		// No entanto, a struct DBTituloDireito tem Pessoa como *string, permitindo nulo.
+		// Se a regra de negócio for que Pessoa NÃO PODE ser nulo,
+		// então o campo no DBTituloDireito deveria ser `Pessoa string gorm:"not null"`
+		// e um placeholder seria usado aqui.
+		// Assumindo que Pessoa pode ser nulo no DB, mas se estiver vazio no CSV
+		// e for uma condição de "pular linha", o serviço ou use_case faria isso.
+		// O repositório tenta processar o que recebe.
+		// Para este exemplo, se Pessoa for fundamental para a validade da linha,
+		// e o DB não permite nulo, um placeholder deveria ser definido.
+		// Se DB permite nulo e CSV está vazio, pPessoa será nil.
+	}
+
+	dbEntry := models.DBTituloDireito{
+		Pessoa:           pPessoa,
+		CNPJCPF:          cleanedCNPJCPF,
+		NumeroEmpresa:    numeroEmpresa,
+		Titulo:           tituloStr,
+		CodigoEspecie:    pCodigoEspecie,
+		DataVencimento:   dataVencimento,
+		DataQuitacao:     dataQuitacao,
+		ValorNominal:     valorNominalStr,
+		ValorPago:        valorPagoStr,
+		Operacao:         pOperacao,
+		DataOperacao:     dataOperacao,
+		DataContabiliza:  dataContabiliza,
+		DataAlteracaoCSV: dataAlteracaoDoCSV, // Armazena a data do arquivo
+		Observacao:       pObservacao,
+		ValorOperacao:    valorOperacaoStr,
+		UsuarioAlteracao: pUsuarioAlteracao,
+		EspecieAbatcomp:  pEspecieAbatcomp,
+		ObsTitulo:        pObsTitulo,
+		ContasQuitacao:   pContasQuitacao,
+		DataProgramada:   dataProgramada,
+		// CreatedAt/UpdatedAt gerenciados pelo GORM
+	}
+	return dbEntry, usedPlaceholderInThisRow
+}
+
+// ReplaceAll substitui todos os dados na tabela titulos_direitos.
+// As linhas são consumidas de `next` à medida que são lidas do arquivo e inseridas em
+// lotes de `importBatchSize` dentro de uma única transação, de modo que o uso de memória
+// não cresce com o tamanho do arquivo. Qualquer erro (do iterador ou do banco) desfaz a transação,
+// preservando os dados anteriores.
+func (r *gormTituloDireitoRepository) ReplaceAll(next TituloDireitoRowIterator, onIssues RowIssueHandler, hooks ImportTxHooks) (insertedCount int, skippedCount int, err error) {
+	if next == nil {
+		return 0, 0, fmt.Errorf("%w: iterador de linhas nulo para ReplaceAll de Títulos de Direitos", appErrors.ErrInvalidInput)
+	}
+
+	rowsWithPlaceholdersUsed := 0 // Conta linhas onde pelo menos um placeholder foi usado para um campo NOT NULL.
+	// O `skippedCount` original do Python era sobre linhas com `PESSOA` inválida.
+	// Essa lógica de pular linhas baseada em um campo específico é melhor no Serviço/Use Case,
+	// que já descarta linhas malformadas antes de entregá-las ao iterador.
+	skippedCount = 0
+
+	err = importTransaction(r.db, hooks, func(tx *gorm.DB) error {
+		appLogger.Info("Deletando dados antigos de Títulos de Direitos...")
+		if txErr := tx.Exec("DELETE FROM " + models.DBTituloDireito{}.TableName()).Error; txErr != nil {
+			return appErrors.WrapErrorf(txErr, "falha ao limpar dados antigos de títulos de direitos (GORM)")
 		}
+		appLogger.Debugf("Dados antigos de Títulos de Direitos (%s) deletados.", models.DBTituloDireito{}.TableName())
 
-		operacao := strings.TrimSpace(row.Operacao)
-		var pOperacao *string
-		if operacao != "" {
-			val := truncateString(operacao, varchar50Limit)
-			pOperacao = &val
+		// O slice do lote é reutilizado entre as inserções para manter a memória constante.
+		batch := make([]models.DBTituloDireito, 0, importBatchSize)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if txErr := tx.Create(&batch).Error; txErr != nil {
+				return appErrors.WrapErrorf(txErr, "falha ao inserir novos títulos de direitos em lote (GORM)")
+			}
+			insertedCount += len(batch)
+			appLogger.Debugf("Lote de %d Títulos de Direitos inserido (total até agora: %d).", len(batch), insertedCount)
+			batch = batch[:0]
+			return hooks.afterBatch(tx)
 		}
 
-		dataOperacao := parseDate(row.DataOperacao, rowNumForLog, "DTAOPERAÇÃO")
-		dataContabiliza := parseDate(row.DataContabiliza, rowNumForLog, "DTACONTABILIZA")
-		// DataAlteracaoCSV é a data do arquivo. GORM UpdatedAt é para quando o registro no DB foi alterado.
-		dataAlteracaoDoCSV := parseDateTime(row.DataAlteracaoCSV, rowNumForLog, "DTAALTERAÇÃO_CSV")
+		var rowIssues []models.ImportRowIssue // Reutilizado entre as linhas.
+		for {
+			row, lineNum, iterErr := next()
+			if errors.Is(iterErr, io.EOF) {
+				break
+			}
+			if iterErr != nil {
+				return iterErr // Erro de leitura/validação propagado pelo serviço.
+			}
 
-		observacao := strings.TrimSpace(row.Observacao)
-		var pObservacao *string
-		if observacao != "" {
-			pObservacao = &observacao // TEXT não precisa de truncamento aqui, mas o DB pode ter limite.
+			dbEntry, usedPlaceholder := toDBTituloDireito(row, lineNum, onIssues.sink(&rowIssues))
+			onIssues.report(lineNum, rowIssues)
+			if usedPlaceholder {
+				rowsWithPlaceholdersUsed++
+			}
+			batch = append(batch, dbEntry)
+			if len(batch) >= importBatchSize {
+				if flushErr := flush(); flushErr != nil {
+					return flushErr
+				}
+			}
 		}
+		return flush() // Insere o lote residual e faz commit da transação.
+	})
+
+	if err != nil {
+		appLogger.Errorf("Erro na transação de ReplaceAll para Títulos de Direitos: %v", err)
+		return 0, skippedCount, err // Retorna o erro da transação.
+	}
+
+	appLogger.Infof("Títulos de Direitos inseridos: %d. Linhas que usaram placeholders para campos NOT NULL: %d.",
+		insertedCount, rowsWithPlaceholdersUsed)
+	return insertedCount, skippedCount, nil
+}
+
+// ReplaceCompanies substitui, na tabela titulos_direitos, apenas os títulos das empresas presentes
+// no arquivo. Como as empresas só são conhecidas ao fim da leitura, as linhas são inseridas em lotes
+// marcadas com o token da execução e, ao final, os títulos anteriores dessas empresas (sem o token)
+// são apagados. Tudo ocorre em uma única transação; qualquer erro preserva os dados anteriores.
+func (r *gormTituloDireitoRepository) ReplaceCompanies(next TituloDireitoRowIterator, onIssues RowIssueHandler, hooks ImportTxHooks) (result TituloCompanyReplaceResult, err error) {
+	if next == nil {
+		return result, fmt.Errorf("%w: iterador de linhas nulo para ReplaceCompanies de Títulos de Direitos", appErrors.ErrInvalidInput)
+	}
+
+	runToken := uuid.NewString()
+	companies := make(companySet)
+	rowsWithPlaceholdersUsed := 0
 
-		valorOperacaoStr, voErr := parseDecimalString(row.ValorOperacao, rowNumForLog, "VLROPERAÇÃO")
-		if voErr != nil {
-			appLogger.Warnf("[Linha %d TDir] Erro ao parsear VLROPERAÇÃO '%s', será NULL no DB. Erro: %v", rowNumForLog, row.ValorOperacao, voErr)
-			valorOperacaoStr = nil
-		} else if valorOperacaoStr != nil {
-			val := truncateString(*valorOperacaoStr, varchar30Limit)
-			valorOperacaoStr = &val
+	err = importTransaction(r.db, hooks, func(tx *gorm.DB) error {
+		batch := make([]models.DBTituloDireito, 0, importBatchSize)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if txErr := tx.Create(&batch).Error; txErr != nil {
+				return appErrors.WrapErrorf(txErr, "falha ao inserir novos títulos de direitos em lote (GORM)")
+			}
+			result.Inserted += len(batch)
+			batch = batch[:0]
+			return hooks.afterBatch(tx)
 		}
 
-		usuarioAlteracao := strings.TrimSpace(row.UsuarioAlteracao)
-		var pUsuarioAlteracao *string
-		if usuarioAlteracao != "" {
-			val := truncateString(usuarioAlteracao, varchar50Limit)
-			pUsuarioAlteracao = &val
+		var rowIssues []models.ImportRowIssue // Reutilizado entre as linhas.
+		for {
+			row, lineNum, iterErr := next()
+			if errors.Is(iterErr, io.EOF) {
+				break
+			}
+			if iterErr != nil {
+				return iterErr
+			}
+
+			dbEntry, usedPlaceholder := toDBTituloDireito(row, lineNum, onIssues.sink(&rowIssues))
+			onIssues.report(lineNum, rowIssues)
+			if usedPlaceholder {
+				rowsWithPlaceholdersUsed++
+			}
+			dbEntry.ImportToken = &runToken
+			companies[dbEntry.NumeroEmpresa] = struct{}{}
+			batch = append(batch, dbEntry)
+			if len(batch) >= importBatchSize {
+				if flushErr := flush(); flushErr != nil {
+					return flushErr
+				}
+			}
+		}
+		if flushErr := flush(); flushErr != nil {
+			return flushErr
 		}
 
-		especieAbatcomp := strings.TrimSpace(row.EspecieAbatcomp)
-		var pEspecieAbatcomp *string
-		if especieAbatcomp != "" {
-			val := truncateString(especieAbatcomp, varchar100Limit)
-			pEspecieAbatcomp = &val
+		result.Companies = companies.sorted()
+		if len(result.Companies) == 0 {
+			return nil // Arquivo sem linhas: nenhuma empresa é afetada.
 		}
+		res := tx.Where("numero_empresa IN ? AND (import_token IS NULL OR import_token <> ?)", result.Companies, runToken).
+			Delete(&models.DBTituloDireito{})
+		if res.Error != nil {
+			return appErrors.WrapErrorf(res.Error, "falha ao apagar títulos de direitos anteriores das empresas do arquivo (GORM)")
+		}
+		result.Replaced = int(res.RowsAffected)
+		return nil
+	})
 
-		obsTitulo := strings.TrimSpace(row.ObsTitulo)
-		var pObsTitulo *string
-		if obsTitulo != "" {
-			pObsTitulo = &obsTitulo
+	if err != nil {
+		appLogger.Errorf("Erro na transação de ReplaceCompanies para Títulos de Direitos: %v", err)
+		return TituloCompanyReplaceResult{}, err
+	}
+
+	appLogger.Infof("Títulos de Direitos das empresas %v substituídos: %d inseridos, %d anteriores apagados. Linhas com placeholders: %d.",
+		result.Companies, result.Inserted, result.Replaced, rowsWithPlaceholdersUsed)
+	return result, nil
+}
+
+// UpsertIncremental aplica o conteúdo do arquivo sobre a tabela titulos_direitos sem apagá-la.
+// Cada linha é casada com os registros existentes pela chave natural
+// (CNPJ/CPF + NROEMPRESA + TÍTULO + CODESPÉCIE): linhas novas são inseridas, linhas com
+// conteúdo diferente são atualizadas (mantendo o ID) e títulos ativos que não aparecem no
+// arquivo são marcados como removidos (`removed_at`) — se `companiesOnly`, apenas os das empresas
+// presentes no arquivo. Tudo ocorre em uma única transação, processando o arquivo em lotes de
+// `incrementalBatchSize`.
+func (r *gormTituloDireitoRepository) UpsertIncremental(next TituloDireitoRowIterator, onIssues RowIssueHandler, companiesOnly bool, hooks ImportTxHooks) (result TituloIncrementalResult, err error) {
+	if next == nil {
+		return result, fmt.Errorf("%w: iterador de linhas nulo para UpsertIncremental de Títulos de Direitos", appErrors.ErrInvalidInput)
+	}
+
+	// O token identifica esta execução; títulos não marcados com ele ao final são considerados removidos.
+	runToken := uuid.NewString()
+	companies := make(companySet)
+	rowsWithPlaceholdersUsed := 0
+
+	err = importTransaction(r.db, hooks, func(tx *gorm.DB) error {
+		batch := make([]models.DBTituloDireito, 0, incrementalBatchSize)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if errBatch := upsertTituloDireitoBatch(tx, batch, runToken, &result); errBatch != nil {
+				return errBatch
+			}
+			batch = batch[:0]
+			return hooks.afterBatch(tx)
 		}
 
-		contasQuitacao := strings.TrimSpace(row.ContasQuitacao)
-		var pContasQuitacao *string
-		if contasQuitacao != "" {
-			pContasQuitacao = &contasQuitacao
+		var rowIssues []models.ImportRowIssue // Reutilizado entre as linhas.
+		for {
+			row, lineNum, iterErr := next()
+			if errors.Is(iterErr, io.EOF) {
+				break
+			}
+			if iterErr != nil {
+				return iterErr
+			}
+
+			dbEntry, usedPlaceholder := toDBTituloDireito(row, lineNum, onIssues.sink(&rowIssues))
+			onIssues.report(lineNum, rowIssues)
+			if usedPlaceholder {
+				rowsWithPlaceholdersUsed++
+			}
+			dbEntry.ImportToken = &runToken
+			companies[dbEntry.NumeroEmpresa] = struct{}{}
+			batch = append(batch, dbEntry)
+			if len(batch) >= incrementalBatchSize {
+				if flushErr := flush(); flushErr != nil {
+					return flushErr
+				}
+			}
 		}
-		dataProgramada := parseDate(row.DataProgramada, rowNumForLog, "DTAPROGRAMADA")
-
-		// Pessoa é usado como critério para pular a linha se estiver vazio.
-		// O CSV original parece ter PESSOA como um campo que pode ser nulo,
-		// mas a lógica Python pulava se fosse inválido.
-		// Aqui, se `pPessoa` for nil (após trim), a linha ainda pode ser incluída
-		// se outros campos obrigatórios estiverem ok e o DB permitir Pessoa nula.
-		// A lógica Python no `titulo_direito_use_case.py` tinha `if not valid_data.get("PESSOA"): ... skip`.
-		// Adaptando: se Pessoa for estritamente necessário:
-		if pPessoa == nil || *pPessoa == "" {
-			// No entanto, a struct DBTituloDireito tem Pessoa como *string, permitindo nulo.
-			// Se a regra de negócio for que Pessoa NÃO PODE ser nulo,
-			// então o campo no DBTituloDireito deveria ser `Pessoa string gorm:"not null"`
-			// e um placeholder seria usado aqui.
-			// Assumindo que Pessoa pode ser nulo no DB, mas se estiver vazio no CSV
-			// e for uma condição de "pular linha", o serviço ou use_case faria isso.
-			// O repositório tenta processar o que recebe.
-			// Para este exemplo, se Pessoa for fundamental para a validade da linha,
-			// e o DB não permite nulo, um placeholder deveria ser definido.
-			// Se DB permite nulo e CSV está vazio, pPessoa será nil.
+		if flushErr := flush(); flushErr != nil {
+			return flushErr
 		}
 
-		if usedPlaceholderInThisRow {
-			rowsWithPlaceholdersUsed++
+		// Marca como removidos os títulos ativos que não constavam no arquivo.
+		result.Companies = companies.sorted()
+		removeQuery := tx.Model(&models.DBTituloDireito{}).
+			Where("removed_at IS NULL AND (import_token IS NULL OR import_token <> ?)", runToken)
+		if companiesOnly {
+			if len(result.Companies) == 0 {
+				return nil // Arquivo sem linhas: nenhuma empresa é afetada.
+			}
+			removeQuery = removeQuery.Where("numero_empresa IN ?", result.Companies)
 		}
-
-		dbEntry := models.DBTituloDireito{
-			Pessoa:           pPessoa,
-			CNPJCPF:          cleanedCNPJCPF,
-			NumeroEmpresa:    numeroEmpresa,
-			Titulo:           tituloStr,
-			CodigoEspecie:    pCodigoEspecie,
-			DataVencimento:   dataVencimento,
-			DataQuitacao:     dataQuitacao,
-			ValorNominal:     valorNominalStr,
-			ValorPago:        valorPagoStr,
-			Operacao:         pOperacao,
-			DataOperacao:     dataOperacao,
-			DataContabiliza:  dataContabiliza,
-			DataAlteracaoCSV: dataAlteracaoDoCSV, // Armazena a data do arquivo
-			Observacao:       pObservacao,
-			ValorOperacao:    valorOperacaoStr,
-			UsuarioAlteracao: pUsuarioAlteracao,
-			EspecieAbatcomp:  pEspecieAbatcomp,
-			ObsTitulo:        pObsTitulo,
-			ContasQuitacao:   pContasQuitacao,
-			DataProgramada:   dataProgramada,
-			// CreatedAt/UpdatedAt gerenciados pelo GORM
+		res := removeQuery.Update("removed_at", time.Now().UTC())
+		if res.Error != nil {
+			return appErrors.WrapErrorf(res.Error, "falha ao marcar títulos de direitos removidos (GORM)")
 		}
-		dbEntries = append(dbEntries, dbEntry)
+		result.Removed = int(res.RowsAffected)
+		return nil
+	})
+
+	if err != nil {
+		appLogger.Errorf("Erro na transação de UpsertIncremental para Títulos de Direitos: %v", err)
+		return TituloIncrementalResult{}, err
 	}
 
-	appLogger.Infof("Títulos de Direitos Processados para inserção: %d. Linhas que usaram placeholders para campos NOT NULL: %d.",
-		len(dbEntries), rowsWithPlaceholdersUsed)
-	// O `skippedCount` original do Python era sobre linhas com `PESSOA` inválida.
-	// Essa lógica de pular linhas baseada em um campo específico é melhor no Serviço/Use Case.
-	// O repositório tenta persistir o que recebe, aplicando defaults para NOT NULL.
-	// Para este exemplo, o `skippedCount` é zero pois o repositório processa todas as linhas fornecidas.
-	skippedCount = 0 // Ou ajustar se a lógica de pular linhas for movida para cá.
+	appLogger.Infof("Importação incremental de Títulos de Direitos: inseridos %d, atualizados %d, inalterados %d, removidos %d. Linhas com placeholders: %d.",
+		result.Inserted, result.Updated, result.Unchanged, result.Removed, rowsWithPlaceholdersUsed)
+	return result, nil
+}
 
-	// Usar transação para deletar todos os antigos e inserir os novos.
-	err = r.db.Transaction(func(tx *gorm.DB) error {
-		appLogger.Info("Deletando dados antigos de Títulos de Direitos...")
-		if txErr := tx.Exec("DELETE FROM " + models.DBTituloDireito{}.TableName()).Error; txErr != nil {
-			return appErrors.WrapErrorf(txErr, "falha ao limpar dados antigos de títulos de direitos (GORM)")
+// upsertTituloDireitoBatch casa um lote de títulos com os registros existentes pela chave natural
+// e aplica inserções/atualizações, acumulando as contagens em `result`.
+func upsertTituloDireitoBatch(tx *gorm.DB, batch []models.DBTituloDireito, runToken string, result *TituloIncrementalResult) error {
+	cnpjSet := make(map[string]struct{}, len(batch))
+	cnpjs := make([]string, 0, len(batch))
+	for i := range batch {
+		if _, seen := cnpjSet[batch[i].CNPJCPF]; !seen {
+			cnpjSet[batch[i].CNPJCPF] = struct{}{}
+			cnpjs = append(cnpjs, batch[i].CNPJCPF)
 		}
-		appLogger.Debugf("Dados antigos de Títulos de Direitos (%s) deletados.", models.DBTituloDireito{}.TableName())
+	}
 
-		// Inserir novos dados em lote (batch) para performance.
-		if len(dbEntries) > 0 {
-			appLogger.Infof("Inserindo %d novos registros de Títulos de Direitos...", len(dbEntries))
-			// GORM `CreateInBatches` divide as inserções em lotes.
-			// O tamanho do lote (ex: 1000) pode ser ajustado conforme a necessidade.
-			if txErr := tx.CreateInBatches(&dbEntries, 1000).Error; txErr != nil {
-				return appErrors.WrapErrorf(txErr, "falha ao inserir novos títulos de direitos em lote (GORM)")
+	// Busca os títulos existentes (inclusive removidos, que podem ser restaurados) dos CNPJs do lote.
+	var existing []models.DBTituloDireito
+	if errFind := tx.Where("cnpjcpf IN ?", cnpjs).Order("id").Find(&existing).Error; errFind != nil {
+		return appErrors.WrapErrorf(errFind, "falha ao buscar títulos de direitos existentes para importação incremental (GORM)")
+	}
+	byKey := make(map[string][]*models.DBTituloDireito, len(existing))
+	for i := range existing {
+		key := tituloNaturalKey(existing[i].CNPJCPF, existing[i].NumeroEmpresa, existing[i].Titulo, existing[i].CodigoEspecie)
+		byKey[key] = append(byKey[key], &existing[i])
+	}
+
+	toInsert := make([]models.DBTituloDireito, 0, len(batch))
+	unchangedIDs := make([]uint64, 0, len(batch))
+	for i := range batch {
+		entry := batch[i]
+		key := tituloNaturalKey(entry.CNPJCPF, entry.NumeroEmpresa, entry.Titulo, entry.CodigoEspecie)
+
+		// Usa o primeiro registro com a mesma chave ainda não casado nesta execução.
+		// Chaves repetidas no arquivo casam com registros distintos (ou geram novos registros).
+		var match *models.DBTituloDireito
+		for _, candidate := range byKey[key] {
+			if candidate.ImportToken == nil || *candidate.ImportToken != runToken {
+				match = candidate
+				break
 			}
-			appLogger.Debugf("%d Títulos de Direitos inseridos com sucesso.", len(dbEntries))
 		}
-		return nil // Commit da transação.
-	})
+		if match == nil {
+			toInsert = append(toInsert, entry)
+			continue
+		}
+		match.ImportToken = &runToken
+
+		if match.RemovedAt == nil && sameTituloDireitoContent(match, &entry) {
+			unchangedIDs = append(unchangedIDs, match.ID)
+			result.Unchanged++
+			continue
+		}
+
+		entry.ID = match.ID
+		entry.RemovedAt = nil // Título que reaparece no arquivo volta a ficar ativo.
+		if errSave := tx.Save(&entry).Error; errSave != nil {
+			return appErrors.WrapErrorf(errSave, "falha ao atualizar título de direito ID %d (GORM)", match.ID)
+		}
+		result.Updated++
+	}
+
+	if len(unchangedIDs) > 0 {
+		if errUpd := tx.Model(&models.DBTituloDireito{}).Where("id IN ?", unchangedIDs).Update("import_token", runToken).Error; errUpd != nil {
+			return appErrors.WrapErrorf(errUpd, "falha ao marcar títulos de direitos inalterados (GORM)")
+		}
+	}
+	if len(toInsert) > 0 {
+		if errCreate := tx.Create(&toInsert).Error; errCreate != nil {
+			return appErrors.WrapErrorf(errCreate, "falha ao inserir novos títulos de direitos (GORM)")
+		}
+		result.Inserted += len(toInsert)
+	}
+	return nil
+}
+
+// sameTituloDireitoContent compara os campos vindos do arquivo de dois títulos de direitos.
+func sameTituloDireitoContent(a, b *models.DBTituloDireito) bool {
+	return equalStringPtr(a.Pessoa, b.Pessoa) &&
+		a.CNPJCPF == b.CNPJCPF &&
+		a.NumeroEmpresa == b.NumeroEmpresa &&
+		a.Titulo == b.Titulo &&
+		equalStringPtr(a.CodigoEspecie, b.CodigoEspecie) &&
+		equalDatePtr(a.DataVencimento, b.DataVencimento) &&
+		equalDatePtr(a.DataQuitacao, b.DataQuitacao) &&
+		equalDecimalString(&a.ValorNominal, &b.ValorNominal) &&
+		equalDecimalString(a.ValorPago, b.ValorPago) &&
+		equalStringPtr(a.Operacao, b.Operacao) &&
+		equalDatePtr(a.DataOperacao, b.DataOperacao) &&
+		equalDatePtr(a.DataContabiliza, b.DataContabiliza) &&
+		equalDateTimePtr(a.DataAlteracaoCSV, b.DataAlteracaoCSV) &&
+		equalStringPtr(a.Observacao, b.Observacao) &&
+		equalDecimalString(a.ValorOperacao, b.ValorOperacao) &&
+		equalStringPtr(a.UsuarioAlteracao, b.UsuarioAlteracao) &&
+		equalStringPtr(a.EspecieAbatcomp, b.EspecieAbatcomp) &&
+		equalStringPtr(a.ObsTitulo, b.ObsTitulo) &&
+		equalStringPtr(a.ContasQuitacao, b.ContasQuitacao) &&
+		equalDatePtr(a.DataProgramada, b.DataProgramada)
+}
 
+// --- Helpers da importação incremental (compartilhados com `titulo_obrigacao_repo.go`) ---
+
+// tituloNaturalKey monta a chave natural de um título: CNPJ/CPF + NROEMPRESA + TÍTULO + CODESPÉCIE.
+func tituloNaturalKey(cnpjCPF string, numeroEmpresa int, titulo string, codigoEspecie *string) string {
+	especie := ""
+	if codigoEspecie != nil {
+		especie = *codigoEspecie
+	}
+	return fmt.Sprintf("%s|%d|%s|%s", cnpjCPF, numeroEmpresa, titulo, especie)
+}
+
+// equalStringPtr compara dois ponteiros de string pelo conteúdo (nil só é igual a nil).
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// equalDatePtr compara duas datas apenas pelo dia, em UTC (as colunas são do tipo DATE,
+// e o driver pode devolvê-las com fuso/hora diferentes dos valores parseados do arquivo).
+func equalDatePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.UTC().Format("2006-01-02") == b.UTC().Format("2006-01-02")
+}
+
+// equalDateTimePtr compara dois instantes com precisão de segundos, independentemente do fuso
+// em que foram lidos. Usada para a data/hora de alteração do título no ERP.
+func equalDateTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Truncate(time.Second).Equal(b.Truncate(time.Second))
+}
+
+// equalDecimalString compara dois valores monetários armazenados como string pelo valor numérico.
+func equalDecimalString(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	da, errA := decimal.NewFromString(*a)
+	db, errB := decimal.NewFromString(*b)
+	if errA != nil || errB != nil {
+		return *a == *b
+	}
+	return da.Equal(db)
+}
+
+// GetFiltered busca títulos de direitos com base nos filtros fornecidos, com paginação.
+func (r *gormTituloDireitoRepository) GetFiltered(filter models.TituloFilter) ([]*models.DBTituloDireito, models.TituloTotals, error) {
+	filter.Normalize()
+	// Session permite reutilizar as condições na totalização e na busca da página.
+	query := applyTituloFilter(r.db.Model(&models.DBTituloDireito{}), filter, tituloDireitoColumns).Session(&gorm.Session{})
+
+	// Totais antes da paginação.
+	totals, err := sumTituloTotals(query, tituloDireitoColumns, "títulos de direitos")
 	if err != nil {
-		appLogger.Errorf("Erro na transação de ReplaceAll para Títulos de Direitos: %v", err)
-		return 0, skippedCount, err // Retorna o erro da transação.
+		return nil, totals, err
+	}
+	if totals.Count == 0 {
+		return []*models.DBTituloDireito{}, totals, nil
 	}
 
-	return len(dbEntries), skippedCount, nil
+	var titulos []*models.DBTituloDireito
+	if err := query.Order(tituloOrder(filter, tituloDireitoColumns)).Limit(filter.Limit).Offset(filter.Offset).Find(&titulos).Error; err != nil {
+		appLogger.Errorf("Erro ao buscar títulos de direitos filtrados: %v", err)
+		return nil, totals, appErrors.WrapErrorf(err, "falha ao buscar títulos de direitos (GORM)")
+	}
+	return titulos, totals, nil
+}
+
+// ForEachBalance percorre em lotes os saldos dos títulos de direitos não removidos.
+func (r *gormTituloDireitoRepository) ForEachBalance(fn func(batch []models.TituloBalance) error) error {
+	return forEachTituloBalance(r.db, models.DBTituloDireito{}.TableName(), tituloDireitoColumns, "títulos de direitos", fn)
 }
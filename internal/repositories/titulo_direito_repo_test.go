@@ -0,0 +1,231 @@
+package repositories
+
+import (
+	"io"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+)
+
+// newTestTituloDB abre um banco SQLite temporário com a tabela de títulos de direitos.
+func newTestTituloDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "titulos.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("falha ao abrir o banco de teste: %v", err)
+	}
+	if err := db.AutoMigrate(&models.DBTituloDireito{}); err != nil {
+		t.Fatalf("falha ao migrar a tabela de títulos de direitos: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("falha ao obter a conexão do banco de teste: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+// testTitulo descreve uma linha do arquivo de títulos de direitos usada nos testes.
+type testTitulo struct {
+	cnpj    string
+	empresa int
+	titulo  string
+	especie string
+	valor   string
+}
+
+// rowIterator entrega as linhas como o serviço de importação entregaria.
+func rowIterator(titulos []testTitulo) TituloDireitoRowIterator {
+	i := 0
+	return func() (models.TituloDireitoFromRow, int, error) {
+		if i >= len(titulos) {
+			return models.TituloDireitoFromRow{}, 0, io.EOF
+		}
+		tt := titulos[i]
+		i++
+		return models.TituloDireitoFromRow{
+			Pessoa:         "Cliente " + tt.cnpj,
+			CNPJCPF:        tt.cnpj,
+			NumeroEmpresa:  strconv.Itoa(tt.empresa),
+			Titulo:         tt.titulo,
+			CodigoEspecie:  tt.especie,
+			DataVencimento: "10/01/2026",
+			ValorNominal:   tt.valor,
+		}, i + 1, nil
+	}
+}
+
+// activeTitulos retorna os títulos não removidos, indexados pela chave natural.
+func activeTitulos(t *testing.T, db *gorm.DB) map[string]models.DBTituloDireito {
+	t.Helper()
+	var rows []models.DBTituloDireito
+	if err := db.Where("removed_at IS NULL").Find(&rows).Error; err != nil {
+		t.Fatalf("falha ao consultar os títulos: %v", err)
+	}
+	byKey := make(map[string]models.DBTituloDireito, len(rows))
+	for _, row := range rows {
+		byKey[tituloNaturalKey(row.CNPJCPF, row.NumeroEmpresa, row.Titulo, row.CodigoEspecie)] = row
+	}
+	return byKey
+}
+
+func TestUpsertIncrementalCycle(t *testing.T) {
+	db := newTestTituloDB(t)
+	repo := NewGormTituloDireitoRepository(db)
+
+	a := testTitulo{"11222333000181", 1, "A-1", "DUPL", "100,00"}
+	aChanged := testTitulo{"11222333000181", 1, "A-1", "DUPL", "150,00"}
+	aOtherEspecie := testTitulo{"11222333000181", 1, "A-1", "CHEQ", "100,00"}
+	b := testTitulo{"11222333000181", 2, "B-1", "DUPL", "200,00"}
+	c := testTitulo{"44555666000199", 1, "C-1", "", "300,00"}
+
+	// Os passos são aplicados em sequência sobre o mesmo banco.
+	steps := []struct {
+		name       string
+		file       []testTitulo
+		want       TituloIncrementalResult
+		wantActive []testTitulo
+	}{
+		{"primeira carga", []testTitulo{a, b}, TituloIncrementalResult{Inserted: 2}, []testTitulo{a, b}},
+		{"mesmo arquivo", []testTitulo{a, b}, TituloIncrementalResult{Unchanged: 2}, []testTitulo{a, b}},
+		{"altera, remove e insere", []testTitulo{aChanged, c}, TituloIncrementalResult{Inserted: 1, Updated: 1, Removed: 1}, []testTitulo{aChanged, c}},
+		{"valor igual em outro formato", []testTitulo{{aChanged.cnpj, 1, "A-1", "DUPL", "150,0"}, c}, TituloIncrementalResult{Unchanged: 2}, []testTitulo{aChanged, c}},
+		{"título removido reaparece", []testTitulo{aChanged, b, c}, TituloIncrementalResult{Updated: 1, Unchanged: 2}, []testTitulo{aChanged, b, c}},
+		{"espécie faz parte da chave", []testTitulo{aOtherEspecie, b, c}, TituloIncrementalResult{Inserted: 1, Unchanged: 2, Removed: 1}, []testTitulo{aOtherEspecie, b, c}},
+		{"arquivo vazio remove tudo", nil, TituloIncrementalResult{Removed: 3}, nil},
+	}
+
+	ids := make(map[string]uint64)
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			got, err := repo.UpsertIncremental(rowIterator(step.file), nil, false, ImportTxHooks{})
+			if err != nil {
+				t.Fatalf("UpsertIncremental: %v", err)
+			}
+			if got.Inserted != step.want.Inserted || got.Updated != step.want.Updated ||
+				got.Unchanged != step.want.Unchanged || got.Removed != step.want.Removed {
+				t.Errorf("resultado = %+v, esperado %+v", got, step.want)
+			}
+
+			active := activeTitulos(t, db)
+			if len(active) != len(step.wantActive) {
+				t.Errorf("%d títulos ativos, esperados %d", len(active), len(step.wantActive))
+			}
+			for _, tt := range step.wantActive {
+				especie := &tt.especie
+				if tt.especie == "" {
+					especie = nil
+				}
+				key := tituloNaturalKey(tt.cnpj, tt.empresa, tt.titulo, especie)
+				row, ok := active[key]
+				if !ok {
+					t.Errorf("título %s não está ativo", key)
+					continue
+				}
+				// A chave natural mantém o ID do registro entre as importações.
+				if id, seen := ids[key]; seen && id != row.ID {
+					t.Errorf("título %s mudou de ID: %d -> %d", key, id, row.ID)
+				}
+				ids[key] = row.ID
+			}
+		})
+	}
+}
+
+func TestReplaceCompaniesScope(t *testing.T) {
+	existing := []testTitulo{
+		{"11222333000181", 1, "E1-1", "DUPL", "10,00"},
+		{"11222333000181", 1, "E1-2", "DUPL", "20,00"},
+		{"11222333000181", 2, "E2-1", "DUPL", "30,00"},
+		{"44555666000199", 3, "E3-1", "DUPL", "40,00"},
+	}
+
+	cases := []struct {
+		name          string
+		file          []testTitulo
+		wantInserted  int
+		wantReplaced  int
+		wantCompanies []int
+		wantTitulos   []string // Títulos na tabela após a substituição, em ordem.
+	}{
+		{
+			name:          "uma empresa",
+			file:          []testTitulo{{"11222333000181", 1, "N1-1", "DUPL", "50,00"}},
+			wantInserted:  1,
+			wantReplaced:  2,
+			wantCompanies: []int{1},
+			wantTitulos:   []string{"E2-1", "E3-1", "N1-1"},
+		},
+		{
+			name: "duas empresas, uma nova",
+			file: []testTitulo{
+				{"11222333000181", 2, "N2-1", "DUPL", "60,00"},
+				{"44555666000199", 4, "N4-1", "DUPL", "70,00"},
+			},
+			wantInserted:  2,
+			wantReplaced:  1,
+			wantCompanies: []int{2, 4},
+			wantTitulos:   []string{"E1-1", "E1-2", "E3-1", "N2-1", "N4-1"},
+		},
+		{
+			name:          "linha repetida do arquivo não apaga a anterior",
+			file:          []testTitulo{{"44555666000199", 3, "E3-1", "DUPL", "40,00"}, {"44555666000199", 3, "E3-1", "DUPL", "40,00"}},
+			wantInserted:  2,
+			wantReplaced:  1,
+			wantCompanies: []int{3},
+			wantTitulos:   []string{"E1-1", "E1-2", "E2-1", "E3-1", "E3-1"},
+		},
+		{
+			name:        "arquivo vazio",
+			file:        nil,
+			wantTitulos: []string{"E1-1", "E1-2", "E2-1", "E3-1"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestTituloDB(t)
+			repo := NewGormTituloDireitoRepository(db)
+			if _, _, err := repo.ReplaceAll(rowIterator(existing), nil, ImportTxHooks{}); err != nil {
+				t.Fatalf("ReplaceAll: %v", err)
+			}
+
+			got, err := repo.ReplaceCompanies(rowIterator(tc.file), nil, ImportTxHooks{})
+			if err != nil {
+				t.Fatalf("ReplaceCompanies: %v", err)
+			}
+			if got.Inserted != tc.wantInserted || got.Replaced != tc.wantReplaced {
+				t.Errorf("inseridos/apagados = %d/%d, esperado %d/%d", got.Inserted, got.Replaced, tc.wantInserted, tc.wantReplaced)
+			}
+			if len(got.Companies) != len(tc.wantCompanies) {
+				t.Fatalf("empresas = %v, esperado %v", got.Companies, tc.wantCompanies)
+			}
+			for i := range got.Companies {
+				if got.Companies[i] != tc.wantCompanies[i] {
+					t.Errorf("empresas = %v, esperado %v", got.Companies, tc.wantCompanies)
+					break
+				}
+			}
+
+			var titulos []string
+			if err := db.Model(&models.DBTituloDireito{}).Order("titulo").Pluck("titulo", &titulos).Error; err != nil {
+				t.Fatalf("falha ao consultar os títulos: %v", err)
+			}
+			if len(titulos) != len(tc.wantTitulos) {
+				t.Fatalf("títulos = %v, esperado %v", titulos, tc.wantTitulos)
+			}
+			for i := range titulos {
+				if titulos[i] != tc.wantTitulos[i] {
+					t.Errorf("títulos = %v, esperado %v", titulos, tc.wantTitulos)
+					break
+				}
+			}
+		})
+	}
+}
@@ -1,12 +1,15 @@
 package repositories
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/shopspring/decimal" // Para validação e conversão de valores monetários
 	"gorm.io/gorm"
 
@@ -18,11 +21,38 @@ import (
 // TituloObrigacaoRepository define a interface para operações no repositório de títulos de obrigações.
 type TituloObrigacaoRepository interface {
 	// ReplaceAll substitui TODOS os dados na tabela `titulos_obrigacoes`.
-	// Recebe uma lista de structs `models.TituloObrigacaoFromRow`.
+	// Consome as linhas brutas (`models.TituloObrigacaoFromRow`) a partir do iterador `next`,
+	// inserindo-as em lotes limitados dentro de uma única transação.
 	// Retorna o número de registros inseridos, pulados e um erro, se houver.
-	ReplaceAll(rawData []models.TituloObrigacaoFromRow) (insertedCount int, skippedCount int, err error)
+	// `onIssues` (opcional) recebe os problemas das linhas corrigidas e `hooks` é executado na transação,
+	// como em `TituloDireitoRepository`.
+	ReplaceAll(next TituloObrigacaoRowIterator, onIssues RowIssueHandler, hooks ImportTxHooks) (insertedCount int, skippedCount int, err error)
+
+	// ReplaceCompanies substitui apenas os títulos das empresas (NROEMPRESA) presentes no arquivo;
+	// os títulos das demais empresas permanecem intactos. `onIssues` e `hooks` têm o mesmo papel que em `ReplaceAll`.
+	ReplaceCompanies(next TituloObrigacaoRowIterator, onIssues RowIssueHandler, hooks ImportTxHooks) (result TituloCompanyReplaceResult, err error)
+
+	// UpsertIncremental aplica as linhas do arquivo sem apagar a tabela, casando-as pela chave
+	// natural (CNPJ/CPF + NROEMPRESA + IdentificadorObrigacao + CODESPÉCIE).
+	// Insere títulos novos, atualiza os alterados e marca como removidos os ausentes do arquivo
+	// (se `companiesOnly`, apenas os das empresas presentes no arquivo).
+	// `onIssues` e `hooks` têm o mesmo papel que em `ReplaceAll`.
+	UpsertIncremental(next TituloObrigacaoRowIterator, onIssues RowIssueHandler, companiesOnly bool, hooks ImportTxHooks) (result TituloIncrementalResult, err error)
+
+	// GetFiltered busca os títulos que correspondem aos filtros, com ordenação e paginação.
+	// Retorna os títulos da página e os totais (contagem, valor nominal e valor pago) de todo o
+	// conjunto filtrado. Títulos removidos só são incluídos com `filter.IncludeRemoved`.
+	GetFiltered(filter models.TituloFilter) (titulos []*models.DBTituloObrigacao, totals models.TituloTotals, err error)
+
+	// ForEachBalance percorre em lotes os saldos (valor nominal e pago) dos títulos não removidos,
+	// sem carregar a tabela inteira em memória. Um erro retornado por `fn` interrompe a leitura.
+	ForEachBalance(fn func(batch []models.TituloBalance) error) error
 }
 
+// TituloObrigacaoRowIterator fornece a próxima linha bruta do arquivo e seu número de linha.
+// Deve retornar `io.EOF` ao final dos dados (mesmo contrato de `TituloDireitoRowIterator`).
+type TituloObrigacaoRowIterator func() (row models.TituloObrigacaoFromRow, lineNum int, err error)
+
 // gormTituloObrigacaoRepository é a implementação GORM de TituloObrigacaoRepository.
 type gormTituloObrigacaoRepository struct {
 	db *gorm.DB
@@ -113,205 +143,548 @@ func parseDecimalStringObrig(valStr string, rowNumForLog int, colNameForLog stri
 	return &formattedDecimalStr, nil
 }
 
-// ReplaceAll substitui todos os dados na tabela titulos_obrigacoes.
-func (r *gormTituloObrigacaoRepository) ReplaceAll(rawData []models.TituloObrigacaoFromRow) (insertedCount int, skippedCount int, err error) {
-	if len(rawData) == 0 {
-		appLogger.Info("ReplaceAll Títulos de Obrigações: Nenhum dado bruto fornecido.")
-		if txErr := r.db.Exec("DELETE FROM " + models.DBTituloObrigacao{}.TableName()).Error; txErr != nil {
-			appLogger.Errorf("Erro ao deletar dados antigos de Títulos de Obrigações (input vazio): %v", txErr)
-			return 0, 0, appErrors.WrapErrorf(txErr, "falha ao limpar dados antigos de títulos de obrigações (GORM)")
-		}
-		return 0, 0, nil
-	}
+// MapTituloObrigacaoRow aplica a uma linha do arquivo o mesmo mapeamento da importação, sem acessar o banco.
+// Retorna o registro como seria gravado e os problemas encontrados por coluna (ver `MapTituloDireitoRow`).
+func MapTituloObrigacaoRow(row models.TituloObrigacaoFromRow, lineNum int) (models.DBTituloObrigacao, []models.ImportRowIssue) {
+	var issues []models.ImportRowIssue
+	dbEntry, _ := toDBTituloObrigacao(row, lineNum, &issues)
+	return dbEntry, issues
+}
 
-	dbEntries := make([]models.DBTituloObrigacao, 0, len(rawData))
-	rowsWithPlaceholdersUsed := 0
+// toDBTituloObrigacao converte uma linha bruta do arquivo em um `models.DBTituloObrigacao`,
+// aplicando truncamentos, parsing de datas/valores e placeholders para campos NOT NULL.
+// Retorna também se algum placeholder foi usado para a linha. Se `issues` não for nil,
+// os problemas encontrados em cada coluna são acrescentados a ele.
+func toDBTituloObrigacao(row models.TituloObrigacaoFromRow, rowNumForLog int, issues *[]models.ImportRowIssue) (models.DBTituloObrigacao, bool) {
+	usedPlaceholderInThisRow := false
+	rec := rowIssueRecorder{lineNum: rowNumForLog, issues: issues}
+
+	pessoaStr := strings.TrimSpace(row.Pessoa)
+	var pPessoa *string
+	if pessoaStr != "" {
+		val := truncateString(pessoaStr, varchar255Limit)
+		pPessoa = &val
+	}
 
-	for i, row := range rawData {
-		rowNumForLog := i + 1
-		usedPlaceholderInThisRow := false
+	cleanedCNPJCPF := models.CleanCNPJ(row.CNPJCPF)
+	if len(cleanedCNPJCPF) > 14 {
+		cleanedCNPJCPF = cleanedCNPJCPF[:14]
+	}
+	if cleanedCNPJCPF == "" {
+		cleanedCNPJCPF = defaultPlaceholderCNPJ
+		usedPlaceholderInThisRow = true
+		appLogger.Debugf("[Linha %d TObrig] CNPJ/CPF vazio, usando placeholder.", rowNumForLog)
+		rec.add("CNPJ/CPF", row.CNPJCPF, "CNPJ/CPF vazio; será usado "+defaultPlaceholderCNPJ, true)
+	}
 
-		pessoaStr := strings.TrimSpace(row.Pessoa)
-		var pPessoa *string
-		if pessoaStr != "" {
-			val := truncateString(pessoaStr, varchar255Limit)
-			pPessoa = &val
+	var numeroEmpresa int
+	trimmedNumEmp := strings.TrimSpace(row.NumeroEmpresa)
+	if trimmedNumEmp == "" {
+		numeroEmpresa = defaultPlaceholderInt
+		usedPlaceholderInThisRow = true
+		appLogger.Debugf("[Linha %d TObrig] NROEMPRESA vazio, usando placeholder.", rowNumForLog)
+		rec.add("NROEMPRESA", row.NumeroEmpresa, fmt.Sprintf("NROEMPRESA vazio; será usado %d", defaultPlaceholderInt), true)
+	} else {
+		parsedNum, parseErr := strconv.Atoi(trimmedNumEmp)
+		if parseErr != nil {
+			appLogger.Warnf("[Linha %d TObrig] Valor inválido para NROEMPRESA: '%s'. Usando placeholder. Erro: %v", rowNumForLog, row.NumeroEmpresa, parseErr)
+			rec.add("NROEMPRESA", row.NumeroEmpresa, fmt.Sprintf("número inteiro inválido; será usado %d", defaultPlaceholderInt), true)
+			numeroEmpresa = defaultPlaceholderInt
+			usedPlaceholderInThisRow = true
+		} else {
+			numeroEmpresa = parsedNum
 		}
+	}
 
-		cleanedCNPJCPF := models.CleanCNPJ(row.CNPJCPF)
-		if len(cleanedCNPJCPF) > 14 {
-			cleanedCNPJCPF = cleanedCNPJCPF[:14]
+	// Mapeia 'Titulo' do CSV para 'IdentificadorObrigacao'
+	identObrigacaoStr := strings.TrimSpace(row.Titulo)
+	if identObrigacaoStr == "" {
+		identObrigacaoStr = defaultPlaceholderString
+		usedPlaceholderInThisRow = true
+		appLogger.Debugf("[Linha %d TObrig] TÍTULO (IdentificadorObrigacao) vazio, usando placeholder.", rowNumForLog)
+		rec.add("TÍTULO", row.Titulo, "TÍTULO vazio; será usado "+defaultPlaceholderString, true)
+	}
+	identObrigacaoStr = truncateString(identObrigacaoStr, varchar100Limit)
+
+	// Mapeia 'ValorNominal' do CSV para 'ValorNominalObrigacao'
+	var valorNominalObrigacaoStr string
+	parsedVNO, vnoErr := parseDecimalStringObrig(row.ValorNominal, rowNumForLog, "VLRNOMINAL (Obrigação)")
+	if vnoErr != nil || parsedVNO == nil {
+		valorNominalObrigacaoStr = defaultPlaceholderDecimalStr
+		usedPlaceholderInThisRow = true
+		appLogger.Warnf("[Linha %d TObrig] VLRNOMINAL (Obrigação) inválido ou vazio ('%s'), usando placeholder. Erro: %v", rowNumForLog, row.ValorNominal, vnoErr)
+		if vnoErr != nil {
+			rec.add("VLRNOMINAL", row.ValorNominal, "valor decimal inválido; será usado "+defaultPlaceholderDecimalStr, true)
+		} else {
+			rec.add("VLRNOMINAL", row.ValorNominal, "VLRNOMINAL vazio; será usado "+defaultPlaceholderDecimalStr, true)
 		}
-		if cleanedCNPJCPF == "" {
-			cleanedCNPJCPF = defaultPlaceholderCNPJ
-			usedPlaceholderInThisRow = true
-			appLogger.Debugf("[Linha %d TObrig] CNPJ/CPF vazio, usando placeholder.", rowNumForLog)
+	} else {
+		valorNominalObrigacaoStr = truncateString(*parsedVNO, varchar30Limit)
+	}
+
+	// Campos Opcionais
+	codigoEspecie := strings.TrimSpace(row.CodigoEspecie)
+	var pCodigoEspecie *string
+	if codigoEspecie != "" {
+		val := truncateString(codigoEspecie, varchar50Limit)
+		pCodigoEspecie = &val
+	}
+
+	dataVencimento := parseDateObrig(row.DataVencimento, rowNumForLog, "DTAVENCIMENTO")
+	rec.checkDate("DTAVENCIMENTO", row.DataVencimento, dataVencimento)
+	dataQuitacao := parseDateObrig(row.DataQuitacao, rowNumForLog, "DTAQUITAÇÃO")
+	rec.checkDate("DTAQUITAÇÃO", row.DataQuitacao, dataQuitacao)
+	valorPagoStr, vpErr := parseDecimalStringObrig(row.ValorPago, rowNumForLog, "VLRPAGO")
+	if vpErr != nil {
+		appLogger.Warnf("[Linha %d TObrig] Erro ao parsear VLRPAGO '%s', será NULL. Erro: %v", rowNumForLog, row.ValorPago, vpErr)
+		rec.add("VLRPAGO", row.ValorPago, "valor decimal inválido; será gravado NULL", false)
+		valorPagoStr = nil
+	} else if valorPagoStr != nil {
+		val := truncateString(*valorPagoStr, varchar30Limit)
+		valorPagoStr = &val
+	}
+
+	operacao := strings.TrimSpace(row.Operacao)
+	var pOperacao *string
+	if operacao != "" {
+		val := truncateString(operacao, varchar50Limit)
+		pOperacao = &val
+	}
+
+	dataOperacao := parseDateObrig(row.DataOperacao, rowNumForLog, "DTAOPERAÇÃO")
+	rec.checkDate("DTAOPERAÇÃO", row.DataOperacao, dataOperacao)
+	dataContabiliza := parseDateObrig(row.DataContabiliza, rowNumForLog, "DTACONTABILIZA")
+	rec.checkDate("DTACONTABILIZA", row.DataContabiliza, dataContabiliza)
+	dataAlteracaoDoCSV := parseDateTimeObrig(row.DataAlteracaoCSV, rowNumForLog, "DTAALTERAÇÃO_CSV")
+	rec.checkDate("DTAALTERAÇÃO", row.DataAlteracaoCSV, dataAlteracaoDoCSV)
+
+	observacao := strings.TrimSpace(row.Observacao)
+	var pObservacao *string
+	if observacao != "" {
+		pObservacao = &observacao
+	}
+
+	valorOperacaoStr, voErr := parseDecimalStringObrig(row.ValorOperacao, rowNumForLog, "VLROPERAÇÃO")
+	if voErr != nil {
+		appLogger.Warnf("[Linha %d TObrig] Erro ao parsear VLROPERAÇÃO '%s', será NULL. Erro: %v", rowNumForLog, row.ValorOperacao, voErr)
+		rec.add("VLROPERAÇÃO", row.ValorOperacao, "valor decimal inválido; será gravado NULL", false)
+		valorOperacaoStr = nil
+	} else if valorOperacaoStr != nil {
+		val := truncateString(*valorOperacaoStr, varchar30Limit)
+		valorOperacaoStr = &val
+	}
+
+	usuarioAlteracao := strings.TrimSpace(row.UsuarioAlteracao)
+	var pUsuarioAlteracao *string
+	if usuarioAlteracao != "" {
+		val := truncateString(usuarioAlteracao, varchar50Limit)
+		pUsuarioAlteracao = &val
+	}
+
+	especieAbatcomp := strings.TrimSpace(row.EspecieAbatcomp)
+	var pEspecieAbatcomp *string
+	if especieAbatcomp != "" {
+		val := truncateString(especieAbatcomp, varchar100Limit)
+		pEspecieAbatcomp = &val
+	}
+
+	obsTitulo := strings.TrimSpace(row.ObsTitulo)
+	var pObsTitulo *string
+	if obsTitulo != "" {
+		pObsTitulo = &obsTitulo
+	}
+
+	contasQuitacao := strings.TrimSpace(row.ContasQuitacao)
+	var pContasQuitacao *string
+	if contasQuitacao != "" {
+		pContasQuitacao = &contasQuitacao
+	}
+	dataProgramada := parseDateObrig(row.DataProgramada, rowNumForLog, "DTAPROGRAMADA")
+	rec.checkDate("DTAPROGRAMADA", row.DataProgramada, dataProgramada)
+
+	dbEntry := models.DBTituloObrigacao{
+		Pessoa:                 pPessoa,
+		CNPJCPF:                cleanedCNPJCPF,
+		NumeroEmpresa:          numeroEmpresa,
+		IdentificadorObrigacao: identObrigacaoStr, // Mapeado de row.Titulo
+		CodigoEspecie:          pCodigoEspecie,
+		DataVencimento:         dataVencimento,
+		DataQuitacao:           dataQuitacao,
+		ValorNominalObrigacao:  valorNominalObrigacaoStr, // Mapeado de row.ValorNominal
+		ValorPago:              valorPagoStr,
+		Operacao:               pOperacao,
+		DataOperacao:           dataOperacao,
+		DataContabiliza:        dataContabiliza,
+		DataAlteracaoCSV:       dataAlteracaoDoCSV,
+		Observacao:             pObservacao,
+		ValorOperacao:          valorOperacaoStr,
+		UsuarioAlteracao:       pUsuarioAlteracao,
+		EspecieAbatcomp:        pEspecieAbatcomp,
+		ObsTitulo:              pObsTitulo,
+		ContasQuitacao:         pContasQuitacao,
+		DataProgramada:         dataProgramada,
+		// CreatedAt/UpdatedAt gerenciados pelo GORM ou DB
+	}
+	return dbEntry, usedPlaceholderInThisRow
+}
+
+// ReplaceAll substitui todos os dados na tabela titulos_obrigacoes.
+// As linhas são consumidas de `next` à medida que são lidas do arquivo e inseridas em
+// lotes de `importBatchSize` dentro de uma única transação, de modo que o uso de memória
+// não cresce com o tamanho do arquivo. Qualquer erro (do iterador ou do banco) desfaz a transação,
+// preservando os dados anteriores.
+func (r *gormTituloObrigacaoRepository) ReplaceAll(next TituloObrigacaoRowIterator, onIssues RowIssueHandler, hooks ImportTxHooks) (insertedCount int, skippedCount int, err error) {
+	if next == nil {
+		return 0, 0, fmt.Errorf("%w: iterador de linhas nulo para ReplaceAll de Títulos de Obrigações", appErrors.ErrInvalidInput)
+	}
+
+	rowsWithPlaceholdersUsed := 0 // Conta linhas onde pelo menos um placeholder foi usado para um campo NOT NULL.
+	// O `skippedCount` original do Python era sobre linhas com `PESSOA` inválida.
+	// Essa lógica de pular linhas baseada em um campo específico é melhor no Serviço/Use Case,
+	// que já descarta linhas malformadas antes de entregá-las ao iterador.
+	skippedCount = 0
+
+	err = importTransaction(r.db, hooks, func(tx *gorm.DB) error {
+		appLogger.Info("Deletando dados antigos de Títulos de Obrigações...")
+		if txErr := tx.Exec("DELETE FROM " + models.DBTituloObrigacao{}.TableName()).Error; txErr != nil {
+			return appErrors.WrapErrorf(txErr, "falha ao limpar dados antigos de títulos de obrigações (GORM)")
 		}
+		appLogger.Debugf("Dados antigos de Títulos de Obrigações (%s) deletados.", models.DBTituloObrigacao{}.TableName())
 
-		var numeroEmpresa int
-		trimmedNumEmp := strings.TrimSpace(row.NumeroEmpresa)
-		if trimmedNumEmp == "" {
-			numeroEmpresa = defaultPlaceholderInt
-			usedPlaceholderInThisRow = true
-			appLogger.Debugf("[Linha %d TObrig] NROEMPRESA vazio, usando placeholder.", rowNumForLog)
-		} else {
-			parsedNum, parseErr := strconv.Atoi(trimmedNumEmp)
-			if parseErr != nil {
-				appLogger.Warnf("[Linha %d TObrig] Valor inválido para NROEMPRESA: '%s'. Usando placeholder. Erro: %v", rowNumForLog, row.NumeroEmpresa, parseErr)
-				numeroEmpresa = defaultPlaceholderInt
-				usedPlaceholderInThisRow = true
-			} else {
-				numeroEmpresa = parsedNum
+		// O slice do lote é reutilizado entre as inserções para manter a memória constante.
+		batch := make([]models.DBTituloObrigacao, 0, importBatchSize)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if txErr := tx.Create(&batch).Error; txErr != nil {
+				return appErrors.WrapErrorf(txErr, "falha ao inserir novos títulos de obrigações em lote (GORM)")
 			}
+			insertedCount += len(batch)
+			appLogger.Debugf("Lote de %d Títulos de Obrigações inserido (total até agora: %d).", len(batch), insertedCount)
+			batch = batch[:0]
+			return hooks.afterBatch(tx)
 		}
 
-		// Mapeia 'Titulo' do CSV para 'IdentificadorObrigacao'
-		identObrigacaoStr := strings.TrimSpace(row.Titulo)
-		if identObrigacaoStr == "" {
-			identObrigacaoStr = defaultPlaceholderString
-			usedPlaceholderInThisRow = true
-			appLogger.Debugf("[Linha %d TObrig] TÍTULO (IdentificadorObrigacao) vazio, usando placeholder.", rowNumForLog)
-		}
-		identObrigacaoStr = truncateString(identObrigacaoStr, varchar100Limit)
+		var rowIssues []models.ImportRowIssue // Reutilizado entre as linhas.
+		for {
+			row, lineNum, iterErr := next()
+			if errors.Is(iterErr, io.EOF) {
+				break
+			}
+			if iterErr != nil {
+				return iterErr // Erro de leitura/validação propagado pelo serviço.
+			}
 
-		// Mapeia 'ValorNominal' do CSV para 'ValorNominalObrigacao'
-		var valorNominalObrigacaoStr string
-		parsedVNO, vnoErr := parseDecimalStringObrig(row.ValorNominal, rowNumForLog, "VLRNOMINAL (Obrigação)")
-		if vnoErr != nil || parsedVNO == nil {
-			valorNominalObrigacaoStr = defaultPlaceholderDecimalStr
-			usedPlaceholderInThisRow = true
-			appLogger.Warnf("[Linha %d TObrig] VLRNOMINAL (Obrigação) inválido ou vazio ('%s'), usando placeholder. Erro: %v", rowNumForLog, row.ValorNominal, vnoErr)
-		} else {
-			valorNominalObrigacaoStr = truncateString(*parsedVNO, varchar30Limit)
+			dbEntry, usedPlaceholder := toDBTituloObrigacao(row, lineNum, onIssues.sink(&rowIssues))
+			onIssues.report(lineNum, rowIssues)
+			if usedPlaceholder {
+				rowsWithPlaceholdersUsed++
+			}
+			batch = append(batch, dbEntry)
+			if len(batch) >= importBatchSize {
+				if flushErr := flush(); flushErr != nil {
+					return flushErr
+				}
+			}
 		}
+		return flush() // Insere o lote residual e faz commit da transação.
+	})
+
+	if err != nil {
+		appLogger.Errorf("Erro na transação de ReplaceAll para Títulos de Obrigações: %v", err)
+		return 0, skippedCount, err // Retorna o erro da transação.
+	}
+
+	appLogger.Infof("Títulos de Obrigações inseridos: %d. Linhas que usaram placeholders para campos NOT NULL: %d.",
+		insertedCount, rowsWithPlaceholdersUsed)
+	return insertedCount, skippedCount, nil
+}
+
+// ReplaceCompanies substitui, na tabela titulos_obrigacoes, apenas os títulos das empresas presentes
+// no arquivo, com a mesma estratégia de `gormTituloDireitoRepository.ReplaceCompanies`: as linhas são
+// inseridas marcadas com o token da execução e, ao final, os títulos anteriores dessas empresas são
+// apagados, tudo em uma única transação.
+func (r *gormTituloObrigacaoRepository) ReplaceCompanies(next TituloObrigacaoRowIterator, onIssues RowIssueHandler, hooks ImportTxHooks) (result TituloCompanyReplaceResult, err error) {
+	if next == nil {
+		return result, fmt.Errorf("%w: iterador de linhas nulo para ReplaceCompanies de Títulos de Obrigações", appErrors.ErrInvalidInput)
+	}
 
-		// Campos Opcionais
-		codigoEspecie := strings.TrimSpace(row.CodigoEspecie)
-		var pCodigoEspecie *string
-		if codigoEspecie != "" {
-			val := truncateString(codigoEspecie, varchar50Limit)
-			pCodigoEspecie = &val
+	runToken := uuid.NewString()
+	companies := make(companySet)
+	rowsWithPlaceholdersUsed := 0
+
+	err = importTransaction(r.db, hooks, func(tx *gorm.DB) error {
+		batch := make([]models.DBTituloObrigacao, 0, importBatchSize)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if txErr := tx.Create(&batch).Error; txErr != nil {
+				return appErrors.WrapErrorf(txErr, "falha ao inserir novos títulos de obrigações em lote (GORM)")
+			}
+			result.Inserted += len(batch)
+			batch = batch[:0]
+			return hooks.afterBatch(tx)
 		}
 
-		dataVencimento := parseDateObrig(row.DataVencimento, rowNumForLog, "DTAVENCIMENTO")
-		dataQuitacao := parseDateObrig(row.DataQuitacao, rowNumForLog, "DTAQUITAÇÃO")
-		valorPagoStr, vpErr := parseDecimalStringObrig(row.ValorPago, rowNumForLog, "VLRPAGO")
-		if vpErr != nil {
-			appLogger.Warnf("[Linha %d TObrig] Erro ao parsear VLRPAGO '%s', será NULL. Erro: %v", rowNumForLog, row.ValorPago, vpErr)
-			valorPagoStr = nil
-		} else if valorPagoStr != nil {
-			val := truncateString(*valorPagoStr, varchar30Limit)
-			valorPagoStr = &val
+		var rowIssues []models.ImportRowIssue // Reutilizado entre as linhas.
+		for {
+			row, lineNum, iterErr := next()
+			if errors.Is(iterErr, io.EOF) {
+				break
+			}
+			if iterErr != nil {
+				return iterErr
+			}
+
+			dbEntry, usedPlaceholder := toDBTituloObrigacao(row, lineNum, onIssues.sink(&rowIssues))
+			onIssues.report(lineNum, rowIssues)
+			if usedPlaceholder {
+				rowsWithPlaceholdersUsed++
+			}
+			dbEntry.ImportToken = &runToken
+			companies[dbEntry.NumeroEmpresa] = struct{}{}
+			batch = append(batch, dbEntry)
+			if len(batch) >= importBatchSize {
+				if flushErr := flush(); flushErr != nil {
+					return flushErr
+				}
+			}
+		}
+		if flushErr := flush(); flushErr != nil {
+			return flushErr
 		}
 
-		operacao := strings.TrimSpace(row.Operacao)
-		var pOperacao *string
-		if operacao != "" {
-			val := truncateString(operacao, varchar50Limit)
-			pOperacao = &val
+		result.Companies = companies.sorted()
+		if len(result.Companies) == 0 {
+			return nil // Arquivo sem linhas: nenhuma empresa é afetada.
 		}
+		res := tx.Where("numero_empresa IN ? AND (import_token IS NULL OR import_token <> ?)", result.Companies, runToken).
+			Delete(&models.DBTituloObrigacao{})
+		if res.Error != nil {
+			return appErrors.WrapErrorf(res.Error, "falha ao apagar títulos de obrigações anteriores das empresas do arquivo (GORM)")
+		}
+		result.Replaced = int(res.RowsAffected)
+		return nil
+	})
 
-		dataOperacao := parseDateObrig(row.DataOperacao, rowNumForLog, "DTAOPERAÇÃO")
-		dataContabiliza := parseDateObrig(row.DataContabiliza, rowNumForLog, "DTACONTABILIZA")
-		dataAlteracaoDoCSV := parseDateTimeObrig(row.DataAlteracaoCSV, rowNumForLog, "DTAALTERAÇÃO_CSV")
+	if err != nil {
+		appLogger.Errorf("Erro na transação de ReplaceCompanies para Títulos de Obrigações: %v", err)
+		return TituloCompanyReplaceResult{}, err
+	}
 
-		observacao := strings.TrimSpace(row.Observacao)
-		var pObservacao *string
-		if observacao != "" {
-			pObservacao = &observacao
-		}
+	appLogger.Infof("Títulos de Obrigações das empresas %v substituídos: %d inseridos, %d anteriores apagados. Linhas com placeholders: %d.",
+		result.Companies, result.Inserted, result.Replaced, rowsWithPlaceholdersUsed)
+	return result, nil
+}
+
+// UpsertIncremental aplica o conteúdo do arquivo sobre a tabela titulos_obrigacoes sem apagá-la.
+// Cada linha é casada com os registros existentes pela chave natural
+// (CNPJ/CPF + NROEMPRESA + TÍTULO/IdentificadorObrigacao + CODESPÉCIE): linhas novas são inseridas, linhas com
+// conteúdo diferente são atualizadas (mantendo o ID) e títulos ativos que não aparecem no
+// arquivo são marcados como removidos (`removed_at`) — se `companiesOnly`, apenas os das empresas
+// presentes no arquivo. Tudo ocorre em uma única transação, processando o arquivo em lotes de
+// `incrementalBatchSize`.
+func (r *gormTituloObrigacaoRepository) UpsertIncremental(next TituloObrigacaoRowIterator, onIssues RowIssueHandler, companiesOnly bool, hooks ImportTxHooks) (result TituloIncrementalResult, err error) {
+	if next == nil {
+		return result, fmt.Errorf("%w: iterador de linhas nulo para UpsertIncremental de Títulos de Obrigações", appErrors.ErrInvalidInput)
+	}
+
+	// O token identifica esta execução; títulos não marcados com ele ao final são considerados removidos.
+	runToken := uuid.NewString()
+	companies := make(companySet)
+	rowsWithPlaceholdersUsed := 0
 
-		valorOperacaoStr, voErr := parseDecimalStringObrig(row.ValorOperacao, rowNumForLog, "VLROPERAÇÃO")
-		if voErr != nil {
-			appLogger.Warnf("[Linha %d TObrig] Erro ao parsear VLROPERAÇÃO '%s', será NULL. Erro: %v", rowNumForLog, row.ValorOperacao, voErr)
-			valorOperacaoStr = nil
-		} else if valorOperacaoStr != nil {
-			val := truncateString(*valorOperacaoStr, varchar30Limit)
-			valorOperacaoStr = &val
+	err = importTransaction(r.db, hooks, func(tx *gorm.DB) error {
+		batch := make([]models.DBTituloObrigacao, 0, incrementalBatchSize)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if errBatch := upsertTituloObrigacaoBatch(tx, batch, runToken, &result); errBatch != nil {
+				return errBatch
+			}
+			batch = batch[:0]
+			return hooks.afterBatch(tx)
 		}
 
-		usuarioAlteracao := strings.TrimSpace(row.UsuarioAlteracao)
-		var pUsuarioAlteracao *string
-		if usuarioAlteracao != "" {
-			val := truncateString(usuarioAlteracao, varchar50Limit)
-			pUsuarioAlteracao = &val
+		var rowIssues []models.ImportRowIssue // Reutilizado entre as linhas.
+		for {
+			row, lineNum, iterErr := next()
+			if errors.Is(iterErr, io.EOF) {
+				break
+			}
+			if iterErr != nil {
+				return iterErr
+			}
+
+			dbEntry, usedPlaceholder := toDBTituloObrigacao(row, lineNum, onIssues.sink(&rowIssues))
+			onIssues.report(lineNum, rowIssues)
+			if usedPlaceholder {
+				rowsWithPlaceholdersUsed++
+			}
+			dbEntry.ImportToken = &runToken
+			companies[dbEntry.NumeroEmpresa] = struct{}{}
+			batch = append(batch, dbEntry)
+			if len(batch) >= incrementalBatchSize {
+				if flushErr := flush(); flushErr != nil {
+					return flushErr
+				}
+			}
+		}
+		if flushErr := flush(); flushErr != nil {
+			return flushErr
 		}
 
-		especieAbatcomp := strings.TrimSpace(row.EspecieAbatcomp)
-		var pEspecieAbatcomp *string
-		if especieAbatcomp != "" {
-			val := truncateString(especieAbatcomp, varchar100Limit)
-			pEspecieAbatcomp = &val
+		// Marca como removidos os títulos ativos que não constavam no arquivo.
+		result.Companies = companies.sorted()
+		removeQuery := tx.Model(&models.DBTituloObrigacao{}).
+			Where("removed_at IS NULL AND (import_token IS NULL OR import_token <> ?)", runToken)
+		if companiesOnly {
+			if len(result.Companies) == 0 {
+				return nil // Arquivo sem linhas: nenhuma empresa é afetada.
+			}
+			removeQuery = removeQuery.Where("numero_empresa IN ?", result.Companies)
+		}
+		res := removeQuery.Update("removed_at", time.Now().UTC())
+		if res.Error != nil {
+			return appErrors.WrapErrorf(res.Error, "falha ao marcar títulos de obrigações removidos (GORM)")
 		}
+		result.Removed = int(res.RowsAffected)
+		return nil
+	})
 
-		obsTitulo := strings.TrimSpace(row.ObsTitulo)
-		var pObsTitulo *string
-		if obsTitulo != "" {
-			pObsTitulo = &obsTitulo
+	if err != nil {
+		appLogger.Errorf("Erro na transação de UpsertIncremental para Títulos de Obrigações: %v", err)
+		return TituloIncrementalResult{}, err
+	}
+
+	appLogger.Infof("Importação incremental de Títulos de Obrigações: inseridos %d, atualizados %d, inalterados %d, removidos %d. Linhas com placeholders: %d.",
+		result.Inserted, result.Updated, result.Unchanged, result.Removed, rowsWithPlaceholdersUsed)
+	return result, nil
+}
+
+// upsertTituloObrigacaoBatch casa um lote de títulos com os registros existentes pela chave natural
+// e aplica inserções/atualizações, acumulando as contagens em `result`.
+func upsertTituloObrigacaoBatch(tx *gorm.DB, batch []models.DBTituloObrigacao, runToken string, result *TituloIncrementalResult) error {
+	cnpjSet := make(map[string]struct{}, len(batch))
+	cnpjs := make([]string, 0, len(batch))
+	for i := range batch {
+		if _, seen := cnpjSet[batch[i].CNPJCPF]; !seen {
+			cnpjSet[batch[i].CNPJCPF] = struct{}{}
+			cnpjs = append(cnpjs, batch[i].CNPJCPF)
 		}
+	}
+
+	// Busca os títulos existentes (inclusive removidos, que podem ser restaurados) dos CNPJs do lote.
+	var existing []models.DBTituloObrigacao
+	if errFind := tx.Where("cnpjcpf IN ?", cnpjs).Order("id").Find(&existing).Error; errFind != nil {
+		return appErrors.WrapErrorf(errFind, "falha ao buscar títulos de obrigações existentes para importação incremental (GORM)")
+	}
+	byKey := make(map[string][]*models.DBTituloObrigacao, len(existing))
+	for i := range existing {
+		key := tituloNaturalKey(existing[i].CNPJCPF, existing[i].NumeroEmpresa, existing[i].IdentificadorObrigacao, existing[i].CodigoEspecie)
+		byKey[key] = append(byKey[key], &existing[i])
+	}
 
-		contasQuitacao := strings.TrimSpace(row.ContasQuitacao)
-		var pContasQuitacao *string
-		if contasQuitacao != "" {
-			pContasQuitacao = &contasQuitacao
+	toInsert := make([]models.DBTituloObrigacao, 0, len(batch))
+	unchangedIDs := make([]uint64, 0, len(batch))
+	for i := range batch {
+		entry := batch[i]
+		key := tituloNaturalKey(entry.CNPJCPF, entry.NumeroEmpresa, entry.IdentificadorObrigacao, entry.CodigoEspecie)
+
+		// Usa o primeiro registro com a mesma chave ainda não casado nesta execução.
+		// Chaves repetidas no arquivo casam com registros distintos (ou geram novos registros).
+		var match *models.DBTituloObrigacao
+		for _, candidate := range byKey[key] {
+			if candidate.ImportToken == nil || *candidate.ImportToken != runToken {
+				match = candidate
+				break
+			}
+		}
+		if match == nil {
+			toInsert = append(toInsert, entry)
+			continue
 		}
-		dataProgramada := parseDateObrig(row.DataProgramada, rowNumForLog, "DTAPROGRAMADA")
+		match.ImportToken = &runToken
 
-		if usedPlaceholderInThisRow {
-			rowsWithPlaceholdersUsed++
+		if match.RemovedAt == nil && sameTituloObrigacaoContent(match, &entry) {
+			unchangedIDs = append(unchangedIDs, match.ID)
+			result.Unchanged++
+			continue
 		}
 
-		dbEntry := models.DBTituloObrigacao{
-			Pessoa:                 pPessoa,
-			CNPJCPF:                cleanedCNPJCPF,
-			NumeroEmpresa:          numeroEmpresa,
-			IdentificadorObrigacao: identObrigacaoStr, // Mapeado de row.Titulo
-			CodigoEspecie:          pCodigoEspecie,
-			DataVencimento:         dataVencimento,
-			DataQuitacao:           dataQuitacao,
-			ValorNominalObrigacao:  valorNominalObrigacaoStr, // Mapeado de row.ValorNominal
-			ValorPago:              valorPagoStr,
-			Operacao:               pOperacao,
-			DataOperacao:           dataOperacao,
-			DataContabiliza:        dataContabiliza,
-			DataAlteracaoCSV:       dataAlteracaoDoCSV,
-			Observacao:             pObservacao,
-			ValorOperacao:          valorOperacaoStr,
-			UsuarioAlteracao:       pUsuarioAlteracao,
-			EspecieAbatcomp:        pEspecieAbatcomp,
-			ObsTitulo:              pObsTitulo,
-			ContasQuitacao:         pContasQuitacao,
-			DataProgramada:         dataProgramada,
-			// CreatedAt/UpdatedAt gerenciados pelo GORM ou DB
+		entry.ID = match.ID
+		entry.RemovedAt = nil // Título que reaparece no arquivo volta a ficar ativo.
+		if errSave := tx.Save(&entry).Error; errSave != nil {
+			return appErrors.WrapErrorf(errSave, "falha ao atualizar título de obrigação ID %d (GORM)", match.ID)
 		}
-		dbEntries = append(dbEntries, dbEntry)
+		result.Updated++
 	}
 
-	appLogger.Infof("Títulos de Obrigações Processados para inserção: %d. Linhas com placeholders: %d.",
-		len(dbEntries), rowsWithPlaceholdersUsed)
-	skippedCount = 0 // Lógica de pular linhas baseada em `PESSOA` deve estar no serviço.
-
-	err = r.db.Transaction(func(tx *gorm.DB) error {
-		appLogger.Info("Deletando dados antigos de Títulos de Obrigações...")
-		if txErr := tx.Exec("DELETE FROM " + models.DBTituloObrigacao{}.TableName()).Error; txErr != nil {
-			return appErrors.WrapErrorf(txErr, "falha ao limpar dados antigos de títulos de obrigações (GORM)")
+	if len(unchangedIDs) > 0 {
+		if errUpd := tx.Model(&models.DBTituloObrigacao{}).Where("id IN ?", unchangedIDs).Update("import_token", runToken).Error; errUpd != nil {
+			return appErrors.WrapErrorf(errUpd, "falha ao marcar títulos de obrigações inalterados (GORM)")
 		}
-		appLogger.Debugf("Dados antigos de Títulos de Obrigações (%s) deletados.", models.DBTituloObrigacao{}.TableName())
-
-		if len(dbEntries) > 0 {
-			appLogger.Infof("Inserindo %d novos registros de Títulos de Obrigações...", len(dbEntries))
-			if txErr := tx.CreateInBatches(&dbEntries, 1000).Error; txErr != nil {
-				return appErrors.WrapErrorf(txErr, "falha ao inserir novos títulos de obrigações em lote (GORM)")
-			}
-			appLogger.Debugf("%d Títulos de Obrigações inseridos com sucesso.", len(dbEntries))
+	}
+	if len(toInsert) > 0 {
+		if errCreate := tx.Create(&toInsert).Error; errCreate != nil {
+			return appErrors.WrapErrorf(errCreate, "falha ao inserir novos títulos de obrigações (GORM)")
 		}
-		return nil
-	})
+		result.Inserted += len(toInsert)
+	}
+	return nil
+}
 
+// sameTituloObrigacaoContent compara os campos vindos do arquivo de dois títulos de obrigações.
+func sameTituloObrigacaoContent(a, b *models.DBTituloObrigacao) bool {
+	return equalStringPtr(a.Pessoa, b.Pessoa) &&
+		a.CNPJCPF == b.CNPJCPF &&
+		a.NumeroEmpresa == b.NumeroEmpresa &&
+		a.IdentificadorObrigacao == b.IdentificadorObrigacao &&
+		equalStringPtr(a.CodigoEspecie, b.CodigoEspecie) &&
+		equalDatePtr(a.DataVencimento, b.DataVencimento) &&
+		equalDatePtr(a.DataQuitacao, b.DataQuitacao) &&
+		equalDecimalString(&a.ValorNominalObrigacao, &b.ValorNominalObrigacao) &&
+		equalDecimalString(a.ValorPago, b.ValorPago) &&
+		equalStringPtr(a.Operacao, b.Operacao) &&
+		equalDatePtr(a.DataOperacao, b.DataOperacao) &&
+		equalDatePtr(a.DataContabiliza, b.DataContabiliza) &&
+		equalDateTimePtr(a.DataAlteracaoCSV, b.DataAlteracaoCSV) &&
+		equalStringPtr(a.Observacao, b.Observacao) &&
+		equalDecimalString(a.ValorOperacao, b.ValorOperacao) &&
+		equalStringPtr(a.UsuarioAlteracao, b.UsuarioAlteracao) &&
+		equalStringPtr(a.EspecieAbatcomp, b.EspecieAbatcomp) &&
+		equalStringPtr(a.ObsTitulo, b.ObsTitulo) &&
+		equalStringPtr(a.ContasQuitacao, b.ContasQuitacao) &&
+		equalDatePtr(a.DataProgramada, b.DataProgramada)
+}
+
+// GetFiltered busca títulos de obrigações com base nos filtros fornecidos, com paginação.
+func (r *gormTituloObrigacaoRepository) GetFiltered(filter models.TituloFilter) ([]*models.DBTituloObrigacao, models.TituloTotals, error) {
+	filter.Normalize()
+	// Session permite reutilizar as condições na totalização e na busca da página.
+	query := applyTituloFilter(r.db.Model(&models.DBTituloObrigacao{}), filter, tituloObrigacaoColumns).Session(&gorm.Session{})
+
+	// Totais antes da paginação.
+	totals, err := sumTituloTotals(query, tituloObrigacaoColumns, "títulos de obrigações")
 	if err != nil {
-		appLogger.Errorf("Erro na transação de ReplaceAll para Títulos de Obrigações: %v", err)
-		return 0, skippedCount, err
+		return nil, totals, err
 	}
+	if totals.Count == 0 {
+		return []*models.DBTituloObrigacao{}, totals, nil
+	}
+
+	var titulos []*models.DBTituloObrigacao
+	if err := query.Order(tituloOrder(filter, tituloObrigacaoColumns)).Limit(filter.Limit).Offset(filter.Offset).Find(&titulos).Error; err != nil {
+		appLogger.Errorf("Erro ao buscar títulos de obrigações filtrados: %v", err)
+		return nil, totals, appErrors.WrapErrorf(err, "falha ao buscar títulos de obrigações (GORM)")
+	}
+	return titulos, totals, nil
+}
 
-	return len(dbEntries), skippedCount, nil
+// ForEachBalance percorre em lotes os saldos dos títulos de obrigações não removidos.
+func (r *gormTituloObrigacaoRepository) ForEachBalance(fn func(batch []models.TituloBalance) error) error {
+	return forEachTituloBalance(r.db, models.DBTituloObrigacao{}.TableName(), tituloObrigacaoColumns, "títulos de obrigações", fn)
 }
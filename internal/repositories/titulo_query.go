@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+)
+
+// tituloColumns identifica as colunas que diferem entre `titulos_direitos` e `titulos_obrigacoes`.
+type tituloColumns struct {
+	titulo       string // Identificador do título.
+	valorNominal string // Valor nominal (string decimal "XXXX.YY").
+}
+
+var (
+	tituloDireitoColumns   = tituloColumns{titulo: "titulo", valorNominal: "valor_nominal"}
+	tituloObrigacaoColumns = tituloColumns{titulo: "identificador_obrigacao", valorNominal: "valor_nominal_obrigacao"}
+)
+
+// numericExpr converte uma coluna de valor (armazenada como string) para número no SQL.
+// CAST AS NUMERIC é aceito tanto pelo PostgreSQL quanto pelo SQLite; strings vazias viram NULL.
+func numericExpr(column string) string {
+	return "CAST(NULLIF(" + column + ", '') AS NUMERIC)"
+}
+
+// applyTituloFilter aplica os filtros de `filter` (já normalizado) à consulta de títulos.
+func applyTituloFilter(query *gorm.DB, filter models.TituloFilter, cols tituloColumns) *gorm.DB {
+	if !filter.IncludeRemoved {
+		query = query.Where("removed_at IS NULL")
+	}
+	if filter.CNPJCPF != nil {
+		if len(*filter.CNPJCPF) == 11 || len(*filter.CNPJCPF) == 14 {
+			query = query.Where("cnpjcpf = ?", *filter.CNPJCPF)
+		} else {
+			query = query.Where("cnpjcpf LIKE ?", *filter.CNPJCPF+"%")
+		}
+	}
+	if filter.NetworkID != nil {
+		query = query.Where("network_id = ?", *filter.NetworkID)
+	}
+	if filter.NumeroEmpresa != nil {
+		query = query.Where("numero_empresa = ?", *filter.NumeroEmpresa)
+	}
+	// As datas dos títulos são gravadas sem fuso (UTC), então os limites do dia também são em UTC.
+	if filter.DueFrom != nil {
+		startOfDay := time.Date(filter.DueFrom.Year(), filter.DueFrom.Month(), filter.DueFrom.Day(), 0, 0, 0, 0, time.UTC)
+		query = query.Where("data_vencimento >= ?", startOfDay)
+	}
+	if filter.DueTo != nil {
+		nextDay := time.Date(filter.DueTo.Year(), filter.DueTo.Month(), filter.DueTo.Day()+1, 0, 0, 0, 0, time.UTC)
+		query = query.Where("data_vencimento < ?", nextDay)
+	}
+	switch filter.Status {
+	case models.TituloStatusOpen:
+		query = query.Where("data_quitacao IS NULL")
+	case models.TituloStatusSettled:
+		query = query.Where("data_quitacao IS NOT NULL")
+	}
+	if filter.CodigoEspecie != nil {
+		query = query.Where("LOWER(codigo_especie) = LOWER(?)", *filter.CodigoEspecie)
+	}
+	if filter.MinValue != nil {
+		query = query.Where(numericExpr(cols.valorNominal)+" >= ?", filter.MinValue.String())
+	}
+	if filter.MaxValue != nil {
+		query = query.Where(numericExpr(cols.valorNominal)+" <= ?", filter.MaxValue.String())
+	}
+	return query
+}
+
+// tituloOrder retorna a cláusula ORDER BY da consulta de títulos. O ID desempata a ordenação,
+// mantendo a paginação estável.
+func tituloOrder(filter models.TituloFilter, cols tituloColumns) string {
+	column := "data_vencimento"
+	switch filter.SortBy {
+	case models.TituloSortSettledDate:
+		column = "data_quitacao"
+	case models.TituloSortNominalValue:
+		column = numericExpr(cols.valorNominal)
+	case models.TituloSortCNPJCPF:
+		column = "cnpjcpf"
+	case models.TituloSortNumeroEmpresa:
+		column = "numero_empresa"
+	case models.TituloSortTitulo:
+		column = cols.titulo
+	}
+	direction := " ASC"
+	if filter.SortDesc {
+		direction = " DESC"
+	}
+	return column + direction + ", id" + direction
+}
+
+// sumTituloTotals calcula a contagem e as somas de valor nominal e pago do conjunto filtrado.
+func sumTituloTotals(query *gorm.DB, cols tituloColumns, tableLabel string) (models.TituloTotals, error) {
+	var totals models.TituloTotals
+	var nominal, paid sql.NullString
+	row := query.Select("COUNT(*), SUM(" + numericExpr(cols.valorNominal) + "), SUM(" + numericExpr("valor_pago") + ")").Row()
+	if err := row.Scan(&totals.Count, &nominal, &paid); err != nil {
+		appLogger.Errorf("Erro ao totalizar %s filtrados: %v", tableLabel, err)
+		return totals, appErrors.WrapErrorf(err, "falha ao totalizar %s (GORM)", tableLabel)
+	}
+
+	var err error
+	if totals.ValorNominal, err = parseSum(nominal); err != nil {
+		return totals, appErrors.WrapErrorf(err, "falha ao converter total de valor nominal de %s", tableLabel)
+	}
+	if totals.ValorPago, err = parseSum(paid); err != nil {
+		return totals, appErrors.WrapErrorf(err, "falha ao converter total de valor pago de %s", tableLabel)
+	}
+	return totals, nil
+}
+
+// parseSum converte o resultado de SUM para decimal com 2 casas. No SQLite a soma pode vir em
+// ponto flutuante (inclusive em notação científica), daí o arredondamento.
+func parseSum(value sql.NullString) (decimal.Decimal, error) {
+	if !value.Valid || value.String == "" {
+		return decimal.Zero, nil
+	}
+	d, err := decimal.NewFromString(value.String)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return d.Round(2), nil
+}
+
+// forEachTituloBalance percorre, em lotes de `importBatchSize`, os saldos dos títulos não removidos
+// de `table`. Os lotes são reutilizados entre as chamadas de `fn` e não devem ser retidos.
+// Usa Table (e não Model) para que o FindInBatches pagine pela chave primária de TituloBalance.
+func forEachTituloBalance(db *gorm.DB, table string, cols tituloColumns, tableLabel string, fn func(batch []models.TituloBalance) error) error {
+	var batch []models.TituloBalance
+	result := db.Table(table).
+		Select("id, cnpjcpf, pessoa, "+cols.titulo+" AS titulo, numero_empresa, codigo_especie, network_id, data_vencimento, data_programada, "+cols.valorNominal+" AS valor_nominal, valor_pago").
+		Where("removed_at IS NULL").
+		FindInBatches(&batch, importBatchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		})
+	if result.Error != nil {
+		appLogger.Errorf("Erro ao percorrer saldos de %s: %v", tableLabel, result.Error)
+		return appErrors.WrapErrorf(result.Error, "falha ao percorrer saldos de %s (GORM)", tableLabel)
+	}
+	return nil
+}
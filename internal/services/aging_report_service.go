@@ -0,0 +1,306 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/config"
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/repositories"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/utils"
+)
+
+const (
+	agingNoNetworkLabel = "Sem rede"
+	agingNoBuyerLabel   = "Sem comprador"
+)
+
+// AgingReportService define a interface do relatório de aging (saldos em aberto por faixa de atraso)
+// dos títulos de direitos (a receber) e obrigações (a pagar).
+type AgingReportService interface {
+	// GenerateAgingReport calcula o aging dos títulos não removidos do tipo `fileType`, agrupado
+	// por `groupBy`, na data de referência informada. Os dias de atraso contam a partir do vencimento
+	// efetivo (próximo dia útil). Para direitos, soma também a multa e os juros de mora na data de
+	// referência. Exige `auth.PermTituloView`.
+	GenerateAgingReport(fileType FileType, groupBy models.AgingGroupBy, referenceDate time.Time, userSession *auth.SessionData) (*models.AgingReport, error)
+
+	// ExportAgingReport gera o relatório e o exporta para um XLSX em `ExportDir`.
+	// Exige `auth.PermTituloView` e `auth.PermExportData`.
+	ExportAgingReport(fileType FileType, groupBy models.AgingGroupBy, referenceDate time.Time, userSession *auth.SessionData) (*models.AgingReport, string, error)
+}
+
+// agingReportServiceImpl é a implementação de AgingReportService.
+type agingReportServiceImpl struct {
+	cfg             *core.Config
+	direitoRepo     repositories.TituloDireitoRepository
+	obrigacaoRepo   repositories.TituloObrigacaoRepository
+	networkRepo     repositories.NetworkRepository
+	holidayService  HolidayService
+	chargeService   ChargeService
+	auditLogService AuditLogService
+	permManager     *auth.PermissionManager
+}
+
+// NewAgingReportService cria uma nova instância de AgingReportService.
+func NewAgingReportService(
+	cfg *core.Config,
+	direitoRepo repositories.TituloDireitoRepository,
+	obrigacaoRepo repositories.TituloObrigacaoRepository,
+	networkRepo repositories.NetworkRepository,
+	holidayService HolidayService,
+	chargeService ChargeService,
+	auditLogService AuditLogService,
+	permManager *auth.PermissionManager,
+) AgingReportService {
+	if cfg == nil || direitoRepo == nil || obrigacaoRepo == nil || networkRepo == nil || holidayService == nil || chargeService == nil || auditLogService == nil || permManager == nil {
+		appLogger.Fatalf("Dependências nulas fornecidas para NewAgingReportService")
+	}
+	return &agingReportServiceImpl{
+		cfg:             cfg,
+		direitoRepo:     direitoRepo,
+		obrigacaoRepo:   obrigacaoRepo,
+		networkRepo:     networkRepo,
+		holidayService:  holidayService,
+		chargeService:   chargeService,
+		auditLogService: auditLogService,
+		permManager:     permManager,
+	}
+}
+
+// agingGroupResolver devolve a chave e o rótulo do grupo de um título.
+type agingGroupResolver func(balance *models.TituloBalance) (key, label string)
+
+// newAgingGroupResolver monta o resolvedor de grupos. Para rede e comprador, carrega as redes
+// (inclusive inativas) uma única vez; títulos sem rede vinculada caem em um grupo próprio.
+func (s *agingReportServiceImpl) newAgingGroupResolver(groupBy models.AgingGroupBy) (agingGroupResolver, error) {
+	switch groupBy {
+	case models.AgingGroupByCNPJ:
+		return func(b *models.TituloBalance) (string, string) {
+			return b.CNPJCPF, models.FormatCNPJCPF(b.CNPJCPF)
+		}, nil
+	case models.AgingGroupByEmpresa:
+		return func(b *models.TituloBalance) (string, string) {
+			numero := strconv.Itoa(b.NumeroEmpresa)
+			return numero, numero
+		}, nil
+	}
+
+	networks, err := s.networkRepo.GetAll(true)
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	byID := make(map[uint64]models.DBNetwork, len(networks))
+	for _, network := range networks {
+		byID[network.ID] = network
+	}
+
+	if groupBy == models.AgingGroupByNetwork {
+		return func(b *models.TituloBalance) (string, string) {
+			if b.NetworkID != nil {
+				if network, ok := byID[*b.NetworkID]; ok {
+					return strconv.FormatUint(network.ID, 10), network.Name
+				}
+			}
+			return "", agingNoNetworkLabel
+		}, nil
+	}
+	return func(b *models.TituloBalance) (string, string) {
+		if b.NetworkID != nil {
+			if network, ok := byID[*b.NetworkID]; ok && strings.TrimSpace(network.Buyer) != "" {
+				return strings.ToLower(network.Buyer), network.Buyer
+			}
+		}
+		return "", agingNoBuyerLabel
+	}, nil
+}
+
+// GenerateAgingReport calcula o relatório de aging.
+func (s *agingReportServiceImpl) GenerateAgingReport(fileType FileType, groupBy models.AgingGroupBy, referenceDate time.Time, userSession *auth.SessionData) (*models.AgingReport, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermTituloView, nil); err != nil {
+		return nil, err
+	}
+	groupBy = models.AgingGroupBy(strings.ToUpper(strings.TrimSpace(string(groupBy))))
+	if err := groupBy.Validate(); err != nil {
+		return nil, err
+	}
+	if referenceDate.IsZero() {
+		return nil, fmt.Errorf("%w: data de referência do aging é obrigatória", appErrors.ErrInvalidInput)
+	}
+
+	var forEachBalance func(fn func(batch []models.TituloBalance) error) error
+	switch fileType {
+	case FileTypeDireitos:
+		forEachBalance = s.direitoRepo.ForEachBalance
+	case FileTypeObrigacoes:
+		forEachBalance = s.obrigacaoRepo.ForEachBalance
+	default:
+		return nil, fmt.Errorf("%w: tipo de título '%s' inválido para o aging", appErrors.ErrInvalidInput, fileType)
+	}
+
+	resolveGroup, err := s.newAgingGroupResolver(groupBy)
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := s.holidayService.BusinessCalendar()
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	// Encargos só se aplicam aos direitos (a receber).
+	var chargeEngine *models.ChargeEngine
+	if fileType == FileTypeDireitos {
+		if chargeEngine, err = s.chargeService.ChargeEngine(); err != nil {
+			return nil, err
+		}
+	}
+
+	// As datas dos títulos são gravadas sem fuso (UTC); a referência é o mesmo dia do calendário em UTC.
+	refDay := time.Date(referenceDate.Year(), referenceDate.Month(), referenceDate.Day(), 0, 0, 0, 0, time.UTC)
+	report := &models.AgingReport{
+		FileType:      string(fileType),
+		GroupBy:       groupBy,
+		ReferenceDate: refDay,
+		GeneratedAt:   time.Now(),
+		Totals:        models.AgingReportRow{Label: "Total"},
+		WithCharges:   chargeEngine != nil,
+	}
+	groups := make(map[string]*models.AgingReportRow)
+
+	err = forEachBalance(func(batch []models.TituloBalance) error {
+		for i := range batch {
+			balance := &batch[i]
+			open, errBalance := balance.OpenBalance()
+			if errBalance != nil {
+				appLogger.Warnf("Aging: título ID %d ignorado: %v", balance.ID, errBalance)
+				report.InvalidTitles++
+				continue
+			}
+			// Títulos quitados ou pagos a maior não têm saldo em aberto.
+			if !open.IsPositive() {
+				continue
+			}
+
+			// Título sem data de vencimento é tratado como "a vencer". Vencimento em fim de semana ou
+			// feriado só é exigível no dia útil seguinte, e o atraso conta a partir dele.
+			bucket := models.AgingBucketNotDue
+			if balance.DataVencimento != nil {
+				dueDay := calendar.NextBusinessDay(*balance.DataVencimento)
+				bucket = models.AgingBucketFor(int(refDay.Sub(dueDay).Hours() / 24))
+			}
+
+			key, label := resolveGroup(balance)
+			group, ok := groups[key]
+			if !ok {
+				group = &models.AgingReportRow{Key: key, Label: label}
+				groups[key] = group
+			}
+			group.Add(bucket, open)
+			report.Totals.Add(bucket, open)
+			if chargeEngine != nil {
+				calc, errCharge := chargeEngine.Calculate(balance, refDay)
+				if errCharge != nil {
+					return errCharge
+				}
+				group.AddCharges(calc)
+				report.Totals.AddCharges(calc)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+
+	report.Rows = make([]models.AgingReportRow, 0, len(groups))
+	for _, group := range groups {
+		report.Rows = append(report.Rows, *group)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if cmp := report.Rows[i].Total.Cmp(report.Rows[j].Total); cmp != 0 {
+			return cmp > 0
+		}
+		return report.Rows[i].Label < report.Rows[j].Label
+	})
+
+	appLogger.Infof("Aging de %s por %s em %s gerado por '%s': %d grupos, %d títulos em aberto, total %s.",
+		fileType, groupBy, refDay.Format("02/01/2006"), userSession.Username, len(report.Rows), report.Totals.TitleCount, report.Totals.Total.StringFixed(2))
+	return report, nil
+}
+
+// agingExportRows monta a planilha do relatório: cabeçalho, uma linha por grupo e a linha de total.
+// Valores vão como "1234.56" para que o exportador XLSX os grave como números.
+func agingExportRows(report *models.AgingReport) [][]string {
+	headers := []string{report.GroupBy.Label(), "Títulos"}
+	headers = append(headers, models.AgingBucketLabels[:]...)
+	headers = append(headers, "Total")
+	if report.WithCharges {
+		headers = append(headers, "Multa", "Juros", "Total atualizado")
+	}
+
+	data := make([][]string, 0, len(report.Rows)+2)
+	data = append(data, headers)
+	appendRow := func(row models.AgingReportRow) {
+		line := make([]string, 0, len(headers))
+		line = append(line, row.Label, strconv.Itoa(row.TitleCount))
+		for _, value := range row.Buckets {
+			line = append(line, value.StringFixed(2))
+		}
+		line = append(line, row.Total.StringFixed(2))
+		if report.WithCharges {
+			line = append(line, row.Fine.StringFixed(2), row.Interest.StringFixed(2), row.UpdatedTotal().StringFixed(2))
+		}
+		data = append(data, line)
+	}
+	for _, row := range report.Rows {
+		appendRow(row)
+	}
+	appendRow(report.Totals)
+	return data
+}
+
+// ExportAgingReport gera o relatório de aging e o exporta para XLSX.
+func (s *agingReportServiceImpl) ExportAgingReport(fileType FileType, groupBy models.AgingGroupBy, referenceDate time.Time, userSession *auth.SessionData) (*models.AgingReport, string, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermExportData, nil); err != nil {
+		return nil, "", err
+	}
+	report, err := s.GenerateAgingReport(fileType, groupBy, referenceDate, userSession)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sheetName := fmt.Sprintf("Aging %s", report.ReferenceDate.Format("02-01-2006"))
+	input, err := utils.NewSliceDataInput(agingExportRows(report), sheetName)
+	if err != nil {
+		return nil, "", err
+	}
+	fileName := fmt.Sprintf("aging_%s_%s_%s.xlsx",
+		strings.ToLower(string(fileType)), strings.ToLower(string(report.GroupBy)), report.GeneratedAt.Format("20060102_150405"))
+	exportPath, err := utils.ExportToXLSX([]utils.DataInput{input}, fileName, s.cfg, nil)
+	if err != nil {
+		appLogger.Errorf("Erro ao exportar o aging dos títulos do tipo %s: %v", fileType, err)
+		return nil, "", err
+	}
+
+	s.auditLogService.LogAction(models.AuditLogEntry{
+		Action: "AGING_REPORT_EXPORT",
+		Description: fmt.Sprintf("Aging dos títulos do tipo %s por %s em %s exportado para '%s'.",
+			fileType, report.GroupBy.Label(), report.ReferenceDate.Format("02/01/2006"), exportPath),
+		Severity: "INFO",
+		Metadata: map[string]interface{}{
+			"file_type":      fileType,
+			"group_by":       report.GroupBy,
+			"reference_date": report.ReferenceDate.Format("2006-01-02"),
+			"export_file":    exportPath,
+			"groups":         len(report.Rows),
+			"open_titles":    report.Totals.TitleCount,
+			"open_total":     report.Totals.Total.StringFixed(2),
+			"with_charges":   report.WithCharges,
+		},
+	}, userSession)
+	return report, exportPath, nil
+}
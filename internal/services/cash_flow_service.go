@@ -0,0 +1,247 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/config"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/repositories"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/utils"
+)
+
+// CashFlowService define a interface da projeção de fluxo de caixa: entradas previstas pelos
+// direitos em aberto e saídas previstas pelas obrigações em aberto.
+type CashFlowService interface {
+	// ProjectCashFlow distribui os saldos em aberto pelos períodos do horizonte, conforme a data
+	// de vencimento (ou a programada, se `UseScheduledDate`) ajustada ao próximo dia útil.
+	// Exige `auth.PermTituloView`.
+	ProjectCashFlow(options models.CashFlowOptions, userSession *auth.SessionData) (*models.CashFlowProjection, error)
+
+	// ExportCashFlow gera a projeção e a exporta para um XLSX em `ExportDir`.
+	// Exige `auth.PermTituloView` e `auth.PermExportData`.
+	ExportCashFlow(options models.CashFlowOptions, userSession *auth.SessionData) (*models.CashFlowProjection, string, error)
+}
+
+// cashFlowServiceImpl é a implementação de CashFlowService.
+type cashFlowServiceImpl struct {
+	cfg             *core.Config
+	direitoRepo     repositories.TituloDireitoRepository
+	obrigacaoRepo   repositories.TituloObrigacaoRepository
+	holidayService  HolidayService
+	auditLogService AuditLogService
+	permManager     *auth.PermissionManager
+}
+
+// NewCashFlowService cria uma nova instância de CashFlowService.
+func NewCashFlowService(
+	cfg *core.Config,
+	direitoRepo repositories.TituloDireitoRepository,
+	obrigacaoRepo repositories.TituloObrigacaoRepository,
+	holidayService HolidayService,
+	auditLogService AuditLogService,
+	permManager *auth.PermissionManager,
+) CashFlowService {
+	if cfg == nil || direitoRepo == nil || obrigacaoRepo == nil || holidayService == nil || auditLogService == nil || permManager == nil {
+		appLogger.Fatalf("Dependências nulas fornecidas para NewCashFlowService")
+	}
+	return &cashFlowServiceImpl{
+		cfg:             cfg,
+		direitoRepo:     direitoRepo,
+		obrigacaoRepo:   obrigacaoRepo,
+		holidayService:  holidayService,
+		auditLogService: auditLogService,
+		permManager:     permManager,
+	}
+}
+
+// cashFlowPeriods monta os períodos vazios de `start` a `end` (inclusive). O primeiro e o último
+// período são limitados ao horizonte, mesmo que a semana ou o mês continuem fora dele.
+func cashFlowPeriods(g models.CashFlowGranularity, start, end time.Time) []models.CashFlowPeriod {
+	var periods []models.CashFlowPeriod
+	for periodStart := g.PeriodStart(start); !periodStart.After(end); periodStart = g.NextPeriodStart(periodStart) {
+		period := models.CashFlowPeriod{Start: periodStart, End: g.NextPeriodStart(periodStart).AddDate(0, 0, -1)}
+		if period.Start.Before(start) {
+			period.Start = start
+		}
+		if period.End.After(end) {
+			period.End = end
+		}
+		periods = append(periods, period)
+	}
+	return periods
+}
+
+// ProjectCashFlow calcula a projeção de fluxo de caixa.
+func (s *cashFlowServiceImpl) ProjectCashFlow(options models.CashFlowOptions, userSession *auth.SessionData) (*models.CashFlowProjection, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermTituloView, nil); err != nil {
+		return nil, err
+	}
+	options.Granularity = models.CashFlowGranularity(strings.ToUpper(strings.TrimSpace(string(options.Granularity))))
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	// As datas dos títulos são gravadas sem fuso (UTC); o início é o mesmo dia do calendário em UTC.
+	start := time.Date(options.StartDate.Year(), options.StartDate.Month(), options.StartDate.Day(), 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, options.HorizonDays-1)
+	options.StartDate = start
+	projection := &models.CashFlowProjection{
+		Options:     options,
+		EndDate:     end,
+		GeneratedAt: time.Now(),
+		Periods:     cashFlowPeriods(options.Granularity, start, end),
+	}
+	calendar, err := s.holidayService.BusinessCalendar()
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+
+	visit := func(direito bool) func(batch []models.TituloBalance) error {
+		return func(batch []models.TituloBalance) error {
+			for i := range batch {
+				balance := &batch[i]
+				open, errBalance := balance.OpenBalance()
+				if errBalance != nil {
+					appLogger.Warnf("Fluxo de caixa: título ID %d ignorado: %v", balance.ID, errBalance)
+					projection.InvalidTitles++
+					continue
+				}
+				// Títulos quitados ou pagos a maior não têm saldo em aberto.
+				if !open.IsPositive() {
+					continue
+				}
+
+				date := balance.DataVencimento
+				if options.UseScheduledDate && balance.DataProgramada != nil {
+					date = balance.DataProgramada
+				}
+
+				var period *models.CashFlowPeriod
+				if date == nil {
+					period = &projection.Undated
+				} else {
+					// Datas em fim de semana ou feriado são liquidadas no dia útil seguinte.
+					day := calendar.NextBusinessDay(*date)
+					switch {
+					case day.Before(start):
+						period = &projection.Overdue
+					case day.After(end):
+						projection.BeyondHorizon++
+						continue
+					default:
+						// Os períodos estão em ordem: o do dia é o último que começa até ele.
+						idx := sort.Search(len(projection.Periods), func(i int) bool { return projection.Periods[i].Start.After(day) }) - 1
+						period = &projection.Periods[idx]
+					}
+				}
+				if direito {
+					period.AddInflow(open)
+				} else {
+					period.AddOutflow(open)
+				}
+			}
+			return nil
+		}
+	}
+	if err := s.direitoRepo.ForEachBalance(visit(true)); err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	if err := s.obrigacaoRepo.ForEachBalance(visit(false)); err != nil {
+		return nil, err
+	}
+
+	projection.Totals = models.CashFlowPeriod{Start: start, End: end}
+	for i := range projection.Periods {
+		period := &projection.Periods[i]
+		projection.Totals.Inflow = projection.Totals.Inflow.Add(period.Inflow)
+		projection.Totals.Outflow = projection.Totals.Outflow.Add(period.Outflow)
+		projection.Totals.Net = projection.Totals.Net.Add(period.Net)
+		projection.Totals.InflowCount += period.InflowCount
+		projection.Totals.OutflowCount += period.OutflowCount
+		period.Cumulative = projection.Totals.Net
+	}
+	projection.Totals.Cumulative = projection.Totals.Net
+
+	appLogger.Infof("Fluxo de caixa %s de %s a %s gerado por '%s' (data programada: %t): entradas %s, saídas %s, saldo %s.",
+		options.Granularity, start.Format("02/01/2006"), end.Format("02/01/2006"), userSession.Username, options.UseScheduledDate,
+		projection.Totals.Inflow.StringFixed(2), projection.Totals.Outflow.StringFixed(2), projection.Totals.Net.StringFixed(2))
+	return projection, nil
+}
+
+// cashFlowExportRows monta a planilha da projeção: cabeçalho, uma linha por período, a linha de total
+// e, separados, os vencidos antes do início e os sem data.
+// Valores vão como "1234.56" para que o exportador XLSX os grave como números.
+func cashFlowExportRows(projection *models.CashFlowProjection) [][]string {
+	headers := []string{"Período", "Início", "Fim", "Entradas", "Títulos a receber", "Saídas", "Títulos a pagar", "Saldo do período", "Saldo acumulado"}
+	data := make([][]string, 0, len(projection.Periods)+5)
+	data = append(data, headers)
+	appendRow := func(label string, period models.CashFlowPeriod, withDates, withCumulative bool) {
+		start, end, cumulative := "", "", ""
+		if withDates {
+			start, end = period.Start.Format("02/01/2006"), period.End.Format("02/01/2006")
+		}
+		if withCumulative {
+			cumulative = period.Cumulative.StringFixed(2)
+		}
+		data = append(data, []string{label, start, end,
+			period.Inflow.StringFixed(2), strconv.Itoa(period.InflowCount),
+			period.Outflow.StringFixed(2), strconv.Itoa(period.OutflowCount),
+			period.Net.StringFixed(2), cumulative})
+	}
+	for _, period := range projection.Periods {
+		appendRow(period.Label(projection.Options.Granularity), period, true, true)
+	}
+	appendRow("Total", projection.Totals, true, true)
+	data = append(data, []string{})
+	appendRow("Vencidos antes do início (fora do saldo)", projection.Overdue, false, false)
+	appendRow("Sem data (fora do saldo)", projection.Undated, false, false)
+	return data
+}
+
+// ExportCashFlow gera a projeção de fluxo de caixa e a exporta para XLSX.
+func (s *cashFlowServiceImpl) ExportCashFlow(options models.CashFlowOptions, userSession *auth.SessionData) (*models.CashFlowProjection, string, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermExportData, nil); err != nil {
+		return nil, "", err
+	}
+	projection, err := s.ProjectCashFlow(options, userSession)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sheetName := fmt.Sprintf("Fluxo %s", projection.Options.Granularity.Label())
+	input, err := utils.NewSliceDataInput(cashFlowExportRows(projection), sheetName)
+	if err != nil {
+		return nil, "", err
+	}
+	fileName := fmt.Sprintf("fluxo_caixa_%s_%s_%s.xlsx",
+		strings.ToLower(string(projection.Options.Granularity)), projection.Options.StartDate.Format("20060102"), projection.GeneratedAt.Format("20060102_150405"))
+	exportPath, err := utils.ExportToXLSX([]utils.DataInput{input}, fileName, s.cfg, nil)
+	if err != nil {
+		appLogger.Errorf("Erro ao exportar a projeção de fluxo de caixa: %v", err)
+		return nil, "", err
+	}
+
+	s.auditLogService.LogAction(models.AuditLogEntry{
+		Action: "CASH_FLOW_EXPORT",
+		Description: fmt.Sprintf("Projeção de fluxo de caixa (%s) de %s a %s exportada para '%s'.",
+			projection.Options.Granularity.Label(), projection.Options.StartDate.Format("02/01/2006"), projection.EndDate.Format("02/01/2006"), exportPath),
+		Severity: "INFO",
+		Metadata: map[string]interface{}{
+			"granularity":        projection.Options.Granularity,
+			"start_date":         projection.Options.StartDate.Format("2006-01-02"),
+			"end_date":           projection.EndDate.Format("2006-01-02"),
+			"use_scheduled_date": projection.Options.UseScheduledDate,
+			"export_file":        exportPath,
+			"inflow_total":       projection.Totals.Inflow.StringFixed(2),
+			"outflow_total":      projection.Totals.Outflow.StringFixed(2),
+			"net_total":          projection.Totals.Net.StringFixed(2),
+		},
+	}, userSession)
+	return projection, exportPath, nil
+}
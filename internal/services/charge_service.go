@@ -0,0 +1,253 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/repositories"
+)
+
+// ChargeService define a interface das regras de encargos (multa e juros de mora) dos títulos vencidos.
+type ChargeService interface {
+	// ChargeEngine monta o motor de encargos com as regras ativas e o calendário de dias úteis.
+	// Usado pelos serviços que exibem o valor atualizado dos títulos; não verifica permissão.
+	ChargeEngine() (*models.ChargeEngine, error)
+
+	// ListChargeRules retorna as regras de encargos, opcionalmente incluindo as inativas.
+	// Exige `auth.PermTituloView`.
+	ListChargeRules(includeInactive bool, userSession *auth.SessionData) ([]*models.ChargeRule, error)
+
+	// CreateChargeRule cria uma regra de encargos. Exige `auth.PermChargeManage`.
+	CreateChargeRule(ruleData models.ChargeRuleCreate, userSession *auth.SessionData) (*models.ChargeRule, error)
+
+	// UpdateChargeRule substitui os campos de uma regra de encargos. Exige `auth.PermChargeManage`.
+	UpdateChargeRule(ruleID uint64, ruleData models.ChargeRuleCreate, userSession *auth.SessionData) (*models.ChargeRule, error)
+
+	// DeleteChargeRule exclui uma regra de encargos. Exige `auth.PermChargeManage`.
+	DeleteChargeRule(ruleID uint64, userSession *auth.SessionData) error
+}
+
+// chargeServiceImpl é a implementação de ChargeService.
+type chargeServiceImpl struct {
+	repo            repositories.ChargeRuleRepository
+	networkRepo     repositories.NetworkRepository
+	holidayService  HolidayService
+	auditLogService AuditLogService
+	permManager     *auth.PermissionManager
+}
+
+// NewChargeService cria uma nova instância de ChargeService.
+func NewChargeService(
+	repo repositories.ChargeRuleRepository,
+	networkRepo repositories.NetworkRepository,
+	holidayService HolidayService,
+	auditLog AuditLogService,
+	pm *auth.PermissionManager,
+) ChargeService {
+	if repo == nil || networkRepo == nil || holidayService == nil || auditLog == nil || pm == nil {
+		appLogger.Fatalf("Dependências nulas fornecidas para NewChargeService (repo, networkRepo, holidayService, auditLog, permManager)")
+	}
+	return &chargeServiceImpl{
+		repo:            repo,
+		networkRepo:     networkRepo,
+		holidayService:  holidayService,
+		auditLogService: auditLog,
+		permManager:     pm,
+	}
+}
+
+// ChargeEngine monta um novo motor a cada chamada, para refletir as regras e os feriados
+// alterados desde a anterior.
+func (s *chargeServiceImpl) ChargeEngine() (*models.ChargeEngine, error) {
+	dbRules, err := s.repo.GetAll(false)
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	rules := make([]models.ChargeRule, 0, len(dbRules))
+	for i := range dbRules {
+		rule, errConv := models.ToChargeRule(&dbRules[i])
+		if errConv != nil {
+			appLogger.Errorf("Erro ao converter regra de encargos: %v", errConv)
+			return nil, appErrors.WrapErrorf(errConv, "falha ao carregar as regras de encargos")
+		}
+		rules = append(rules, *rule)
+	}
+	calendar, err := s.holidayService.BusinessCalendar()
+	if err != nil {
+		return nil, err
+	}
+	return models.NewChargeEngine(rules, calendar), nil
+}
+
+// ListChargeRules retorna as regras de encargos.
+func (s *chargeServiceImpl) ListChargeRules(includeInactive bool, userSession *auth.SessionData) ([]*models.ChargeRule, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermTituloView, nil); err != nil {
+		return nil, err
+	}
+	dbRules, err := s.repo.GetAll(includeInactive)
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	result := make([]*models.ChargeRule, 0, len(dbRules))
+	for i := range dbRules {
+		rule, errConv := models.ToChargeRule(&dbRules[i])
+		if errConv != nil {
+			appLogger.Errorf("Erro ao converter regra de encargos: %v", errConv)
+			return nil, appErrors.WrapErrorf(errConv, "falha ao carregar as regras de encargos")
+		}
+		result = append(result, rule)
+	}
+	return result, nil
+}
+
+// validateScope verifica se a rede da regra existe e se nenhuma outra regra ativa cobre a mesma
+// espécie e rede, o que tornaria a escolha da regra ambígua. `ruleID` é 0 na criação.
+func (s *chargeServiceImpl) validateScope(ruleID uint64, ruleData models.ChargeRuleCreate) error {
+	if ruleData.NetworkID != nil {
+		if _, err := s.networkRepo.GetByID(*ruleData.NetworkID); err != nil {
+			return err
+		}
+	}
+	if !ruleData.Active {
+		return nil
+	}
+	existing, err := s.repo.GetAll(false)
+	if err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if other.ID == ruleID || !sameOptionalString(other.CodigoEspecie, ruleData.CodigoEspecie) ||
+			!sameOptionalID(other.NetworkID, ruleData.NetworkID) {
+			continue
+		}
+		return fmt.Errorf("%w: a regra ativa '%s' (ID %d) já cobre esta espécie e rede", appErrors.ErrConflict, other.Name, other.ID)
+	}
+	return nil
+}
+
+// sameOptionalString compara dois textos opcionais; nil só é igual a nil.
+func sameOptionalString(a, b *string) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+// sameOptionalID compara dois IDs opcionais; nil só é igual a nil.
+func sameOptionalID(a, b *uint64) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+// chargeRuleAuditMetadata monta os metadados de auditoria de uma regra.
+func chargeRuleAuditMetadata(rule *models.ChargeRule) map[string]interface{} {
+	return map[string]interface{}{
+		"rule_id": rule.ID, "name": rule.Name, "codigo_especie": rule.CodigoEspecie, "network_id": rule.NetworkID,
+		"fine_type": rule.FineType, "fine_value": rule.FineValue.String(),
+		"interest_period": rule.InterestPeriod, "interest_rate": rule.InterestRate.String(), "interest_method": rule.InterestMethod,
+		"grace_days": rule.GraceDays, "active": rule.Active,
+	}
+}
+
+// CreateChargeRule cria uma regra de encargos.
+func (s *chargeServiceImpl) CreateChargeRule(ruleData models.ChargeRuleCreate, userSession *auth.SessionData) (*models.ChargeRule, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermChargeManage, nil); err != nil {
+		return nil, err
+	}
+	if err := ruleData.CleanAndValidate(); err != nil {
+		appLogger.Warnf("Dados de criação de regra de encargos inválidos para '%s': %v", ruleData.Name, err)
+		return nil, err // Retorna o ValidationError.
+	}
+	if err := s.validateScope(0, ruleData); err != nil {
+		return nil, err
+	}
+
+	dbRule, err := s.repo.Create(ruleData, userSession.Username)
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	rule, err := models.ToChargeRule(dbRule)
+	if err != nil {
+		return nil, appErrors.WrapErrorf(err, "falha ao converter a regra de encargos criada")
+	}
+
+	logEntry := models.AuditLogEntry{
+		Action:      "CHARGE_RULE_CREATE",
+		Description: fmt.Sprintf("Regra de encargos '%s' criada: %s.", rule.Name, rule.Describe()),
+		Severity:    "INFO",
+		Metadata:    chargeRuleAuditMetadata(rule),
+	}
+	if logErr := s.auditLogService.LogAction(logEntry, userSession); logErr != nil {
+		appLogger.Warnf("Falha ao registrar log de auditoria para criação da regra de encargos '%s': %v", rule.Name, logErr)
+	}
+	return rule, nil
+}
+
+// UpdateChargeRule substitui os campos de uma regra de encargos.
+func (s *chargeServiceImpl) UpdateChargeRule(ruleID uint64, ruleData models.ChargeRuleCreate, userSession *auth.SessionData) (*models.ChargeRule, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermChargeManage, nil); err != nil {
+		return nil, err
+	}
+	if err := ruleData.CleanAndValidate(); err != nil {
+		appLogger.Warnf("Dados de atualização da regra de encargos ID %d inválidos: %v", ruleID, err)
+		return nil, err
+	}
+	oldDBRule, err := s.repo.GetByID(ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateScope(ruleID, ruleData); err != nil {
+		return nil, err
+	}
+
+	dbRule, err := s.repo.Update(ruleID, ruleData, userSession.Username)
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	rule, err := models.ToChargeRule(dbRule)
+	if err != nil {
+		return nil, appErrors.WrapErrorf(err, "falha ao converter a regra de encargos atualizada")
+	}
+
+	metadata := chargeRuleAuditMetadata(rule)
+	metadata["old_fine_value"] = oldDBRule.FineValue
+	metadata["old_interest_rate"] = oldDBRule.InterestRate
+	metadata["old_grace_days"] = oldDBRule.GraceDays
+	metadata["old_active"] = oldDBRule.Active
+	logEntry := models.AuditLogEntry{
+		Action:      "CHARGE_RULE_UPDATE",
+		Description: fmt.Sprintf("Regra de encargos ID %d ('%s') atualizada: %s.", rule.ID, rule.Name, rule.Describe()),
+		Severity:    "INFO",
+		Metadata:    metadata,
+	}
+	if logErr := s.auditLogService.LogAction(logEntry, userSession); logErr != nil {
+		appLogger.Warnf("Falha ao registrar log de auditoria para atualização da regra de encargos ID %d: %v", ruleID, logErr)
+	}
+	return rule, nil
+}
+
+// DeleteChargeRule exclui uma regra de encargos.
+func (s *chargeServiceImpl) DeleteChargeRule(ruleID uint64, userSession *auth.SessionData) error {
+	if err := s.permManager.CheckPermission(userSession, auth.PermChargeManage, nil); err != nil {
+		return err
+	}
+	dbRule, err := s.repo.GetByID(ruleID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ruleID); err != nil {
+		return err
+	}
+
+	logEntry := models.AuditLogEntry{
+		Action:      "CHARGE_RULE_DELETE",
+		Description: fmt.Sprintf("Regra de encargos '%s' (ID %d) excluída.", dbRule.Name, dbRule.ID),
+		Severity:    "INFO",
+		Metadata: map[string]interface{}{
+			"rule_id": dbRule.ID, "name": dbRule.Name, "codigo_especie": dbRule.CodigoEspecie, "network_id": dbRule.NetworkID,
+		},
+	}
+	if logErr := s.auditLogService.LogAction(logEntry, userSession); logErr != nil {
+		appLogger.Warnf("Falha ao registrar log de auditoria para exclusão da regra de encargos ID %d: %v", ruleID, logErr)
+	}
+	return nil
+}
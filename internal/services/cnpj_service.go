@@ -40,7 +40,8 @@ type CNPJService interface {
 // cnpjServiceImpl é a implementação de CNPJService.
 type cnpjServiceImpl struct {
 	repo            repositories.CNPJRepository
-	networkRepo     repositories.NetworkRepository // Para verificar existência de NetworkID
+	networkRepo     repositories.NetworkRepository        // Para verificar existência de NetworkID
+	linkRepo        repositories.TituloCNPJLinkRepository // Para refazer o vínculo dos títulos após alterações
 	auditLogService AuditLogService
 	permManager     *auth.PermissionManager
 }
@@ -49,15 +50,17 @@ type cnpjServiceImpl struct {
 func NewCNPJService(
 	repo repositories.CNPJRepository,
 	networkRepo repositories.NetworkRepository,
+	linkRepo repositories.TituloCNPJLinkRepository,
 	auditLogService AuditLogService,
 	permManager *auth.PermissionManager,
 ) CNPJService {
-	if repo == nil || networkRepo == nil || auditLogService == nil || permManager == nil {
-		appLogger.Fatalf("Dependências nulas fornecidas para NewCNPJService (repo, networkRepo, auditLog, permManager)")
+	if repo == nil || networkRepo == nil || linkRepo == nil || auditLogService == nil || permManager == nil {
+		appLogger.Fatalf("Dependências nulas fornecidas para NewCNPJService (repo, networkRepo, linkRepo, auditLog, permManager)")
 	}
 	return &cnpjServiceImpl{
 		repo:            repo,
 		networkRepo:     networkRepo,
+		linkRepo:        linkRepo,
 		auditLogService: auditLogService,
 		permManager:     permManager,
 	}
@@ -112,7 +115,10 @@ func (s *cnpjServiceImpl) RegisterCNPJ(cnpjData models.CNPJCreate, userSession *
 		return nil, err // Propaga o erro do repositório.
 	}
 
-	// 5. Log de Auditoria
+	// 5. Vincular os títulos já importados ao novo CNPJ.
+	s.relinkTitles(fmt.Sprintf("cadastro do CNPJ %s", dbCNPJ.FormatCNPJ()))
+
+	// 6. Log de Auditoria
 	logEntry := models.AuditLogEntry{
 		Action:      "CNPJ_CREATE",
 		Description: fmt.Sprintf("CNPJ %s cadastrado para rede '%s' (ID: %d).", dbCNPJ.FormatCNPJ(), network.Name, dbCNPJ.NetworkID),
@@ -154,7 +160,10 @@ func (s *cnpjServiceImpl) DeleteCNPJ(cnpjID uint64, userSession *auth.SessionDat
 		return err
 	}
 
-	// 4. Log de Auditoria
+	// 4. Desfazer o vínculo dos títulos com o CNPJ excluído.
+	s.relinkTitles(fmt.Sprintf("exclusão do CNPJ %s", cnpjToLog))
+
+	// 5. Log de Auditoria
 	logEntry := models.AuditLogEntry{
 		Action:      "CNPJ_DELETE",
 		Description: fmt.Sprintf("CNPJ %s (ID %d) excluído.", cnpjToLog, cnpjID),
@@ -167,6 +176,17 @@ func (s *cnpjServiceImpl) DeleteCNPJ(cnpjID uint64, userSession *auth.SessionDat
 	return nil
 }
 
+// relinkTitles refaz o vínculo dos títulos de direitos e obrigações com o cadastro de CNPJs após uma
+// alteração no cadastro. Falhas são apenas logadas: o cadastro já foi gravado, e o vínculo pode ser
+// refeito por `ImportService.RelinkTitleCNPJs` ou pela próxima importação.
+func (s *cnpjServiceImpl) relinkTitles(reason string) {
+	for _, fileType := range []FileType{FileTypeDireitos, FileTypeObrigacoes} {
+		if _, err := s.linkRepo.LinkTitles(string(fileType)); err != nil {
+			appLogger.Warnf("Falha ao refazer o vínculo dos títulos do tipo %s após %s: %v", fileType, reason, err)
+		}
+	}
+}
+
 // GetAllCNPJs busca todos os CNPJs.
 func (s *cnpjServiceImpl) GetAllCNPJs(includeInactive bool, userSession *auth.SessionData) ([]*models.CNPJPublic, error) {
 	if err := s.permManager.CheckPermission(userSession, auth.PermCNPJView, nil); err != nil {
@@ -237,7 +257,12 @@ func (s *cnpjServiceImpl) UpdateCNPJ(cnpjID uint64, cnpjUpdateData models.CNPJUp
 		return nil, err // Erro já logado e formatado pelo repo.
 	}
 
-	// 5. Log de Auditoria
+	// 5. A rede dos títulos acompanha a rede do CNPJ.
+	if cnpjUpdateData.NetworkID != nil {
+		s.relinkTitles(fmt.Sprintf("mudança de rede do CNPJ %s", dbCNPJ.FormatCNPJ()))
+	}
+
+	// 6. Log de Auditoria
 	updatedFields := []string{}
 	meta := map[string]interface{}{"updated_cnpj_id": dbCNPJ.ID, "cnpj": dbCNPJ.CNPJ}
 	if cnpjUpdateData.NetworkID != nil {
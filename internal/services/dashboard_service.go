@@ -0,0 +1,380 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/repositories"
+)
+
+// DashboardService define a interface do painel de exposição financeira por rede.
+type DashboardService interface {
+	// GetNetworkDashboard consolida, para cada rede visível ao usuário, os direitos e obrigações
+	// em aberto, os vencidos (após o vencimento efetivo, em dia útil), os maiores títulos, a posição por CNPJ e a variação desde a importação
+	// anterior. Exige `auth.PermTituloView` e `auth.PermNetworkView`; com apenas
+	// `auth.PermNetworkViewOwn`, mostra somente as redes criadas pelo usuário.
+	GetNetworkDashboard(userSession *auth.SessionData) (*models.NetworkDashboard, error)
+}
+
+// dashboardServiceImpl é a implementação de DashboardService.
+type dashboardServiceImpl struct {
+	direitoRepo        repositories.TituloDireitoRepository
+	obrigacaoRepo      repositories.TituloObrigacaoRepository
+	networkRepo        repositories.NetworkRepository
+	cnpjRepo           repositories.CNPJRepository
+	importRunRepo      repositories.ImportRunRepository
+	importSnapshotRepo repositories.ImportSnapshotRepository
+	holidayService     HolidayService
+	permManager        *auth.PermissionManager
+}
+
+// NewDashboardService cria uma nova instância de DashboardService.
+func NewDashboardService(
+	direitoRepo repositories.TituloDireitoRepository,
+	obrigacaoRepo repositories.TituloObrigacaoRepository,
+	networkRepo repositories.NetworkRepository,
+	cnpjRepo repositories.CNPJRepository,
+	importRunRepo repositories.ImportRunRepository,
+	importSnapshotRepo repositories.ImportSnapshotRepository,
+	holidayService HolidayService,
+	permManager *auth.PermissionManager,
+) DashboardService {
+	if direitoRepo == nil || obrigacaoRepo == nil || networkRepo == nil || cnpjRepo == nil || importRunRepo == nil || importSnapshotRepo == nil || holidayService == nil || permManager == nil {
+		appLogger.Fatalf("Dependências nulas fornecidas para NewDashboardService")
+	}
+	return &dashboardServiceImpl{
+		direitoRepo:        direitoRepo,
+		obrigacaoRepo:      obrigacaoRepo,
+		networkRepo:        networkRepo,
+		cnpjRepo:           cnpjRepo,
+		importRunRepo:      importRunRepo,
+		importSnapshotRepo: importSnapshotRepo,
+		holidayService:     holidayService,
+		permManager:        permManager,
+	}
+}
+
+// visibleNetworks retorna as redes (ativas e inativas) que o usuário pode ver e se a visão está
+// restrita às redes criadas por ele.
+func (s *dashboardServiceImpl) visibleNetworks(userSession *auth.SessionData) ([]models.DBNetwork, bool, error) {
+	canViewAll, err := s.permManager.HasPermission(userSession, auth.PermNetworkView, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if !canViewAll {
+		// O próprio usuário como dono confirma se ele tem `PermNetworkViewOwn`.
+		canViewOwn, err := s.permManager.HasPermission(userSession, auth.PermNetworkViewOwn, &userSession.Username)
+		if err != nil {
+			return nil, false, err
+		}
+		if !canViewOwn {
+			return nil, false, s.permManager.CheckPermission(userSession, auth.PermNetworkView, nil)
+		}
+	}
+
+	networks, err := s.networkRepo.GetAll(true)
+	if err != nil {
+		return nil, false, err // Erro já logado pelo repo.
+	}
+	if canViewAll {
+		return networks, false, nil
+	}
+	own := make([]models.DBNetwork, 0, len(networks))
+	for _, network := range networks {
+		if network.CreatedBy == nil {
+			continue
+		}
+		isOwner, err := s.permManager.HasPermission(userSession, auth.PermNetworkViewOwn, network.CreatedBy)
+		if err != nil {
+			return nil, true, err
+		}
+		if isOwner {
+			own = append(own, network)
+		}
+	}
+	return own, true, nil
+}
+
+// networkExposureState acumula a exposição de uma rede durante a leitura dos títulos.
+type networkExposureState struct {
+	exposure *models.NetworkExposure
+	cnpjs    map[string]*models.CNPJExposure
+}
+
+// cnpj retorna (criando, se preciso) a posição do CNPJ na rede.
+func (st *networkExposureState) cnpj(cnpjcpf string) *models.CNPJExposure {
+	entry, ok := st.cnpjs[cnpjcpf]
+	if !ok {
+		entry = &models.CNPJExposure{CNPJCPF: cnpjcpf}
+		st.cnpjs[cnpjcpf] = entry
+	}
+	return entry
+}
+
+// addLargestTitle insere o título na lista dos maiores da rede, mantendo-a ordenada e limitada.
+func addLargestTitle(titles []models.ExposureTitle, title models.ExposureTitle) []models.ExposureTitle {
+	pos := sort.Search(len(titles), func(i int) bool { return titles[i].OpenBalance.LessThan(title.OpenBalance) })
+	if pos >= models.NetworkDashboardTopTitles {
+		return titles
+	}
+	titles = append(titles, models.ExposureTitle{})
+	copy(titles[pos+1:], titles[pos:])
+	titles[pos] = title
+	if len(titles) > models.NetworkDashboardTopTitles {
+		titles = titles[:models.NetworkDashboardTopTitles]
+	}
+	return titles
+}
+
+// accumulateOpen percorre os saldos em aberto de `forEachBalance` e soma o total por rede
+// (apenas as redes presentes em `networkIDs`; todas se `networkIDs` for nil).
+func accumulateOpen(forEachBalance func(fn func(batch []models.TituloBalance) error) error, networkIDs map[uint64]struct{}) (map[uint64]decimal.Decimal, error) {
+	totals := make(map[uint64]decimal.Decimal)
+	err := forEachBalance(func(batch []models.TituloBalance) error {
+		for i := range batch {
+			b := &batch[i]
+			if b.NetworkID == nil {
+				continue
+			}
+			if _, ok := networkIDs[*b.NetworkID]; !ok && networkIDs != nil {
+				continue
+			}
+			open, err := b.OpenBalance()
+			if err != nil || !open.IsPositive() {
+				continue
+			}
+			totals[*b.NetworkID] = totals[*b.NetworkID].Add(open)
+		}
+		return nil
+	})
+	return totals, err
+}
+
+// previousOpenTotals retorna os saldos em aberto por rede da importação anterior à última do tipo,
+// identificada pelo histórico de execuções. Usa os totais gravados na própria execução e, para
+// execuções sem totais gravados, o snapshot da importação, se ainda existir. Retorna nil (sem erro)
+// quando não há importação anterior ou nenhuma das duas fontes está disponível.
+func (s *dashboardServiceImpl) previousOpenTotals(fileType FileType, networkIDs map[uint64]struct{}) (map[uint64]decimal.Decimal, *time.Time, error) {
+	runs, err := recentSuccessfulImportRuns(s.importRunRepo, fileType, 2)
+	if err != nil {
+		return nil, nil, err // Erro já logado pelo repo.
+	}
+	if len(runs) < 2 {
+		return nil, nil, nil
+	}
+	previousRun := runs[1] // A mais recente corresponde aos dados atuais.
+	previousAt := previousRun.StartedAt
+	if previousRun.FinishedAt != nil {
+		previousAt = *previousRun.FinishedAt
+	}
+
+	if previousRun.NetworkTotalsRecorded {
+		recorded, err := s.importRunRepo.GetNetworkTotals(previousRun.ID)
+		if err != nil {
+			return nil, nil, err // Erro já logado pelo repo.
+		}
+		totals := make(map[uint64]decimal.Decimal, len(recorded))
+		for _, total := range recorded {
+			if _, ok := networkIDs[total.NetworkID]; !ok {
+				continue
+			}
+			open, err := decimal.NewFromString(total.OpenTotal)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%w: saldo '%s' inválido gravado para a rede %d na execução %d", appErrors.ErrDatabase, total.OpenTotal, total.NetworkID, previousRun.ID)
+			}
+			totals[total.NetworkID] = open
+		}
+		return totals, &previousAt, nil
+	}
+
+	snapshots, err := s.importSnapshotRepo.GetAll(string(fileType))
+	if err != nil {
+		return nil, nil, err // Erro já logado pelo repo.
+	}
+	var previous *models.DBImportSnapshot
+	for i := range snapshots {
+		if snapshots[i].ImportRunID != nil && *snapshots[i].ImportRunID == previousRun.ID {
+			previous = &snapshots[i]
+			break
+		}
+	}
+	if previous == nil {
+		return nil, nil, nil
+	}
+	forEach := func(fn func(batch []models.TituloBalance) error) error {
+		return s.importSnapshotRepo.ForEachBalance(previous.ID, fn)
+	}
+	totals, err := accumulateOpen(forEach, networkIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return totals, &previousAt, nil
+}
+
+// GetNetworkDashboard monta o painel de exposição por rede.
+func (s *dashboardServiceImpl) GetNetworkDashboard(userSession *auth.SessionData) (*models.NetworkDashboard, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermTituloView, nil); err != nil {
+		return nil, err
+	}
+	networks, ownOnly, err := s.visibleNetworks(userSession)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	refDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	dashboard := &models.NetworkDashboard{ReferenceDate: refDay, GeneratedAt: now, OwnNetworksOnly: ownOnly}
+
+	states := make(map[uint64]*networkExposureState, len(networks))
+	networkIDs := make(map[uint64]struct{}, len(networks))
+	for _, network := range networks {
+		states[network.ID] = &networkExposureState{
+			exposure: &models.NetworkExposure{NetworkID: network.ID, Name: network.Name, Buyer: network.Buyer, Active: network.Status},
+			cnpjs:    make(map[string]*models.CNPJExposure),
+		}
+		networkIDs[network.ID] = struct{}{}
+	}
+	if len(states) == 0 {
+		return dashboard, nil
+	}
+
+	// CNPJs cadastrados nas redes, para que apareçam no detalhamento mesmo sem títulos em aberto.
+	cnpjs, err := s.cnpjRepo.GetAll(true)
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	for _, registered := range cnpjs {
+		if st, ok := states[registered.NetworkID]; ok {
+			entry := st.cnpj(registered.CNPJ)
+			entry.Registered = true
+			entry.Active = registered.Active
+		}
+	}
+
+	calendar, err := s.holidayService.BusinessCalendar()
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+
+	visit := func(fileType FileType) func(batch []models.TituloBalance) error {
+		direito := fileType == FileTypeDireitos
+		return func(batch []models.TituloBalance) error {
+			for i := range batch {
+				b := &batch[i]
+				if b.NetworkID == nil {
+					continue
+				}
+				st, ok := states[*b.NetworkID]
+				if !ok {
+					continue
+				}
+				open, err := b.OpenBalance()
+				if err != nil {
+					appLogger.Warnf("Painel de redes: título ID %d ignorado: %v", b.ID, err)
+					continue
+				}
+				if !open.IsPositive() {
+					continue
+				}
+				// Vencido só depois do vencimento efetivo (próximo dia útil).
+				overdue := b.DataVencimento != nil && calendar.NextBusinessDay(*b.DataVencimento).Before(refDay)
+
+				exp := st.exposure
+				entry := st.cnpj(b.CNPJCPF)
+				if entry.Pessoa == "" && b.Pessoa != nil {
+					entry.Pessoa = strings.TrimSpace(*b.Pessoa)
+				}
+				if direito {
+					exp.Receivable = exp.Receivable.Add(open)
+					exp.ReceivableCount++
+					entry.Receivable = entry.Receivable.Add(open)
+					if overdue {
+						exp.OverdueReceivable = exp.OverdueReceivable.Add(open)
+					}
+				} else {
+					exp.Payable = exp.Payable.Add(open)
+					exp.PayableCount++
+					entry.Payable = entry.Payable.Add(open)
+					if overdue {
+						exp.OverduePayable = exp.OverduePayable.Add(open)
+					}
+				}
+				if overdue {
+					entry.Overdue = entry.Overdue.Add(open)
+				}
+
+				title := models.ExposureTitle{
+					FileType: string(fileType), ID: b.ID, CNPJCPF: b.CNPJCPF, Titulo: b.Titulo,
+					DataVencimento: b.DataVencimento, OpenBalance: open, Overdue: overdue,
+				}
+				if b.Pessoa != nil {
+					title.Pessoa = strings.TrimSpace(*b.Pessoa)
+				}
+				exp.LargestTitles = addLargestTitle(exp.LargestTitles, title)
+			}
+			return nil
+		}
+	}
+	if err := s.direitoRepo.ForEachBalance(visit(FileTypeDireitos)); err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	if err := s.obrigacaoRepo.ForEachBalance(visit(FileTypeObrigacoes)); err != nil {
+		return nil, err
+	}
+
+	// Comparação com a importação anterior. Falhas aqui não impedem a exibição do painel.
+	prevDireitos, prevDireitosAt, err := s.previousOpenTotals(FileTypeDireitos, networkIDs)
+	if err != nil {
+		appLogger.Warnf("Painel de redes: falha ao calcular os direitos da importação anterior: %v", err)
+	}
+	prevObrigacoes, prevObrigacoesAt, err := s.previousOpenTotals(FileTypeObrigacoes, networkIDs)
+	if err != nil {
+		appLogger.Warnf("Painel de redes: falha ao calcular as obrigações da importação anterior: %v", err)
+	}
+	dashboard.PrevDireitosAt = prevDireitosAt
+	dashboard.PrevObrigacoesAt = prevObrigacoesAt
+
+	dashboard.Networks = make([]models.NetworkExposure, 0, len(states))
+	for id, st := range states {
+		exp := st.exposure
+		if prevDireitos != nil {
+			prev := prevDireitos[id]
+			exp.PrevReceivable = &prev
+		}
+		if prevObrigacoes != nil {
+			prev := prevObrigacoes[id]
+			exp.PrevPayable = &prev
+		}
+		exp.CNPJs = make([]models.CNPJExposure, 0, len(st.cnpjs))
+		for _, entry := range st.cnpjs {
+			exp.CNPJs = append(exp.CNPJs, *entry)
+		}
+		sort.Slice(exp.CNPJs, func(i, j int) bool {
+			a, b := exp.CNPJs[i].Receivable.Add(exp.CNPJs[i].Payable), exp.CNPJs[j].Receivable.Add(exp.CNPJs[j].Payable)
+			if cmp := a.Cmp(b); cmp != 0 {
+				return cmp > 0
+			}
+			return exp.CNPJs[i].CNPJCPF < exp.CNPJs[j].CNPJCPF
+		})
+		dashboard.Networks = append(dashboard.Networks, *exp)
+	}
+	sort.Slice(dashboard.Networks, func(i, j int) bool {
+		a := dashboard.Networks[i].Receivable.Add(dashboard.Networks[i].Payable)
+		b := dashboard.Networks[j].Receivable.Add(dashboard.Networks[j].Payable)
+		if cmp := a.Cmp(b); cmp != 0 {
+			return cmp > 0
+		}
+		return dashboard.Networks[i].Name < dashboard.Networks[j].Name
+	})
+
+	appLogger.Infof("Painel de redes gerado para '%s': %d redes (somente próprias: %t).", userSession.Username, len(dashboard.Networks), ownOnly)
+	return dashboard, nil
+}
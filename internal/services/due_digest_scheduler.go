@@ -0,0 +1,94 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/config"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+)
+
+// dueDigestSchedulerUsername identifica os envios agendados do resumo de vencimentos na auditoria.
+const dueDigestSchedulerUsername = "sistema.resumos"
+
+// DueDigestScheduler verifica periodicamente as inscrições no resumo de vencimentos e envia os
+// resumos pendentes a partir da hora configurada.
+type DueDigestScheduler interface {
+	// Start inicia a goroutine de agendamento. Não faz nada se o resumo estiver desabilitado.
+	Start()
+	// Shutdown para o agendamento, aguardando o envio em andamento terminar.
+	Shutdown()
+}
+
+// dueDigestSchedulerImpl é a implementação de DueDigestScheduler.
+type dueDigestSchedulerImpl struct {
+	cfg           *core.Config
+	digestService DueDigestService
+
+	shutdownChan chan struct{}
+	wg           sync.WaitGroup
+	started      bool
+}
+
+// NewDueDigestScheduler cria uma nova instância de DueDigestScheduler.
+func NewDueDigestScheduler(cfg *core.Config, digestSvc DueDigestService) DueDigestScheduler {
+	if cfg == nil || digestSvc == nil {
+		appLogger.Fatalf("Dependências nulas fornecidas para NewDueDigestScheduler (cfg, digestSvc)")
+	}
+	return &dueDigestSchedulerImpl{
+		cfg:           cfg,
+		digestService: digestSvc,
+		shutdownChan:  make(chan struct{}),
+	}
+}
+
+// Start inicia a goroutine de agendamento dos resumos.
+func (d *dueDigestSchedulerImpl) Start() {
+	if !d.cfg.DueDigestEnabled {
+		appLogger.Info("Envio agendado do resumo de vencimentos desabilitado.")
+		return
+	}
+
+	d.started = true
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(d.cfg.DueDigestCheckInterval)
+		defer ticker.Stop()
+
+		appLogger.Infof("Goroutine do resumo de vencimentos iniciada (envio a partir das %02dh, intervalo: %v).",
+			d.cfg.DueDigestSendHour, d.cfg.DueDigestCheckInterval)
+		d.check()
+		for {
+			select {
+			case <-ticker.C:
+				d.check()
+			case <-d.shutdownChan:
+				appLogger.Info("Goroutine do resumo de vencimentos recebendo sinal de shutdown.")
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown para a goroutine de agendamento.
+func (d *dueDigestSchedulerImpl) Shutdown() {
+	if !d.started {
+		return
+	}
+	close(d.shutdownChan)
+	d.wg.Wait()
+	d.started = false
+	appLogger.Info("Goroutine do resumo de vencimentos finalizada.")
+}
+
+// check envia os resumos pendentes se a hora de envio do dia já chegou.
+func (d *dueDigestSchedulerImpl) check() {
+	now := time.Now()
+	if now.Hour() < d.cfg.DueDigestSendHour {
+		return
+	}
+	if _, err := d.digestService.SendScheduledDigests(now); err != nil {
+		appLogger.Errorf("Erro no envio agendado do resumo de vencimentos: %v", err)
+	}
+}
@@ -0,0 +1,546 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/repositories"
+)
+
+// Tipos dos títulos listados no resumo.
+const (
+	dueDigestKindDireito   = "A receber"
+	dueDigestKindObrigacao = "A pagar"
+)
+
+// DueDigestService define a interface do resumo de vencimentos por e-mail: títulos a vencer nos
+// próximos dias e títulos que venceram desde a última importação.
+// Compradores recebem os títulos das redes pelas quais respondem; o financeiro
+// (`auth.PermTituloDigestAll`) recebe os de todas as redes.
+type DueDigestService interface {
+	// GetMySubscription retorna a inscrição do usuário logado, com o público e as redes do resumo.
+	// Exige `auth.PermTituloView`.
+	GetMySubscription(userSession *auth.SessionData) (*models.DueDigestSubscriptionPublic, error)
+
+	// UpdateMySubscription altera a inscrição do usuário logado (opt-in, frequência e janela).
+	// Exige `auth.PermTituloView`.
+	UpdateMySubscription(data models.DueDigestSubscriptionUpdate, userSession *auth.SessionData) (*models.DueDigestSubscriptionPublic, error)
+
+	// SendMyDigestNow envia imediatamente o resumo ao usuário logado, mesmo vazio e sem alterar o agendamento.
+	// Exige `auth.PermTituloView`.
+	SendMyDigestNow(userSession *auth.SessionData) (*models.DueDigest, error)
+
+	// SendScheduledDigests processa as inscrições ativas com resumo pendente no dia de `now`.
+	// Usado pelo agendador; não verifica permissão. Retorna o número de e-mails enviados.
+	SendScheduledDigests(now time.Time) (int, error)
+}
+
+// dueDigestServiceImpl é a implementação de DueDigestService.
+type dueDigestServiceImpl struct {
+	repo            repositories.DueDigestSubscriptionRepository
+	userRepo        repositories.UserRepository
+	networkRepo     repositories.NetworkRepository
+	direitoRepo     repositories.TituloDireitoRepository
+	obrigacaoRepo   repositories.TituloObrigacaoRepository
+	importRunRepo   repositories.ImportRunRepository
+	holidayService  HolidayService
+	emailService    EmailService // Pode ser nil se o SMTP não estiver configurado.
+	auditLogService AuditLogService
+	permManager     *auth.PermissionManager
+
+	systemSession *auth.SessionData // Identidade usada na auditoria dos envios agendados.
+}
+
+// NewDueDigestService cria uma nova instância de DueDigestService.
+// `emailService` pode ser nil; nesse caso os envios falham com ErrConfiguration.
+func NewDueDigestService(
+	repo repositories.DueDigestSubscriptionRepository,
+	userRepo repositories.UserRepository,
+	networkRepo repositories.NetworkRepository,
+	direitoRepo repositories.TituloDireitoRepository,
+	obrigacaoRepo repositories.TituloObrigacaoRepository,
+	importRunRepo repositories.ImportRunRepository,
+	holidayService HolidayService,
+	emailService EmailService,
+	auditLog AuditLogService,
+	pm *auth.PermissionManager,
+) DueDigestService {
+	if repo == nil || userRepo == nil || networkRepo == nil || direitoRepo == nil || obrigacaoRepo == nil ||
+		importRunRepo == nil || holidayService == nil || auditLog == nil || pm == nil {
+		appLogger.Fatalf("Dependências nulas fornecidas para NewDueDigestService (repo, userRepo, networkRepo, direitoRepo, obrigacaoRepo, importRunRepo, holidayService, auditLog, permManager)")
+	}
+	now := time.Now().UTC()
+	return &dueDigestServiceImpl{
+		repo:            repo,
+		userRepo:        userRepo,
+		networkRepo:     networkRepo,
+		direitoRepo:     direitoRepo,
+		obrigacaoRepo:   obrigacaoRepo,
+		importRunRepo:   importRunRepo,
+		holidayService:  holidayService,
+		emailService:    emailService,
+		auditLogService: auditLog,
+		permManager:     pm,
+		systemSession: &auth.SessionData{
+			ID:           "system-due-digest",
+			Username:     dueDigestSchedulerUsername,
+			IPAddress:    "local",
+			UserAgent:    "DueDigestScheduler",
+			CreatedAt:    now,
+			LastActivity: now,
+		},
+	}
+}
+
+// dueDigestRecipient é um usuário que tem conteúdo para receber no resumo.
+type dueDigestRecipient struct {
+	user       *models.DBUser
+	audience   models.DueDigestAudience
+	networkIDs map[uint64]bool // Redes do comprador; nil para o financeiro.
+	networks   []string        // Nomes das redes do comprador, em ordem alfabética.
+}
+
+// dueDigestCandidate é um título que pode entrar em algum resumo.
+type dueDigestCandidate struct {
+	item      models.DueDigestItem
+	networkID *uint64
+	overdue   bool // Vencido desde a última importação; caso contrário, a vencer.
+}
+
+// dueDigestData são os títulos candidatos do dia, compartilhados entre todos os resumos.
+type dueDigestData struct {
+	today        time.Time
+	overdueSince time.Time            // O mais antigo dos cortes por tipo de arquivo (exibido no e-mail).
+	candidates   []dueDigestCandidate // Ordenados por vencimento efetivo.
+}
+
+// sessionForUser monta uma sessão com os roles do usuário, para avaliar suas permissões fora do login.
+func sessionForUser(user *models.DBUser) *auth.SessionData {
+	roles := make([]string, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		if role != nil {
+			roles = append(roles, role.Name)
+		}
+	}
+	return &auth.SessionData{UserID: user.ID, Username: user.Username, Roles: roles}
+}
+
+// normalizeBuyerName compara nomes de comprador sem diferenciar maiúsculas e espaços.
+func normalizeBuyerName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// resolveRecipient identifica o público do resumo do usuário. Retorna nil se o usuário não pode ver
+// títulos ou não é comprador de nenhuma rede ativa nem do financeiro.
+func (s *dueDigestServiceImpl) resolveRecipient(user *models.DBUser, session *auth.SessionData, networks []models.DBNetwork) (*dueDigestRecipient, error) {
+	canView, err := s.permManager.HasPermission(session, auth.PermTituloView, nil)
+	if err != nil || !canView {
+		return nil, err
+	}
+	isFinance, err := s.permManager.HasPermission(session, auth.PermTituloDigestAll, nil)
+	if err != nil {
+		return nil, err
+	}
+	if isFinance {
+		return &dueDigestRecipient{user: user, audience: models.DueDigestAudienceFinance}, nil
+	}
+
+	names := map[string]bool{normalizeBuyerName(user.Username): true}
+	if user.FullName != nil && strings.TrimSpace(*user.FullName) != "" {
+		names[normalizeBuyerName(*user.FullName)] = true
+	}
+	recipient := &dueDigestRecipient{user: user, audience: models.DueDigestAudienceBuyer, networkIDs: make(map[uint64]bool)}
+	for _, network := range networks {
+		if network.Status && names[normalizeBuyerName(network.Buyer)] {
+			recipient.networkIDs[network.ID] = true
+			recipient.networks = append(recipient.networks, network.Name)
+		}
+	}
+	if len(recipient.networks) == 0 {
+		return nil, nil
+	}
+	sort.Strings(recipient.networks)
+	return recipient, nil
+}
+
+// overdueCutoff retorna o primeiro vencimento efetivo considerado "vencido desde a última importação"
+// de um tipo de arquivo: o dia da importação anterior aos dados atuais, segundo o histórico de execuções.
+// Sem importação anterior, usa o dia da importação atual e, sem nenhuma, o dia anterior a `today`.
+func (s *dueDigestServiceImpl) overdueCutoff(fileType FileType, today time.Time) (time.Time, error) {
+	runs, err := recentSuccessfulImportRuns(s.importRunRepo, fileType, 2)
+	if err != nil {
+		return time.Time{}, err // Erro já logado pelo repo.
+	}
+	if len(runs) == 0 {
+		return today.AddDate(0, 0, -1), nil
+	}
+	run := runs[len(runs)-1] // A execução anterior, ou a única execução.
+	imported := run.StartedAt
+	if run.FinishedAt != nil {
+		imported = *run.FinishedAt
+	}
+	imported = imported.Local()
+	return time.Date(imported.Year(), imported.Month(), imported.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// loadDigestData percorre os títulos em aberto uma única vez e separa os candidatos do dia: a vencer
+// até `maxDaysAhead` dias e vencidos desde a última importação.
+func (s *dueDigestServiceImpl) loadDigestData(today time.Time, maxDaysAhead int) (*dueDigestData, error) {
+	calendar, err := s.holidayService.BusinessCalendar()
+	if err != nil {
+		return nil, err
+	}
+	allNetworks, err := s.networkRepo.GetAll(true)
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	networkNames := make(map[uint64]string, len(allNetworks))
+	for _, network := range allNetworks {
+		networkNames[network.ID] = network.Name
+	}
+
+	data := &dueDigestData{today: today}
+	upcomingUntil := today.AddDate(0, 0, maxDaysAhead)
+	visit := func(kind string, overdueSince time.Time) func(batch []models.TituloBalance) error {
+		return func(batch []models.TituloBalance) error {
+			for i := range batch {
+				balance := &batch[i]
+				if balance.DataVencimento == nil {
+					continue
+				}
+				open, errBalance := balance.OpenBalance()
+				if errBalance != nil {
+					appLogger.Warnf("Resumo de vencimentos: título ID %d ignorado: %v", balance.ID, errBalance)
+					continue
+				}
+				if !open.IsPositive() {
+					continue
+				}
+				due := calendar.NextBusinessDay(*balance.DataVencimento)
+				candidate := dueDigestCandidate{networkID: balance.NetworkID}
+				switch {
+				case !due.Before(today) && !due.After(upcomingUntil):
+				case due.Before(today) && !due.Before(overdueSince):
+					candidate.overdue = true
+				default:
+					continue
+				}
+				network := ""
+				if balance.NetworkID != nil {
+					network = networkNames[*balance.NetworkID]
+				}
+				candidate.item = models.NewDueDigestItem(kind, network, balance, open, due)
+				data.candidates = append(data.candidates, candidate)
+			}
+			return nil
+		}
+	}
+
+	for i, source := range []struct {
+		fileType FileType
+		kind     string
+		forEach  func(fn func(batch []models.TituloBalance) error) error
+	}{
+		{FileTypeDireitos, dueDigestKindDireito, s.direitoRepo.ForEachBalance},
+		{FileTypeObrigacoes, dueDigestKindObrigacao, s.obrigacaoRepo.ForEachBalance},
+	} {
+		cutoff, err := s.overdueCutoff(source.fileType, today)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 || cutoff.Before(data.overdueSince) {
+			data.overdueSince = cutoff
+		}
+		if err := source.forEach(visit(source.kind, cutoff)); err != nil {
+			return nil, err // Erro já logado pelo repo.
+		}
+	}
+
+	sort.SliceStable(data.candidates, func(i, j int) bool {
+		a, b := data.candidates[i].item, data.candidates[j].item
+		if !a.EffectiveDue().Equal(b.EffectiveDue()) {
+			return a.EffectiveDue().Before(b.EffectiveDue())
+		}
+		if a.Network != b.Network {
+			return a.Network < b.Network
+		}
+		return a.Titulo < b.Titulo
+	})
+	return data, nil
+}
+
+// buildDigest monta o resumo de um destinatário a partir dos candidatos do dia.
+func buildDigest(recipient *dueDigestRecipient, frequency models.DueDigestFrequency, daysAhead int, data *dueDigestData) *models.DueDigest {
+	name := recipient.user.Username
+	if recipient.user.FullName != nil && strings.TrimSpace(*recipient.user.FullName) != "" {
+		name = strings.TrimSpace(*recipient.user.FullName)
+	}
+	digest := &models.DueDigest{
+		RecipientName: name,
+		Audience:      recipient.audience,
+		Frequency:     frequency,
+		Networks:      recipient.networks,
+		ReferenceDate: data.today,
+		DaysAhead:     daysAhead,
+		UpcomingUntil: data.today.AddDate(0, 0, daysAhead),
+		OverdueSince:  data.overdueSince,
+		GeneratedAt:   time.Now(),
+	}
+	for _, candidate := range data.candidates {
+		if recipient.networkIDs != nil && (candidate.networkID == nil || !recipient.networkIDs[*candidate.networkID]) {
+			continue
+		}
+		if candidate.overdue {
+			digest.NewlyOverdue.Add(candidate.item)
+		} else if !candidate.item.EffectiveDue().After(digest.UpcomingUntil) {
+			digest.Upcoming.Add(candidate.item)
+		}
+	}
+	return digest
+}
+
+// digestToday retorna o dia do calendário (UTC, como as datas dos títulos) correspondente a `now`.
+func digestToday(now time.Time) time.Time {
+	local := now.Local()
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// GetMySubscription retorna a inscrição do usuário logado.
+func (s *dueDigestServiceImpl) GetMySubscription(userSession *auth.SessionData) (*models.DueDigestSubscriptionPublic, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermTituloView, nil); err != nil {
+		return nil, err
+	}
+	sub, err := s.repo.GetByUserID(userSession.UserID)
+	if err != nil && !errors.Is(err, appErrors.ErrNotFound) {
+		return nil, err // Erro já logado pelo repo.
+	}
+	public := models.ToDueDigestSubscriptionPublic(sub)
+	if err := s.fillAudience(public, userSession); err != nil {
+		return nil, err
+	}
+	return public, nil
+}
+
+// fillAudience preenche o público e as redes do resumo do usuário logado.
+func (s *dueDigestServiceImpl) fillAudience(public *models.DueDigestSubscriptionPublic, userSession *auth.SessionData) error {
+	user, err := s.userRepo.GetByID(userSession.UserID)
+	if err != nil {
+		return err
+	}
+	networks, err := s.networkRepo.GetAll(false)
+	if err != nil {
+		return err // Erro já logado pelo repo.
+	}
+	recipient, err := s.resolveRecipient(user, userSession, networks)
+	if err != nil {
+		return err
+	}
+	if recipient != nil {
+		public.Audience = recipient.audience
+		public.Networks = recipient.networks
+	}
+	return nil
+}
+
+// UpdateMySubscription altera a inscrição do usuário logado.
+func (s *dueDigestServiceImpl) UpdateMySubscription(data models.DueDigestSubscriptionUpdate, userSession *auth.SessionData) (*models.DueDigestSubscriptionPublic, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermTituloView, nil); err != nil {
+		return nil, err
+	}
+	if err := data.CleanAndValidate(); err != nil {
+		appLogger.Warnf("Dados de inscrição no resumo de vencimentos inválidos para '%s': %v", userSession.Username, err)
+		return nil, err // Retorna o ValidationError.
+	}
+	sub, err := s.repo.Upsert(userSession.UserID, data)
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+
+	description := fmt.Sprintf("Resumo de vencimentos por e-mail desativado por '%s'.", userSession.Username)
+	if data.Enabled {
+		description = fmt.Sprintf("Resumo de vencimentos por e-mail ativado por '%s': %s, próximos %d dias.",
+			userSession.Username, strings.ToLower(data.Frequency.Label()), data.DaysAhead)
+	}
+	logEntry := models.AuditLogEntry{
+		Action:      "DUE_DIGEST_SUBSCRIPTION_UPDATE",
+		Description: description,
+		Severity:    "INFO",
+		Metadata: map[string]interface{}{
+			"subscription_id": sub.ID, "enabled": data.Enabled, "frequency": data.Frequency, "days_ahead": data.DaysAhead,
+		},
+	}
+	if logErr := s.auditLogService.LogAction(logEntry, userSession); logErr != nil {
+		appLogger.Warnf("Falha ao registrar log de auditoria para inscrição no resumo de vencimentos de '%s': %v", userSession.Username, logErr)
+	}
+
+	public := models.ToDueDigestSubscriptionPublic(sub)
+	if err := s.fillAudience(public, userSession); err != nil {
+		return nil, err
+	}
+	return public, nil
+}
+
+// SendMyDigestNow envia o resumo ao usuário logado.
+func (s *dueDigestServiceImpl) SendMyDigestNow(userSession *auth.SessionData) (*models.DueDigest, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermTituloView, nil); err != nil {
+		return nil, err
+	}
+	if s.emailService == nil {
+		return nil, fmt.Errorf("%w: envio de e-mails não configurado", appErrors.ErrConfiguration)
+	}
+	user, err := s.userRepo.GetByID(userSession.UserID)
+	if err != nil {
+		return nil, err
+	}
+	networks, err := s.networkRepo.GetAll(false)
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	recipient, err := s.resolveRecipient(user, userSession, networks)
+	if err != nil {
+		return nil, err
+	}
+	if recipient == nil {
+		return nil, fmt.Errorf("%w: você não é comprador responsável por nenhuma rede ativa nem tem acesso ao resumo de todas as redes",
+			appErrors.ErrInvalidInput)
+	}
+
+	frequency, daysAhead := models.DueDigestDaily, models.DueDigestDefaultDaysAhead
+	sub, err := s.repo.GetByUserID(user.ID)
+	if err != nil && !errors.Is(err, appErrors.ErrNotFound) {
+		return nil, err // Erro já logado pelo repo.
+	}
+	if sub != nil {
+		frequency, daysAhead = models.DueDigestFrequency(sub.Frequency), sub.DaysAhead
+	}
+
+	data, err := s.loadDigestData(digestToday(time.Now()), daysAhead)
+	if err != nil {
+		return nil, err
+	}
+	digest := buildDigest(recipient, frequency, daysAhead, data)
+	if err := s.send(recipient, digest, "manual", userSession); err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+// SendScheduledDigests envia os resumos pendentes do dia.
+func (s *dueDigestServiceImpl) SendScheduledDigests(now time.Time) (int, error) {
+	subs, err := s.repo.GetAllEnabled()
+	if err != nil {
+		return 0, err // Erro já logado pelo repo.
+	}
+	calendar, err := s.holidayService.BusinessCalendar()
+	if err != nil {
+		return 0, err
+	}
+	today := digestToday(now)
+	var pending []models.DBDueDigestSubscription
+	for _, sub := range subs {
+		if sub.IsDue(today, calendar) {
+			pending = append(pending, sub)
+		}
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+	if s.emailService == nil {
+		appLogger.Warnf("%d resumos de vencimentos pendentes não enviados: envio de e-mails não configurado.", len(pending))
+		return 0, fmt.Errorf("%w: envio de e-mails não configurado", appErrors.ErrConfiguration)
+	}
+
+	networks, err := s.networkRepo.GetAll(false)
+	if err != nil {
+		return 0, err // Erro já logado pelo repo.
+	}
+	data, err := s.loadDigestData(today, models.DueDigestMaxDaysAhead)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, sub := range pending {
+		delivered, errSub := s.sendScheduled(&sub, networks, data)
+		if errSub != nil {
+			appLogger.Errorf("Erro ao processar o resumo de vencimentos da inscrição ID %d: %v", sub.ID, errSub)
+		}
+		// Falhas também contam como processamento, para não repetir o envio a cada verificação;
+		// o usuário pode pedir o envio imediato pela tela do resumo.
+		if errMark := s.repo.MarkRun(sub.ID, now, delivered); errMark != nil {
+			appLogger.Errorf("Erro ao registrar o processamento do resumo de vencimentos da inscrição ID %d: %v", sub.ID, errMark)
+		}
+		if delivered {
+			sent++
+		}
+	}
+	appLogger.Infof("Resumos de vencimentos de %s: %d inscrições processadas, %d e-mails enviados.",
+		today.Format("02/01/2006"), len(pending), sent)
+	return sent, nil
+}
+
+// sendScheduled monta e envia o resumo agendado de uma inscrição. Resumos vazios e usuários inativos
+// ou sem público são ignorados sem erro. Retorna se o e-mail foi enviado.
+func (s *dueDigestServiceImpl) sendScheduled(sub *models.DBDueDigestSubscription, networks []models.DBNetwork, data *dueDigestData) (bool, error) {
+	user, err := s.userRepo.GetByID(sub.UserID)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !user.Active {
+		return false, nil
+	}
+	recipient, err := s.resolveRecipient(user, sessionForUser(user), networks)
+	if err != nil || recipient == nil {
+		return false, err
+	}
+	digest := buildDigest(recipient, models.DueDigestFrequency(sub.Frequency), sub.DaysAhead, data)
+	if digest.IsEmpty() {
+		appLogger.Debugf("Resumo de vencimentos de '%s' vazio; e-mail não enviado.", user.Username)
+		return false, nil
+	}
+	if err := s.send(recipient, digest, "scheduled", s.systemSession); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// send envia o resumo por e-mail e registra o envio (ou a falha) na auditoria.
+func (s *dueDigestServiceImpl) send(recipient *dueDigestRecipient, digest *models.DueDigest, trigger string, session *auth.SessionData) error {
+	errSend := s.emailService.SendDueDigest(recipient.user.Email, digest)
+
+	logEntry := models.AuditLogEntry{
+		Action: "DUE_DIGEST_SENT",
+		Description: fmt.Sprintf("Resumo de vencimentos enviado a '%s': %d a vencer (%s), %d vencidos (%s).",
+			recipient.user.Username, digest.Upcoming.Count, models.FormatMoneyBRL(digest.Upcoming.Total),
+			digest.NewlyOverdue.Count, models.FormatMoneyBRL(digest.NewlyOverdue.Total)),
+		Severity: "INFO",
+		Metadata: map[string]interface{}{
+			"recipient": recipient.user.Username, "email": recipient.user.Email, "audience": recipient.audience,
+			"trigger": trigger, "networks": recipient.networks,
+			"upcoming_count": digest.Upcoming.Count, "upcoming_total": digest.Upcoming.Total.StringFixed(2),
+			"overdue_count": digest.NewlyOverdue.Count, "overdue_total": digest.NewlyOverdue.Total.StringFixed(2),
+		},
+	}
+	if errSend != nil {
+		logEntry.Action = "DUE_DIGEST_FAILED"
+		logEntry.Description = fmt.Sprintf("Falha ao enviar o resumo de vencimentos a '%s': %v", recipient.user.Username, errSend)
+		logEntry.Severity = "ERROR"
+	}
+	if logErr := s.auditLogService.LogAction(logEntry, session); logErr != nil {
+		appLogger.Warnf("Falha ao registrar log de auditoria para o resumo de vencimentos de '%s': %v", recipient.user.Username, logErr)
+	}
+	if errSend != nil {
+		return errSend // Erro já logado pelo serviço de e-mail.
+	}
+	return nil
+}
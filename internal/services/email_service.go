@@ -17,6 +17,7 @@ import (
 	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/config"
 	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
 	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
 	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/appassets"      // <<< IMPORTADO O NOVO PACOTE
 )
 
@@ -26,6 +27,7 @@ type EmailService interface {
 	SendWelcomeEmail(to, username string, contextData map[string]interface{}) error
 	SendPasswordResetCode(to, resetCode, requestIP string) error
 	SendNotificationEmail(to, message, title, actionURL, actionText string) error
+	SendDueDigest(to string, digest *models.DueDigest) error
 }
 
 // emailServiceImpl implementa EmailService.
@@ -363,6 +365,37 @@ func (s *emailServiceImpl) SendNotificationEmail(to, message, title, actionURL,
 	return s.SendEmail(to, subject, htmlBody, textBody, s.cfg.AppName)
 }
 
+// SendDueDigest envia o resumo de vencimentos (títulos a vencer e recém-vencidos) a um destinatário.
+func (s *emailServiceImpl) SendDueDigest(to string, digest *models.DueDigest) error {
+	if digest == nil {
+		return fmt.Errorf("%w: resumo de vencimentos nulo", appErrors.ErrInvalidInput)
+	}
+	contextData := map[string]interface{}{
+		"Digest":         digest,
+		"ReferenceDate":  digest.ReferenceDate.Format("02/01/2006"),
+		"UpcomingUntil":  digest.UpcomingUntil.Format("02/01/2006"),
+		"OverdueSince":   digest.OverdueSince.Format("02/01/2006"),
+		"Networks":       strings.Join(digest.Networks, ", "),
+		"UpcomingTotal":  models.FormatMoneyBRL(digest.Upcoming.Total),
+		"OverdueTotal":   models.FormatMoneyBRL(digest.NewlyOverdue.Total),
+		"AudienceLabel":  strings.ToLower(digest.Audience.Label()),
+		"FrequencyLabel": strings.ToLower(digest.Frequency.Label()),
+	}
+
+	htmlBody, err := s.renderTemplate("due_digest.html", contextData, true)
+	if err != nil {
+		return err
+	}
+	textBody, err := s.renderTemplate("due_digest.txt", contextData, false)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("Resumo de vencimentos de %s: %d a vencer, %d vencidos - %s",
+		digest.ReferenceDate.Format("02/01/2006"), digest.Upcoming.Count, digest.NewlyOverdue.Count, s.cfg.AppName)
+	return s.SendEmail(to, subject, htmlBody, textBody, s.cfg.AppName)
+}
+
 // truncateForLog trunca uma string para um tamanho máximo para logging.
 func truncateForLog(s string, maxLength int) string {
 	if len(s) > maxLength {
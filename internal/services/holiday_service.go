@@ -0,0 +1,167 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	core "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/config"
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/repositories"
+)
+
+// HolidayService define a interface do calendário de dias úteis: feriados nacionais (calculados)
+// e feriados estaduais e municipais cadastrados pelo administrador ou importados de arquivo.
+type HolidayService interface {
+	// BusinessCalendar monta o calendário de dias úteis com os feriados locais cadastrados para a praça
+	// configurada (ver `models.HolidayCreate`). Usado pelos serviços que calculam vencimentos efetivos; não verifica permissão.
+	BusinessCalendar() (*models.BusinessCalendar, error)
+
+	// ListHolidays retorna os feriados (nacionais e locais) do ano, em ordem cronológica.
+	// Exige `auth.PermTituloView`.
+	ListHolidays(year int, userSession *auth.SessionData) ([]*models.HolidayPublic, error)
+
+	// CreateHoliday cadastra um feriado estadual ou municipal. Exige `auth.PermHolidayManage`.
+	CreateHoliday(holidayData models.HolidayCreate, userSession *auth.SessionData) (*models.HolidayPublic, error)
+
+	// DeleteHoliday exclui um feriado cadastrado. Exige `auth.PermHolidayManage`.
+	DeleteHoliday(holidayID uint64, userSession *auth.SessionData) error
+}
+
+// holidayServiceImpl é a implementação de HolidayService.
+type holidayServiceImpl struct {
+	locality        models.HolidayLocality
+	repo            repositories.HolidayRepository
+	auditLogService AuditLogService
+	permManager     *auth.PermissionManager
+}
+
+// NewHolidayService cria uma nova instância de HolidayService.
+func NewHolidayService(
+	cfg *core.Config,
+	repo repositories.HolidayRepository,
+	auditLog AuditLogService,
+	pm *auth.PermissionManager,
+) HolidayService {
+	if cfg == nil || repo == nil || auditLog == nil || pm == nil {
+		appLogger.Fatalf("Dependências nulas fornecidas para NewHolidayService (cfg, repo, auditLog, permManager)")
+	}
+	return &holidayServiceImpl{
+		locality:        holidayLocality(cfg),
+		repo:            repo,
+		auditLogService: auditLog,
+		permManager:     pm,
+	}
+}
+
+// holidayLocality retorna a praça do calendário de dias úteis configurada.
+func holidayLocality(cfg *core.Config) models.HolidayLocality {
+	return models.HolidayLocality{UF: cfg.HolidayUF, Municipality: cfg.HolidayMunicipality}
+}
+
+// BusinessCalendar monta um novo calendário a cada chamada, para refletir os feriados cadastrados
+// ou importados desde a anterior. Feriados de outra praça (gravados antes da configuração atual)
+// são ignorados.
+func (s *holidayServiceImpl) BusinessCalendar() (*models.BusinessCalendar, error) {
+	all, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	local := make([]models.DBHoliday, 0, len(all))
+	for _, h := range all {
+		if !s.locality.Includes(models.HolidayScope(h.Scope), h.Location) {
+			appLogger.Debugf("Feriado %s '%s' (%s) ignorado: fora da praça configurada.", h.Scope, h.Name, h.Location)
+			continue
+		}
+		local = append(local, h)
+	}
+	return models.NewBusinessCalendar(local), nil
+}
+
+// ListHolidays retorna os feriados do ano.
+func (s *holidayServiceImpl) ListHolidays(year int, userSession *auth.SessionData) ([]*models.HolidayPublic, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermTituloView, nil); err != nil {
+		return nil, err
+	}
+	if year < 1900 || year > 2200 {
+		return nil, fmt.Errorf("%w: ano %d fora do intervalo suportado pelo calendário", appErrors.ErrInvalidInput, year)
+	}
+	calendar, err := s.BusinessCalendar()
+	if err != nil {
+		return nil, err
+	}
+	holidays := calendar.HolidaysIn(year)
+	result := make([]*models.HolidayPublic, len(holidays))
+	for i, h := range holidays {
+		result[i] = models.ToHolidayPublic(h)
+	}
+	return result, nil
+}
+
+// CreateHoliday cadastra um feriado local.
+func (s *holidayServiceImpl) CreateHoliday(holidayData models.HolidayCreate, userSession *auth.SessionData) (*models.HolidayPublic, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermHolidayManage, nil); err != nil {
+		return nil, err
+	}
+	if err := holidayData.CleanAndValidate(); err != nil {
+		appLogger.Warnf("Dados de criação de feriado inválidos para '%s': %v", holidayData.Name, err)
+		return nil, err // Retorna o ValidationError.
+	}
+	if err := holidayData.CheckLocality(s.locality); err != nil {
+		appLogger.Warnf("Feriado '%s' recusado: %v", holidayData.Name, err)
+		return nil, err
+	}
+
+	dbHoliday, err := s.repo.Create(holidayData, userSession.Username)
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+
+	logEntry := models.AuditLogEntry{
+		Action: "HOLIDAY_CREATE",
+		Description: fmt.Sprintf("Feriado %s '%s' cadastrado em %s.",
+			models.HolidayScope(dbHoliday.Scope).Label(), dbHoliday.Name, dbHoliday.Date.Format("02/01/2006")),
+		Severity: "INFO",
+		Metadata: map[string]interface{}{
+			"holiday_id": dbHoliday.ID, "date": dbHoliday.Date.Format("2006-01-02"), "name": dbHoliday.Name,
+			"scope": dbHoliday.Scope, "location": dbHoliday.Location, "recurring": dbHoliday.Recurring,
+		},
+	}
+	if logErr := s.auditLogService.LogAction(logEntry, userSession); logErr != nil {
+		appLogger.Warnf("Falha ao registrar log de auditoria para criação do feriado '%s': %v", dbHoliday.Name, logErr)
+	}
+
+	return models.ToHolidayPublic(models.Holiday{
+		ID: dbHoliday.ID, Date: dbHoliday.Date, Name: dbHoliday.Name, Scope: models.HolidayScope(dbHoliday.Scope),
+		Location: dbHoliday.Location, Recurring: dbHoliday.Recurring,
+	}), nil
+}
+
+// DeleteHoliday exclui um feriado cadastrado.
+func (s *holidayServiceImpl) DeleteHoliday(holidayID uint64, userSession *auth.SessionData) error {
+	if err := s.permManager.CheckPermission(userSession, auth.PermHolidayManage, nil); err != nil {
+		return err
+	}
+	dbHoliday, err := s.repo.GetByID(holidayID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(holidayID); err != nil {
+		return err
+	}
+
+	logEntry := models.AuditLogEntry{
+		Action:      "HOLIDAY_DELETE",
+		Description: fmt.Sprintf("Feriado '%s' de %s excluído.", dbHoliday.Name, dbHoliday.Date.Format("02/01/2006")),
+		Severity:    "INFO",
+		Metadata: map[string]interface{}{
+			"holiday_id": dbHoliday.ID, "date": dbHoliday.Date.Format("2006-01-02"), "name": dbHoliday.Name,
+			"scope": dbHoliday.Scope, "location": dbHoliday.Location,
+		},
+	}
+	if logErr := s.auditLogService.LogAction(logEntry, userSession); logErr != nil {
+		appLogger.Warnf("Falha ao registrar log de auditoria para exclusão do feriado ID %d: %v", holidayID, logErr)
+	}
+	return nil
+}
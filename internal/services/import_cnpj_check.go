@@ -0,0 +1,187 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/utils"
+)
+
+// --- Vínculo dos Títulos com o Cadastro de CNPJs ---
+
+// cnpjCheckStatusOrder define a ordem das situações no relatório (as que exigem cadastro primeiro).
+var cnpjCheckStatusOrder = map[string]int{
+	models.TituloCNPJStatusUnknown:         0,
+	models.TituloCNPJStatusInactive:        1,
+	models.TituloCNPJStatusNetworkInactive: 2,
+	models.TituloCNPJStatusInvalid:         3,
+}
+
+// cnpjCheckExportHeaders são as colunas do CSV exportado com os CNPJs pendentes.
+var cnpjCheckExportHeaders = []string{"CNPJ/CPF", "SITUACAO", "QTD_TITULOS", "PESSOA", "REDE"}
+
+// linkAndCheckTitleCNPJs vincula os títulos do tipo aos CNPJs cadastrados e monta o relatório
+// dos CNPJs/CPFs que não puderam ser vinculados a um CNPJ ativo de uma rede ativa.
+func (s *importServiceImpl) linkAndCheckTitleCNPJs(fileType FileType) (*models.TituloCNPJCheckReport, error) {
+	if _, err := s.tituloCNPJLinkRepo.LinkTitles(string(fileType)); err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	return s.checkTitleCNPJs(fileType)
+}
+
+// checkTitleCNPJs monta o relatório a partir dos vínculos atuais, sem alterá-los.
+func (s *importServiceImpl) checkTitleCNPJs(fileType FileType) (*models.TituloCNPJCheckReport, error) {
+	summaries, err := s.tituloCNPJLinkRepo.GetCNPJSummary(string(fileType))
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+
+	report := &models.TituloCNPJCheckReport{
+		FileType:      string(fileType),
+		CheckedAt:     time.Now(),
+		DistinctCNPJs: len(summaries),
+		Issues:        []models.TituloCNPJIssue{},
+	}
+	for _, summary := range summaries {
+		report.TotalTitles += summary.TitleCount
+		if summary.CNPJID != nil {
+			report.LinkedTitles += summary.TitleCount
+		}
+
+		issue := models.TituloCNPJIssue{CNPJCPF: summary.CNPJCPF, TitleCount: summary.TitleCount}
+		if summary.Pessoa != nil {
+			issue.Pessoa = *summary.Pessoa
+		}
+		if summary.NetworkName != nil {
+			issue.NetworkName = *summary.NetworkName
+		}
+
+		switch {
+		case summary.CNPJID != nil && summary.CNPJActive != nil && !*summary.CNPJActive:
+			issue.Status = models.TituloCNPJStatusInactive
+		case summary.CNPJID != nil && summary.NetworkStatus != nil && !*summary.NetworkStatus:
+			issue.Status = models.TituloCNPJStatusNetworkInactive
+		case summary.CNPJID != nil:
+			continue // Vinculado a um CNPJ ativo de uma rede ativa.
+		case len(summary.CNPJCPF) == 11:
+			report.CPFTitles += summary.TitleCount // Pessoa física: fora do cadastro de CNPJs.
+			continue
+		case !utils.IsValidCNPJ(summary.CNPJCPF):
+			issue.Status = models.TituloCNPJStatusInvalid
+		default:
+			issue.Status = models.TituloCNPJStatusUnknown
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		a, b := report.Issues[i], report.Issues[j]
+		if a.Status != b.Status {
+			return cnpjCheckStatusOrder[a.Status] < cnpjCheckStatusOrder[b.Status]
+		}
+		return a.TitleCount > b.TitleCount
+	})
+	return report, nil
+}
+
+// linkTitlesAfterImport executa o vínculo com os CNPJs após uma importação ou restauração.
+// Falhas são apenas logadas: os títulos já foram gravados. Retorna nil em caso de falha.
+func (s *importServiceImpl) linkTitlesAfterImport(fileType FileType, fileName string) *models.TituloCNPJCheckReport {
+	report, err := s.linkAndCheckTitleCNPJs(fileType)
+	if err != nil {
+		appLogger.Warnf("Falha ao vincular os títulos do tipo %s (Arquivo: '%s') aos CNPJs cadastrados: %v", fileType, fileName, err)
+		return nil
+	}
+	appLogger.Infof("Títulos do tipo %s vinculados aos CNPJs: %d de %d títulos vinculados; %d CNPJs pendentes.",
+		fileType, report.LinkedTitles, report.TotalTitles, len(report.Issues))
+	return report
+}
+
+// cnpjCheckSummaryText resume o relatório em uma frase, para mensagens e auditoria.
+func cnpjCheckSummaryText(report *models.TituloCNPJCheckReport) string {
+	return fmt.Sprintf("%d de %d títulos vinculados a CNPJs cadastrados. CNPJs não cadastrados: %d, inativos: %d, com rede inativa: %d, inválidos: %d.",
+		report.LinkedTitles, report.TotalTitles,
+		report.CountByStatus(models.TituloCNPJStatusUnknown),
+		report.CountByStatus(models.TituloCNPJStatusInactive),
+		report.CountByStatus(models.TituloCNPJStatusNetworkInactive),
+		report.CountByStatus(models.TituloCNPJStatusInvalid))
+}
+
+// CheckTitleCNPJs retorna o relatório dos vínculos atuais dos títulos ativos do tipo, sem alterá-los.
+func (s *importServiceImpl) CheckTitleCNPJs(fileType FileType, userSession *auth.SessionData) (*models.TituloCNPJCheckReport, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermImportViewStatus, nil); err != nil {
+		return nil, err
+	}
+	return s.checkTitleCNPJs(fileType)
+}
+
+// RelinkTitleCNPJs refaz o vínculo dos títulos do tipo com os CNPJs cadastrados e retorna o relatório.
+func (s *importServiceImpl) RelinkTitleCNPJs(fileType FileType, userSession *auth.SessionData) (*models.TituloCNPJCheckReport, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermImportExecute, nil); err != nil {
+		return nil, err
+	}
+	report, err := s.linkAndCheckTitleCNPJs(fileType)
+	if err != nil {
+		return nil, err
+	}
+	s.auditLogService.LogAction(models.AuditLogEntry{
+		Action:      fmt.Sprintf("IMPORT_%s_CNPJ_RELINK", strings.ToUpper(string(fileType))),
+		Description: fmt.Sprintf("Vínculo dos títulos do tipo %s com os CNPJs cadastrados refeito. %s", fileType, cnpjCheckSummaryText(report)),
+		Severity:    "INFO",
+		Metadata: map[string]interface{}{
+			"file_type":     fileType,
+			"total_titles":  report.TotalTitles,
+			"linked_titles": report.LinkedTitles,
+			"pending_cnpjs": len(report.Issues),
+		},
+	}, userSession)
+	return report, nil
+}
+
+// ExportTitleCNPJCheck exporta os CNPJs pendentes dos vínculos atuais para um CSV em `ExportDir`.
+func (s *importServiceImpl) ExportTitleCNPJCheck(fileType FileType, userSession *auth.SessionData) (*models.TituloCNPJCheckReport, string, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermExportData, nil); err != nil {
+		return nil, "", err
+	}
+	report, err := s.checkTitleCNPJs(fileType)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data := make([][]string, 0, len(report.Issues)+1)
+	data = append(data, cnpjCheckExportHeaders)
+	for _, issue := range report.Issues {
+		data = append(data, []string{issue.CNPJCPF, issue.Status, strconv.Itoa(issue.TitleCount), issue.Pessoa, issue.NetworkName})
+	}
+	input, err := utils.NewSliceDataInput(data, "CNPJs Pendentes")
+	if err != nil {
+		return nil, "", err
+	}
+	fileName := fmt.Sprintf("cnpjs_pendentes_%s_%s.csv", strings.ToLower(string(fileType)), report.CheckedAt.Format("20060102_150405"))
+	// Sem sanitização: o CNPJ completo é necessário para o cadastro.
+	exportPath, err := utils.ExportToCSV(input, fileName, s.cfg, nil)
+	if err != nil {
+		appLogger.Errorf("Erro ao exportar a verificação de CNPJs dos títulos do tipo %s: %v", fileType, err)
+		return nil, "", err
+	}
+
+	s.auditLogService.LogAction(models.AuditLogEntry{
+		Action:      fmt.Sprintf("IMPORT_%s_CNPJ_CHECK_EXPORT", strings.ToUpper(string(fileType))),
+		Description: fmt.Sprintf("Verificação de CNPJs dos títulos do tipo %s exportada para '%s'. %s", fileType, exportPath, cnpjCheckSummaryText(report)),
+		Severity:    "INFO",
+		Metadata: map[string]interface{}{
+			"file_type":     fileType,
+			"export_file":   exportPath,
+			"total_titles":  report.TotalTitles,
+			"linked_titles": report.LinkedTitles,
+			"pending_cnpjs": len(report.Issues),
+		},
+	}, userSession)
+	return report, exportPath, nil
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+)
+
+// --- Importação de Feriados Locais ---
+
+// holidayDateLayouts são os formatos aceitos na coluna DATA (os mesmos dos arquivos de títulos).
+var holidayDateLayouts = []string{"02/01/2006", "2/1/2006", "02-01-2006", "2-1-2006", "2006-01-02", "20060102"}
+
+// holidayImportCounts acumula o resultado das linhas de um arquivo de feriados.
+type holidayImportCounts struct {
+	created, updated, unchanged int
+	rejected                    []string // "Linha N: motivo".
+}
+
+// importHolidays executa a importação de feriados estaduais e municipais, registrando a execução no histórico.
+// Cada linha cadastra o feriado ou atualiza o nome e a repetição anual de um já cadastrado na mesma data,
+// abrangência e local; o arquivo nunca apaga feriados.
+func (s *importServiceImpl) importHolidays(ctx context.Context, filePath string, opts ImportOptions, userSession *auth.SessionData) (map[string]interface{}, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermHolidayManage, nil); err != nil {
+		return nil, err
+	}
+	opts.Mode = ImportModeIncremental
+
+	if opts.Progress != nil {
+		opts.Progress(ImportProgress{Phase: ImportPhaseReading})
+	}
+	run := s.startImportRun(filePath, FileTypeFeriados, opts.Mode, userSession)
+	result, err := s.executeHolidayImport(ctx, filePath, opts, run, userSession)
+	s.finishImportRun(run, result, err)
+	if result != nil && run.ID != 0 {
+		result["import_run_id"] = run.ID
+	}
+	return result, err
+}
+
+// executeHolidayImport lê o arquivo e aplica cada linha ao cadastro de feriados.
+// Linhas inválidas são rejeitadas sem interromper a importação. Preenche em `run` o hash do conteúdo lido.
+func (s *importServiceImpl) executeHolidayImport(ctx context.Context, filePath string, opts ImportOptions, run *models.DBImportRun, userSession *auth.SessionData) (map[string]interface{}, error) {
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		appLogger.Errorf("Arquivo de importação não encontrado: %s", filePath)
+		return nil, fmt.Errorf("%w: arquivo '%s' não encontrado", appErrors.ErrNotFound, filepath.Base(filePath))
+	}
+	fileName := filepath.Base(filePath)
+	appLogger.Infof("Iniciando importação de feriados: Arquivo='%s', Usuário='%s'", fileName, userSession.Username)
+
+	counts := &holidayImportCounts{}
+	onSkip := func(lineNum int, record []string) {
+		counts.rejected = append(counts.rejected, fmt.Sprintf("Linha %d: número incorreto de campos: %d, esperado %d.", lineNum, len(record), len(models.ExpectedHeadersHoliday)))
+	}
+
+	stream, detectedEncoding, err := s.openImportStream(ctx, filePath, FileTypeFeriados, opts, onSkip)
+	if err != nil {
+		s.auditLogService.LogAction(models.AuditLogEntry{
+			Action:      fmt.Sprintf("IMPORT_%s_FAILED_READ", FileTypeFeriados),
+			Description: fmt.Sprintf("Falha ao ler ou validar arquivo '%s' (Encoding: %s): %v", fileName, detectedEncoding, err),
+			Severity:    "ERROR",
+			Metadata:    map[string]interface{}{"file_type": FileTypeFeriados, "filename": fileName, "error": err.Error()},
+		}, userSession)
+		return nil, err
+	}
+	defer stream.Close()
+	defer stream.recordContentHash(run)
+
+	if !stream.hasData() {
+		appLogger.Warnf("Arquivo de feriados '%s' não contém dados para importar (apenas cabeçalho ou vazio).", fileName)
+		return map[string]interface{}{
+			"status":                  "success_empty_file",
+			"import_mode":             string(ImportModeIncremental),
+			"records_processed":       0,
+			"records_skipped_parsing": 0,
+			"records_skipped_repo":    0,
+			"message":                 "Arquivo vazio ou contém apenas cabeçalho. Nenhum feriado importado.",
+		}, nil
+	}
+
+	var readErr error
+	for {
+		record, lineNum, errNext := stream.Next()
+		if errors.Is(errNext, io.EOF) {
+			break
+		}
+		if errNext != nil {
+			readErr = errNext
+			break
+		}
+		if msg := s.applyHolidayRow(record, counts, fileName, userSession); msg != "" {
+			counts.rejected = append(counts.rejected, fmt.Sprintf("Linha %d: %s", lineNum, msg))
+		}
+	}
+
+	summary := fmt.Sprintf("Feriados cadastrados: %d, atualizados: %d, inalterados: %d. Linhas rejeitadas: %d.",
+		counts.created, counts.updated, counts.unchanged, len(counts.rejected))
+	for _, rejection := range counts.rejected {
+		appLogger.Warnf("Importação de feriados '%s': %s", fileName, rejection)
+	}
+	metadata := map[string]interface{}{
+		"file_type":               FileTypeFeriados,
+		"filename":                fileName,
+		"encoding_detected":       detectedEncoding,
+		"import_profile":          stream.profileName(),
+		"total_data_rows_in_file": stream.totalDataRows,
+		"holidays_created":        counts.created,
+		"holidays_updated":        counts.updated,
+		"holidays_unchanged":      counts.unchanged,
+		"rows_rejected":           len(counts.rejected),
+	}
+
+	if readErr != nil {
+		metadata["error"] = readErr.Error()
+		entry := models.AuditLogEntry{
+			Action: fmt.Sprintf("IMPORT_%s_FAILED_READ", FileTypeFeriados),
+			Description: fmt.Sprintf("Falha ao ler arquivo '%s' (Encoding: %s) após %d linhas de dados: %v. As linhas anteriores já foram aplicadas. %s",
+				fileName, detectedEncoding, stream.totalDataRows, readErr, summary),
+			Severity: "ERROR",
+			Metadata: metadata,
+		}
+		if errors.Is(readErr, appErrors.ErrCancelled) {
+			entry.Action = fmt.Sprintf("IMPORT_%s_CANCELLED", FileTypeFeriados)
+			entry.Description = fmt.Sprintf("Importação do arquivo '%s' cancelada após %d linhas de dados. As linhas anteriores já foram aplicadas. %s",
+				fileName, stream.totalDataRows, summary)
+			entry.Severity = "WARNING"
+		}
+		s.auditLogService.LogAction(entry, userSession)
+		return nil, readErr
+	}
+
+	appliedCount := counts.created + counts.updated + counts.unchanged
+	if _, metaErr := s.importMetadataRepo.Upsert(models.ImportMetadataUpsert{
+		FileType: string(FileTypeFeriados), OriginalFilename: &fileName, RecordCount: &appliedCount, ImportedBy: &userSession.Username,
+	}); metaErr != nil {
+		appLogger.Warnf("Falha ao atualizar metadados para importação de '%s' (Tipo: %s): %v", fileName, FileTypeFeriados, metaErr)
+	}
+
+	s.auditLogService.LogAction(models.AuditLogEntry{
+		Action:      fmt.Sprintf("IMPORT_%s_SUCCESS", FileTypeFeriados),
+		Description: fmt.Sprintf("Arquivo de feriados '%s' importado. %s", fileName, summary),
+		Severity:    "INFO",
+		Metadata:    metadata,
+	}, userSession)
+	appLogger.Infof("Importação de feriados (Arquivo: '%s') concluída. %s", fileName, summary)
+
+	message := "Importação de feriados concluída. " + summary
+	if len(counts.rejected) > 0 {
+		message += " Primeira rejeição: " + counts.rejected[0]
+	}
+	return map[string]interface{}{
+		"status":                  "success",
+		"import_mode":             string(ImportModeIncremental),
+		"records_processed":       appliedCount,
+		"records_inserted":        counts.created,
+		"records_updated":         counts.updated,
+		"records_unchanged":       counts.unchanged,
+		"records_removed":         0,
+		"records_skipped_parsing": len(counts.rejected),
+		"records_skipped_repo":    0,
+		"total_data_rows_in_file": stream.totalDataRows,
+		"import_profile":          stream.profileName(),
+		"rejected_rows":           counts.rejected,
+		"message":                 message,
+	}, nil
+}
+
+// applyHolidayRow valida e aplica uma linha (DATA, NOME, ABRANGENCIA, LOCAL, ANUAL) ao cadastro de feriados,
+// atualizando os contadores. Retorna o motivo da rejeição, ou vazio se a linha foi aplicada.
+func (s *importServiceImpl) applyHolidayRow(record []string, counts *holidayImportCounts, fileName string, userSession *auth.SessionData) string {
+	rawDate := strings.TrimSpace(record[0])
+	var date time.Time
+	for _, layout := range holidayDateLayouts {
+		if parsed, err := time.Parse(layout, rawDate); err == nil {
+			date = parsed
+			break
+		}
+	}
+	if date.IsZero() {
+		return fmt.Sprintf("data '%s' inválida.", rawDate)
+	}
+	var recurring bool
+	switch strings.ToUpper(strings.TrimSpace(record[4])) {
+	case "S", "SIM", "1", "X", "TRUE":
+		recurring = true
+	case "", "N", "NAO", "NÃO", "0", "FALSE":
+	default:
+		return fmt.Sprintf("valor '%s' inválido na coluna ANUAL (use S ou N).", strings.TrimSpace(record[4]))
+	}
+
+	holidayData := models.HolidayCreate{Date: date, Name: record[1], Scope: models.HolidayScope(record[2]), Location: record[3], Recurring: recurring}
+	err := holidayData.CleanAndValidate()
+	if err == nil {
+		err = holidayData.CheckLocality(holidayLocality(s.cfg))
+	}
+	if err != nil {
+		var validationErr *appErrors.ValidationError
+		if errors.As(err, &validationErr) {
+			return validationErr.Message
+		}
+		return err.Error()
+	}
+
+	existing, err := s.holidayRepo.GetByKey(holidayData.Date, holidayData.Scope, holidayData.Location)
+	if err != nil && !errors.Is(err, appErrors.ErrNotFound) {
+		return fmt.Sprintf("falha ao consultar o feriado: %v", err)
+	}
+	switch {
+	case existing == nil:
+		dbHoliday, errCreate := s.holidayRepo.Create(holidayData, userSession.Username)
+		if errCreate != nil {
+			return fmt.Sprintf("falha ao gravar o feriado: %v", errCreate)
+		}
+		counts.created++
+		s.auditLogService.LogAction(models.AuditLogEntry{
+			Action: "HOLIDAY_CREATE",
+			Description: fmt.Sprintf("Feriado %s '%s' cadastrado em %s pela importação do arquivo '%s'.",
+				holidayData.Scope.Label(), dbHoliday.Name, dbHoliday.Date.Format("02/01/2006"), fileName),
+			Severity: "INFO",
+			Metadata: map[string]interface{}{
+				"holiday_id": dbHoliday.ID, "date": dbHoliday.Date.Format("2006-01-02"), "name": dbHoliday.Name,
+				"scope": dbHoliday.Scope, "location": dbHoliday.Location, "recurring": dbHoliday.Recurring,
+				"source": "import", "filename": fileName,
+			},
+		}, userSession)
+	case existing.Name == holidayData.Name && existing.Recurring == holidayData.Recurring:
+		counts.unchanged++
+	default:
+		if _, errUpdate := s.holidayRepo.UpdateDetails(existing.ID, holidayData.Name, holidayData.Recurring, userSession.Username); errUpdate != nil {
+			return fmt.Sprintf("falha ao atualizar o feriado: %v", errUpdate)
+		}
+		counts.updated++
+		s.auditLogService.LogAction(models.AuditLogEntry{
+			Action: "HOLIDAY_UPDATE",
+			Description: fmt.Sprintf("Feriado ID %d de %s atualizado pela importação do arquivo '%s'.",
+				existing.ID, holidayData.Date.Format("02/01/2006"), fileName),
+			Severity: "INFO",
+			Metadata: map[string]interface{}{
+				"holiday_id": existing.ID, "old_name": existing.Name, "new_name": holidayData.Name,
+				"old_recurring": existing.Recurring, "new_recurring": holidayData.Recurring,
+				"source": "import", "filename": fileName,
+			},
+		}, userSession)
+	}
+	return ""
+}
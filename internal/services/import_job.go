@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+)
+
+// --- Jobs de Importação (execução assíncrona, andamento e cancelamento) ---
+
+// ImportPhase é a etapa em que uma importação se encontra.
+type ImportPhase string
+
+const (
+	ImportPhaseReading    ImportPhase = "LENDO"         // Abrindo o arquivo e calculando o hash do conteúdo.
+	ImportPhaseDecoding   ImportPhase = "DECODIFICANDO" // Detectando encoding e delimitador e validando o cabeçalho.
+	ImportPhaseMapping    ImportPhase = "MAPEANDO"      // Lendo e mapeando as linhas, enviadas em lotes à transação.
+	ImportPhasePersisting ImportPhase = "GRAVANDO"      // Confirmando a transação e atualizando vínculos, snapshot e metadados.
+	ImportPhaseFinished   ImportPhase = "FINALIZADA"
+)
+
+// ImportProgress é um instantâneo do andamento de uma importação.
+type ImportProgress struct {
+	Phase         ImportPhase
+	RowsProcessed int   // Linhas de dados lidas do arquivo até o momento.
+	BytesRead     int64 // Bytes lidos do arquivo (apenas arquivos de texto; zero para XLSX).
+	TotalBytes    int64 // Tamanho do arquivo (apenas arquivos de texto).
+}
+
+// Fraction estima o andamento entre 0 e 1 pelos bytes lidos. Retorna -1 se não for possível
+// estimar (ex: arquivos XLSX, lidos por linha sem tamanho conhecido).
+func (p ImportProgress) Fraction() float32 {
+	switch {
+	case p.Phase == ImportPhasePersisting || p.Phase == ImportPhaseFinished:
+		return 1
+	case p.TotalBytes <= 0:
+		return -1
+	case p.BytesRead >= p.TotalBytes:
+		return 1
+	default:
+		return float32(p.BytesRead) / float32(p.TotalBytes)
+	}
+}
+
+// importProgressInterval é o intervalo mínimo entre dois relatos de andamento durante a leitura das linhas.
+const importProgressInterval = 250 * time.Millisecond
+
+// countingReader conta os bytes lidos do arquivo, para estimar o andamento da leitura.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// importProgressTracker repassa o andamento de uma importação para `ImportOptions.Progress`.
+// É usado apenas pela goroutine da importação; métodos chamados em um tracker nil não fazem nada.
+type importProgressTracker struct {
+	report     func(ImportProgress)
+	progress   ImportProgress
+	counter    *countingReader
+	lastReport time.Time
+}
+
+// newImportProgressTracker cria um tracker, ou retorna nil se não houver quem receba o andamento.
+func newImportProgressTracker(report func(ImportProgress)) *importProgressTracker {
+	if report == nil {
+		return nil
+	}
+	return &importProgressTracker{report: report}
+}
+
+// setPhase muda a etapa e relata o andamento imediatamente.
+func (t *importProgressTracker) setPhase(phase ImportPhase) {
+	if t == nil || t.progress.Phase == phase {
+		return
+	}
+	t.progress.Phase = phase
+	t.emit()
+}
+
+// rowsRead atualiza a contagem de linhas lidas, relatando no máximo a cada `importProgressInterval`.
+func (t *importProgressTracker) rowsRead(count int) {
+	if t == nil {
+		return
+	}
+	t.progress.RowsProcessed = count
+	if time.Since(t.lastReport) >= importProgressInterval {
+		t.emit()
+	}
+}
+
+func (t *importProgressTracker) emit() {
+	if t.counter != nil {
+		t.progress.BytesRead = t.counter.n
+	}
+	t.lastReport = time.Now()
+	t.report(t.progress)
+}
+
+// ImportJobState é a situação de um job de importação.
+type ImportJobState string
+
+const (
+	ImportJobRunning   ImportJobState = "EM_ANDAMENTO"
+	ImportJobSucceeded ImportJobState = "CONCLUIDO"
+	ImportJobFailed    ImportJobState = "FALHOU"
+	ImportJobCancelled ImportJobState = "CANCELADO" // Interrompido pelo usuário; a transação foi desfeita.
+)
+
+// ImportJobStatus é um instantâneo de um job de importação, seguro para leitura pela UI.
+type ImportJobStatus struct {
+	ID         uint64
+	FileType   FileType
+	FileName   string
+	Mode       ImportMode
+	StartedBy  string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+
+	State           ImportJobState
+	Progress        ImportProgress
+	CancelRequested bool
+	CancelledBy     string
+
+	// Result e Err são os retornos de `ImportFileContext`, preenchidos ao final do job.
+	Result map[string]interface{}
+	Err    error
+}
+
+// Done indica se o job terminou (com sucesso, falha ou cancelamento).
+func (st *ImportJobStatus) Done() bool {
+	return st != nil && st.State != ImportJobRunning
+}
+
+// importJob é um job de importação em execução ou concluído recentemente.
+type importJob struct {
+	mu     sync.Mutex
+	status ImportJobStatus
+	cancel context.CancelFunc
+}
+
+func (j *importJob) snapshot() *ImportJobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := j.status
+	return &status
+}
+
+func (j *importJob) setProgress(progress ImportProgress) {
+	j.mu.Lock()
+	j.status.Progress = progress
+	j.mu.Unlock()
+}
+
+func (j *importJob) finish(result map[string]interface{}, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	finishedAt := time.Now()
+	j.status.FinishedAt = &finishedAt
+	j.status.Progress.Phase = ImportPhaseFinished
+	j.status.Result = result
+	j.status.Err = err
+	switch {
+	case err == nil:
+		j.status.State = ImportJobSucceeded
+	case errors.Is(err, appErrors.ErrCancelled):
+		j.status.State = ImportJobCancelled
+	default:
+		j.status.State = ImportJobFailed
+	}
+}
+
+// importJobRetention é o número de jobs concluídos mantidos em memória para consulta.
+const importJobRetention = 20
+
+// importJobRegistry guarda os jobs de importação do processo. O valor zero está pronto para uso.
+type importJobRegistry struct {
+	mu     sync.Mutex
+	nextID uint64
+	jobs   []*importJob // Em ordem de criação.
+}
+
+// start registra um novo job, recusando-o se já houver um job em andamento para o mesmo tipo.
+func (r *importJobRegistry) start(status ImportJobStatus, cancel context.CancelFunc) (*importJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, job := range r.jobs {
+		if current := job.snapshot(); current.FileType == status.FileType && !current.Done() {
+			return nil, fmt.Errorf("%w: já existe uma importação do tipo %s em andamento (job #%d, arquivo '%s')",
+				appErrors.ErrConflict, status.FileType, current.ID, current.FileName)
+		}
+	}
+
+	r.nextID++
+	status.ID = r.nextID
+	status.State = ImportJobRunning
+	job := &importJob{status: status, cancel: cancel}
+	r.jobs = append(r.jobs, job)
+
+	// Descarta os jobs concluídos mais antigos além do limite de retenção.
+	finished := 0
+	for _, existing := range r.jobs {
+		if existing.snapshot().Done() {
+			finished++
+		}
+	}
+	kept := make([]*importJob, 0, len(r.jobs))
+	for _, existing := range r.jobs {
+		if finished > importJobRetention && existing.snapshot().Done() {
+			finished--
+			continue
+		}
+		kept = append(kept, existing)
+	}
+	r.jobs = kept
+	return job, nil
+}
+
+func (r *importJobRegistry) get(jobID uint64) *importJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, job := range r.jobs {
+		if job.status.ID == jobID { // O ID não muda após a criação.
+			return job
+		}
+	}
+	return nil
+}
+
+func (r *importJobRegistry) list() []*ImportJobStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	statuses := make([]*ImportJobStatus, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		statuses = append(statuses, job.snapshot())
+	}
+	sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].ID > statuses[j].ID })
+	return statuses
+}
+
+// StartImportJob inicia a importação em segundo plano e retorna o job criado.
+func (s *importServiceImpl) StartImportJob(filePath string, fileType FileType, opts ImportOptions, userSession *auth.SessionData) (*ImportJobStatus, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermImportExecute, nil); err != nil {
+		return nil, err
+	}
+	if _, err := getExpectedHeaders(fileType); err != nil {
+		return nil, fmt.Errorf("%w: tipo de arquivo '%s' não é suportado: %v", appErrors.ErrInvalidInput, fileType, err)
+	}
+
+	// Verificação antecipada do lock entre processos, para recusar o job já na chamada. O lock é
+	// obtido de fato pela própria importação.
+	if holder, errLock := s.importLockRepo.Get(string(fileType)); errLock == nil && holder != nil {
+		return nil, &models.ImportLockedError{FileType: holder.FileType, HeldBy: holder.HeldBy, HostName: holder.HostName, AcquiredAt: holder.AcquiredAt}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job, err := s.jobs.start(ImportJobStatus{
+		FileType:  fileType,
+		FileName:  filepath.Base(filePath),
+		Mode:      opts.Mode,
+		StartedBy: userSession.Username,
+		StartedAt: time.Now(),
+		Progress:  ImportProgress{Phase: ImportPhaseReading},
+	}, cancel)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	callerProgress := opts.Progress
+	opts.Progress = func(progress ImportProgress) {
+		job.setProgress(progress)
+		if callerProgress != nil {
+			callerProgress(progress)
+		}
+	}
+	status := job.snapshot()
+	appLogger.Infof("Job de importação #%d iniciado: Tipo='%s', Arquivo='%s', Usuário='%s'", status.ID, fileType, status.FileName, userSession.Username)
+
+	go func() {
+		defer cancel()
+		result, errImport := s.ImportFileContext(ctx, filePath, fileType, opts, userSession)
+		job.finish(result, errImport)
+		final := job.snapshot()
+		appLogger.Infof("Job de importação #%d finalizado: %s", final.ID, final.State)
+	}()
+	return status, nil
+}
+
+// GetImportJob retorna o estado atual de um job de importação.
+func (s *importServiceImpl) GetImportJob(jobID uint64, userSession *auth.SessionData) (*ImportJobStatus, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermImportViewStatus, nil); err != nil {
+		return nil, err
+	}
+	job := s.jobs.get(jobID)
+	if job == nil {
+		return nil, fmt.Errorf("%w: job de importação #%d não encontrado", appErrors.ErrNotFound, jobID)
+	}
+	return job.snapshot(), nil
+}
+
+// GetImportJobs retorna os jobs em andamento e os concluídos recentemente, do mais novo ao mais antigo.
+func (s *importServiceImpl) GetImportJobs(userSession *auth.SessionData) ([]*ImportJobStatus, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermImportViewStatus, nil); err != nil {
+		return nil, err
+	}
+	return s.jobs.list(), nil
+}
+
+// CancelImportJob pede a interrupção de um job em andamento. A leitura para na próxima linha e a
+// transação é desfeita. Depois que a gravação final começou, o job não pode mais ser cancelado.
+func (s *importServiceImpl) CancelImportJob(jobID uint64, userSession *auth.SessionData) error {
+	if err := s.permManager.CheckPermission(userSession, auth.PermImportExecute, nil); err != nil {
+		return err
+	}
+	job := s.jobs.get(jobID)
+	if job == nil {
+		return fmt.Errorf("%w: job de importação #%d não encontrado", appErrors.ErrNotFound, jobID)
+	}
+
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	switch {
+	case job.status.State != ImportJobRunning:
+		return fmt.Errorf("%w: o job de importação #%d já foi finalizado", appErrors.ErrConflict, jobID)
+	case job.status.Progress.Phase == ImportPhasePersisting:
+		return fmt.Errorf("%w: o job de importação #%d já está gravando os dados e não pode mais ser cancelado", appErrors.ErrConflict, jobID)
+	}
+	job.status.CancelRequested = true
+	job.status.CancelledBy = userSession.Username
+	job.cancel()
+	appLogger.Infof("Cancelamento do job de importação #%d (Arquivo: '%s') solicitado por '%s'.", jobID, job.status.FileName, userSession.Username)
+	return nil
+}
@@ -0,0 +1,395 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/utils"
+)
+
+// --- Importação em Lote de Redes e CNPJs ---
+
+// networkCNPJReportHeaders são as colunas do CSV com o resultado de cada linha.
+var networkCNPJReportHeaders = []string{"LINHA", "REDE", "COMPRADOR", "CNPJ", "SITUACAO", "REDE_CRIADA", "MENSAGEM"}
+
+// networkCNPJSeen guarda a primeira linha do arquivo em que um CNPJ apareceu.
+type networkCNPJSeen struct {
+	lineNumber  int
+	networkName string // Nome normalizado (minúsculas).
+}
+
+// networkCNPJImporter processa as linhas de um arquivo de redes e CNPJs, mantendo as redes já
+// resolvidas e os CNPJs já vistos no arquivo.
+type networkCNPJImporter struct {
+	s           *importServiceImpl
+	userSession *auth.SessionData
+	fileName    string
+	networks    map[string]*models.DBNetwork // Por nome normalizado.
+	seenCNPJs   map[string]networkCNPJSeen
+}
+
+// importNetworkCNPJs executa a importação em lote de redes e CNPJs, registrando a execução no histórico.
+// Se `ctx` for cancelado, a leitura para na próxima linha; as linhas já aplicadas permanecem gravadas
+// (cada linha é independente) e constam do relatório.
+func (s *importServiceImpl) importNetworkCNPJs(ctx context.Context, filePath string, opts ImportOptions, userSession *auth.SessionData) (map[string]interface{}, error) {
+	for _, perm := range []auth.Permission{auth.PermNetworkCreate, auth.PermCNPJCreate, auth.PermCNPJUpdate} {
+		if err := s.permManager.CheckPermission(userSession, perm, nil); err != nil {
+			return nil, err
+		}
+	}
+	// Cada linha é aplicada individualmente; o arquivo nunca substitui o cadastro.
+	opts.Mode = ImportModeIncremental
+
+	if opts.Progress != nil {
+		opts.Progress(ImportProgress{Phase: ImportPhaseReading})
+	}
+	run := s.startImportRun(filePath, FileTypeRedesCNPJs, opts.Mode, userSession)
+	result, err := s.executeNetworkCNPJImport(ctx, filePath, opts, run, userSession)
+	s.finishImportRun(run, result, err)
+	if result != nil && run.ID != 0 {
+		result["import_run_id"] = run.ID
+	}
+	return result, err
+}
+
+// executeNetworkCNPJImport lê o arquivo e aplica cada linha ao cadastro de redes e CNPJs.
+// Linhas inválidas são rejeitadas sem interromper a importação; todas constam do relatório.
+func (s *importServiceImpl) executeNetworkCNPJImport(ctx context.Context, filePath string, opts ImportOptions, run *models.DBImportRun, userSession *auth.SessionData) (map[string]interface{}, error) {
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		appLogger.Errorf("Arquivo de importação não encontrado: %s", filePath)
+		return nil, fmt.Errorf("%w: arquivo '%s' não encontrado", appErrors.ErrNotFound, filepath.Base(filePath))
+	}
+	fileName := filepath.Base(filePath)
+	appLogger.Infof("Iniciando importação de redes e CNPJs: Arquivo='%s', Usuário='%s'", fileName, userSession.Username)
+
+	report := &models.NetworkCNPJImportReport{OriginalFilename: fileName, Rows: []models.NetworkCNPJImportRow{}}
+	onSkip := func(lineNum int, record []string) {
+		report.AddRow(models.NetworkCNPJImportRow{
+			LineNumber: lineNum,
+			Status:     models.NetworkCNPJRowRejected,
+			Message:    fmt.Sprintf("Número incorreto de campos: %d, esperado %d.", len(record), len(models.ExpectedHeadersNetworkCNPJ)),
+		})
+	}
+
+	stream, detectedEncoding, err := s.openImportStream(ctx, filePath, FileTypeRedesCNPJs, opts, onSkip)
+	if detectedEncoding != "" {
+		run.EncodingDetected = &detectedEncoding
+	}
+	if err != nil {
+		s.auditLogService.LogAction(models.AuditLogEntry{
+			Action:      fmt.Sprintf("IMPORT_%s_FAILED_READ", FileTypeRedesCNPJs),
+			Description: fmt.Sprintf("Falha ao ler ou validar arquivo '%s' (Encoding: %s): %v", fileName, detectedEncoding, err),
+			Severity:    "ERROR",
+			Metadata:    map[string]interface{}{"file_type": FileTypeRedesCNPJs, "filename": fileName, "error": err.Error()},
+		}, userSession)
+		return nil, err
+	}
+	defer stream.Close()
+	defer stream.recordContentHash(run)
+	if stream.delimiterDesc != "" {
+		run.DelimiterDetected = &stream.delimiterDesc
+	}
+
+	if !stream.hasData() {
+		appLogger.Warnf("Arquivo de redes e CNPJs '%s' não contém dados para importar (apenas cabeçalho ou vazio).", fileName)
+		return map[string]interface{}{
+			"status":                  "success_empty_file",
+			"import_mode":             string(ImportModeIncremental),
+			"records_processed":       0,
+			"records_skipped_parsing": 0,
+			"records_skipped_repo":    0,
+			"network_cnpj_report":     report,
+			"message":                 "Arquivo vazio ou contém apenas cabeçalho. Nenhuma linha de dados processada.",
+		}, nil
+	}
+
+	importer := &networkCNPJImporter{
+		s:           s,
+		userSession: userSession,
+		fileName:    fileName,
+		networks:    make(map[string]*models.DBNetwork),
+		seenCNPJs:   make(map[string]networkCNPJSeen),
+	}
+	var readErr error
+	for {
+		record, lineNum, errNext := stream.Next()
+		if errors.Is(errNext, io.EOF) {
+			break
+		}
+		if errNext != nil {
+			readErr = errNext
+			break
+		}
+		report.AddRow(importer.processRow(record, lineNum))
+	}
+	report.TotalRows = stream.totalDataRows
+
+	// O relatório é gravado mesmo após uma falha de leitura: as linhas anteriores já foram aplicadas.
+	report.ReportFile = s.writeNetworkCNPJReport(report)
+
+	// Novos CNPJs (ou CNPJs movidos de rede) mudam o vínculo dos títulos já importados.
+	if report.CNPJsCreated > 0 || report.CNPJsUpdated > 0 {
+		s.linkTitlesAfterImport(FileTypeDireitos, fileName)
+		s.linkTitlesAfterImport(FileTypeObrigacoes, fileName)
+	}
+
+	appliedCount := report.CNPJsCreated + report.CNPJsUpdated + report.CNPJsUnchanged
+	summary := fmt.Sprintf("Redes criadas: %d. CNPJs cadastrados: %d, atualizados: %d, inalterados: %d. Linhas rejeitadas: %d, repetidas: %d.",
+		report.NetworksCreated, report.CNPJsCreated, report.CNPJsUpdated, report.CNPJsUnchanged, report.RejectedRows, report.DuplicateRows)
+	metadata := map[string]interface{}{
+		"file_type":               FileTypeRedesCNPJs,
+		"import_run_id":           run.ID,
+		"filename":                fileName,
+		"encoding_detected":       detectedEncoding,
+		"delimiter_detected":      stream.delimiterDesc,
+		"import_profile":          stream.profileName(),
+		"total_data_rows_in_file": report.TotalRows,
+		"networks_created":        report.NetworksCreated,
+		"cnpjs_created":           report.CNPJsCreated,
+		"cnpjs_updated":           report.CNPJsUpdated,
+		"cnpjs_unchanged":         report.CNPJsUnchanged,
+		"rows_rejected":           report.RejectedRows,
+		"rows_duplicate":          report.DuplicateRows,
+		"report_file":             report.ReportFile,
+	}
+
+	if readErr != nil {
+		metadata["error"] = readErr.Error()
+		entry := models.AuditLogEntry{
+			Action: fmt.Sprintf("IMPORT_%s_FAILED_READ", FileTypeRedesCNPJs),
+			Description: fmt.Sprintf("Falha ao ler arquivo '%s' (Encoding: %s) após %d linhas de dados: %v. As linhas anteriores já foram aplicadas. %s",
+				fileName, detectedEncoding, report.TotalRows, readErr, summary),
+			Severity: "ERROR",
+			Metadata: metadata,
+		}
+		if errors.Is(readErr, appErrors.ErrCancelled) {
+			entry.Action = fmt.Sprintf("IMPORT_%s_CANCELLED", FileTypeRedesCNPJs)
+			entry.Description = fmt.Sprintf("Importação do arquivo '%s' cancelada após %d linhas de dados. As linhas anteriores já foram aplicadas. %s",
+				fileName, report.TotalRows, summary)
+			entry.Severity = "WARNING"
+		}
+		s.auditLogService.LogAction(entry, userSession)
+		if report.ReportFile != "" {
+			readErr = fmt.Errorf("%w (resultado das linhas anteriores gravado em '%s')", readErr, report.ReportFile)
+		}
+		return nil, readErr
+	}
+
+	if _, metaErr := s.importMetadataRepo.Upsert(models.ImportMetadataUpsert{
+		FileType: string(FileTypeRedesCNPJs), OriginalFilename: &fileName, RecordCount: &appliedCount, ImportedBy: &userSession.Username,
+	}); metaErr != nil {
+		appLogger.Warnf("Falha ao atualizar metadados para importação de '%s' (Tipo: %s): %v", fileName, FileTypeRedesCNPJs, metaErr)
+	}
+
+	description := fmt.Sprintf("Arquivo de redes e CNPJs '%s' importado. %s", fileName, summary)
+	message := "Importação de redes e CNPJs concluída. " + summary
+	if report.ReportFile != "" {
+		description += fmt.Sprintf(" Resultado por linha gravado em '%s'.", report.ReportFile)
+		message += fmt.Sprintf(" Resultado por linha gravado em '%s'.", report.ReportFile)
+	}
+	s.auditLogService.LogAction(models.AuditLogEntry{
+		Action:      fmt.Sprintf("IMPORT_%s_SUCCESS", FileTypeRedesCNPJs),
+		Description: description,
+		Severity:    "INFO",
+		Metadata:    metadata,
+	}, userSession)
+	appLogger.Infof("Importação de redes e CNPJs (Arquivo: '%s') concluída. %s", fileName, summary)
+
+	return map[string]interface{}{
+		"status":                  "success",
+		"import_mode":             string(ImportModeIncremental),
+		"records_processed":       appliedCount,
+		"records_inserted":        report.CNPJsCreated,
+		"records_updated":         report.CNPJsUpdated,
+		"records_unchanged":       report.CNPJsUnchanged,
+		"records_removed":         0,
+		"records_skipped_parsing": report.RejectedRows + report.DuplicateRows,
+		"records_skipped_repo":    0,
+		"total_data_rows_in_file": report.TotalRows,
+		"import_profile":          stream.profileName(),
+		"network_cnpj_report":     report,
+		"message":                 message,
+	}, nil
+}
+
+// processRow valida e aplica uma linha (REDE, COMPRADOR, CNPJ) ao cadastro.
+func (imp *networkCNPJImporter) processRow(record []string, lineNum int) models.NetworkCNPJImportRow {
+	row := models.NetworkCNPJImportRow{
+		LineNumber:  lineNum,
+		NetworkName: strings.TrimSpace(record[0]),
+		Buyer:       strings.TrimSpace(record[1]),
+		CNPJ:        strings.TrimSpace(record[2]),
+	}
+	reject := func(format string, args ...interface{}) models.NetworkCNPJImportRow {
+		row.Status = models.NetworkCNPJRowRejected
+		row.Message = fmt.Sprintf(format, args...)
+		return row
+	}
+
+	// 1. Validar a rede e o CNPJ, com as mesmas regras do cadastro manual.
+	networkData := models.NetworkCreate{Name: record[0], Buyer: record[1]}
+	if err := networkData.CleanAndValidate(); err != nil {
+		var validationErr *appErrors.ValidationError
+		if errors.As(err, &validationErr) {
+			return reject("%s", validationErr.Message)
+		}
+		return reject("Rede inválida: %v", err)
+	}
+	cnpj := models.CleanCNPJ(record[2])
+	if !utils.IsValidCNPJ(cnpj) {
+		return reject("CNPJ '%s' inválido.", row.CNPJ)
+	}
+	row.CNPJ = cnpj
+
+	// 2. Um CNPJ pertence a uma única rede: repetições no arquivo são ignoradas ou rejeitadas.
+	if seen, ok := imp.seenCNPJs[cnpj]; ok {
+		if seen.networkName == networkData.Name {
+			row.Status = models.NetworkCNPJRowDuplicate
+			row.Message = fmt.Sprintf("CNPJ já informado na linha %d para a mesma rede.", seen.lineNumber)
+			return row
+		}
+		return reject("CNPJ já informado na linha %d para a rede '%s'.", seen.lineNumber, seen.networkName)
+	}
+	imp.seenCNPJs[cnpj] = networkCNPJSeen{lineNumber: lineNum, networkName: networkData.Name}
+
+	// 3. Obter a rede, criando-a se ainda não existir.
+	network, created, err := imp.resolveNetwork(networkData)
+	if err != nil {
+		return reject("Falha ao obter ou criar a rede '%s': %v", networkData.Name, err)
+	}
+	row.NetworkCreated = created
+	var notes []string
+	if created {
+		notes = append(notes, "Rede criada.")
+	} else if !strings.EqualFold(network.Buyer, networkData.Buyer) {
+		notes = append(notes, fmt.Sprintf("Comprador da rede no cadastro ('%s') difere do arquivo; cadastro mantido.", network.Buyer))
+	}
+	if !network.Status {
+		return reject("Rede '%s' está inativa; o CNPJ não foi associado.", network.Name)
+	}
+
+	// 4. Cadastrar o CNPJ ou atualizá-lo (rede e reativação).
+	existing, err := imp.s.cnpjRepo.GetByCNPJ(cnpj)
+	if err != nil && !errors.Is(err, appErrors.ErrNotFound) {
+		return reject("Falha ao consultar o CNPJ: %v", err)
+	}
+	if existing != nil && existing.NetworkID == network.ID && existing.Active {
+		row.Status = models.NetworkCNPJRowUnchanged
+		row.Message = strings.Join(notes, " ")
+		return row
+	}
+
+	dbCNPJ, err := imp.s.cnpjRepo.UpsertCNPJ(models.CNPJCreate{CNPJ: cnpj, NetworkID: network.ID})
+	if err != nil {
+		return reject("Falha ao gravar o CNPJ: %v", err)
+	}
+
+	if existing == nil {
+		row.Status = models.NetworkCNPJRowCreated
+		imp.s.auditLogService.LogAction(models.AuditLogEntry{
+			Action:      "CNPJ_CREATE",
+			Description: fmt.Sprintf("CNPJ %s cadastrado para rede '%s' (ID: %d) pela importação do arquivo '%s'.", models.ToCNPJPublic(dbCNPJ).FormatCNPJ(), network.Name, network.ID, imp.fileName),
+			Severity:    "INFO",
+			Metadata: map[string]interface{}{
+				"cnpj_id": dbCNPJ.ID, "cnpj": dbCNPJ.CNPJ, "network_id": network.ID, "network_name": network.Name,
+				"source": "import", "filename": imp.fileName, "line_number": lineNum,
+			},
+		}, imp.userSession)
+	} else {
+		row.Status = models.NetworkCNPJRowUpdated
+		meta := map[string]interface{}{
+			"cnpj_id": dbCNPJ.ID, "cnpj": dbCNPJ.CNPJ, "source": "import", "filename": imp.fileName, "line_number": lineNum,
+		}
+		var changed []string
+		if existing.NetworkID != network.ID {
+			changed = append(changed, "network_id")
+			meta["old_network_id"] = existing.NetworkID
+			meta["new_network_id"] = network.ID
+			notes = append(notes, fmt.Sprintf("CNPJ movido da rede ID %d.", existing.NetworkID))
+		}
+		if !existing.Active {
+			changed = append(changed, "active")
+			meta["new_active"] = true
+			notes = append(notes, "CNPJ reativado.")
+		}
+		imp.s.auditLogService.LogAction(models.AuditLogEntry{
+			Action: "CNPJ_UPDATE",
+			Description: fmt.Sprintf("CNPJ %s (ID %d) atualizado pela importação do arquivo '%s'. Campos alterados: %s.",
+				models.ToCNPJPublic(dbCNPJ).FormatCNPJ(), dbCNPJ.ID, imp.fileName, strings.Join(changed, ", ")),
+			Severity: "INFO",
+			Metadata: meta,
+		}, imp.userSession)
+	}
+	row.Message = strings.Join(notes, " ")
+	return row
+}
+
+// resolveNetwork busca a rede pelo nome normalizado (com cache) e a cria se não existir.
+// Retorna a rede e se ela foi criada nesta chamada.
+func (imp *networkCNPJImporter) resolveNetwork(networkData models.NetworkCreate) (*models.DBNetwork, bool, error) {
+	if network, ok := imp.networks[networkData.Name]; ok {
+		return network, false, nil
+	}
+	network, err := imp.s.networkRepo.GetByName(networkData.Name)
+	if err == nil {
+		imp.networks[networkData.Name] = network
+		return network, false, nil
+	}
+	if !errors.Is(err, appErrors.ErrNotFound) {
+		return nil, false, err
+	}
+
+	network, err = imp.s.networkRepo.Create(networkData, imp.userSession.Username)
+	if err != nil {
+		return nil, false, err
+	}
+	imp.networks[networkData.Name] = network
+	imp.s.auditLogService.LogAction(models.AuditLogEntry{
+		Action:      "NETWORK_CREATE",
+		Description: fmt.Sprintf("Nova rede '%s' (Comprador: %s) criada pela importação do arquivo '%s'.", network.Name, network.Buyer, imp.fileName),
+		Severity:    "INFO",
+		Metadata: map[string]interface{}{
+			"network_id": network.ID, "name": network.Name, "buyer": network.Buyer, "source": "import", "filename": imp.fileName,
+		},
+	}, imp.userSession)
+	return network, true, nil
+}
+
+// writeNetworkCNPJReport grava o resultado de cada linha em um CSV em `ExportDir`.
+// Retorna o caminho do arquivo, ou "" se não pôde ser gravado (a falha é apenas logada).
+func (s *importServiceImpl) writeNetworkCNPJReport(report *models.NetworkCNPJImportReport) string {
+	data := make([][]string, 0, len(report.Rows)+1)
+	data = append(data, networkCNPJReportHeaders)
+	for _, row := range report.Rows {
+		networkCreated := "NAO"
+		if row.NetworkCreated {
+			networkCreated = "SIM"
+		}
+		data = append(data, []string{strconv.Itoa(row.LineNumber), row.NetworkName, row.Buyer, row.CNPJ, row.Status, networkCreated, row.Message})
+	}
+	input, err := utils.NewSliceDataInput(data, "Redes e CNPJs")
+	if err != nil {
+		appLogger.Warnf("Falha ao montar o relatório da importação de redes e CNPJs '%s': %v", report.OriginalFilename, err)
+		return ""
+	}
+	baseName := strings.TrimSuffix(report.OriginalFilename, filepath.Ext(report.OriginalFilename))
+	fileName := fmt.Sprintf("resultado_redes_cnpjs_%s_%s.csv", baseName, time.Now().Format("20060102_150405"))
+	// Sem sanitização: o CNPJ completo é necessário para corrigir as linhas rejeitadas.
+	reportPath, err := utils.ExportToCSV(input, fileName, s.cfg, nil)
+	if err != nil {
+		appLogger.Warnf("Falha ao gravar o relatório da importação de redes e CNPJs '%s': %v", report.OriginalFilename, err)
+		return ""
+	}
+	return reportPath
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+)
+
+// importDateTimeField é a coluna de data que também guarda a hora (as demais são apenas datas).
+const importDateTimeField = "DTAALTERAÇÃO"
+
+// importColumnLayout mapeia as colunas do arquivo para as colunas esperadas do tipo, segundo um
+// perfil de importação. `apply` entrega as linhas já na ordem esperada, com datas e valores
+// normalizados para os formatos que o repositório interpreta ("DD/MM/AAAA" e vírgula decimal).
+type importColumnLayout struct {
+	profileName string
+	fileCols    int      // Número de colunas do cabeçalho do arquivo.
+	sourceIdx   []int    // Para cada coluna esperada, o índice da coluna no arquivo (-1 se ausente).
+	fileFields  []string // Para cada coluna do arquivo, o nome da coluna esperada ("" se ignorada).
+
+	dateKinds   []importDateKind // Tipo de data de cada coluna esperada.
+	decimalCols []bool           // Colunas esperadas com valores monetários.
+	dateLayouts []string         // Layouts (`time`) dos formatos de data do perfil.
+	decimalSep  string
+
+	record []string // Buffer reutilizado entre as linhas.
+}
+
+// importDateKind classifica as colunas de data para a normalização.
+type importDateKind int
+
+const (
+	importDateNone importDateKind = iota
+	importDateOnly
+	importDateTime
+)
+
+// classifyImportField indica se a coluna esperada é de data (e se guarda hora) ou de valor monetário.
+func classifyImportField(field string) (importDateKind, bool) {
+	upper := strings.ToUpper(strings.TrimSpace(field))
+	switch {
+	case upper == importDateTimeField:
+		return importDateTime, false
+	case strings.HasPrefix(upper, "DTA"):
+		return importDateOnly, false
+	case strings.HasPrefix(upper, "VLR"):
+		return importDateNone, true
+	}
+	return importDateNone, false
+}
+
+// newImportColumnLayout resolve o cabeçalho do arquivo contra as colunas esperadas usando o perfil.
+// Cada coluna esperada é procurada pelo próprio nome ou por um de seus aliases (case-insensitive);
+// colunas extras do arquivo são ignoradas. Retorna `appErrors.ErrValidation` se uma coluna
+// obrigatória não for encontrada ou aparecer mais de uma vez.
+func newImportColumnLayout(fileName string, headerRow, expectedHeaders []string, profile *models.DBImportProfile) (*importColumnLayout, error) {
+	public := models.ToImportProfilePublic(profile)
+
+	headerIdx := make(map[string][]int, len(headerRow))
+	for i, header := range headerRow {
+		key := strings.ToUpper(strings.TrimSpace(header))
+		if key != "" {
+			headerIdx[key] = append(headerIdx[key], i)
+		}
+	}
+
+	layout := &importColumnLayout{
+		profileName: profile.Name,
+		fileCols:    len(headerRow),
+		sourceIdx:   make([]int, len(expectedHeaders)),
+		fileFields:  make([]string, len(headerRow)),
+		dateKinds:   make([]importDateKind, len(expectedHeaders)),
+		decimalCols: make([]bool, len(expectedHeaders)),
+		decimalSep:  profile.DecimalSeparator,
+		record:      make([]string, len(expectedHeaders)),
+	}
+	var missing []string
+	for i, field := range expectedHeaders {
+		names := []string{field}
+		optional := false
+		if col := public.Column(field); col != nil {
+			names = append(names, col.Aliases...)
+			optional = col.Optional
+		}
+
+		var found []int
+		for _, name := range names {
+			found = append(found, headerIdx[strings.ToUpper(strings.TrimSpace(name))]...)
+		}
+		switch {
+		case len(found) > 1:
+			appLogger.Errorf("Arquivo '%s' (perfil '%s'): coluna '%s' encontrada %d vezes no cabeçalho %v.", fileName, profile.Name, field, len(found), headerRow)
+			return nil, fmt.Errorf("%w: arquivo '%s' tem mais de uma coluna para '%s' (perfil '%s')",
+				appErrors.ErrValidation, fileName, field, profile.Name)
+		case len(found) == 1:
+			layout.sourceIdx[i] = found[0]
+			layout.fileFields[found[0]] = field
+		case optional:
+			layout.sourceIdx[i] = -1
+		default:
+			missing = append(missing, field)
+		}
+		layout.dateKinds[i], layout.decimalCols[i] = classifyImportField(field)
+	}
+	if len(missing) > 0 {
+		appLogger.Errorf("Arquivo '%s' (perfil '%s'): colunas obrigatórias ausentes %v. Cabeçalho recebido: %v", fileName, profile.Name, missing, headerRow)
+		return nil, fmt.Errorf("%w: arquivo '%s' não tem as colunas obrigatórias %s (perfil '%s')",
+			appErrors.ErrValidation, fileName, strings.Join(missing, ", "), profile.Name)
+	}
+
+	for _, format := range public.DateFormats {
+		goLayout, err := models.ImportDateFormatToLayout(format)
+		if err != nil { // Validado ao salvar o perfil; ignora formatos inválidos gravados por fora.
+			appLogger.Warnf("Perfil de importação '%s': formato de data '%s' ignorado: %v", profile.Name, format, err)
+			continue
+		}
+		layout.dateLayouts = append(layout.dateLayouts, goLayout)
+	}
+	return layout, nil
+}
+
+// apply projeta uma linha do arquivo na ordem das colunas esperadas e normaliza datas e valores.
+// O slice retornado é reutilizado pela próxima chamada.
+func (l *importColumnLayout) apply(record []string) []string {
+	for i, src := range l.sourceIdx {
+		value := ""
+		if src >= 0 && src < len(record) {
+			value = record[src]
+		}
+		switch {
+		case l.dateKinds[i] != importDateNone:
+			value = l.normalizeDate(value, l.dateKinds[i] == importDateTime)
+		case l.decimalCols[i]:
+			value = l.normalizeDecimal(value)
+		}
+		l.record[i] = value
+	}
+	return l.record
+}
+
+// normalizeDate converte uma data em um dos formatos do perfil para "DD/MM/AAAA" (com a hora,
+// em colunas de data/hora). Valores que não correspondem a nenhum formato são mantidos, para que
+// o parsing padrão da importação tente interpretá-los (e registre o problema, se falhar).
+func (l *importColumnLayout) normalizeDate(value string, withTime bool) string {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" || len(l.dateLayouts) == 0 {
+		return value
+	}
+	for _, layout := range l.dateLayouts {
+		t, err := time.Parse(layout, trimmed)
+		if err != nil {
+			continue
+		}
+		if withTime && (t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0) {
+			return t.Format("02/01/2006 15:04:05")
+		}
+		return t.Format("02/01/2006")
+	}
+	return value
+}
+
+// normalizeDecimal remove o separador de milhar quando o separador decimal do perfil é o ponto,
+// para que o valor não seja confundido com o formato brasileiro (vírgula decimal).
+func (l *importColumnLayout) normalizeDecimal(value string) string {
+	if l.decimalSep != "." {
+		return value
+	}
+	return strings.ReplaceAll(value, ",", "")
+}
+
+// --- Gerenciamento de Perfis ---
+
+// loadImportProfile busca o perfil de importação informado e confere se ele é do tipo de arquivo.
+// Retorna nil se `profileID` for zero (layout padrão).
+func (s *importServiceImpl) loadImportProfile(profileID uint64, fileType FileType) (*models.DBImportProfile, error) {
+	if profileID == 0 {
+		return nil, nil
+	}
+	profile, err := s.importProfileRepo.GetByID(profileID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(profile.FileType, string(fileType)) {
+		return nil, fmt.Errorf("%w: perfil de importação '%s' é do tipo %s, não %s", appErrors.ErrInvalidInput, profile.Name, profile.FileType, fileType)
+	}
+	return profile, nil
+}
+
+// GetImportProfiles busca os perfis de importação do tipo de arquivo.
+func (s *importServiceImpl) GetImportProfiles(fileType FileType, userSession *auth.SessionData) ([]*models.ImportProfilePublic, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermImportExecute, nil); err != nil {
+		return nil, err
+	}
+	profiles, err := s.importProfileRepo.GetAll(string(fileType))
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	return models.ToImportProfilePublicList(profiles), nil
+}
+
+// SaveImportProfile cria (`profileID` zero) ou atualiza um perfil de importação.
+func (s *importServiceImpl) SaveImportProfile(profileID uint64, data models.ImportProfileUpsert, userSession *auth.SessionData) (*models.ImportProfilePublic, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermImportManageProfiles, nil); err != nil {
+		return nil, err
+	}
+	expectedHeaders, err := getExpectedHeaders(FileType(strings.ToUpper(strings.TrimSpace(data.FileType))))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", appErrors.ErrInvalidInput, err)
+	}
+	if err := data.CleanAndValidate(expectedHeaders); err != nil {
+		return nil, err
+	}
+
+	profile := &models.DBImportProfile{
+		ID:               profileID,
+		Name:             data.Name,
+		FileType:         data.FileType,
+		DecimalSeparator: data.DecimalSeparator,
+		DateFormats:      strings.Join(data.DateFormats, models.ImportProfileListSeparator),
+		IsDefault:        data.IsDefault,
+		UpdatedBy:        &userSession.Username,
+	}
+	for _, col := range data.Columns {
+		profile.Columns = append(profile.Columns, models.DBImportProfileColumn{
+			Field:    col.Field,
+			Aliases:  strings.Join(col.Aliases, models.ImportProfileListSeparator),
+			Optional: col.Optional,
+		})
+	}
+
+	action := "IMPORT_PROFILE_CREATE"
+	if profileID == 0 {
+		profile.CreatedBy = &userSession.Username
+		err = s.importProfileRepo.Create(profile)
+	} else {
+		action = "IMPORT_PROFILE_UPDATE"
+		if existing, errGet := s.importProfileRepo.GetByID(profileID); errGet != nil {
+			return nil, errGet
+		} else if !strings.EqualFold(existing.FileType, profile.FileType) {
+			return nil, fmt.Errorf("%w: o tipo de arquivo de um perfil existente não pode ser alterado", appErrors.ErrInvalidInput)
+		}
+		err = s.importProfileRepo.Update(profile)
+	}
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+
+	saved, err := s.importProfileRepo.GetByID(profile.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.auditLogService.LogAction(models.AuditLogEntry{
+		Action:      action,
+		Description: fmt.Sprintf("Perfil de importação '%s' (Tipo: %s) salvo com %d colunas configuradas.", saved.Name, saved.FileType, len(saved.Columns)),
+		Severity:    "INFO",
+		Metadata: map[string]interface{}{
+			"profile_id":        saved.ID,
+			"profile_name":      saved.Name,
+			"file_type":         saved.FileType,
+			"decimal_separator": saved.DecimalSeparator,
+			"date_formats":      saved.DateFormats,
+			"is_default":        saved.IsDefault,
+		},
+	}, userSession)
+	return models.ToImportProfilePublic(saved), nil
+}
+
+// DeleteImportProfile exclui um perfil de importação.
+func (s *importServiceImpl) DeleteImportProfile(profileID uint64, userSession *auth.SessionData) error {
+	if err := s.permManager.CheckPermission(userSession, auth.PermImportManageProfiles, nil); err != nil {
+		return err
+	}
+	profile, err := s.importProfileRepo.GetByID(profileID)
+	if err != nil {
+		return err
+	}
+	if err := s.importProfileRepo.Delete(profileID); err != nil {
+		if errors.Is(err, appErrors.ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("falha ao excluir perfil de importação '%s': %w", profile.Name, err)
+	}
+	s.auditLogService.LogAction(models.AuditLogEntry{
+		Action:      "IMPORT_PROFILE_DELETE",
+		Description: fmt.Sprintf("Perfil de importação '%s' (Tipo: %s) excluído.", profile.Name, profile.FileType),
+		Severity:    "WARNING",
+		Metadata:    map[string]interface{}{"profile_id": profile.ID, "profile_name": profile.Name, "file_type": profile.FileType},
+	}, userSession)
+	return nil
+}
@@ -1,13 +1,21 @@
 package services
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"crypto/sha256"
 	"encoding/csv"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8" // Para checagem de encoding e remoção de BOM
 
 	"golang.org/x/text/encoding/charmap" // Para Latin-1 (ISO-8859-1)
@@ -27,17 +35,145 @@ type FileType string
 const (
 	FileTypeDireitos   FileType = "DIREITOS"
 	FileTypeObrigacoes FileType = "OBRIGACOES"
+	// FileTypeRedesCNPJs é o cadastro em lote de redes e CNPJs (colunas REDE, COMPRADOR e CNPJ).
+	// Não gera títulos: cada linha cria a rede, se necessário, e cadastra ou atualiza o CNPJ.
+	FileTypeRedesCNPJs FileType = "REDES_CNPJS"
+	// FileTypeFeriados é o cadastro de feriados estaduais e municipais (DATA, NOME, ABRANGENCIA, LOCAL, ANUAL)
+	// usado no cálculo do vencimento efetivo. Também não gera títulos.
+	FileTypeFeriados FileType = "FERIADOS"
 	// Adicionar outros tipos de arquivo conforme necessário.
 )
 
+// ImportMode define como o conteúdo do arquivo é aplicado às tabelas de títulos.
+type ImportMode string
+
+const (
+	// ImportModeReplace apaga todos os títulos do tipo e insere o conteúdo do arquivo (comportamento padrão).
+	ImportModeReplace ImportMode = "REPLACE"
+	// ImportModeIncremental casa os títulos pela chave natural (CNPJ/CPF + NROEMPRESA + TÍTULO + CODESPÉCIE),
+	// inserindo novos, atualizando alterados e marcando como removidos os ausentes do arquivo.
+	// Os IDs dos títulos existentes são preservados.
+	ImportModeIncremental ImportMode = "INCREMENTAL"
+)
+
+// ImportScope define quais títulos já gravados uma importação pode substituir ou remover.
+type ImportScope string
+
+const (
+	// ImportScopeAll abrange todos os títulos do tipo, de todas as empresas (comportamento padrão).
+	ImportScopeAll ImportScope = "ALL"
+	// ImportScopeCompanies abrange apenas os títulos das empresas (NROEMPRESA) presentes no arquivo;
+	// os títulos das demais empresas permanecem intactos. Útil quando cada filial exporta o próprio arquivo.
+	ImportScopeCompanies ImportScope = "COMPANIES"
+)
+
+// ImportOptions reúne os parâmetros opcionais de uma importação ou pré-visualização.
+type ImportOptions struct {
+	// Mode define como o arquivo é aplicado (vazio equivale a `ImportModeReplace`).
+	// Não é usado na pré-visualização.
+	Mode ImportMode
+	// Scope define quais títulos existentes são substituídos (no modo substituição) ou marcados como
+	// removidos (no modo incremental). Vazio equivale a `ImportScopeAll`. Ignorado para `FileTypeRedesCNPJs`.
+	Scope ImportScope
+	// SheetName seleciona a planilha de arquivos XLSX. Se vazio, é usada a planilha ativa
+	// ou a primeira cujo cabeçalho corresponda ao esperado para o tipo.
+	SheetName string
+	// ProfileID seleciona o perfil de mapeamento de colunas (`models.DBImportProfile`) do tipo.
+	// Zero exige o layout padrão: as colunas esperadas, na ordem e com os nomes exatos.
+	ProfileID uint64
+	// Encoding força o encoding de arquivos de texto delimitado (vazio para detecção automática;
+	// ver `ImportEncodings`). Ignorado para arquivos XLSX.
+	Encoding ImportEncoding
+	// Delimiter força o separador de campos de arquivos de texto delimitado (zero para detecção
+	// automática; ver `ImportDelimiters`). Ignorado para arquivos XLSX.
+	Delimiter rune
+	// Progress, se definido, recebe o andamento da importação (etapa e linhas lidas). É chamado
+	// na goroutine da importação e não deve bloquear. Não é usado na pré-visualização.
+	Progress func(ImportProgress)
+	// SkipDuplicate desfaz a importação de títulos, retornando `*models.DuplicateImportError`, se o
+	// conteúdo lido do arquivo (SHA-256) for igual ao de uma importação anterior do tipo concluída
+	// com sucesso. Usado pela importação automática.
+	SkipDuplicate bool
+}
+
 // ImportService define a interface para o serviço de importação.
 type ImportService interface {
 	// ImportFile processa a importação de um arquivo.
 	// Retorna um mapa com resultados (ex: "records_processed") e um erro, se houver.
 	ImportFile(filePath string, fileType FileType, userSession *auth.SessionData) (map[string]interface{}, error)
 
+	// ImportFileContext processa a importação de um arquivo com as opções informadas (modo, escopo e
+	// planilha, para arquivos XLSX). Arquivos `.xlsx` são lidos como planilha; os demais, como texto
+	// delimitado. `ImportFile` equivale a `ImportFileContext` com as opções padrão (`ImportModeReplace`).
+	// No modo incremental, o resultado inclui "records_inserted", "records_updated",
+	// "records_unchanged" e "records_removed". Se `ctx` for cancelado durante a leitura das linhas,
+	// a transação é desfeita e o erro retornado envolve `appErrors.ErrCancelled`.
+	//
+	// Para `FileTypeRedesCNPJs`, o modo é ignorado e o resultado inclui "network_cnpj_report"
+	// (*models.NetworkCNPJImportReport), com a situação de cada linha. Exige também as permissões
+	// de criação de redes e de criação e edição de CNPJs. Para `FileTypeFeriados`, o modo também é
+	// ignorado, o resultado inclui "rejected_rows" ([]string) e exige `auth.PermHolidayManage`.
+	ImportFileContext(ctx context.Context, filePath string, fileType FileType, opts ImportOptions, userSession *auth.SessionData) (map[string]interface{}, error)
+
+	// StartImportJob inicia a importação em segundo plano, como um job acompanhável por
+	// `GetImportJob`, e retorna imediatamente. Apenas um job por tipo de arquivo pode estar em
+	// andamento (`appErrors.ErrConflict`). O resultado fica em `ImportJobStatus.Result`/`Err`.
+	StartImportJob(filePath string, fileType FileType, opts ImportOptions, userSession *auth.SessionData) (*ImportJobStatus, error)
+	// GetImportJob retorna o estado atual (etapa, linhas lidas, resultado) de um job de importação.
+	GetImportJob(jobID uint64, userSession *auth.SessionData) (*ImportJobStatus, error)
+	// GetImportJobs retorna os jobs em andamento e os concluídos recentemente, do mais novo ao mais antigo.
+	GetImportJobs(userSession *auth.SessionData) ([]*ImportJobStatus, error)
+	// CancelImportJob interrompe um job em andamento; a transação é desfeita. Retorna `appErrors.ErrConflict`
+	// se o job já terminou ou se já está gravando os dados.
+	CancelImportJob(jobID uint64, userSession *auth.SessionData) error
+
+	// ListImportSheets retorna os nomes das planilhas de um arquivo XLSX, para que o usuário
+	// escolha a planilha a importar. Retorna `appErrors.ErrInvalidInput` para arquivos que não são XLSX.
+	ListImportSheets(filePath string, userSession *auth.SessionData) ([]string, error)
+
 	GetAllImportStatus(userSession *auth.SessionData) ([]models.ImportMetadataPublic, error)
 	GetImportStatus(fileType FileType, userSession *auth.SessionData) (*models.ImportMetadataPublic, error)
+
+	// PreviewImport executa uma pré-visualização (dry-run) da importação: lê e mapeia o arquivo inteiro
+	// exatamente como `ImportFile`, mas sem gravar nada no banco. Retorna um relatório com a contagem
+	// de linhas, os problemas por coluna (com número de linha) e uma amostra dos registros mapeados.
+	// Erros de estrutura do arquivo (ex: cabeçalho inválido) são retornados como erro, como na importação.
+	// `opts.SheetName` seleciona a planilha de arquivos XLSX; `opts.Mode` é ignorado.
+	PreviewImport(filePath string, fileType FileType, opts ImportOptions, userSession *auth.SessionData) (*models.ImportPreviewReport, error)
+
+	// GetImportRuns busca o histórico de execuções de importação (uma entrada por execução),
+	// aplicando os filtros e a paginação de `filter`. Retorna também a contagem total.
+	GetImportRuns(filter models.ImportRunFilter, userSession *auth.SessionData) ([]*models.ImportRunPublic, int64, error)
+	// GetImportRun busca uma execução de importação do histórico pelo ID.
+	GetImportRun(runID uint64, userSession *auth.SessionData) (*models.ImportRunPublic, error)
+
+	// GetImportProfiles busca os perfis de mapeamento de colunas do tipo de arquivo.
+	GetImportProfiles(fileType FileType, userSession *auth.SessionData) ([]*models.ImportProfilePublic, error)
+	// SaveImportProfile cria (`profileID` zero) ou atualiza um perfil de mapeamento de colunas.
+	// Exige `auth.PermImportManageProfiles`.
+	SaveImportProfile(profileID uint64, data models.ImportProfileUpsert, userSession *auth.SessionData) (*models.ImportProfilePublic, error)
+	// DeleteImportProfile exclui um perfil de mapeamento de colunas. Exige `auth.PermImportManageProfiles`.
+	DeleteImportProfile(profileID uint64, userSession *auth.SessionData) error
+
+	// GetImportSnapshots busca os snapshots dos títulos de um tipo de arquivo. Com
+	// `Config.ImportSnapshotEnabled` (padrão), cada importação grava, na própria transação, uma cópia
+	// da tabela do tipo; apenas os últimos `Config.ImportSnapshotRetention` são mantidos, e cada um
+	// ocupa aproximadamente o tamanho da tabela do tipo.
+	GetImportSnapshots(fileType FileType, userSession *auth.SessionData) ([]*models.ImportSnapshotPublic, error)
+	// RestoreImportSnapshot substitui atomicamente os títulos do tipo pelos do snapshot informado.
+	// Retorna o snapshot e o número de títulos restaurados. Exige `auth.PermImportRestoreSnapshot`.
+	RestoreImportSnapshot(snapshotID uint64, userSession *auth.SessionData) (*models.ImportSnapshotPublic, int, error)
+
+	// CheckTitleCNPJs retorna, a partir dos vínculos atuais (sem alterá-los), os CNPJs dos títulos
+	// ativos do tipo não cadastrados, inativos ou inválidos. Os vínculos são refeitos após cada
+	// importação e a cada alteração do cadastro de CNPJs. Exige `auth.PermImportViewStatus`.
+	CheckTitleCNPJs(fileType FileType, userSession *auth.SessionData) (*models.TituloCNPJCheckReport, error)
+	// RelinkTitleCNPJs refaz o vínculo dos títulos do tipo com os CNPJs e redes cadastrados e retorna o
+	// mesmo relatório de `CheckTitleCNPJs`. Exige `auth.PermImportExecute`.
+	RelinkTitleCNPJs(fileType FileType, userSession *auth.SessionData) (*models.TituloCNPJCheckReport, error)
+	// ExportTitleCNPJCheck faz a mesma verificação de `CheckTitleCNPJs` e exporta os CNPJs pendentes
+	// para um CSV em `Config.ExportDir`. Retorna o relatório e o caminho do arquivo. Exige `auth.PermExportData`.
+	ExportTitleCNPJCheck(fileType FileType, userSession *auth.SessionData) (*models.TituloCNPJCheckReport, string, error)
 }
 
 // importServiceImpl é a implementação de ImportService.
@@ -46,8 +182,18 @@ type importServiceImpl struct {
 	auditLogService     AuditLogService
 	permManager         *auth.PermissionManager
 	importMetadataRepo  repositories.ImportMetadataRepository
+	importRunRepo       repositories.ImportRunRepository
+	importLockRepo      repositories.ImportLockRepository // Lock de importação por tipo, compartilhado entre processos
+	importProfileRepo   repositories.ImportProfileRepository
+	importSnapshotRepo  repositories.ImportSnapshotRepository
+	tituloCNPJLinkRepo  repositories.TituloCNPJLinkRepository
 	tituloDireitoRepo   repositories.TituloDireitoRepository
 	tituloObrigacaoRepo repositories.TituloObrigacaoRepository
+	networkRepo         repositories.NetworkRepository
+	cnpjRepo            repositories.CNPJRepository
+	holidayRepo         repositories.HolidayRepository
+
+	jobs importJobRegistry // Jobs de importação em segundo plano (`StartImportJob`).
 }
 
 // NewImportService cria uma nova instância de ImportService.
@@ -56,19 +202,35 @@ func NewImportService(
 	auditLog AuditLogService,
 	pm *auth.PermissionManager,
 	imRepo repositories.ImportMetadataRepository,
+	irRepo repositories.ImportRunRepository,
+	ilRepo repositories.ImportLockRepository,
+	ipRepo repositories.ImportProfileRepository,
+	isRepo repositories.ImportSnapshotRepository,
+	tlRepo repositories.TituloCNPJLinkRepository,
 	tdRepo repositories.TituloDireitoRepository,
 	toRepo repositories.TituloObrigacaoRepository,
+	netRepo repositories.NetworkRepository,
+	cnpjRepo repositories.CNPJRepository,
+	holidayRepo repositories.HolidayRepository,
 ) ImportService {
-	if cfg == nil || auditLog == nil || pm == nil || imRepo == nil || tdRepo == nil || toRepo == nil {
-		appLogger.Fatalf("Dependências nulas fornecidas para NewImportService (cfg, auditLog, pm, imRepo, tdRepo, toRepo)")
+	if cfg == nil || auditLog == nil || pm == nil || imRepo == nil || irRepo == nil || ilRepo == nil || ipRepo == nil || isRepo == nil || tlRepo == nil || tdRepo == nil || toRepo == nil || netRepo == nil || cnpjRepo == nil || holidayRepo == nil {
+		appLogger.Fatalf("Dependências nulas fornecidas para NewImportService (cfg, auditLog, pm, imRepo, irRepo, ilRepo, ipRepo, isRepo, tlRepo, tdRepo, toRepo, netRepo, cnpjRepo, holidayRepo)")
 	}
 	return &importServiceImpl{
 		cfg:                 cfg,
 		auditLogService:     auditLog,
 		permManager:         pm,
 		importMetadataRepo:  imRepo,
+		importRunRepo:       irRepo,
+		importLockRepo:      ilRepo,
+		importProfileRepo:   ipRepo,
+		importSnapshotRepo:  isRepo,
+		tituloCNPJLinkRepo:  tlRepo,
 		tituloDireitoRepo:   tdRepo,
 		tituloObrigacaoRepo: toRepo,
+		networkRepo:         netRepo,
+		cnpjRepo:            cnpjRepo,
+		holidayRepo:         holidayRepo,
 	}
 }
 
@@ -79,122 +241,673 @@ func getExpectedHeaders(fileType FileType) ([]string, error) {
 		return models.ExpectedHeadersTituloDireito, nil
 	case FileTypeObrigacoes:
 		return models.ExpectedHeadersTituloObrigacao, nil
+	case FileTypeRedesCNPJs:
+		return models.ExpectedHeadersNetworkCNPJ, nil
+	case FileTypeFeriados:
+		return models.ExpectedHeadersHoliday, nil
 	default:
 		return nil, fmt.Errorf("tipo de arquivo '%s' não tem cabeçalhos esperados definidos", fileType)
 	}
 }
 
-// detectAndDecode tenta detectar o encoding (UTF-8 com/sem BOM, Latin-1) e decodifica para UTF-8.
-func (s *importServiceImpl) detectAndDecode(rawBytes []byte) ([]byte, string, error) {
+// ExpectedImportHeaders retorna uma cópia dos cabeçalhos esperados para um tipo de arquivo
+// (vazio para tipos desconhecidos). Usado pela UI para montar o editor de perfis de importação.
+func ExpectedImportHeaders(fileType FileType) []string {
+	headers, err := getExpectedHeaders(fileType)
+	if err != nil {
+		return nil
+	}
+	return append([]string(nil), headers...)
+}
+
+// encodingSniffSize é a quantidade de bytes do início do arquivo inspecionada para detectar o encoding.
+// Apenas esta janela é mantida em memória; o restante do arquivo é decodificado em streaming.
+const encodingSniffSize = 64 * 1024
+
+// trimIncompleteRune remove uma possível runa UTF-8 cortada no final da amostra,
+// evitando que a janela de detecção classifique erroneamente um arquivo UTF-8 válido.
+func trimIncompleteRune(sample []byte) []byte {
+	for i := len(sample) - 1; i >= 0 && i >= len(sample)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(sample[i]) {
+			if !utf8.FullRune(sample[i:]) {
+				return sample[:i]
+			}
+			break
+		}
+	}
+	return sample
+}
+
+// strictUTF8Decoder repassa o conteúdo UTF-8 sem alterá-lo e interrompe a leitura na primeira
+// sequência inválida, em qualquer ponto do arquivo (a detecção só inspeciona o início). Assim um
+// arquivo com trechos em outro encoding falha em vez de ser gravado com caracteres trocados.
+type strictUTF8Decoder struct {
+	offset int64 // Bytes já validados, para indicar a posição do erro.
+}
+
+// Transform implementa `transform.Transformer`.
+func (d *strictUTF8Decoder) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	defer func() { d.offset += int64(nSrc) }()
+	for nSrc < len(src) {
+		if nDst >= len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		if c := src[nSrc]; c < utf8.RuneSelf {
+			dst[nDst] = c
+			nDst++
+			nSrc++
+			continue
+		}
+		if !atEOF && !utf8.FullRune(src[nSrc:]) {
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+		r, size := utf8.DecodeRune(src[nSrc:])
+		if r == utf8.RuneError && size <= 1 {
+			return nDst, nSrc, fmt.Errorf("%w: sequência UTF-8 inválida no byte %d do conteúdo; o arquivo mistura encodings ou não é UTF-8 (informe o encoding na importação)",
+				appErrors.ErrInvalidInput, d.offset+int64(nSrc)+1)
+		}
+		if nDst+size > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += copy(dst[nDst:], src[nSrc:nSrc+size])
+		nSrc += size
+	}
+	return nDst, nSrc, nil
+}
+
+// Reset implementa `transform.Transformer`.
+func (d *strictUTF8Decoder) Reset() {
+	d.offset = 0
+}
+
+// newStrictUTF8Reader remove o BOM UTF-8, se presente, e retorna um leitor que valida o restante
+// do conteúdo com `strictUTF8Decoder`.
+func newStrictUTF8Reader(bufReader *bufio.Reader) (io.Reader, error) {
+	if prefix, _ := bufReader.Peek(3); bytes.Equal(prefix, []byte{0xEF, 0xBB, 0xBF}) {
+		if _, err := bufReader.Discard(3); err != nil {
+			return nil, err
+		}
+	}
+	return transform.NewReader(bufReader, &strictUTF8Decoder{}), nil
+}
+
+// detectAndDecode detecta o encoding (UTF-8 com/sem BOM, UTF-16 com/sem BOM, Windows-1252 ou Latin-1)
+// a partir do início do conteúdo e retorna um leitor que entrega o conteúdo decodificado para UTF-8
+// em streaming. Se `override` não for vazio, a detecção é ignorada e o encoding informado é usado.
+func (s *importServiceImpl) detectAndDecode(r io.Reader, override ImportEncoding) (io.Reader, string, error) {
+	bufReader := bufio.NewReaderSize(r, encodingSniffSize)
+	if override != "" {
+		decoder, ok := importEncodingDecoders[override]
+		if !ok {
+			return nil, string(override), fmt.Errorf("%w: encoding '%s' não suportado", appErrors.ErrInvalidInput, override)
+		}
+		appLogger.Infof("Encoding '%s' informado pelo usuário; detecção automática ignorada.", override)
+		if override == ImportEncodingUTF8 {
+			strictReader, errBOM := newStrictUTF8Reader(bufReader)
+			return strictReader, fmt.Sprintf("%s (informado)", override), errBOM
+		}
+		return transform.NewReader(bufReader, decoder.NewDecoder()), fmt.Sprintf("%s (informado)", override), nil
+	}
+
+	sample, err := bufReader.Peek(encodingSniffSize)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, "Desconhecido", err
+	}
+	if len(sample) == 0 {
+		return bufReader, "Vazio", nil
+	}
+
 	// Remover BOM UTF-8 se presente.
 	bomUtf8 := []byte{0xEF, 0xBB, 0xBF}
-	if bytes.HasPrefix(rawBytes, bomUtf8) {
+	if bytes.HasPrefix(sample, bomUtf8) {
 		appLogger.Debug("BOM UTF-8 detectado e removido.")
-		return bytes.TrimPrefix(rawBytes, bomUtf8), "UTF-8 (com BOM)", nil
+		strictReader, errDiscard := newStrictUTF8Reader(bufReader)
+		return strictReader, "UTF-8 (com BOM)", errDiscard
+	}
+
+	// UTF-16: pelo BOM (removido pelo decoder) ou, sem BOM, pela posição dos bytes nulos.
+	// Precisa vir antes da checagem de UTF-8, pois bytes nulos são UTF-8 válido.
+	utf16Encoding, utf16Desc := ImportEncoding(""), ""
+	switch {
+	case bytes.HasPrefix(sample, []byte{0xFF, 0xFE}):
+		utf16Encoding, utf16Desc = ImportEncodingUTF16LE, "UTF-16LE (com BOM)"
+	case bytes.HasPrefix(sample, []byte{0xFE, 0xFF}):
+		utf16Encoding, utf16Desc = ImportEncodingUTF16BE, "UTF-16BE (com BOM)"
+	default:
+		if utf16Encoding = sniffUTF16(sample); utf16Encoding != "" {
+			utf16Desc = fmt.Sprintf("%s (sem BOM)", utf16Encoding)
+		}
+	}
+	if utf16Encoding != "" {
+		appLogger.Infof("Arquivo detectado como %s. Decodificando para UTF-8.", utf16Desc)
+		return transform.NewReader(bufReader, importEncodingDecoders[utf16Encoding].NewDecoder()), utf16Desc, nil
 	}
 
-	// Verificar se é UTF-8 válido.
-	if utf8.Valid(rawBytes) {
+	// Verificar se o início do arquivo é UTF-8 válido. O restante continua sendo validado durante a
+	// leitura: uma sequência inválida depois da janela de detecção faz a importação falhar.
+	if utf8.Valid(trimIncompleteRune(sample)) {
 		appLogger.Debug("Arquivo detectado como UTF-8 válido (sem BOM).")
-		return rawBytes, "UTF-8", nil
+		strictReader, errReader := newStrictUTF8Reader(bufReader)
+		return strictReader, "UTF-8", errReader
 	}
 
-	// Tentar decodificar como Latin-1 (ISO-8859-1).
-	appLogger.Debug("Arquivo não é UTF-8 válido. Tentando decodificar como Latin-1.")
-	decoder := charmap.ISO8859_1.NewDecoder()
-	utf8Bytes, _, errTransform := transform.Bytes(decoder, rawBytes)
-	if errTransform != nil {
-		appLogger.Errorf("Erro ao decodificar arquivo como Latin-1 após falha UTF-8: %v", errTransform)
-		return nil, "Desconhecido", fmt.Errorf("arquivo não pôde ser decodificado como UTF-8 ou Latin-1: %w", errTransform)
+	// Não é UTF-8: arquivos de ERPs Windows são CP1252. Se houver bytes inexistentes no CP1252,
+	// decodificar como Latin-1 (ISO-8859-1), em que todo byte é válido.
+	if hasUndefinedWindows1252Bytes(sample) {
+		appLogger.Info("Arquivo não é UTF-8 válido nem Windows-1252. Decodificando como Latin-1 para UTF-8.")
+		return transform.NewReader(bufReader, charmap.ISO8859_1.NewDecoder()), "Latin-1 (convertido para UTF-8)", nil
 	}
-	appLogger.Info("Arquivo decodificado com sucesso de Latin-1 para UTF-8.")
-	return utf8Bytes, "Latin-1 (convertido para UTF-8)", nil
+	appLogger.Info("Arquivo não é UTF-8 válido. Decodificando como Windows-1252 para UTF-8.")
+	return transform.NewReader(bufReader, charmap.Windows1252.NewDecoder()), "Windows-1252 (convertido para UTF-8)", nil
 }
 
-// readAndValidateCSV lê o conteúdo do arquivo, valida o cabeçalho e retorna as linhas de dados.
-// Retorna as linhas de dados (sem o cabeçalho), o encoding detectado, e um erro.
-func (s *importServiceImpl) readAndValidateCSV(filePath string, fileType FileType) ([][]string, string, error) {
-	rawBytes, err := os.ReadFile(filePath)
+// importRowSource fornece as linhas brutas de um arquivo de importação, seja texto delimitado
+// (CSV/TXT) ou planilha XLSX. A primeira linha retornada é o cabeçalho.
+type importRowSource interface {
+	// Read retorna a próxima linha, o número dela no arquivo (ou na planilha) e `io.EOF` ao final.
+	// Erros de formato já vêm formatados como `appErrors.ErrValidation`.
+	// O slice retornado pode ser reutilizado pela próxima chamada.
+	Read() (record []string, lineNum int, err error)
+	// ContentHash retorna o SHA-256 (hex) e o tamanho dos bytes lidos do arquivo; `ok` é falso
+	// enquanto o arquivo não tiver sido lido até o fim.
+	ContentHash() (sha256Hex string, size int64, ok bool)
+	// Close libera o arquivo subjacente.
+	Close() error
+}
+
+// csvRowSource lê linhas de um arquivo de texto delimitado.
+type csvRowSource struct {
+	file      *os.File
+	csvReader *csv.Reader
+	fileName  string
+	// delimiterDesc descreve o separador de campos usado (ex: "ponto e vírgula (;)").
+	delimiterDesc string
+	// counter conta os bytes lidos do arquivo, de tamanho fileSize, para estimar o andamento.
+	counter  *countingReader
+	fileSize int64
+	// hasher calcula o SHA-256 dos bytes lidos; eof indica que o arquivo foi lido até o fim.
+	hasher hash.Hash
+	eof    bool
+}
+
+// Read lê a próxima linha do arquivo delimitado.
+func (src *csvRowSource) Read() ([]string, int, error) {
+	record, err := src.csvReader.Read()
+	if errors.Is(err, io.EOF) {
+		src.eof = true
+		return nil, 0, io.EOF
+	}
 	if err != nil {
-		appLogger.Errorf("Erro ao ler arquivo de importação '%s': %v", filePath, err)
-		return nil, "", fmt.Errorf("%w: falha ao ler arquivo '%s'", appErrors.ErrResourceLoading, filepath.Base(filePath))
+		return nil, 0, src.wrapParseError(err)
 	}
-	if len(rawBytes) == 0 {
-		appLogger.Warnf("Arquivo de importação '%s' está vazio.", filePath)
-		return [][]string{}, "Vazio", nil // Arquivo vazio não é um erro de formato, mas não tem dados.
+	lineNum, _ := src.csvReader.FieldPos(0)
+	return record, lineNum, nil
+}
+
+// ContentHash retorna o SHA-256 dos bytes lidos, após o fim do arquivo.
+func (src *csvRowSource) ContentHash() (string, int64, bool) {
+	if !src.eof {
+		return "", 0, false
 	}
+	return hex.EncodeToString(src.hasher.Sum(nil)), src.counter.n, true
+}
+
+// Close fecha o arquivo subjacente.
+func (src *csvRowSource) Close() error {
+	return src.file.Close()
+}
 
-	utf8Bytes, detectedEncoding, err := s.detectAndDecode(rawBytes)
+// wrapParseError converte erros do leitor CSV em erros de validação com contexto de linha/coluna.
+func (src *csvRowSource) wrapParseError(err error) error {
+	if e, ok := err.(*csv.ParseError); ok {
+		appLogger.Errorf("Erro de parse CSV no arquivo '%s' na linha %d, coluna %d: %v",
+			src.fileName, e.Line, e.Column, e.Err)
+		return fmt.Errorf("%w: arquivo '%s' mal formatado (erro na linha %d, coluna %d): %v",
+			appErrors.ErrValidation, src.fileName, e.Line, e.Column, e.Err)
+	}
+	appLogger.Errorf("Erro desconhecido ao ler CSV do arquivo '%s': %v", src.fileName, err)
+	return fmt.Errorf("%w: falha ao ler conteúdo CSV do arquivo '%s'", appErrors.ErrValidation, src.fileName)
+}
+
+// openCSVRowSource abre um arquivo delimitado, detectando o encoding e o separador de campos (ou usando
+// os informados em `opts`) e decodificando para UTF-8 em streaming.
+// Retorna a fonte, a descrição do encoding detectado e um erro.
+func (s *importServiceImpl) openCSVRowSource(filePath string, opts ImportOptions) (*csvRowSource, string, error) {
+	fileName := filepath.Base(filePath)
+	if err := validateTextFormatOptions(opts); err != nil {
+		return nil, "", err
+	}
+	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, detectedEncoding, err // Propaga erro de decodificação.
+		appLogger.Errorf("Erro ao abrir arquivo de importação '%s': %v", filePath, err)
+		return nil, "", fmt.Errorf("%w: falha ao ler arquivo '%s'", appErrors.ErrResourceLoading, fileName)
+	}
+
+	var fileSize int64
+	if info, errStat := file.Stat(); errStat == nil {
+		fileSize = info.Size()
+	}
+	// O hash é calculado sobre os mesmos bytes entregues ao decoder, isto é, os bytes importados.
+	hasher := sha256.New()
+	counter := &countingReader{r: io.TeeReader(file, hasher)}
+	decodedReader, detectedEncoding, err := s.detectAndDecode(counter, opts.Encoding)
+	if err != nil {
+		file.Close()
+		appLogger.Errorf("Erro ao detectar encoding do arquivo '%s': %v", filePath, err)
+		return nil, detectedEncoding, fmt.Errorf("%w: falha ao ler arquivo '%s'", appErrors.ErrResourceLoading, fileName)
+	}
+
+	// O separador é detectado no conteúdo já decodificado (ponto e vírgula, tabulação, barra
+	// vertical ou vírgula), a menos que tenha sido informado pelo usuário.
+	contentReader := bufio.NewReaderSize(decodedReader, encodingSniffSize)
+	delimiter := opts.Delimiter
+	delimiterDesc := ImportDelimiterLabel(delimiter) + " (informado)"
+	if delimiter == 0 {
+		var detected bool
+		delimiter, detected, err = sniffDelimiter(contentReader)
+		if err != nil {
+			file.Close()
+			appLogger.Errorf("Erro ao detectar o delimitador do arquivo '%s': %v", filePath, err)
+			return nil, detectedEncoding, fmt.Errorf("%w: falha ao ler arquivo '%s'", appErrors.ErrResourceLoading, fileName)
+		}
+		delimiterDesc = ImportDelimiterLabel(delimiter)
+		if !detected {
+			delimiterDesc += " (padrão)"
+		}
 	}
+	appLogger.Debugf("Arquivo '%s': delimitador %s.", fileName, delimiterDesc)
 
-	// Usar encoding/csv para parsear.
-	// O delimitador é ponto e vírgula. LazyQuotes lida com algumas aspas malformadas.
-	// TrimLeadingSpace remove espaços antes dos campos.
-	csvReader := csv.NewReader(bytes.NewReader(utf8Bytes))
-	csvReader.Comma = ';'
+	// LazyQuotes lida com algumas aspas malformadas; campos entre aspas podem conter
+	// quebras de linha (ex: OBSERVAÇÃO) e o leitor aceita tanto LF quanto CRLF.
+	// TrimLeadingSpace remove espaços antes dos campos. FieldsPerRecord = -1 permite
+	// que linhas com número incorreto de campos sejam puladas individualmente.
+	// ReuseRecord evita uma alocação de slice por linha.
+	csvReader := csv.NewReader(contentReader)
+	csvReader.Comma = delimiter
 	csvReader.LazyQuotes = true
-	csvReader.TrimLeadingSpace = true
-	// csvReader.FieldsPerRecord = -1 // Permite número variável de campos por linha para pegar erros.
+	csvReader.TrimLeadingSpace = delimiter != '\t' // Com tabulação, o espaço inicial seria removido junto.
+	csvReader.FieldsPerRecord = -1
+	csvReader.ReuseRecord = true
 
-	allRecords, err := csvReader.ReadAll()
-	if err != nil {
-		// Tenta fornecer mais contexto sobre o erro do CSV.
-		if e, ok := err.(*csv.ParseError); ok {
-			// Log detalhado do erro de parsing, incluindo a linha (se disponível).
-			// A linha original antes do join pode ser útil para depuração, mas não é trivial obter aqui.
-			appLogger.Errorf("Erro de parse CSV no arquivo '%s' na linha %d, coluna %d (relativo aos dados processados): %v",
-				filepath.Base(filePath), e.Line, e.Column, e.Err)
-			return nil, detectedEncoding, fmt.Errorf("%w: arquivo '%s' mal formatado (erro na linha %d, coluna %d): %v",
-				appErrors.ErrValidation, filepath.Base(filePath), e.Line, e.Column, e.Err)
+	return &csvRowSource{
+		file: file, csvReader: csvReader, fileName: fileName, delimiterDesc: delimiterDesc, counter: counter, fileSize: fileSize,
+		hasher: hasher,
+	}, detectedEncoding, nil
+}
+
+// importRowStream lê as linhas de dados de um arquivo de importação uma a uma,
+// após a validação do cabeçalho. Linhas com número incorreto de campos são puladas e contadas.
+type importRowStream struct {
+	source        importRowSource
+	fileName      string
+	fileType      FileType
+	expectedCols  int
+	totalDataRows int    // Linhas de dados lidas do arquivo (inclui as puladas).
+	skippedRows   int    // Linhas puladas por número incorreto de campos.
+	readErr       error  // Erro de leitura/parse encontrado durante o streaming, se houver.
+	delimiterDesc string // Separador de campos de arquivos de texto ("" para XLSX).
+
+	// ctx interrompe a leitura quando cancelado; progress relata o andamento (nil se ninguém acompanha).
+	ctx      context.Context
+	progress *importProgressTracker
+
+	// onSkip, se definido, é chamado para cada linha pulada por número incorreto de campos.
+	onSkip func(lineNum int, record []string)
+	// atEOF, se definido, é chamado uma vez ao chegar ao fim do arquivo, antes de `io.EOF` ser
+	// entregue; um erro retornado interrompe a importação (ex: `ImportOptions.SkipDuplicate`).
+	atEOF func() error
+
+	// layout, se definido (importação com perfil), converte as linhas do arquivo para a ordem e os
+	// formatos das colunas esperadas. Sem perfil, as linhas já estão no layout padrão.
+	layout *importColumnLayout
+
+	// Primeira linha válida, lida antecipadamente em `openImportStream` para detectar
+	// arquivos sem dados antes de qualquer alteração no banco.
+	pending     []string
+	pendingLine int
+}
+
+// Close fecha o arquivo subjacente.
+func (st *importRowStream) Close() error {
+	return st.source.Close()
+}
+
+// hasData indica se o arquivo possui ao menos uma linha de dados válida.
+func (st *importRowStream) hasData() bool {
+	return st.pending != nil
+}
+
+// readValid lê a próxima linha com o número esperado de campos, pulando as demais.
+// Retorna a linha, o número da linha no arquivo e `io.EOF` ao final.
+func (st *importRowStream) readValid() ([]string, int, error) {
+	for {
+		if ctxErr := st.ctx.Err(); ctxErr != nil {
+			appLogger.Warnf("Importação do arquivo '%s' (Tipo: %s) cancelada após %d linhas de dados.", st.fileName, st.fileType, st.totalDataRows)
+			return nil, 0, fmt.Errorf("%w: importação do arquivo '%s' interrompida após %d linhas de dados (%v)",
+				appErrors.ErrCancelled, st.fileName, st.totalDataRows, ctxErr)
+		}
+		record, lineNum, err := st.source.Read()
+		if errors.Is(err, io.EOF) {
+			if st.atEOF != nil {
+				atEOF := st.atEOF
+				st.atEOF = nil
+				if errEOF := atEOF(); errEOF != nil {
+					return nil, 0, errEOF
+				}
+			}
+			return nil, 0, io.EOF
 		}
-		appLogger.Errorf("Erro desconhecido ao parsear CSV do arquivo '%s' (encoding: %s): %v", filepath.Base(filePath), detectedEncoding, err)
-		return nil, detectedEncoding, fmt.Errorf("%w: falha ao parsear conteúdo CSV do arquivo '%s'", appErrors.ErrValidation, filepath.Base(filePath))
+		if err != nil {
+			st.readErr = err
+			return nil, 0, st.readErr
+		}
+		st.totalDataRows++
+		st.progress.rowsRead(st.totalDataRows)
+		if len(record) != st.expectedCols {
+			appLogger.Warnf("[Linha %d, Tipo: %s] Número incorreto de campos: %d, esperado %d. Linha ignorada: %v",
+				lineNum, st.fileType, len(record), st.expectedCols, record)
+			st.skippedRows++
+			if st.onSkip != nil {
+				st.onSkip(lineNum, record)
+			}
+			continue
+		}
+		return record, lineNum, nil
 	}
+}
 
-	if len(allRecords) == 0 {
-		appLogger.Warnf("Nenhum registro encontrado no CSV de '%s' após parse (pode ser apenas uma linha de cabeçalho vazia ou comentários).", filepath.Base(filePath))
-		return [][]string{}, detectedEncoding, nil
+// Next retorna a próxima linha de dados válida, começando pela linha lida antecipadamente.
+// Com perfil, a linha é entregue já na ordem das colunas esperadas.
+func (st *importRowStream) Next() ([]string, int, error) {
+	var record []string
+	var lineNum int
+	if st.pending != nil {
+		record, lineNum = st.pending, st.pendingLine
+		st.pending = nil
+	} else {
+		var err error
+		record, lineNum, err = st.readValid()
+		if errors.Is(err, io.EOF) { // Fim do arquivo: resta a gravação final.
+			st.progress.rowsRead(st.totalDataRows)
+			st.progress.setPhase(ImportPhasePersisting)
+		}
+		if err != nil {
+			return nil, lineNum, err
+		}
 	}
+	if st.layout != nil {
+		record = st.layout.apply(record)
+	}
+	return record, lineNum, nil
+}
 
-	// Validar cabeçalho.
-	headerRow := allRecords[0]
-	expectedHeaders, err := getExpectedHeaders(fileType)
-	if err != nil { // Deveria ser pego antes, mas checagem de segurança.
-		return nil, detectedEncoding, fmt.Errorf("%w: %v", appErrors.ErrConfiguration, err)
+// recordContentHash grava em `run` o SHA-256 e o tamanho dos bytes lidos, se o arquivo foi lido até o fim.
+func (st *importRowStream) recordContentHash(run *models.DBImportRun) {
+	if hash, size, ok := st.source.ContentHash(); ok {
+		run.FileSHA256 = &hash
+		run.FileSizeBytes = &size
 	}
+}
 
+// profileName retorna o nome do perfil de importação usado, ou "" para o layout padrão.
+func (st *importRowStream) profileName() string {
+	if st.layout == nil {
+		return ""
+	}
+	return st.layout.profileName
+}
+
+// validateImportHeader compara o cabeçalho lido do arquivo com o esperado para o tipo
+// (case-insensitive, ignorando espaços nas bordas).
+func validateImportHeader(fileName string, headerRow, expectedHeaders []string) error {
 	if len(headerRow) != len(expectedHeaders) {
 		appLogger.Errorf("Arquivo '%s': número de colunas no cabeçalho (%d) diferente do esperado (%d). Cabeçalho recebido: %v. Esperado: %v",
-			filepath.Base(filePath), len(headerRow), len(expectedHeaders), headerRow, expectedHeaders)
-		return nil, detectedEncoding, fmt.Errorf("%w: arquivo '%s' tem %d colunas no cabeçalho, esperado %d",
-			appErrors.ErrValidation, filepath.Base(filePath), len(headerRow), len(expectedHeaders))
+			fileName, len(headerRow), len(expectedHeaders), headerRow, expectedHeaders)
+		return fmt.Errorf("%w: arquivo '%s' tem %d colunas no cabeçalho, esperado %d",
+			appErrors.ErrValidation, fileName, len(headerRow), len(expectedHeaders))
 	}
-
 	for i, expected := range expectedHeaders {
-		// Comparação case-insensitive e trim de espaços para cabeçalhos.
 		if !strings.EqualFold(strings.TrimSpace(headerRow[i]), strings.TrimSpace(expected)) {
 			appLogger.Errorf("Arquivo '%s': cabeçalho da coluna %d é '%s', esperado '%s'.",
-				filepath.Base(filePath), i+1, headerRow[i], expected)
-			return nil, detectedEncoding, fmt.Errorf("%w: arquivo '%s' tem cabeçalho inválido (coluna %d: '%s' != '%s')",
-				appErrors.ErrValidation, filepath.Base(filePath), i+1, headerRow[i], expected)
+				fileName, i+1, headerRow[i], expected)
+			return fmt.Errorf("%w: arquivo '%s' tem cabeçalho inválido (coluna %d: '%s' != '%s')",
+				appErrors.ErrValidation, fileName, i+1, headerRow[i], expected)
 		}
 	}
+	return nil
+}
+
+// openImportStream abre o arquivo (texto delimitado ou XLSX, conforme a extensão), valida o
+// cabeçalho e lê antecipadamente a primeira linha de dados válida. O arquivo não é carregado
+// inteiro em memória. `opts.SheetName` seleciona a planilha de arquivos XLSX (vazio para seleção
+// automática) e `opts.ProfileID` o perfil de mapeamento de colunas (zero para o layout padrão).
+// `onSkip` (opcional) é chamado para cada linha pulada por número incorreto de campos.
+// `ctx` interrompe a leitura das linhas quando cancelado e `opts.Progress` recebe o andamento.
+// Retorna o stream (que deve ser fechado pelo chamador), a descrição do formato/encoding lido e um erro.
+func (s *importServiceImpl) openImportStream(ctx context.Context, filePath string, fileType FileType, opts ImportOptions, onSkip func(lineNum int, record []string)) (*importRowStream, string, error) {
+	fileName := filepath.Base(filePath)
+	expectedHeaders, err := getExpectedHeaders(fileType)
+	if err != nil { // Deveria ser pego antes, mas checagem de segurança.
+		return nil, "", fmt.Errorf("%w: %v", appErrors.ErrConfiguration, err)
+	}
+	profile, err := s.loadImportProfile(opts.ProfileID, fileType)
+	if err != nil {
+		return nil, "", err
+	}
+	progress := newImportProgressTracker(opts.Progress)
+	progress.setPhase(ImportPhaseDecoding)
+
+	// resolveHeader valida o cabeçalho e, com perfil, monta o mapeamento das colunas.
+	resolveHeader := func(headerRow []string) (*importColumnLayout, error) {
+		if profile == nil {
+			return nil, validateImportHeader(fileName, headerRow, expectedHeaders)
+		}
+		return newImportColumnLayout(fileName, headerRow, expectedHeaders, profile)
+	}
+
+	var source importRowSource
+	var detectedEncoding string
+	if isXLSXFile(filePath) {
+		var xlsxSource *xlsxRowSource
+		headerMatches := func(headerRow []string) bool {
+			_, errHeader := resolveHeader(headerRow)
+			return errHeader == nil
+		}
+		xlsxSource, err = openXLSXRowSource(filePath, opts.SheetName, headerMatches)
+		if err == nil {
+			source = xlsxSource
+			detectedEncoding = fmt.Sprintf("XLSX (planilha '%s')", xlsxSource.sheetName)
+		}
+	} else {
+		var csvSource *csvRowSource
+		csvSource, detectedEncoding, err = s.openCSVRowSource(filePath, opts)
+		if err == nil {
+			source = csvSource
+		}
+	}
+	if err != nil {
+		return nil, detectedEncoding, err
+	}
+
+	stream := &importRowStream{
+		source:       source,
+		fileName:     fileName,
+		fileType:     fileType,
+		expectedCols: len(expectedHeaders),
+		onSkip:       onSkip,
+		ctx:          ctx,
+		progress:     progress,
+	}
+	if csvSource, ok := source.(*csvRowSource); ok {
+		stream.delimiterDesc = csvSource.delimiterDesc
+		if progress != nil {
+			progress.counter = csvSource.counter
+			progress.progress.TotalBytes = csvSource.fileSize
+		}
+	}
+
+	headerRow, _, err := source.Read()
+	if errors.Is(err, io.EOF) {
+		appLogger.Warnf("Arquivo de importação '%s' está vazio.", filePath)
+		return stream, detectedEncoding, nil // Arquivo vazio não é um erro de formato, mas não tem dados.
+	}
+	if err != nil {
+		source.Close()
+		return nil, detectedEncoding, err
+	}
+	layout, err := resolveHeader(headerRow)
+	if err != nil {
+		source.Close()
+		return nil, detectedEncoding, err
+	}
+	fileFields := expectedHeaders
+	if layout != nil {
+		stream.layout = layout
+		stream.expectedCols = layout.fileCols
+		fileFields = layout.fileFields
+	}
+	if xlsxSource, ok := source.(*xlsxRowSource); ok {
+		xlsxSource.classifyColumns(fileFields)
+	}
+
+	// Lê antecipadamente a primeira linha válida. Assim, um arquivo sem dados (ou com todas as
+	// linhas malformadas) é identificado antes de a tabela ser substituída.
+	first, firstLine, err := stream.readValid()
+	if err != nil && !errors.Is(err, io.EOF) {
+		source.Close()
+		return nil, detectedEncoding, err
+	}
+	if errors.Is(err, io.EOF) && stream.skippedRows > 0 {
+		source.Close()
+		return nil, detectedEncoding, fmt.Errorf("%w: todas as %d linhas de dados no arquivo '%s' tinham um número incorreto de campos e foram ignoradas",
+			appErrors.ErrValidation, stream.totalDataRows, fileName)
+	}
+	if first != nil {
+		// Copia a linha, pois o leitor pode reutilizar o slice na próxima leitura.
+		stream.pending = append([]string(nil), first...)
+		stream.pendingLine = firstLine
+	}
 
-	appLogger.Infof("Arquivo '%s' (encoding: %s) lido. Cabeçalho validado. %d linhas de dados encontradas.",
-		filepath.Base(filePath), detectedEncoding, len(allRecords)-1)
-	return allRecords[1:], detectedEncoding, nil // Retorna apenas as linhas de dados.
+	progress.setPhase(ImportPhaseMapping)
+	if profile != nil {
+		appLogger.Infof("Arquivo '%s' (%s) aberto para importação em streaming com o perfil '%s'. Cabeçalho validado.", fileName, detectedEncoding, profile.Name)
+	} else {
+		appLogger.Infof("Arquivo '%s' (%s) aberto para importação em streaming. Cabeçalho validado.", fileName, detectedEncoding)
+	}
+	return stream, detectedEncoding, nil
 }
 
-// ImportFile processa a importação de um arquivo.
+// recordToTituloDireitoRow mapeia as colunas de uma linha do arquivo para `models.TituloDireitoFromRow`.
+func recordToTituloDireitoRow(record []string) models.TituloDireitoFromRow {
+	return models.TituloDireitoFromRow{
+		Pessoa: record[0], CNPJCPF: record[1], NumeroEmpresa: record[2], Titulo: record[3],
+		CodigoEspecie: record[4], DataVencimento: record[5], DataQuitacao: record[6], ValorNominal: record[7],
+		ValorPago: record[8], Operacao: record[9], DataOperacao: record[10], DataContabiliza: record[11],
+		DataAlteracaoCSV: record[12], Observacao: record[13], ValorOperacao: record[14], UsuarioAlteracao: record[15],
+		EspecieAbatcomp: record[16], ObsTitulo: record[17], ContasQuitacao: record[18], DataProgramada: record[19],
+	}
+}
+
+// recordToTituloObrigacaoRow mapeia as colunas de uma linha do arquivo para `models.TituloObrigacaoFromRow`.
+func recordToTituloObrigacaoRow(record []string) models.TituloObrigacaoFromRow {
+	return models.TituloObrigacaoFromRow{
+		Pessoa: record[0], CNPJCPF: record[1], NumeroEmpresa: record[2], Titulo: record[3],
+		CodigoEspecie: record[4], DataVencimento: record[5], DataQuitacao: record[6], ValorNominal: record[7],
+		ValorPago: record[8], Operacao: record[9], DataOperacao: record[10], DataContabiliza: record[11],
+		DataAlteracaoCSV: record[12], Observacao: record[13], ValorOperacao: record[14], UsuarioAlteracao: record[15],
+		EspecieAbatcomp: record[16], ObsTitulo: record[17], ContasQuitacao: record[18], DataProgramada: record[19],
+	}
+}
+
+// ImportFile processa a importação de um arquivo, substituindo todos os títulos do tipo.
 func (s *importServiceImpl) ImportFile(filePath string, fileType FileType, userSession *auth.SessionData) (map[string]interface{}, error) {
+	return s.ImportFileContext(context.Background(), filePath, fileType, ImportOptions{Mode: ImportModeReplace}, userSession)
+}
+
+// ImportFileContext processa a importação de um arquivo com as opções informadas, interrompendo-a
+// (com a transação desfeita) se `ctx` for cancelado durante a leitura das linhas.
+// O arquivo é lido, decodificado, mapeado e persistido em streaming: apenas um lote
+// de registros é mantido em memória por vez, independentemente do tamanho do arquivo.
+func (s *importServiceImpl) ImportFileContext(ctx context.Context, filePath string, fileType FileType, opts ImportOptions, userSession *auth.SessionData) (map[string]interface{}, error) {
 	// 1. Verificar Permissão
 	if err := s.permManager.CheckPermission(userSession, auth.PermImportExecute, nil); err != nil {
 		return nil, err
 	}
+	if opts.Mode == "" {
+		opts.Mode = ImportModeReplace
+	}
+	if opts.Mode != ImportModeReplace && opts.Mode != ImportModeIncremental {
+		return nil, fmt.Errorf("%w: modo de importação '%s' inválido", appErrors.ErrInvalidInput, opts.Mode)
+	}
+	if opts.Scope == "" {
+		opts.Scope = ImportScopeAll
+	}
+	if opts.Scope != ImportScopeAll && opts.Scope != ImportScopeCompanies {
+		return nil, fmt.Errorf("%w: escopo de importação '%s' inválido", appErrors.ErrInvalidInput, opts.Scope)
+	}
+	// Outra importação do mesmo tipo (neste ou em outro processo) não pode ser aplicada ao mesmo tempo.
+	lock, err := s.acquireImportLock(fileType, filePath, userSession)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
+	switch fileType {
+	case FileTypeRedesCNPJs:
+		return s.importNetworkCNPJs(ctx, filePath, opts, userSession)
+	case FileTypeFeriados:
+		return s.importHolidays(ctx, filePath, opts, userSession)
+	}
+
+	// 2. Registrar a execução no histórico, executar a importação e finalizar o registro.
+	if opts.Progress != nil {
+		opts.Progress(ImportProgress{Phase: ImportPhaseReading})
+	}
+	run := s.startImportRun(filePath, fileType, opts.Mode, userSession)
+	// A validade do lock é renovada também dentro da transação de gravação, que no SQLite bloqueia a
+	// renovação em segundo plano.
+	hooks := repositories.ImportTxHooks{AfterBatch: lock.RenewTx}
+	result, err := s.executeImport(ctx, filePath, fileType, opts, run, hooks, userSession)
+	s.finishImportRun(run, result, err)
+	if result != nil && run.ID != 0 {
+		result["import_run_id"] = run.ID
+	}
+	return result, err
+}
 
-	// 2. Validações Iniciais do Arquivo
+// acquireImportLock obtém o lock de importação do tipo de arquivo, compartilhado por todos os processos
+// que usam o mesmo banco. Se outra importação do tipo estiver em andamento, retorna o
+// `*models.ImportLockedError` do repositório (com o usuário e o início da outra importação).
+func (s *importServiceImpl) acquireImportLock(fileType FileType, filePath string, userSession *auth.SessionData) (repositories.ImportLock, error) {
+	lock, err := s.importLockRepo.Acquire(string(fileType), userSession.Username)
+	if err != nil {
+		var lockedErr *models.ImportLockedError
+		if errors.As(err, &lockedErr) {
+			appLogger.Warnf("Importação do arquivo '%s' (%s) recusada para '%s': %v", filepath.Base(filePath), fileType, userSession.Username, err)
+			s.auditLogService.LogAction(models.AuditLogEntry{
+				Action:      fmt.Sprintf("IMPORT_%s_LOCKED", strings.ToUpper(string(fileType))),
+				Description: fmt.Sprintf("Importação do arquivo '%s' não iniciada: %v.", filepath.Base(filePath), err),
+				Severity:    "WARNING",
+				Metadata: map[string]interface{}{
+					"file_type":    fileType,
+					"filename":     filepath.Base(filePath),
+					"lock_held_by": lockedErr.HeldBy,
+					"lock_host":    lockedErr.HostName,
+					"lock_since":   lockedErr.AcquiredAt,
+				},
+			}, userSession)
+		}
+		return nil, err
+	}
+	return lock, nil
+}
+
+// executeImport realiza a leitura e a persistência do arquivo. Preenche em `run` os dados
+// conhecidos apenas durante a leitura (ex: encoding detectado). `hooks` é repassado à transação
+// de gravação dos títulos.
+func (s *importServiceImpl) executeImport(ctx context.Context, filePath string, fileType FileType, opts ImportOptions, run *models.DBImportRun, hooks repositories.ImportTxHooks, userSession *auth.SessionData) (map[string]interface{}, error) {
+	mode := opts.Mode
+	// Validações Iniciais do Arquivo
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		appLogger.Errorf("Arquivo de importação não encontrado: %s", filePath)
 		return nil, fmt.Errorf("%w: arquivo '%s' não encontrado", appErrors.ErrNotFound, filepath.Base(filePath))
@@ -207,23 +920,47 @@ func (s *importServiceImpl) ImportFile(filePath string, fileType FileType, userS
 	}
 
 	fileName := filepath.Base(filePath)
-	appLogger.Infof("Iniciando importação: Tipo='%s', Arquivo='%s', Usuário='%s'", fileType, fileName, userSession.Username)
+	appLogger.Infof("Iniciando importação: Tipo='%s', Modo='%s', Arquivo='%s', Usuário='%s'", fileType, mode, fileName, userSession.Username)
 
-	// 3. Ler e Validar Arquivo CSV/TXT
-	allDataRows, detectedEncoding, err := s.readAndValidateCSV(filePath, fileType)
+	// Linhas rejeitadas ou corrigidas com placeholders são gravadas no arquivo de quarentena.
+	quarantine := newImportQuarantineWriter(s.cfg.ExportDir, fileType, fileName, expectedHeaders)
+	defer quarantine.Close()
+
+	// 3. Abrir o arquivo e validar o cabeçalho (sem carregar o conteúdo em memória).
+	stream, detectedEncoding, err := s.openImportStream(ctx, filePath, fileType, opts, quarantine.onSkip)
+	if detectedEncoding != "" {
+		run.EncodingDetected = &detectedEncoding
+	}
 	if err != nil {
-		// `readAndValidateCSV` já loga o erro específico e formata para `appErrors`.
-		// Registrar falha na auditoria.
+		// `openImportStream` já loga o erro específico e formata para `appErrors`.
+		// Registrar falha na auditoria. Se todas as linhas foram rejeitadas, elas estão na quarentena.
+		description := fmt.Sprintf("Falha ao ler ou validar arquivo '%s' (Encoding: %s): %v", fileName, detectedEncoding, err)
+		metadata := map[string]interface{}{"file_type": fileType, "filename": fileName, "error": err.Error()}
+		if quarantinePath, quarantineRows := quarantine.Close(); quarantinePath != "" {
+			description += fmt.Sprintf(" Linhas rejeitadas (%d) gravadas em '%s'.", quarantineRows, quarantinePath)
+			metadata["quarantine_file"] = quarantinePath
+			metadata["records_quarantined"] = quarantineRows
+			err = fmt.Errorf("%w (linhas rejeitadas gravadas em '%s')", err, quarantinePath)
+		}
 		s.auditLogService.LogAction(models.AuditLogEntry{
 			Action:      fmt.Sprintf("IMPORT_%s_FAILED_READ", strings.ToUpper(string(fileType))),
-			Description: fmt.Sprintf("Falha ao ler ou validar arquivo '%s' (Encoding: %s): %v", fileName, detectedEncoding, err),
+			Description: description,
 			Severity:    "ERROR",
-			Metadata:    map[string]interface{}{"file_type": fileType, "filename": fileName, "error": err.Error()},
+			Metadata:    metadata,
 		}, userSession)
 		return nil, err
 	}
+	defer stream.Close()
+	// O histórico guarda o SHA-256 dos bytes efetivamente lidos nesta importação.
+	defer stream.recordContentHash(run)
+	if stream.delimiterDesc != "" {
+		run.DelimiterDetected = &stream.delimiterDesc
+	}
+	if opts.SkipDuplicate {
+		stream.atEOF = func() error { return s.rejectDuplicateContent(fileType, stream) }
+	}
 
-	if len(allDataRows) == 0 {
+	if !stream.hasData() {
 		appLogger.Warnf("Arquivo '%s' (Tipo: %s, Encoding: %s) não contém dados para importar (apenas cabeçalho ou vazio).", fileName, fileType, detectedEncoding)
 		// Atualizar metadados para registrar a tentativa de importação de arquivo vazio.
 		zeroRecords := 0
@@ -234,6 +971,7 @@ func (s *importServiceImpl) ImportFile(filePath string, fileType FileType, userS
 		}
 		return map[string]interface{}{
 			"status":                  "success_empty_file",
+			"import_mode":             string(mode),
 			"records_processed":       0,
 			"records_skipped_parsing": 0,
 			"records_skipped_repo":    0,
@@ -241,57 +979,53 @@ func (s *importServiceImpl) ImportFile(filePath string, fileType FileType, userS
 		}, nil
 	}
 
-	// 4. Mapear dados para as structs FromRow e chamar o repositório.
+	// 4. Mapear as linhas à medida que são lidas e entregá-las ao repositório,
+	// que persiste em lotes dentro de uma única transação.
 	var insertedCount, skippedInRepoCount int
+	var incResult repositories.TituloIncrementalResult
+	var companyResult repositories.TituloCompanyReplaceResult
 	var repoErr error
-	linesSkippedDuringMapping := 0
+	byCompany := opts.Scope == ImportScopeCompanies
+	run.ImportScope = string(opts.Scope)
+	// O snapshot dos títulos resultantes (se habilitado) é gravado na própria transação da importação,
+	// para permitir voltar a esta versão depois.
+	hooks, snapshot := s.withImportSnapshot(hooks, fileType, fileName, mode, run, userSession)
 
 	switch fileType {
 	case FileTypeDireitos:
-		direitosData := make([]models.TituloDireitoFromRow, 0, len(allDataRows))
-		for i, record := range allDataRows {
-			if len(record) != len(expectedHeaders) {
-				appLogger.Warnf("[Linha CSV %d, Tipo: %s] Número incorreto de campos: %d, esperado %d. Linha ignorada: %v",
-					i+2, fileType, len(record), len(expectedHeaders), record) // i+2 para contar a partir da primeira linha do arquivo
-				linesSkippedDuringMapping++
-				continue
+		nextDireito := func() (models.TituloDireitoFromRow, int, error) {
+			record, lineNum, errNext := stream.Next()
+			if errNext != nil {
+				return models.TituloDireitoFromRow{}, lineNum, errNext
 			}
-			// Mapeamento direto de colunas para campos da struct.
-			direitosData = append(direitosData, models.TituloDireitoFromRow{
-				Pessoa: record[0], CNPJCPF: record[1], NumeroEmpresa: record[2], Titulo: record[3],
-				CodigoEspecie: record[4], DataVencimento: record[5], DataQuitacao: record[6], ValorNominal: record[7],
-				ValorPago: record[8], Operacao: record[9], DataOperacao: record[10], DataContabiliza: record[11],
-				DataAlteracaoCSV: record[12], Observacao: record[13], ValorOperacao: record[14], UsuarioAlteracao: record[15],
-				EspecieAbatcomp: record[16], ObsTitulo: record[17], ContasQuitacao: record[18], DataProgramada: record[19],
-			})
+			quarantine.track(lineNum, record)
+			return recordToTituloDireitoRow(record), lineNum, nil
 		}
-		if len(direitosData) > 0 { // Só chama o repo se houver dados válidos após o mapeamento.
-			insertedCount, skippedInRepoCount, repoErr = s.tituloDireitoRepo.ReplaceAll(direitosData)
-		} else if linesSkippedDuringMapping == len(allDataRows) { // Todas as linhas foram puladas.
-			repoErr = fmt.Errorf("%w: todas as %d linhas de dados no arquivo '%s' tinham um número incorreto de campos e foram ignoradas", appErrors.ErrValidation, len(allDataRows), fileName)
+		switch {
+		case mode == ImportModeIncremental:
+			incResult, repoErr = s.tituloDireitoRepo.UpsertIncremental(nextDireito, quarantine.onIssues, byCompany, hooks)
+		case byCompany:
+			companyResult, repoErr = s.tituloDireitoRepo.ReplaceCompanies(nextDireito, quarantine.onIssues, hooks)
+		default:
+			insertedCount, skippedInRepoCount, repoErr = s.tituloDireitoRepo.ReplaceAll(nextDireito, quarantine.onIssues, hooks)
 		}
 
 	case FileTypeObrigacoes:
-		obrigacoesData := make([]models.TituloObrigacaoFromRow, 0, len(allDataRows))
-		for i, record := range allDataRows {
-			if len(record) != len(expectedHeaders) {
-				appLogger.Warnf("[Linha CSV %d, Tipo: %s] Número incorreto de campos: %d, esperado %d. Linha ignorada: %v",
-					i+2, fileType, len(record), len(expectedHeaders), record)
-				linesSkippedDuringMapping++
-				continue
+		nextObrigacao := func() (models.TituloObrigacaoFromRow, int, error) {
+			record, lineNum, errNext := stream.Next()
+			if errNext != nil {
+				return models.TituloObrigacaoFromRow{}, lineNum, errNext
 			}
-			obrigacoesData = append(obrigacoesData, models.TituloObrigacaoFromRow{
-				Pessoa: record[0], CNPJCPF: record[1], NumeroEmpresa: record[2], Titulo: record[3],
-				CodigoEspecie: record[4], DataVencimento: record[5], DataQuitacao: record[6], ValorNominal: record[7],
-				ValorPago: record[8], Operacao: record[9], DataOperacao: record[10], DataContabiliza: record[11],
-				DataAlteracaoCSV: record[12], Observacao: record[13], ValorOperacao: record[14], UsuarioAlteracao: record[15],
-				EspecieAbatcomp: record[16], ObsTitulo: record[17], ContasQuitacao: record[18], DataProgramada: record[19],
-			})
+			quarantine.track(lineNum, record)
+			return recordToTituloObrigacaoRow(record), lineNum, nil
 		}
-		if len(obrigacoesData) > 0 {
-			insertedCount, skippedInRepoCount, repoErr = s.tituloObrigacaoRepo.ReplaceAll(obrigacoesData)
-		} else if linesSkippedDuringMapping == len(allDataRows) {
-			repoErr = fmt.Errorf("%w: todas as %d linhas de dados no arquivo '%s' tinham um número incorreto de campos e foram ignoradas", appErrors.ErrValidation, len(allDataRows), fileName)
+		switch {
+		case mode == ImportModeIncremental:
+			incResult, repoErr = s.tituloObrigacaoRepo.UpsertIncremental(nextObrigacao, quarantine.onIssues, byCompany, hooks)
+		case byCompany:
+			companyResult, repoErr = s.tituloObrigacaoRepo.ReplaceCompanies(nextObrigacao, quarantine.onIssues, hooks)
+		default:
+			insertedCount, skippedInRepoCount, repoErr = s.tituloObrigacaoRepo.ReplaceAll(nextObrigacao, quarantine.onIssues, hooks)
 		}
 
 	default:
@@ -299,20 +1033,83 @@ func (s *importServiceImpl) ImportFile(filePath string, fileType FileType, userS
 		repoErr = fmt.Errorf("%w: lógica de importação não implementada para tipo '%s'", appErrors.ErrInternal, fileType)
 	}
 
+	linesSkippedDuringMapping := stream.skippedRows
+	totalDataRows := stream.totalDataRows
+	quarantinePath, quarantineRows := quarantine.Close()
+
 	if repoErr != nil {
-		// Erro já logado pelo repositório ou pelo bloco de mapeamento.
+		// Erro já logado pelo repositório ou pelo stream. A transação foi desfeita,
+		// portanto os dados anteriores permanecem intactos.
+		action := fmt.Sprintf("IMPORT_%s_FAILED_REPO", strings.ToUpper(string(fileType)))
+		description := fmt.Sprintf("Falha na persistência de dados do arquivo '%s': %v", fileName, repoErr)
+		severity := "ERROR"
+		var duplicateErr *models.DuplicateImportError
+		if errors.Is(repoErr, appErrors.ErrCancelled) { // Cancelada pelo usuário durante a leitura.
+			action = fmt.Sprintf("IMPORT_%s_CANCELLED", strings.ToUpper(string(fileType)))
+			description = fmt.Sprintf("Importação do arquivo '%s' cancelada após %d linhas de dados. Nenhuma alteração foi gravada.", fileName, totalDataRows)
+			severity = "WARNING"
+		} else if errors.As(repoErr, &duplicateErr) { // `ImportOptions.SkipDuplicate`.
+			action = fmt.Sprintf("IMPORT_%s_DUPLICATE", strings.ToUpper(string(fileType)))
+			description = fmt.Sprintf("Importação do arquivo '%s' desfeita: %v. Nenhuma alteração foi gravada.", fileName, repoErr)
+			severity = "WARNING"
+		} else if stream.readErr != nil { // Falha de leitura/parse no meio do arquivo.
+			action = fmt.Sprintf("IMPORT_%s_FAILED_READ", strings.ToUpper(string(fileType)))
+			description = fmt.Sprintf("Falha ao ler arquivo '%s' (Encoding: %s) após %d linhas de dados: %v", fileName, detectedEncoding, totalDataRows, repoErr)
+		}
+		metadata := map[string]interface{}{"file_type": fileType, "import_mode": mode, "import_scope": opts.Scope, "filename": fileName, "error": repoErr.Error()}
+		if quarantinePath != "" {
+			description += fmt.Sprintf(" Linhas com problemas até a falha (%d) gravadas em '%s'.", quarantineRows, quarantinePath)
+			metadata["quarantine_file"] = quarantinePath
+			metadata["records_quarantined"] = quarantineRows
+		}
 		s.auditLogService.LogAction(models.AuditLogEntry{
-			Action:      fmt.Sprintf("IMPORT_%s_FAILED_REPO", strings.ToUpper(string(fileType))),
-			Description: fmt.Sprintf("Falha na persistência de dados do arquivo '%s': %v", fileName, repoErr),
-			Severity:    "ERROR",
-			Metadata:    map[string]interface{}{"file_type": fileType, "filename": fileName, "error": repoErr.Error()},
+			Action:      action,
+			Description: description,
+			Severity:    severity,
+			Metadata:    metadata,
 		}, userSession)
-		return nil, repoErr // Propaga o erro do repositório ou de mapeamento.
+		return nil, repoErr // Propaga o erro do repositório ou de leitura.
+	}
+
+	// No modo substituição, todos os registros gravados são novos.
+	// No modo incremental, os registros ativos vindos do arquivo são os inseridos, atualizados e inalterados.
+	processedCount := insertedCount
+	companies := incResult.Companies
+	if mode == ImportModeIncremental {
+		insertedCount = incResult.Inserted
+		processedCount = incResult.Inserted + incResult.Updated + incResult.Unchanged
+	} else {
+		if byCompany {
+			insertedCount, processedCount, companies = companyResult.Inserted, companyResult.Inserted, companyResult.Companies
+		}
+		incResult.Inserted = insertedCount
+	}
+	// Empresas presentes no arquivo; no escopo por empresa, são as únicas afetadas.
+	companiesText := formatCompanyList(companies)
+	if companiesText != "" {
+		run.Companies = &companiesText
+	}
+	var affectedCompanies *string // Nulo nos metadados quando todas as empresas foram abrangidas.
+	if byCompany {
+		affectedCompanies = &companiesText
 	}
 
-	// 5. Atualizar Metadados e Logar Sucesso
+	// 5. Vincular os títulos aos CNPJs e redes cadastrados e verificar os CNPJs do arquivo.
+	cnpjCheck := s.linkTitlesAfterImport(fileType, fileName)
+
+	// Saldos em aberto por rede após a importação, para a comparação do painel de redes.
+	s.recordImportNetworkTotals(fileType, run)
+
+	var snapshotID uint64 // Zero se os snapshots estiverem desabilitados.
+	if snapshot != nil {
+		snapshotID = snapshot.ID
+	}
+	fileSHA256, _, _ := stream.source.ContentHash() // O arquivo já foi lido até o fim.
+
+	// 6. Atualizar Metadados e Logar Sucesso
 	if _, metaErr := s.importMetadataRepo.Upsert(models.ImportMetadataUpsert{
-		FileType: string(fileType), OriginalFilename: &fileName, RecordCount: &insertedCount, ImportedBy: &userSession.Username,
+		FileType: string(fileType), OriginalFilename: &fileName, RecordCount: &processedCount, ImportedBy: &userSession.Username,
+		Companies: affectedCompanies,
 	}); metaErr != nil {
 		appLogger.Warnf("Falha ao atualizar metadados para importação de '%s' (Tipo: %s): %v", fileName, fileType, metaErr)
 		// Não falha a operação principal por isso, mas é um aviso importante.
@@ -320,35 +1117,585 @@ func (s *importServiceImpl) ImportFile(filePath string, fileType FileType, userS
 
 	description := fmt.Sprintf("Arquivo '%s' importado com sucesso. Registros inseridos: %d. Linhas puladas (parsing CSV): %d. Linhas puladas (processamento repositório): %d.",
 		fileName, insertedCount, linesSkippedDuringMapping, skippedInRepoCount)
+	message := fmt.Sprintf("Importação concluída. %d registros inseridos.", insertedCount)
+	if mode == ImportModeIncremental {
+		description = fmt.Sprintf("Arquivo '%s' importado (incremental). Inseridos: %d, atualizados: %d, inalterados: %d, removidos: %d. Linhas puladas (parsing CSV): %d.",
+			fileName, incResult.Inserted, incResult.Updated, incResult.Unchanged, incResult.Removed, linesSkippedDuringMapping)
+		message = fmt.Sprintf("Importação incremental concluída. Inseridos: %d, atualizados: %d, inalterados: %d, removidos: %d.",
+			incResult.Inserted, incResult.Updated, incResult.Unchanged, incResult.Removed)
+	}
+	if byCompany {
+		description += fmt.Sprintf(" Escopo: apenas as empresas do arquivo (%s); as demais empresas não foram alteradas.", companiesText)
+		message += fmt.Sprintf(" Empresas afetadas: %s.", companiesText)
+		if mode != ImportModeIncremental {
+			description += fmt.Sprintf(" Títulos anteriores dessas empresas substituídos: %d.", companyResult.Replaced)
+		}
+	}
+	if quarantinePath != "" {
+		description += fmt.Sprintf(" Linhas rejeitadas ou corrigidas (%d) gravadas em '%s'.", quarantineRows, quarantinePath)
+		message += fmt.Sprintf(" %d linhas rejeitadas ou corrigidas gravadas em '%s'.", quarantineRows, quarantinePath)
+	}
+	pendingCNPJs := 0
+	if cnpjCheck != nil {
+		pendingCNPJs = len(cnpjCheck.Issues)
+		description += " " + cnpjCheckSummaryText(cnpjCheck)
+	}
 	s.auditLogService.LogAction(models.AuditLogEntry{
 		Action:      fmt.Sprintf("IMPORT_%s_SUCCESS", strings.ToUpper(string(fileType))),
 		Description: description,
 		Severity:    "INFO",
 		Metadata: map[string]interface{}{
 			"file_type":                  fileType,
+			"import_mode":                mode,
+			"import_scope":               opts.Scope,
+			"companies":                  companiesText,
+			"records_replaced":           companyResult.Replaced,
+			"import_run_id":              run.ID,
 			"filename":                   fileName,
 			"encoding_detected":          detectedEncoding,
-			"total_data_rows_in_file":    len(allDataRows),
-			"records_mapped_to_model":    len(allDataRows) - linesSkippedDuringMapping,
+			"delimiter_detected":         stream.delimiterDesc,
+			"import_profile":             stream.profileName(),
+			"total_data_rows_in_file":    totalDataRows,
+			"records_mapped_to_model":    totalDataRows - linesSkippedDuringMapping,
 			"records_inserted_by_repo":   insertedCount,
 			"records_skipped_by_parsing": linesSkippedDuringMapping,
 			"records_skipped_by_repo":    skippedInRepoCount,
+			"records_updated":            incResult.Updated,
+			"records_unchanged":          incResult.Unchanged,
+			"records_removed":            incResult.Removed,
+			"records_quarantined":        quarantineRows,
+			"quarantine_file":            quarantinePath,
+			"snapshot_id":                snapshotID,
+			"cnpj_pending_count":         pendingCNPJs,
 		},
 	}, userSession)
 
-	appLogger.Infof("Importação para Tipo='%s' (Arquivo: '%s') concluída. Inseridos: %d, Pulados (parsing): %d, Pulados (repo): %d.",
-		fileType, fileName, insertedCount, linesSkippedDuringMapping, skippedInRepoCount)
+	appLogger.Infof("Importação para Tipo='%s' (Modo: %s, Arquivo: '%s') concluída. Inseridos: %d, Atualizados: %d, Inalterados: %d, Removidos: %d, Pulados (parsing): %d, Pulados (repo): %d.",
+		fileType, mode, fileName, insertedCount, incResult.Updated, incResult.Unchanged, incResult.Removed, linesSkippedDuringMapping, skippedInRepoCount)
 
 	return map[string]interface{}{
 		"status":                  "success",
-		"records_processed":       insertedCount, // Registros efetivamente no banco.
+		"import_mode":             string(mode),
+		"import_scope":            string(opts.Scope),
+		"companies":               companies,              // []int: empresas (NROEMPRESA) presentes no arquivo.
+		"records_replaced":        companyResult.Replaced, // Títulos anteriores apagados no escopo por empresa.
+		"records_processed":       processedCount,         // Registros do arquivo efetivamente ativos no banco.
+		"records_inserted":        incResult.Inserted,
+		"records_updated":         incResult.Updated,
+		"records_unchanged":       incResult.Unchanged,
+		"records_removed":         incResult.Removed,
 		"records_skipped_parsing": linesSkippedDuringMapping,
 		"records_skipped_repo":    skippedInRepoCount,
-		"total_data_rows_in_file": len(allDataRows),
-		"message":                 fmt.Sprintf("Importação concluída. %d registros inseridos.", insertedCount),
+		"total_data_rows_in_file": totalDataRows,
+		"import_profile":          stream.profileName(), // Vazio para o layout padrão.
+		"records_quarantined":     quarantineRows,
+		"quarantine_file":         quarantinePath, // Vazio se nenhuma linha foi rejeitada ou corrigida.
+		"file_sha256":             fileSHA256,     // SHA-256 dos bytes importados (o mesmo gravado no histórico).
+		"snapshot_id":             snapshotID,     // Zero se os snapshots estiverem desabilitados.
+		"cnpj_check":              cnpjCheck,      // *models.TituloCNPJCheckReport; nil se a verificação falhou.
+		"message":                 message,
 	}, nil
 }
 
+// --- Arquivo de Quarentena ---
+
+// Situações das linhas gravadas no arquivo de quarentena.
+const (
+	quarantineStatusRejected  = "REJEITADA"        // Linha ignorada; não foi importada.
+	quarantineStatusPatched   = "CORRIGIDA"        // Importada com valor padrão (placeholder) em campo obrigatório.
+	quarantineStatusDiscarded = "VALOR_DESCARTADO" // Importada, mas um valor opcional inválido foi gravado como NULL.
+)
+
+// quarantineLeadingHeaders são as colunas descritivas do arquivo de quarentena. Em seguida vêm as
+// colunas originais do arquivo importado, com os valores como foram lidos, para que a linha possa
+// ser corrigida na origem (ERP) e reimportada.
+var quarantineLeadingHeaders = []string{"LINHA_ORIGINAL", "SITUACAO", "COLUNAS_COM_PROBLEMA", "MOTIVO"}
+
+// importQuarantineWriter grava em `ExportDir`, em streaming, um CSV com as linhas de uma importação
+// que foram rejeitadas ou corrigidas com placeholders. O arquivo só é criado quando a primeira linha
+// com problema aparece. Falhas de escrita são apenas logadas e não interrompem a importação.
+type importQuarantineWriter struct {
+	dir      string
+	fileName string
+	headers  []string
+
+	path     string
+	file     *os.File
+	writer   *csv.Writer
+	rowCount int
+	failed   bool // Após uma falha de escrita, a quarentena é desativada para esta importação.
+	closed   bool
+
+	// Última linha entregue ao repositório. O leitor CSV reutiliza o slice (`ReuseRecord`), mas o
+	// `RowIssueHandler` é chamado antes da próxima leitura, então a referência ainda é válida nele.
+	currentRecord []string
+	currentLine   int
+}
+
+// newImportQuarantineWriter prepara o arquivo de quarentena de uma importação (sem criá-lo ainda).
+func newImportQuarantineWriter(exportDir string, fileType FileType, sourceFileName string, headers []string) *importQuarantineWriter {
+	baseName := strings.TrimSuffix(sourceFileName, filepath.Ext(sourceFileName))
+	return &importQuarantineWriter{
+		dir:      filepath.Join(exportDir, "quarentena"),
+		fileName: fmt.Sprintf("quarentena_%s_%s_%s.csv", strings.ToLower(string(fileType)), time.Now().Format("20060102_150405"), baseName),
+		headers:  headers,
+	}
+}
+
+// track registra a linha que está sendo entregue ao repositório.
+func (q *importQuarantineWriter) track(lineNum int, record []string) {
+	q.currentLine = lineNum
+	q.currentRecord = record
+}
+
+// onSkip grava uma linha ignorada por número incorreto de campos.
+func (q *importQuarantineWriter) onSkip(lineNum int, record []string) {
+	q.write(lineNum, quarantineStatusRejected, models.ImportRowIssueColumnRow,
+		fmt.Sprintf("número incorreto de campos: %d, esperado %d; linha não importada", len(record), len(q.headers)), record)
+}
+
+// onIssues grava uma linha importada com problemas (usado como `repositories.RowIssueHandler`).
+func (q *importQuarantineWriter) onIssues(lineNum int, issues []models.ImportRowIssue) {
+	record := q.currentRecord
+	if lineNum != q.currentLine { // Não deveria acontecer; evita gravar o conteúdo de outra linha.
+		record = nil
+	}
+	status := quarantineStatusDiscarded
+	columns := make([]string, 0, len(issues))
+	reasons := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		if issue.UsedPlaceholder {
+			status = quarantineStatusPatched
+		}
+		columns = append(columns, issue.Column)
+		reasons = append(reasons, fmt.Sprintf("%s: %s", issue.Column, issue.Message))
+	}
+	q.write(lineNum, status, strings.Join(columns, ", "), strings.Join(reasons, " | "), record)
+}
+
+// write acrescenta uma linha ao arquivo de quarentena, criando-o se necessário.
+func (q *importQuarantineWriter) write(lineNum int, status, columns, reason string, record []string) {
+	if q.failed || q.closed {
+		return
+	}
+	if q.writer == nil {
+		if err := q.open(); err != nil {
+			appLogger.Warnf("Falha ao criar arquivo de quarentena '%s': %v. Linhas com problemas ficarão apenas nos logs.", q.fileName, err)
+			q.failed = true
+			return
+		}
+	}
+	row := make([]string, 0, len(quarantineLeadingHeaders)+len(record))
+	row = append(row, strconv.Itoa(lineNum), status, columns, reason)
+	row = append(row, record...)
+	if err := q.writer.Write(row); err != nil {
+		appLogger.Warnf("Falha ao gravar linha %d no arquivo de quarentena '%s': %v", lineNum, q.path, err)
+		q.failed = true
+		return
+	}
+	q.rowCount++
+}
+
+// open cria o diretório e o arquivo de quarentena e grava o cabeçalho.
+func (q *importQuarantineWriter) open() error {
+	if err := os.MkdirAll(q.dir, os.ModePerm); err != nil {
+		return err
+	}
+	path, err := filepath.Abs(filepath.Join(q.dir, q.fileName))
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	// BOM UTF-8 para que o Excel reconheça os acentos ao abrir o arquivo.
+	if _, err := file.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+		file.Close()
+		return err
+	}
+	// Mesmo delimitador do arquivo de importação e das exportações CSV (compatível com Excel em PT-BR).
+	writer := csv.NewWriter(file)
+	writer.Comma = ';'
+	if err := writer.Write(append(append([]string{}, quarantineLeadingHeaders...), q.headers...)); err != nil {
+		file.Close()
+		return err
+	}
+	q.path = path
+	q.file = file
+	q.writer = writer
+	return nil
+}
+
+// Close finaliza o arquivo de quarentena. Retorna o caminho do arquivo e o número de linhas gravadas,
+// ou "" se nenhuma linha teve problema. Pode ser chamado mais de uma vez.
+func (q *importQuarantineWriter) Close() (string, int) {
+	if q.writer == nil {
+		return "", 0
+	}
+	if !q.closed {
+		q.closed = true
+		q.writer.Flush()
+		if err := q.writer.Error(); err != nil {
+			appLogger.Warnf("Falha ao finalizar arquivo de quarentena '%s': %v", q.path, err)
+		}
+		if err := q.file.Close(); err != nil {
+			appLogger.Warnf("Falha ao fechar arquivo de quarentena '%s': %v", q.path, err)
+		}
+		appLogger.Infof("Arquivo de quarentena gravado: %s (%d linhas).", q.path, q.rowCount)
+	}
+	return q.path, q.rowCount
+}
+
+// --- Pré-visualização (Dry-run) ---
+
+const (
+	// previewSampleSize é o número de registros mapeados incluídos como amostra no relatório de pré-visualização.
+	previewSampleSize = 20
+	// previewMaxIssues limita os problemas listados individualmente no relatório de pré-visualização,
+	// para que arquivos com muitos erros não gerem relatórios enormes. As contagens por coluna
+	// continuam considerando todos os problemas.
+	previewMaxIssues = 1000
+	// previewMaxRawValueLen limita o tamanho do valor bruto guardado em cada problema.
+	previewMaxRawValueLen = 200
+)
+
+// PreviewImport lê e mapeia o arquivo sem gravar no banco, produzindo um relatório de validação.
+func (s *importServiceImpl) PreviewImport(filePath string, fileType FileType, opts ImportOptions, userSession *auth.SessionData) (*models.ImportPreviewReport, error) {
+	// A pré-visualização é o primeiro passo de uma importação, portanto exige a mesma permissão.
+	if err := s.permManager.CheckPermission(userSession, auth.PermImportExecute, nil); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		appLogger.Errorf("Arquivo para pré-visualização não encontrado: %s", filePath)
+		return nil, fmt.Errorf("%w: arquivo '%s' não encontrado", appErrors.ErrNotFound, filepath.Base(filePath))
+	}
+	if _, err := getExpectedHeaders(fileType); err != nil {
+		return nil, fmt.Errorf("%w: tipo de arquivo '%s' não é suportado ou não tem colunas definidas: %v", appErrors.ErrConfiguration, fileType, err)
+	}
+	if fileType == FileTypeRedesCNPJs || fileType == FileTypeFeriados {
+		return nil, fmt.Errorf("%w: a pré-visualização não está disponível para os cadastros de redes, CNPJs e feriados", appErrors.ErrInvalidInput)
+	}
+
+	fileName := filepath.Base(filePath)
+	appLogger.Infof("Iniciando pré-visualização de importação: Tipo='%s', Arquivo='%s', Usuário='%s'", fileType, fileName, userSession.Username)
+
+	report := &models.ImportPreviewReport{
+		FileType:           strings.ToUpper(string(fileType)),
+		OriginalFilename:   fileName,
+		IssueCountByColumn: make(map[string]int),
+		Issues:             []models.ImportRowIssue{},
+	}
+
+	// Os problemas chegam em ordem crescente de linha, então basta comparar com a última linha vista
+	// para contar as linhas com problemas.
+	lastIssueLine := 0
+	addIssue := func(issue models.ImportRowIssue) {
+		report.IssueCountByColumn[issue.Column]++
+		if issue.LineNum != lastIssueLine {
+			report.RowsWithIssues++
+			lastIssueLine = issue.LineNum
+		}
+		if len(report.Issues) >= previewMaxIssues {
+			report.IssuesTruncated = true
+			return
+		}
+		issue.RawValue = truncateForPreview(issue.RawValue)
+		report.Issues = append(report.Issues, issue)
+	}
+
+	expectedHeaders, _ := getExpectedHeaders(fileType)
+	onSkip := func(lineNum int, record []string) {
+		addIssue(models.ImportRowIssue{
+			LineNum:  lineNum,
+			Column:   models.ImportRowIssueColumnRow,
+			RawValue: strings.Join(record, ";"),
+			Message:  fmt.Sprintf("número incorreto de campos: %d, esperado %d; a linha será ignorada", len(record), len(expectedHeaders)),
+		})
+	}
+
+	opts.Progress = nil // A pré-visualização não relata andamento.
+	stream, detectedEncoding, err := s.openImportStream(context.Background(), filePath, fileType, opts, onSkip)
+	report.EncodingDetected = detectedEncoding
+	if err != nil {
+		return nil, err // Erro já logado e formatado por `openImportStream`.
+	}
+	defer stream.Close()
+	report.ProfileName = stream.profileName()
+	report.DelimiterDetected = stream.delimiterDesc
+
+	for {
+		record, lineNum, errNext := stream.Next()
+		if errors.Is(errNext, io.EOF) {
+			break
+		}
+		if errNext != nil {
+			return nil, errNext // Erro de parse no meio do arquivo: a importação real também falharia.
+		}
+		report.ValidRows++
+
+		var rowIssues []models.ImportRowIssue
+		switch fileType {
+		case FileTypeDireitos:
+			var dbEntry models.DBTituloDireito
+			dbEntry, rowIssues = repositories.MapTituloDireitoRow(recordToTituloDireitoRow(record), lineNum)
+			if len(report.SampleDireitos) < previewSampleSize {
+				if publicEntry, errConv := models.ToTituloDireitoPublic(&dbEntry); errConv == nil {
+					report.SampleDireitos = append(report.SampleDireitos, publicEntry)
+				} else {
+					appLogger.Warnf("[Linha %d TDir] Falha ao converter registro para a amostra da pré-visualização: %v", lineNum, errConv)
+				}
+			}
+		case FileTypeObrigacoes:
+			var dbEntry models.DBTituloObrigacao
+			dbEntry, rowIssues = repositories.MapTituloObrigacaoRow(recordToTituloObrigacaoRow(record), lineNum)
+			if len(report.SampleObrigacoes) < previewSampleSize {
+				if publicEntry, errConv := models.ToTituloObrigacaoPublic(&dbEntry); errConv == nil {
+					report.SampleObrigacoes = append(report.SampleObrigacoes, publicEntry)
+				} else {
+					appLogger.Warnf("[Linha %d TObrig] Falha ao converter registro para a amostra da pré-visualização: %v", lineNum, errConv)
+				}
+			}
+		}
+
+		usedPlaceholder := false
+		for _, issue := range rowIssues {
+			addIssue(issue)
+			if issue.UsedPlaceholder {
+				usedPlaceholder = true
+			}
+		}
+		if usedPlaceholder {
+			report.RowsWithPlaceholders++
+		}
+	}
+
+	report.TotalDataRows = stream.totalDataRows
+	report.RowsSkippedParsing = stream.skippedRows
+
+	s.auditLogService.LogAction(models.AuditLogEntry{
+		Action: fmt.Sprintf("IMPORT_%s_PREVIEW", strings.ToUpper(string(fileType))),
+		Description: fmt.Sprintf("Pré-visualização do arquivo '%s': %d linhas de dados, %d válidas, %d ignoradas, %d com placeholders, %d problemas.",
+			fileName, report.TotalDataRows, report.ValidRows, report.RowsSkippedParsing, report.RowsWithPlaceholders, report.TotalIssues()),
+		Severity: "INFO",
+		Metadata: map[string]interface{}{
+			"file_type":              fileType,
+			"filename":               fileName,
+			"encoding_detected":      detectedEncoding,
+			"delimiter_detected":     report.DelimiterDetected,
+			"import_profile":         report.ProfileName,
+			"total_data_rows":        report.TotalDataRows,
+			"valid_rows":             report.ValidRows,
+			"rows_skipped_parsing":   report.RowsSkippedParsing,
+			"rows_with_placeholders": report.RowsWithPlaceholders,
+			"rows_with_issues":       report.RowsWithIssues,
+			"issue_count_by_column":  report.IssueCountByColumn,
+		},
+	}, userSession)
+
+	appLogger.Infof("Pré-visualização de '%s' (Tipo: %s) concluída. Linhas: %d, válidas: %d, ignoradas: %d, com placeholders: %d, problemas: %d.",
+		fileName, fileType, report.TotalDataRows, report.ValidRows, report.RowsSkippedParsing, report.RowsWithPlaceholders, report.TotalIssues())
+	return report, nil
+}
+
+// ListImportSheets retorna os nomes das planilhas de um arquivo XLSX.
+func (s *importServiceImpl) ListImportSheets(filePath string, userSession *auth.SessionData) ([]string, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermImportExecute, nil); err != nil {
+		return nil, err
+	}
+	if !isXLSXFile(filePath) {
+		return nil, fmt.Errorf("%w: arquivo '%s' não é uma planilha XLSX", appErrors.ErrInvalidInput, filepath.Base(filePath))
+	}
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: arquivo '%s' não encontrado", appErrors.ErrNotFound, filepath.Base(filePath))
+	}
+	return listXLSXSheets(filePath)
+}
+
+// truncateForPreview limita o tamanho de um valor bruto exibido no relatório de pré-visualização,
+// sem cortar caracteres multibyte ao meio.
+func truncateForPreview(value string) string {
+	if len(value) <= previewMaxRawValueLen {
+		return value
+	}
+	cut := previewMaxRawValueLen
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut] + "..."
+}
+
+// --- Histórico de Execuções ---
+
+// startImportRun registra o início de uma execução de importação no histórico. O SHA-256 do
+// conteúdo é gravado depois, a partir dos bytes efetivamente lidos (`importRowStream.recordContentHash`).
+// Falhas ao gravar o histórico são apenas logadas e não impedem a importação.
+func (s *importServiceImpl) startImportRun(filePath string, fileType FileType, mode ImportMode, userSession *auth.SessionData) *models.DBImportRun {
+	run := &models.DBImportRun{
+		FileType:         strings.ToUpper(string(fileType)),
+		ImportMode:       string(mode),
+		ImportScope:      string(ImportScopeAll),
+		OriginalFilename: filepath.Base(filePath),
+		ImportedBy:       userSession.Username,
+		StartedAt:        time.Now().UTC(),
+		Status:           models.ImportRunStatusRunning,
+	}
+
+	if info, err := os.Stat(filePath); err == nil {
+		size := info.Size()
+		run.FileSizeBytes = &size
+	}
+
+	if err := s.importRunRepo.Create(run); err != nil {
+		appLogger.Warnf("Falha ao registrar início da execução de importação '%s' no histórico: %v", run.OriginalFilename, err)
+	}
+	return run
+}
+
+// formatCompanyList formata as empresas (NROEMPRESA) para exibição e registro (ex: "1, 3, 12").
+func formatCompanyList(companies []int) string {
+	parts := make([]string, len(companies))
+	for i, company := range companies {
+		parts[i] = strconv.Itoa(company)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// finishImportRun grava o resultado final (status, contadores e erro) de uma execução no histórico.
+func (s *importServiceImpl) finishImportRun(run *models.DBImportRun, result map[string]interface{}, importErr error) {
+	finishedAt := time.Now().UTC()
+	run.FinishedAt = &finishedAt
+
+	intFromResult := func(key string) int {
+		if val, ok := result[key].(int); ok {
+			return val
+		}
+		return 0
+	}
+	run.TotalDataRows = intFromResult("total_data_rows_in_file")
+	run.RecordsProcessed = intFromResult("records_processed")
+	run.RecordsInserted = intFromResult("records_inserted")
+	run.RecordsUpdated = intFromResult("records_updated")
+	run.RecordsUnchanged = intFromResult("records_unchanged")
+	run.RecordsRemoved = intFromResult("records_removed")
+	run.RecordsSkippedParsing = intFromResult("records_skipped_parsing")
+	run.RecordsSkippedRepo = intFromResult("records_skipped_repo")
+
+	switch {
+	case errors.Is(importErr, appErrors.ErrCancelled):
+		run.Status = models.ImportRunStatusCancelled
+		errMsg := importErr.Error()
+		run.ErrorMessage = &errMsg
+	case importErr != nil:
+		run.Status = models.ImportRunStatusFailed
+		errMsg := importErr.Error()
+		run.ErrorMessage = &errMsg
+	case result["status"] == "success_empty_file":
+		run.Status = models.ImportRunStatusSuccessEmpty
+	default:
+		run.Status = models.ImportRunStatusSuccess
+	}
+
+	var err error
+	if run.ID == 0 { // O registro inicial falhou; tenta gravar a execução completa.
+		err = s.importRunRepo.Create(run)
+	} else {
+		err = s.importRunRepo.Update(run)
+	}
+	if err != nil {
+		appLogger.Warnf("Falha ao registrar resultado da execução de importação '%s' (Status: %s) no histórico: %v", run.OriginalFilename, run.Status, err)
+	}
+}
+
+// GetImportRuns busca o histórico de execuções de importação com filtros e paginação.
+func (s *importServiceImpl) GetImportRuns(filter models.ImportRunFilter, userSession *auth.SessionData) ([]*models.ImportRunPublic, int64, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermImportViewStatus, nil); err != nil {
+		return nil, 0, err
+	}
+	runs, totalCount, err := s.importRunRepo.GetFiltered(filter)
+	if err != nil {
+		return nil, 0, err // Erro já logado pelo repo.
+	}
+	return models.ToImportRunPublicList(runs), totalCount, nil
+}
+
+// GetImportRun busca uma execução de importação específica do histórico.
+func (s *importServiceImpl) GetImportRun(runID uint64, userSession *auth.SessionData) (*models.ImportRunPublic, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermImportViewStatus, nil); err != nil {
+		return nil, err
+	}
+	run, err := s.importRunRepo.GetByID(runID)
+	if err != nil {
+		return nil, err
+	}
+	return models.ToImportRunPublic(run), nil
+}
+
+// recentSuccessfulImportRuns busca no histórico as `limit` execuções mais recentes do tipo de arquivo que
+// gravaram dados (status `SUCCESS`), da mais recente para a mais antiga. A primeira corresponde aos dados
+// atuais; as importações vazias, com falha ou canceladas não alteram os títulos e são ignoradas.
+func recentSuccessfulImportRuns(importRunRepo repositories.ImportRunRepository, fileType FileType, limit int) ([]models.DBImportRun, error) {
+	fileTypeStr := string(fileType)
+	status := models.ImportRunStatusSuccess
+	runs, _, err := importRunRepo.GetFiltered(models.ImportRunFilter{FileType: &fileTypeStr, Status: &status, Limit: limit})
+	return runs, err
+}
+
+// recordImportNetworkTotals grava na execução os saldos em aberto por rede dos títulos do tipo.
+// Falhas são apenas logadas: sem os totais, o painel compara com o snapshot da importação, se houver.
+func (s *importServiceImpl) recordImportNetworkTotals(fileType FileType, run *models.DBImportRun) {
+	if run == nil || run.ID == 0 {
+		return
+	}
+	var forEachBalance func(fn func(batch []models.TituloBalance) error) error
+	switch fileType {
+	case FileTypeDireitos:
+		forEachBalance = s.tituloDireitoRepo.ForEachBalance
+	case FileTypeObrigacoes:
+		forEachBalance = s.tituloObrigacaoRepo.ForEachBalance
+	default:
+		return
+	}
+	totals, err := accumulateOpen(forEachBalance, nil)
+	if err != nil {
+		appLogger.Warnf("Falha ao calcular os saldos por rede da importação ID %d (Tipo: %s): %v", run.ID, fileType, err)
+		return
+	}
+	rows := make([]models.DBImportRunNetworkTotal, 0, len(totals))
+	for networkID, open := range totals {
+		rows = append(rows, models.DBImportRunNetworkTotal{NetworkID: networkID, OpenTotal: open.StringFixed(2)})
+	}
+	if err := s.importRunRepo.SaveNetworkTotals(run.ID, rows); err != nil {
+		return // Erro já logado pelo repo.
+	}
+	run.NetworkTotalsRecorded = true // Gravado junto com a finalização da execução.
+}
+
+// rejectDuplicateContent retorna `*models.DuplicateImportError` se o conteúdo lido pelo stream já foi
+// importado com sucesso para o tipo. Chamada ao fim da leitura, antes do commit (`ImportOptions.SkipDuplicate`).
+func (s *importServiceImpl) rejectDuplicateContent(fileType FileType, stream *importRowStream) error {
+	hash, _, ok := stream.source.ContentHash()
+	if !ok {
+		return nil
+	}
+	fileTypeStr := string(fileType)
+	runs, _, err := s.importRunRepo.GetFiltered(models.ImportRunFilter{FileType: &fileTypeStr, FileSHA256: &hash, Limit: 100})
+	if err != nil {
+		return err // Erro já logado pelo repo.
+	}
+	// Execuções com falha, canceladas ou interrompidas (RUNNING para sempre) não gravaram dados.
+	for _, run := range runs {
+		if run.Status == models.ImportRunStatusSuccess || run.Status == models.ImportRunStatusSuccessEmpty {
+			appLogger.Warnf("Conteúdo do arquivo '%s' (%s, SHA-256 %s) já importado na execução #%d.", stream.fileName, fileType, hash, run.ID)
+			return &models.DuplicateImportError{
+				FileType: fileTypeStr, FileSHA256: hash,
+				PreviousRunID: run.ID, PreviousImportedBy: run.ImportedBy, PreviousStartedAt: run.StartedAt,
+			}
+		}
+	}
+	return nil
+}
+
 // GetAllImportStatus busca os metadados de todas as importações.
 func (s *importServiceImpl) GetAllImportStatus(userSession *auth.SessionData) ([]models.ImportMetadataPublic, error) {
 	if err := s.permManager.CheckPermission(userSession, auth.PermImportViewStatus, nil); err != nil {
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/repositories"
+)
+
+// --- Snapshots dos Títulos Importados ---
+
+// withImportSnapshot acrescenta a `hooks` a gravação do snapshot dos títulos resultantes, na mesma
+// transação da importação, se os snapshots estiverem habilitados (`Config.ImportSnapshotEnabled`).
+// Retorna os hooks e o snapshot, cujo ID é preenchido após o commit (nil se desabilitado).
+func (s *importServiceImpl) withImportSnapshot(hooks repositories.ImportTxHooks, fileType FileType, fileName string, mode ImportMode, run *models.DBImportRun, userSession *auth.SessionData) (repositories.ImportTxHooks, *models.DBImportSnapshot) {
+	if !s.cfg.ImportSnapshotEnabled {
+		return hooks, nil
+	}
+	snapshot := &models.DBImportSnapshot{
+		FileType:         string(fileType),
+		OriginalFilename: fileName,
+		ImportMode:       string(mode),
+		CreatedBy:        userSession.Username,
+	}
+	if run != nil && run.ID != 0 {
+		runID := run.ID
+		snapshot.ImportRunID = &runID
+	}
+	// O snapshot guarda `network_id`: os vínculos com os CNPJs são refeitos antes da cópia.
+	hooks.BeforeCommit = append(hooks.BeforeCommit,
+		s.tituloCNPJLinkRepo.LinkTitlesTx(string(fileType)),
+		s.importSnapshotRepo.CaptureTx(snapshot, s.cfg.ImportSnapshotRetention))
+	return hooks, snapshot
+}
+
+// GetImportSnapshots busca os snapshots guardados de um tipo de arquivo, do mais recente para o mais antigo.
+func (s *importServiceImpl) GetImportSnapshots(fileType FileType, userSession *auth.SessionData) ([]*models.ImportSnapshotPublic, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermImportViewStatus, nil); err != nil {
+		return nil, err
+	}
+	snapshots, err := s.importSnapshotRepo.GetAll(string(fileType))
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	return models.ToImportSnapshotPublicList(snapshots), nil
+}
+
+// RestoreImportSnapshot substitui, de forma atômica, os títulos do tipo do snapshot pelos títulos
+// guardados nele. Retorna o snapshot restaurado e o número de títulos gravados.
+func (s *importServiceImpl) RestoreImportSnapshot(snapshotID uint64, userSession *auth.SessionData) (*models.ImportSnapshotPublic, int, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermImportRestoreSnapshot, nil); err != nil {
+		return nil, 0, err
+	}
+
+	// A restauração substitui todos os títulos do tipo: não pode coincidir com uma importação do mesmo tipo.
+	target, err := s.importSnapshotRepo.GetByID(snapshotID)
+	if err != nil {
+		return nil, 0, err // Erro já logado pelo repo.
+	}
+	lock, err := s.acquireImportLock(FileType(target.FileType), target.OriginalFilename, userSession)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer lock.Release()
+
+	snapshot, restoredCount, err := s.importSnapshotRepo.Restore(snapshotID, userSession.Username)
+	if err != nil {
+		s.auditLogService.LogAction(models.AuditLogEntry{
+			Action:      "IMPORT_SNAPSHOT_RESTORE_FAILED",
+			Description: fmt.Sprintf("Falha ao restaurar o snapshot de importação ID %d: %v", snapshotID, err),
+			Severity:    "ERROR",
+			Metadata:    map[string]interface{}{"snapshot_id": snapshotID, "error": err.Error()},
+		}, userSession)
+		return nil, 0, err // Erro já logado pelo repo.
+	}
+
+	// Os vínculos guardados no snapshot podem estar desatualizados em relação ao cadastro de CNPJs.
+	cnpjCheck := s.linkTitlesAfterImport(FileType(snapshot.FileType), snapshot.OriginalFilename)
+
+	// O status da importação passa a refletir os dados restaurados.
+	restoredName := fmt.Sprintf("%s (snapshot #%d restaurado)", snapshot.OriginalFilename, snapshot.ID)
+	if _, metaErr := s.importMetadataRepo.Upsert(models.ImportMetadataUpsert{
+		FileType: snapshot.FileType, OriginalFilename: &restoredName, RecordCount: &restoredCount, ImportedBy: &userSession.Username,
+	}); metaErr != nil {
+		appLogger.Warnf("Falha ao atualizar metadados após restaurar o snapshot ID %d (Tipo: %s): %v", snapshot.ID, snapshot.FileType, metaErr)
+	}
+
+	s.auditLogService.LogAction(models.AuditLogEntry{
+		Action: fmt.Sprintf("IMPORT_%s_SNAPSHOT_RESTORE", strings.ToUpper(snapshot.FileType)),
+		Description: fmt.Sprintf("Títulos do tipo %s substituídos pelo snapshot #%d (arquivo '%s', criado em %s por %s). %d títulos restaurados.",
+			snapshot.FileType, snapshot.ID, snapshot.OriginalFilename, snapshot.CreatedAt.Local().Format("02/01/2006 15:04:05"), snapshot.CreatedBy, restoredCount),
+		Severity: "WARNING",
+		Metadata: map[string]interface{}{
+			"snapshot_id":       snapshot.ID,
+			"file_type":         snapshot.FileType,
+			"original_filename": snapshot.OriginalFilename,
+			"import_run_id":     snapshot.ImportRunID,
+			"snapshot_created":  snapshot.CreatedAt,
+			"records_restored":  restoredCount,
+			"cnpj_check_failed": cnpjCheck == nil,
+		},
+	}, userSession)
+	return models.ToImportSnapshotPublic(snapshot), restoredCount, nil
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+)
+
+// --- Detecção de Encoding e Delimitador (arquivos de texto) ---
+
+// ImportEncoding identifica o encoding de um arquivo de texto delimitado.
+type ImportEncoding string
+
+const (
+	ImportEncodingUTF8        ImportEncoding = "UTF-8"
+	ImportEncodingWindows1252 ImportEncoding = "WINDOWS-1252" // CP1252: Latin-1 com €, aspas curvas etc. em 0x80–0x9F.
+	ImportEncodingLatin1      ImportEncoding = "ISO-8859-1"
+	ImportEncodingUTF16LE     ImportEncoding = "UTF-16LE"
+	ImportEncodingUTF16BE     ImportEncoding = "UTF-16BE"
+)
+
+// ImportEncodings lista os encodings que o usuário pode informar em `ImportOptions.Encoding`.
+var ImportEncodings = []ImportEncoding{
+	ImportEncodingUTF8, ImportEncodingWindows1252, ImportEncodingLatin1, ImportEncodingUTF16LE, ImportEncodingUTF16BE,
+}
+
+// ImportDelimiters lista os separadores de campo aceitos, na ordem de preferência da detecção.
+var ImportDelimiters = []rune{';', '\t', '|', ','}
+
+// importEncodingDecoders associa cada encoding ao decoder para UTF-8. Os decoders UTF-8 e UTF-16
+// removem o BOM, se presente (no UTF-16, o BOM prevalece sobre a ordem de bytes informada).
+var importEncodingDecoders = map[ImportEncoding]encoding.Encoding{
+	ImportEncodingUTF8:        unicode.UTF8BOM,
+	ImportEncodingWindows1252: charmap.Windows1252,
+	ImportEncodingLatin1:      charmap.ISO8859_1,
+	ImportEncodingUTF16LE:     unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	ImportEncodingUTF16BE:     unicode.UTF16(unicode.BigEndian, unicode.UseBOM),
+}
+
+// ImportDelimiterLabel retorna o nome do separador de campos para exibição (ex: "ponto e vírgula (;)").
+func ImportDelimiterLabel(delimiter rune) string {
+	switch delimiter {
+	case ';':
+		return "ponto e vírgula (;)"
+	case '\t':
+		return "tabulação"
+	case '|':
+		return "barra vertical (|)"
+	case ',':
+		return "vírgula (,)"
+	case 0:
+		return ""
+	default:
+		return fmt.Sprintf("'%c'", delimiter)
+	}
+}
+
+// validateTextFormatOptions verifica o encoding e o delimitador informados pelo usuário (se houver).
+func validateTextFormatOptions(opts ImportOptions) error {
+	if opts.Encoding != "" {
+		if _, ok := importEncodingDecoders[opts.Encoding]; !ok {
+			return fmt.Errorf("%w: encoding '%s' não suportado", appErrors.ErrInvalidInput, opts.Encoding)
+		}
+	}
+	if opts.Delimiter != 0 {
+		for _, delimiter := range ImportDelimiters {
+			if delimiter == opts.Delimiter {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: delimitador %q não suportado", appErrors.ErrInvalidInput, opts.Delimiter)
+	}
+	return nil
+}
+
+// sniffUTF16 identifica UTF-16 sem BOM pela proporção de bytes nulos nas posições pares ou ímpares
+// (texto latino em UTF-16 tem um byte nulo a cada caractere). Retorna "" se não parecer UTF-16.
+func sniffUTF16(sample []byte) ImportEncoding {
+	if len(sample) > 4096 {
+		sample = sample[:4096]
+	}
+	pairs := len(sample) / 2
+	if pairs < 8 {
+		return ""
+	}
+	var evenZeros, oddZeros int
+	for i := 0; i+1 < len(sample); i += 2 {
+		if sample[i] == 0 {
+			evenZeros++
+		}
+		if sample[i+1] == 0 {
+			oddZeros++
+		}
+	}
+	switch {
+	case oddZeros*10 >= pairs*7 && evenZeros*10 < pairs:
+		return ImportEncodingUTF16LE
+	case evenZeros*10 >= pairs*7 && oddZeros*10 < pairs:
+		return ImportEncodingUTF16BE
+	}
+	return ""
+}
+
+// hasUndefinedWindows1252Bytes indica se a amostra tem bytes que não existem no CP1252. Sem eles, o
+// CP1252 é preferido ao ISO-8859-1: os dois só diferem em 0x80–0x9F, onde o ISO-8859-1 tem caracteres
+// de controle e o CP1252 tem €, aspas curvas, travessões etc.
+func hasUndefinedWindows1252Bytes(sample []byte) bool {
+	for _, b := range sample {
+		switch b {
+		case 0x81, 0x8D, 0x8F, 0x90, 0x9D:
+			return true
+		}
+	}
+	return false
+}
+
+// delimiterSniffMaxRecords é o número máximo de registros da amostra usados para detectar o delimitador.
+const delimiterSniffMaxRecords = 50
+
+// sniffDelimiter detecta o separador de campos a partir do início do conteúdo já decodificado.
+// Vence o candidato que divide o cabeçalho no maior número de campos; em caso de empate, o que
+// produz mais registros seguintes com o mesmo número de campos do cabeçalho. Campos entre aspas
+// (inclusive com quebras de linha) são respeitados. Retorna o delimitador e se ele foi de fato
+// detectado (false quando nenhum candidato divide o cabeçalho, caso em que ';' é usado).
+func sniffDelimiter(r *bufio.Reader) (rune, bool, error) {
+	sample, err := r.Peek(encodingSniffSize)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return 0, false, err
+	}
+	// Descarta o último registro, provavelmente cortado pela janela de detecção.
+	if len(sample) == encodingSniffSize {
+		if lastNewline := bytes.LastIndexByte(sample, '\n'); lastNewline > 0 {
+			sample = sample[:lastNewline+1]
+		}
+	}
+
+	bestDelimiter, bestFields, bestConsistent := ImportDelimiters[0], 1, -1
+	for _, delimiter := range ImportDelimiters {
+		csvReader := csv.NewReader(bytes.NewReader(sample))
+		csvReader.Comma = delimiter
+		csvReader.LazyQuotes = true
+		csvReader.FieldsPerRecord = -1
+
+		header, errHeader := csvReader.Read()
+		if errHeader != nil || len(header) < 2 {
+			continue
+		}
+		consistent := 0
+		for i := 0; i < delimiterSniffMaxRecords; i++ {
+			record, errRead := csvReader.Read()
+			if errRead != nil {
+				break
+			}
+			if len(record) == len(header) {
+				consistent++
+			}
+		}
+		if len(header) > bestFields || (len(header) == bestFields && consistent > bestConsistent) {
+			bestDelimiter, bestFields, bestConsistent = delimiter, len(header), consistent
+		}
+	}
+	return bestDelimiter, bestConsistent >= 0, nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+)
+
+func TestSniffDelimiter(t *testing.T) {
+	cases := []struct {
+		name         string
+		content      string
+		want         rune
+		wantDetected bool
+	}{
+		{"ponto e vírgula", "PESSOA;CNPJ/CPF;VALOR\nA;1;10,00\nB;2;20,00\n", ';', true},
+		{"tabulação", "PESSOA\tCNPJ/CPF\tVALOR\nA\t1\t10,00\n", '\t', true},
+		{"barra vertical", "PESSOA|CNPJ/CPF|VALOR\nA|1|10,00\n", '|', true},
+		{"vírgula", "PESSOA,CNPJ/CPF,VALOR\nA,1,10.00\n", ',', true},
+		{"vírgula decimal não vence o ponto e vírgula", "PESSOA;VALOR\nA;10,00\nB;20,00\n", ';', true},
+		{"mais campos no cabeçalho vence", "A;B,C,D\n1;2,3,4\n", ',', true},
+		{
+			name:         "empate decidido pelos registros consistentes",
+			content:      "A|B;C\n1|2\n3|4\n5;6\n",
+			want:         '|',
+			wantDetected: true,
+		},
+		{"delimitador entre aspas é ignorado", "\"NOME;COMPLETO\",VALOR\n\"A;B\",1\n", ',', true},
+		{"quebra de linha entre aspas", "NOME;OBS\nA;\"linha 1\nlinha 2\"\nB;ok\n", ';', true},
+		{"uma coluna só", "PESSOA\nA\nB\n", ';', false},
+		{"vazio", "", ';', false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, detected, err := sniffDelimiter(bufio.NewReaderSize(strings.NewReader(tc.content), encodingSniffSize))
+			if err != nil {
+				t.Fatalf("sniffDelimiter: %v", err)
+			}
+			if got != tc.want || detected != tc.wantDetected {
+				t.Errorf("sniffDelimiter = %q (detectado %t), esperado %q (detectado %t)", got, detected, tc.want, tc.wantDetected)
+			}
+		})
+	}
+}
+
+// encodeUTF16 codifica texto ASCII/Latin-1 em UTF-16 na ordem de bytes indicada.
+func encodeUTF16(text string, bigEndian bool) []byte {
+	out := make([]byte, 0, len(text)*2)
+	for _, r := range text {
+		hi, lo := byte(r>>8), byte(r)
+		if bigEndian {
+			out = append(out, hi, lo)
+		} else {
+			out = append(out, lo, hi)
+		}
+	}
+	return out
+}
+
+func TestDetectAndDecode(t *testing.T) {
+	const text = "PESSOA;OBSERVAÇÃO\nJoão;Ação\n"
+	// Windows-1252: "Ç" = 0xC7, "Ã" = 0xC3, "ã" = 0xE3, "ç" = 0xE7 e "€" = 0x80.
+	cp1252 := []byte("PESSOA;OBSERVA\xC7\xC3O\nJo\xE3o;A\xE7\xE3o \x80\n")
+	// 0x81 não existe no Windows-1252: o conteúdo é decodificado como Latin-1.
+	latin1 := []byte("PESSOA;VALOR\nJo\xE3o;\x81\n")
+
+	cases := []struct {
+		name     string
+		content  []byte
+		override ImportEncoding
+		wantDesc string
+		wantText string
+	}{
+		{"UTF-8 sem BOM", []byte(text), "", "UTF-8", text},
+		{"UTF-8 com BOM", append([]byte{0xEF, 0xBB, 0xBF}, text...), "", "UTF-8 (com BOM)", text},
+		{"UTF-16LE com BOM", append([]byte{0xFF, 0xFE}, encodeUTF16(text, false)...), "", "UTF-16LE (com BOM)", text},
+		{"UTF-16BE com BOM", append([]byte{0xFE, 0xFF}, encodeUTF16(text, true)...), "", "UTF-16BE (com BOM)", text},
+		{"UTF-16LE sem BOM", encodeUTF16(text, false), "", "UTF-16LE (sem BOM)", text},
+		{"UTF-16BE sem BOM", encodeUTF16(text, true), "", "UTF-16BE (sem BOM)", text},
+		{"Windows-1252", cp1252, "", "Windows-1252 (convertido para UTF-8)", "PESSOA;OBSERVAÇÃO\nJoão;Ação €\n"},
+		{"Latin-1", latin1, "", "Latin-1 (convertido para UTF-8)", "PESSOA;VALOR\nJoão;\u0081\n"},
+		{"encoding informado prevalece", cp1252, ImportEncodingLatin1, "ISO-8859-1 (informado)", "PESSOA;OBSERVAÇÃO\nJoão;Ação \u0080\n"},
+		{"UTF-8 informado remove o BOM", append([]byte{0xEF, 0xBB, 0xBF}, text...), ImportEncodingUTF8, "UTF-8 (informado)", text},
+		{"vazio", nil, "", "Vazio", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reader, desc, err := (&importServiceImpl{}).detectAndDecode(bytes.NewReader(tc.content), tc.override)
+			if err != nil {
+				t.Fatalf("detectAndDecode: %v", err)
+			}
+			if desc != tc.wantDesc {
+				t.Errorf("encoding = %q, esperado %q", desc, tc.wantDesc)
+			}
+			decoded, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("leitura do conteúdo decodificado: %v", err)
+			}
+			if string(decoded) != tc.wantText {
+				t.Errorf("conteúdo = %q, esperado %q", decoded, tc.wantText)
+			}
+		})
+	}
+}
+
+func TestDetectAndDecodeInvalidUTF8AfterSniffWindow(t *testing.T) {
+	// A janela de detecção é UTF-8 válido; a sequência inválida só aparece depois dela.
+	valid := strings.Repeat("Ação;123\n", encodingSniffSize/8)
+	content := append([]byte(valid), []byte("Jo\xE3o;1\n")...)
+	invalidAt := int64(len(valid) + 2)
+
+	for _, override := range []ImportEncoding{"", ImportEncodingUTF8} {
+		reader, desc, err := (&importServiceImpl{}).detectAndDecode(bytes.NewReader(content), override)
+		if err != nil {
+			t.Fatalf("detectAndDecode(%q): %v", override, err)
+		}
+		if !strings.HasPrefix(desc, "UTF-8") {
+			t.Fatalf("encoding = %q, esperado UTF-8", desc)
+		}
+		decoded, err := io.ReadAll(reader)
+		if !errors.Is(err, appErrors.ErrInvalidInput) {
+			t.Fatalf("leitura com encoding %q: erro %v, esperado %v", override, err, appErrors.ErrInvalidInput)
+		}
+		if int64(len(decoded)) > invalidAt {
+			t.Errorf("leitura com encoding %q entregou %d bytes, além da posição inválida %d", override, len(decoded), invalidAt)
+		}
+	}
+}
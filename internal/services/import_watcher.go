@@ -0,0 +1,317 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/config"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+)
+
+// Subpastas da pasta monitorada para onde os arquivos são movidos após o processamento.
+const (
+	importWatchDoneDir   = "done"
+	importWatchFailedDir = "failed"
+)
+
+// importWatcherUsername identifica a importação automática no histórico e na auditoria.
+const importWatcherUsername = "sistema.importacao"
+
+// importWatchAllowedExts são as extensões de arquivo processadas na pasta monitorada.
+var importWatchAllowedExts = []string{".txt", ".csv", ".xlsx"}
+
+// ImportWatcher monitora pastas configuradas (uma por tipo de arquivo) e importa automaticamente os
+// arquivos que aparecem nelas, como se o usuário tivesse clicado em Importar.
+type ImportWatcher interface {
+	// Start inicia a goroutine de monitoramento. Não faz nada se nenhuma pasta estiver configurada.
+	Start()
+	// Shutdown para o monitoramento, aguardando a importação em andamento terminar.
+	Shutdown()
+}
+
+// importWatchDir associa uma pasta monitorada ao tipo de arquivo importado a partir dela.
+type importWatchDir struct {
+	fileType FileType
+	path     string
+}
+
+// importWatcherImpl é a implementação de ImportWatcher. As pastas são varridas periodicamente
+// (polling), o que funciona também em compartilhamentos de rede, onde notificações do sistema de
+// arquivos não são confiáveis.
+type importWatcherImpl struct {
+	cfg             *core.Config
+	importService   ImportService
+	auditLogService AuditLogService
+
+	dirs    []importWatchDir
+	session *auth.SessionData // Identidade de sistema usada nas importações.
+
+	shutdownChan chan struct{}
+	wg           sync.WaitGroup
+	started      bool
+}
+
+// NewImportWatcher cria uma nova instância de ImportWatcher a partir das pastas configuradas.
+func NewImportWatcher(
+	cfg *core.Config,
+	importSvc ImportService,
+	auditLog AuditLogService,
+) ImportWatcher {
+	if cfg == nil || importSvc == nil || auditLog == nil {
+		appLogger.Fatalf("Dependências nulas fornecidas para NewImportWatcher (cfg, importSvc, auditLog)")
+	}
+
+	w := &importWatcherImpl{
+		cfg:             cfg,
+		importService:   importSvc,
+		auditLogService: auditLog,
+		shutdownChan:    make(chan struct{}),
+	}
+	for _, dir := range []importWatchDir{
+		{fileType: FileTypeDireitos, path: cfg.ImportWatchDirDireitos},
+		{fileType: FileTypeObrigacoes, path: cfg.ImportWatchDirObrigacoes},
+	} {
+		if trimmed := strings.TrimSpace(dir.path); trimmed != "" {
+			dir.path = trimmed
+			w.dirs = append(w.dirs, dir)
+		}
+	}
+
+	now := time.Now().UTC()
+	w.session = &auth.SessionData{
+		ID:           "system-import-watcher",
+		UserID:       uuid.Nil,
+		Username:     importWatcherUsername,
+		Roles:        []string{auth.RoleImportWatcher},
+		IPAddress:    "local",
+		UserAgent:    "ImportWatcher",
+		CreatedAt:    now,
+		LastActivity: now,
+	}
+	return w
+}
+
+// Start inicia a goroutine de monitoramento das pastas.
+func (w *importWatcherImpl) Start() {
+	if !w.cfg.ImportWatchEnabled {
+		appLogger.Info("Importação automática por pasta monitorada desabilitada.")
+		return
+	}
+	if len(w.dirs) == 0 {
+		appLogger.Warn("Importação automática habilitada, mas nenhuma pasta monitorada foi configurada.")
+		return
+	}
+	for _, dir := range w.dirs {
+		for _, sub := range []string{importWatchDoneDir, importWatchFailedDir} {
+			if err := os.MkdirAll(filepath.Join(dir.path, sub), os.ModePerm); err != nil {
+				appLogger.Errorf("Não foi possível criar a subpasta '%s' da pasta monitorada '%s': %v", sub, dir.path, err)
+			}
+		}
+	}
+
+	w.started = true
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.cfg.ImportWatchInterval)
+		defer ticker.Stop()
+
+		appLogger.Infof("Goroutine de importação automática iniciada (%d pastas, intervalo: %v).", len(w.dirs), w.cfg.ImportWatchInterval)
+		w.scanAll()
+		for {
+			select {
+			case <-ticker.C:
+				w.scanAll()
+			case <-w.shutdownChan:
+				appLogger.Info("Goroutine de importação automática recebendo sinal de shutdown.")
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown para a goroutine de monitoramento.
+func (w *importWatcherImpl) Shutdown() {
+	if !w.started {
+		return
+	}
+	close(w.shutdownChan)
+	w.wg.Wait()
+	w.started = false
+	appLogger.Info("Goroutine de importação automática finalizada.")
+}
+
+// stopping indica se o shutdown foi solicitado (para interromper a varredura entre arquivos).
+func (w *importWatcherImpl) stopping() bool {
+	select {
+	case <-w.shutdownChan:
+		return true
+	default:
+		return false
+	}
+}
+
+// scanAll varre todas as pastas monitoradas.
+func (w *importWatcherImpl) scanAll() {
+	for _, dir := range w.dirs {
+		if w.stopping() {
+			return
+		}
+		w.scanDir(dir)
+	}
+}
+
+// scanDir importa, em ordem de modificação, os arquivos prontos de uma pasta monitorada.
+func (w *importWatcherImpl) scanDir(dir importWatchDir) {
+	entries, err := os.ReadDir(dir.path)
+	if err != nil {
+		appLogger.Errorf("Erro ao ler a pasta monitorada '%s' (%s): %v", dir.path, dir.fileType, err)
+		return
+	}
+
+	type readyFile struct {
+		path    string
+		modTime time.Time
+	}
+	var ready []readyFile
+	for _, entry := range entries {
+		if entry.IsDir() || !isImportWatchCandidate(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // Arquivo removido durante a varredura.
+		}
+		// O ERP pode ainda estar gravando o arquivo; só é processado após ficar sem alterações.
+		if time.Since(info.ModTime()) < w.cfg.ImportWatchSettleTime {
+			continue
+		}
+		ready = append(ready, readyFile{path: filepath.Join(dir.path, entry.Name()), modTime: info.ModTime()})
+	}
+	sort.Slice(ready, func(i, j int) bool { return ready[i].modTime.Before(ready[j].modTime) })
+
+	for _, file := range ready {
+		if w.stopping() {
+			return
+		}
+		w.processFile(dir, file.path)
+	}
+}
+
+// isImportWatchCandidate indica se o arquivo deve ser processado (ignora arquivos ocultos,
+// temporários e de extensões não suportadas).
+func isImportWatchCandidate(name string) bool {
+	if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "~$") {
+		return false
+	}
+	return containsString(importWatchAllowedExts, strings.ToLower(filepath.Ext(name)))
+}
+
+// processFile importa um arquivo da pasta monitorada e o move para `done/` ou `failed/`.
+// Um arquivo cujo conteúdo já foi importado não é importado de novo: a importação compara o SHA-256
+// dos bytes que ela própria leu com o histórico (`ImportOptions.SkipDuplicate`) e é desfeita; o arquivo
+// vai para `failed/` e a duplicidade é registrada na auditoria.
+func (w *importWatcherImpl) processFile(dir importWatchDir, filePath string) {
+	fileName := filepath.Base(filePath)
+	metadata := map[string]interface{}{
+		"file_type": string(dir.fileType),
+		"file_name": fileName,
+		"watch_dir": dir.path,
+	}
+
+	appLogger.Infof("Importação automática do arquivo '%s' (%s) iniciada.", fileName, dir.fileType)
+	opts := ImportOptions{Mode: ImportModeReplace, SkipDuplicate: true}
+	result, importErr := w.importService.ImportFileContext(context.Background(), filePath, dir.fileType, opts, w.session)
+	var lockedErr *models.ImportLockedError
+	if errors.As(importErr, &lockedErr) {
+		appLogger.Infof("Importação automática do arquivo '%s' adiada: %v.", fileName, importErr)
+		return // O arquivo fica na pasta e é importado numa próxima varredura.
+	}
+	var duplicateErr *models.DuplicateImportError
+	if errors.As(importErr, &duplicateErr) {
+		dest, moveErr := moveImportWatchFile(filePath, importWatchFailedDir)
+		metadata["destination"] = dest
+		metadata["file_sha256"] = duplicateErr.FileSHA256
+		metadata["previous_import_run_id"] = duplicateErr.PreviousRunID
+		description := fmt.Sprintf("Arquivo '%s' (%s) da pasta monitorada ignorado: %v.", fileName, dir.fileType, importErr)
+		if moveErr != nil {
+			description += fmt.Sprintf(" Falha ao mover para '%s': %v.", importWatchFailedDir, moveErr)
+		}
+		appLogger.Warn(description)
+		w.logAudit("IMPORT_WATCH_DUPLICATE", description, "WARNING", metadata)
+		return
+	}
+	if result != nil {
+		for _, key := range []string{"import_run_id", "file_sha256", "records_processed", "records_skipped_parsing", "records_skipped_repo", "quarantine_file"} {
+			if val, ok := result[key]; ok {
+				metadata[key] = val
+			}
+		}
+	}
+
+	destDir, action, severity := importWatchDoneDir, "IMPORT_WATCH_SUCCESS", "INFO"
+	if importErr != nil {
+		destDir, action, severity = importWatchFailedDir, "IMPORT_WATCH_FAILURE", "ERROR"
+		metadata["error"] = importErr.Error()
+	}
+	dest, moveErr := moveImportWatchFile(filePath, destDir)
+	metadata["destination"] = dest
+
+	description := fmt.Sprintf("Importação automática do arquivo '%s' (%s) concluída; arquivo movido para '%s'.", fileName, dir.fileType, destDir)
+	if importErr != nil {
+		description = fmt.Sprintf("Importação automática do arquivo '%s' (%s) falhou: %v. Arquivo movido para '%s'.", fileName, dir.fileType, importErr, destDir)
+	}
+	if moveErr != nil {
+		description += fmt.Sprintf(" Falha ao mover o arquivo: %v.", moveErr)
+		severity = "ERROR"
+		appLogger.Errorf("Erro ao mover o arquivo '%s' da pasta monitorada para '%s': %v", filePath, destDir, moveErr)
+	}
+	if importErr != nil {
+		appLogger.Errorf("Importação automática do arquivo '%s' (%s) falhou: %v", fileName, dir.fileType, importErr)
+	} else {
+		appLogger.Infof("Importação automática do arquivo '%s' (%s) concluída.", fileName, dir.fileType)
+	}
+	w.logAudit(action, description, severity, metadata)
+}
+
+// logAudit registra uma entrada de auditoria da importação automática.
+func (w *importWatcherImpl) logAudit(action, description, severity string, metadata map[string]interface{}) {
+	if err := w.auditLogService.LogAction(models.AuditLogEntry{
+		Action:      action,
+		Description: description,
+		Severity:    severity,
+		Metadata:    metadata,
+	}, w.session); err != nil {
+		appLogger.Warnf("Falha ao registrar auditoria da importação automática (%s): %v", action, err)
+	}
+}
+
+// moveImportWatchFile move o arquivo para a subpasta `subDir` da sua pasta. Se já existir um arquivo
+// com o mesmo nome no destino, o horário é acrescentado ao nome. Retorna o caminho de destino.
+func moveImportWatchFile(filePath, subDir string) (string, error) {
+	destDir := filepath.Join(filepath.Dir(filePath), subDir)
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return "", err
+	}
+	fileName := filepath.Base(filePath)
+	dest := filepath.Join(destDir, fileName)
+	if _, err := os.Stat(dest); err == nil {
+		ext := filepath.Ext(fileName)
+		dest = filepath.Join(destDir, fmt.Sprintf("%s_%s%s", strings.TrimSuffix(fileName, ext), time.Now().Format("20060102_150405"), ext))
+	}
+	if err := os.Rename(filePath, dest); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
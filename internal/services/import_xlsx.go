@@ -0,0 +1,319 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/xuri/excelize/v2"
+
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+)
+
+// isXLSXFile indica se o arquivo deve ser lido como planilha Excel (pela extensão).
+func isXLSXFile(filePath string) bool {
+	return strings.EqualFold(filepath.Ext(filePath), ".xlsx")
+}
+
+// xlsxRowSource lê as linhas de uma planilha XLSX em streaming (via `excelize.Rows`),
+// convertendo as células para o mesmo formato de texto esperado do arquivo delimitado:
+//   - Datas (colunas "DTA...") armazenadas como número serial do Excel viram "DD/MM/AAAA"
+//     (ou "DD/MM/AAAA HH:MM:SS" em DTAALTERAÇÃO, se houver hora).
+//   - Números em notação científica (ex: CNPJ "1.2345678000190E+13") são expandidos.
+//   - CNPJ/CPF numérico que perdeu zeros à esquerda é completado.
+//
+// O tipo de cada coluna é definido por `classifyColumns`, após a validação do cabeçalho.
+// Linhas totalmente vazias são ignoradas, e as células vazias no fim da linha (que o Excel não
+// grava) são completadas até o número de colunas do cabeçalho.
+type xlsxRowSource struct {
+	workbook   *excelize.File
+	rows       *excelize.Rows
+	fileName   string
+	sheetName  string
+	headerCols int  // Número de colunas do cabeçalho da planilha.
+	date1904   bool // Pasta de trabalho usa o sistema de datas 1904 (comum em arquivos gerados no Mac).
+
+	dateKinds   map[int]importDateKind // Índices das colunas de data (e se guardam hora).
+	numericCols map[int]bool           // Índices das colunas numéricas (valores, NROEMPRESA, CNPJ/CPF).
+	cnpjCol     int                    // Índice da coluna CNPJ/CPF (-1 se não houver).
+
+	rowNum     int  // Número da linha atual na planilha (1 = primeira linha).
+	headerRead bool // O cabeçalho (primeira linha não vazia) já foi retornado.
+
+	// contentSHA256 e contentSize descrevem os bytes do arquivo, lido por inteiro na abertura.
+	contentSHA256 string
+	contentSize   int64
+
+	// Cabeçalho lido durante a seleção automática da planilha, devolvido na primeira chamada a `Read`.
+	pendingHeader     []string
+	pendingHeaderLine int
+}
+
+// openXLSXRowSource abre a pasta de trabalho e posiciona a leitura na planilha `sheetName`.
+// Se `sheetName` for vazio e houver mais de uma planilha, é usada a primeira (começando pela
+// planilha ativa) cujo cabeçalho seja aceito por `headerMatches`.
+func openXLSXRowSource(filePath string, sheetName string, headerMatches func(headerRow []string) bool) (*xlsxRowSource, error) {
+	fileName := filepath.Base(filePath)
+	file, err := os.Open(filePath)
+	if err != nil {
+		appLogger.Errorf("Erro ao abrir arquivo de importação '%s': %v", filePath, err)
+		return nil, fmt.Errorf("%w: falha ao ler arquivo '%s'", appErrors.ErrResourceLoading, fileName)
+	}
+	defer file.Close()
+	// A planilha é lida uma única vez: o hash é calculado sobre os mesmos bytes abertos pelo excelize.
+	hasher := sha256.New()
+	counter := &countingReader{r: io.TeeReader(file, hasher)}
+	workbook, err := excelize.OpenReader(counter)
+	if err != nil {
+		appLogger.Errorf("Erro ao abrir planilha de importação '%s': %v", filePath, err)
+		return nil, fmt.Errorf("%w: arquivo '%s' não é uma planilha XLSX válida", appErrors.ErrValidation, fileName)
+	}
+
+	date1904 := false
+	if props, errProps := workbook.GetWorkbookProps(); errProps == nil && props.Date1904 != nil {
+		date1904 = *props.Date1904
+	}
+
+	src := &xlsxRowSource{
+		workbook:      workbook,
+		fileName:      fileName,
+		date1904:      date1904,
+		cnpjCol:       -1,
+		contentSHA256: hex.EncodeToString(hasher.Sum(nil)),
+		contentSize:   counter.n,
+	}
+
+	sheets := workbook.GetSheetList()
+	if sheetName != "" {
+		if !containsString(sheets, sheetName) {
+			workbook.Close()
+			return nil, fmt.Errorf("%w: planilha '%s' não encontrada no arquivo '%s' (planilhas disponíveis: %s)",
+				appErrors.ErrValidation, sheetName, fileName, strings.Join(sheets, ", "))
+		}
+		if err := src.startSheet(sheetName); err != nil {
+			workbook.Close()
+			return nil, err
+		}
+		return src, nil
+	}
+
+	// Seleção automática: planilha ativa primeiro, depois as demais na ordem do arquivo.
+	candidates := make([]string, 0, len(sheets))
+	if active := workbook.GetSheetName(workbook.GetActiveSheetIndex()); active != "" {
+		candidates = append(candidates, active)
+	}
+	for _, name := range sheets {
+		if !containsString(candidates, name) {
+			candidates = append(candidates, name)
+		}
+	}
+	if len(candidates) == 0 {
+		workbook.Close()
+		return nil, fmt.Errorf("%w: arquivo '%s' não contém planilhas", appErrors.ErrValidation, fileName)
+	}
+	if len(candidates) == 1 { // Única planilha: o cabeçalho é validado normalmente pelo stream.
+		if err := src.startSheet(candidates[0]); err != nil {
+			workbook.Close()
+			return nil, err
+		}
+		return src, nil
+	}
+
+	for _, candidate := range candidates {
+		if err := src.startSheet(candidate); err != nil {
+			workbook.Close()
+			return nil, err
+		}
+		header, headerLine, errRead := src.Read()
+		if errRead == nil && headerMatches(header) {
+			src.pendingHeader = append([]string(nil), header...)
+			src.pendingHeaderLine = headerLine
+			appLogger.Infof("Planilha '%s' selecionada automaticamente no arquivo '%s'.", candidate, fileName)
+			return src, nil
+		}
+		src.rows.Close()
+	}
+	workbook.Close()
+	return nil, fmt.Errorf("%w: nenhuma planilha do arquivo '%s' tem o cabeçalho esperado (planilhas: %s). Selecione a planilha manualmente",
+		appErrors.ErrValidation, fileName, strings.Join(sheets, ", "))
+}
+
+// startSheet (re)inicia a leitura na planilha informada.
+func (src *xlsxRowSource) startSheet(sheetName string) error {
+	rows, err := src.workbook.Rows(sheetName)
+	if err != nil {
+		appLogger.Errorf("Erro ao ler planilha '%s' do arquivo '%s': %v", sheetName, src.fileName, err)
+		return fmt.Errorf("%w: falha ao ler planilha '%s' do arquivo '%s'", appErrors.ErrValidation, sheetName, src.fileName)
+	}
+	src.rows = rows
+	src.sheetName = sheetName
+	src.rowNum = 0
+	src.headerRead = false
+	src.headerCols = 0
+	return nil
+}
+
+// classifyColumns define o tipo de cada coluna da planilha a partir do nome da coluna esperada
+// correspondente (`fields[i]` para a coluna i; "" para colunas ignoradas).
+func (src *xlsxRowSource) classifyColumns(fields []string) {
+	src.dateKinds = make(map[int]importDateKind)
+	src.numericCols = make(map[int]bool)
+	src.cnpjCol = -1
+	for i, field := range fields {
+		upper := strings.ToUpper(strings.TrimSpace(field))
+		dateKind, isValue := classifyImportField(upper)
+		switch {
+		case dateKind != importDateNone:
+			src.dateKinds[i] = dateKind
+		case isValue, upper == "NROEMPRESA":
+			src.numericCols[i] = true
+		case upper == "CNPJ/CPF", upper == "CNPJ":
+			src.numericCols[i] = true
+			src.cnpjCol = i
+		}
+	}
+}
+
+// Read retorna a próxima linha não vazia da planilha, com as células já convertidas.
+func (src *xlsxRowSource) Read() ([]string, int, error) {
+	if src.pendingHeader != nil {
+		header, line := src.pendingHeader, src.pendingHeaderLine
+		src.pendingHeader = nil
+		return header, line, nil
+	}
+	for src.rows.Next() {
+		src.rowNum++
+		// RawCellValue evita a formatação de exibição do Excel (ex: datas "m/d/yy"),
+		// entregando o número serial das datas e os números sem separadores.
+		cells, err := src.rows.Columns(excelize.Options{RawCellValue: true})
+		if err != nil {
+			appLogger.Errorf("Erro ao ler linha %d da planilha '%s' do arquivo '%s': %v", src.rowNum, src.sheetName, src.fileName, err)
+			return nil, src.rowNum, fmt.Errorf("%w: arquivo '%s' mal formatado (erro na linha %d da planilha '%s'): %v",
+				appErrors.ErrValidation, src.fileName, src.rowNum, src.sheetName, err)
+		}
+		cells = trimTrailingEmptyCells(cells)
+		if len(cells) == 0 {
+			continue // Linha vazia.
+		}
+		if !src.headerRead {
+			src.headerRead = true
+			src.headerCols = len(cells)
+			return cells, src.rowNum, nil
+		}
+		for len(cells) < src.headerCols {
+			cells = append(cells, "")
+		}
+		if len(cells) == src.headerCols {
+			for i := range cells {
+				cells[i] = src.convertCell(i, cells[i])
+			}
+		}
+		return cells, src.rowNum, nil
+	}
+	if err := src.rows.Error(); err != nil {
+		appLogger.Errorf("Erro ao ler planilha '%s' do arquivo '%s': %v", src.sheetName, src.fileName, err)
+		return nil, src.rowNum, fmt.Errorf("%w: falha ao ler planilha '%s' do arquivo '%s'", appErrors.ErrValidation, src.sheetName, src.fileName)
+	}
+	return nil, 0, io.EOF
+}
+
+// convertCell converte o valor bruto de uma célula para o formato de texto do arquivo delimitado.
+func (src *xlsxRowSource) convertCell(col int, raw string) string {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return value
+	}
+	if dateKind := src.dateKinds[col]; dateKind != importDateNone {
+		serial, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return value // Data gravada como texto; o parsing normal se encarrega dela.
+		}
+		t, err := excelize.ExcelDateToTime(serial, src.date1904)
+		if err != nil {
+			return value
+		}
+		if dateKind == importDateOnly || (t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0) {
+			return t.Format("02/01/2006")
+		}
+		return t.Format("02/01/2006 15:04:05")
+	}
+	if src.numericCols[col] && strings.ContainsAny(value, "eE") {
+		if d, err := decimal.NewFromString(value); err == nil {
+			value = d.String()
+		}
+	}
+	if col == src.cnpjCol && isAllDigits(value) {
+		// Células numéricas perdem os zeros à esquerda: 12 ou 13 dígitos só podem ser um CNPJ,
+		// 9 ou 10 dígitos, um CPF.
+		switch len(value) {
+		case 12, 13:
+			value = strings.Repeat("0", 14-len(value)) + value
+		case 9, 10:
+			value = strings.Repeat("0", 11-len(value)) + value
+		}
+	}
+	return value
+}
+
+// ContentHash retorna o SHA-256 do arquivo, calculado na abertura.
+func (src *xlsxRowSource) ContentHash() (string, int64, bool) {
+	return src.contentSHA256, src.contentSize, true
+}
+
+// Close fecha o iterador de linhas e a pasta de trabalho (removendo arquivos temporários do excelize).
+func (src *xlsxRowSource) Close() error {
+	if src.rows != nil {
+		src.rows.Close()
+	}
+	return src.workbook.Close()
+}
+
+// listXLSXSheets retorna os nomes das planilhas de um arquivo XLSX.
+func listXLSXSheets(filePath string) ([]string, error) {
+	workbook, err := excelize.OpenFile(filePath)
+	if err != nil {
+		appLogger.Errorf("Erro ao abrir planilha '%s' para listar as abas: %v", filePath, err)
+		return nil, fmt.Errorf("%w: arquivo '%s' não é uma planilha XLSX válida", appErrors.ErrValidation, filepath.Base(filePath))
+	}
+	defer workbook.Close()
+	return workbook.GetSheetList(), nil
+}
+
+// trimTrailingEmptyCells remove as células vazias do fim da linha.
+func trimTrailingEmptyCells(cells []string) []string {
+	end := len(cells)
+	for end > 0 && strings.TrimSpace(cells[end-1]) == "" {
+		end--
+	}
+	return cells[:end]
+}
+
+// containsString indica se `list` contém `value`.
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// isAllDigits indica se a string é não vazia e contém apenas dígitos.
+func isAllDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,424 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/config"
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/repositories"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/utils"
+)
+
+// NettingService define a interface do encontro de contas entre títulos de direitos (a receber)
+// e de obrigações (a pagar) de parceiros que são, ao mesmo tempo, clientes e fornecedores.
+type NettingService interface {
+	// GenerateNettingReport associa os títulos em aberto pelo CNPJ ou pela raiz do CNPJ e calcula
+	// a posição líquida por parceiro e por rede, com as compensações propostas por vencimento.
+	// Apenas parceiros com saldo a receber e a pagar são incluídos. Exige `auth.PermTituloView`.
+	GenerateNettingReport(mode models.NettingMatchMode, userSession *auth.SessionData) (*models.NettingReport, error)
+
+	// ExportNettingStatement exporta para XLSX o extrato de encontro de contas de um parceiro
+	// (resumo, títulos de cada lado e compensações propostas), para envio ao parceiro.
+	// `partnerKey` é o CNPJ/CPF ou a raiz do CNPJ, conforme `mode`. Exige `auth.PermTituloView` e `auth.PermExportData`.
+	ExportNettingStatement(mode models.NettingMatchMode, partnerKey string, userSession *auth.SessionData) (*models.NettingPartner, string, error)
+}
+
+// nettingServiceImpl é a implementação de NettingService.
+type nettingServiceImpl struct {
+	cfg             *core.Config
+	direitoRepo     repositories.TituloDireitoRepository
+	obrigacaoRepo   repositories.TituloObrigacaoRepository
+	networkRepo     repositories.NetworkRepository
+	auditLogService AuditLogService
+	permManager     *auth.PermissionManager
+}
+
+// NewNettingService cria uma nova instância de NettingService.
+func NewNettingService(
+	cfg *core.Config,
+	direitoRepo repositories.TituloDireitoRepository,
+	obrigacaoRepo repositories.TituloObrigacaoRepository,
+	networkRepo repositories.NetworkRepository,
+	auditLogService AuditLogService,
+	permManager *auth.PermissionManager,
+) NettingService {
+	if cfg == nil || direitoRepo == nil || obrigacaoRepo == nil || networkRepo == nil || auditLogService == nil || permManager == nil {
+		appLogger.Fatalf("Dependências nulas fornecidas para NewNettingService")
+	}
+	return &nettingServiceImpl{
+		cfg:             cfg,
+		direitoRepo:     direitoRepo,
+		obrigacaoRepo:   obrigacaoRepo,
+		networkRepo:     networkRepo,
+		auditLogService: auditLogService,
+		permManager:     permManager,
+	}
+}
+
+// nettingPartnerState acumula os títulos de um parceiro durante a leitura das tabelas.
+type nettingPartnerState struct {
+	partner *models.NettingPartner
+	cnpjs   map[string]struct{}
+}
+
+// add inclui um título em aberto no lado indicado (direitos ou obrigações).
+func (st *nettingPartnerState) add(b *models.TituloBalance, open decimal.Decimal, direito bool) {
+	title := models.NettingTitle{
+		ID:             b.ID,
+		CNPJCPF:        b.CNPJCPF,
+		Titulo:         b.Titulo,
+		NumeroEmpresa:  b.NumeroEmpresa,
+		DataVencimento: b.DataVencimento,
+		OpenBalance:    open,
+	}
+	p := st.partner
+	if direito {
+		p.Direitos = append(p.Direitos, title)
+		p.Receivable = p.Receivable.Add(open)
+	} else {
+		p.Obrigacoes = append(p.Obrigacoes, title)
+		p.Payable = p.Payable.Add(open)
+	}
+	st.cnpjs[b.CNPJCPF] = struct{}{}
+	if p.Name == "" && b.Pessoa != nil {
+		p.Name = strings.TrimSpace(*b.Pessoa)
+	}
+	if p.NetworkID == nil && b.NetworkID != nil {
+		id := *b.NetworkID
+		p.NetworkID = &id
+	}
+}
+
+// collectPartners lê os títulos em aberto e monta os parceiros com saldo nos dois lados.
+// As obrigações são lidas primeiro (em geral são menos numerosas) e apenas os direitos de
+// parceiros com obrigações são mantidos em memória. Se `onlyKey` não for vazio, considera só esse parceiro.
+func (s *nettingServiceImpl) collectPartners(mode models.NettingMatchMode, onlyKey string) ([]*models.NettingPartner, int, error) {
+	states := make(map[string]*nettingPartnerState)
+	invalid := 0
+
+	visit := func(direito bool) func(batch []models.TituloBalance) error {
+		return func(batch []models.TituloBalance) error {
+			for i := range batch {
+				b := &batch[i]
+				key := mode.PartnerKey(b.CNPJCPF)
+				if key == "" || (onlyKey != "" && key != onlyKey) {
+					continue
+				}
+				st, ok := states[key]
+				if direito && !ok {
+					continue // Parceiro sem obrigações em aberto.
+				}
+				open, err := b.OpenBalance()
+				if err != nil {
+					appLogger.Warnf("Encontro de contas: título ID %d ignorado: %v", b.ID, err)
+					invalid++
+					continue
+				}
+				if !open.IsPositive() {
+					continue
+				}
+				if !ok {
+					st = &nettingPartnerState{partner: &models.NettingPartner{Key: key}, cnpjs: make(map[string]struct{})}
+					states[key] = st
+				}
+				st.add(b, open, direito)
+			}
+			return nil
+		}
+	}
+	if err := s.obrigacaoRepo.ForEachBalance(visit(false)); err != nil {
+		return nil, invalid, err // Erro já logado pelo repo.
+	}
+	if err := s.direitoRepo.ForEachBalance(visit(true)); err != nil {
+		return nil, invalid, err
+	}
+
+	partners := make([]*models.NettingPartner, 0, len(states))
+	for _, st := range states {
+		p := st.partner
+		if len(p.Direitos) == 0 {
+			continue
+		}
+		for cnpj := range st.cnpjs {
+			p.CNPJs = append(p.CNPJs, cnpj)
+		}
+		sort.Strings(p.CNPJs)
+		sortNettingTitles(p.Direitos)
+		sortNettingTitles(p.Obrigacoes)
+		p.Pairs = proposeNettingPairs(p.Direitos, p.Obrigacoes)
+		p.Net = p.Receivable.Sub(p.Payable)
+		p.Offsettable = decimal.Min(p.Receivable, p.Payable)
+		if p.Name == "" {
+			p.Name = models.FormatCNPJCPF(p.Key)
+		}
+		partners = append(partners, p)
+	}
+	return partners, invalid, nil
+}
+
+// sortNettingTitles ordena os títulos por vencimento; títulos sem vencimento ficam por último.
+func sortNettingTitles(titles []models.NettingTitle) {
+	sort.SliceStable(titles, func(i, j int) bool {
+		a, b := titles[i].DataVencimento, titles[j].DataVencimento
+		switch {
+		case a == nil && b == nil:
+			return titles[i].ID < titles[j].ID
+		case a == nil:
+			return false
+		case b == nil:
+			return true
+		case !a.Equal(*b):
+			return a.Before(*b)
+		}
+		return titles[i].ID < titles[j].ID
+	})
+}
+
+// proposeNettingPairs propõe as compensações casando os títulos na ordem de vencimento: o direito
+// mais antigo é compensado com a obrigação mais antiga até que um dos saldos se esgote, e assim
+// por diante. A soma dos pares é igual ao menor dos dois saldos totais.
+func proposeNettingPairs(direitos, obrigacoes []models.NettingTitle) []models.NettingPair {
+	var pairs []models.NettingPair
+	if len(direitos) == 0 || len(obrigacoes) == 0 {
+		return pairs
+	}
+	i, j := 0, 0
+	restDireito, restObrigacao := direitos[0].OpenBalance, obrigacoes[0].OpenBalance
+	for i < len(direitos) && j < len(obrigacoes) {
+		amount := decimal.Min(restDireito, restObrigacao)
+		pairs = append(pairs, models.NettingPair{Direito: direitos[i], Obrigacao: obrigacoes[j], Amount: amount})
+		restDireito = restDireito.Sub(amount)
+		restObrigacao = restObrigacao.Sub(amount)
+		if !restDireito.IsPositive() {
+			if i++; i < len(direitos) {
+				restDireito = direitos[i].OpenBalance
+			}
+		}
+		if !restObrigacao.IsPositive() {
+			if j++; j < len(obrigacoes) {
+				restObrigacao = obrigacoes[j].OpenBalance
+			}
+		}
+	}
+	return pairs
+}
+
+// resolveNetworkNames preenche o nome da rede dos parceiros.
+func (s *nettingServiceImpl) resolveNetworkNames(partners []*models.NettingPartner) error {
+	networks, err := s.networkRepo.GetAll(true)
+	if err != nil {
+		return err // Erro já logado pelo repo.
+	}
+	names := make(map[uint64]string, len(networks))
+	for _, network := range networks {
+		names[network.ID] = network.Name
+	}
+	for _, p := range partners {
+		p.NetworkName = agingNoNetworkLabel
+		if p.NetworkID != nil {
+			if name, ok := names[*p.NetworkID]; ok {
+				p.NetworkName = name
+			}
+		}
+	}
+	return nil
+}
+
+// GenerateNettingReport calcula o encontro de contas de todos os parceiros.
+func (s *nettingServiceImpl) GenerateNettingReport(mode models.NettingMatchMode, userSession *auth.SessionData) (*models.NettingReport, error) {
+	if err := s.permManager.CheckPermission(userSession, auth.PermTituloView, nil); err != nil {
+		return nil, err
+	}
+	mode = models.NettingMatchMode(strings.ToUpper(strings.TrimSpace(string(mode))))
+	if err := mode.Validate(); err != nil {
+		return nil, err
+	}
+
+	partners, invalid, err := s.collectPartners(mode, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resolveNetworkNames(partners); err != nil {
+		return nil, err
+	}
+	sort.Slice(partners, func(i, j int) bool {
+		if cmp := partners[i].Offsettable.Cmp(partners[j].Offsettable); cmp != 0 {
+			return cmp > 0
+		}
+		return partners[i].Name < partners[j].Name
+	})
+
+	report := &models.NettingReport{
+		MatchMode:     mode,
+		GeneratedAt:   time.Now(),
+		Partners:      make([]models.NettingPartner, 0, len(partners)),
+		Totals:        models.NettingNetworkSummary{Name: "Total"},
+		InvalidTitles: invalid,
+	}
+	networks := make(map[string]*models.NettingNetworkSummary)
+	var networkOrder []string
+	for _, p := range partners {
+		report.Partners = append(report.Partners, *p)
+
+		key := ""
+		if p.NetworkID != nil {
+			key = strconv.FormatUint(*p.NetworkID, 10)
+		}
+		summary, ok := networks[key]
+		if !ok {
+			summary = &models.NettingNetworkSummary{NetworkID: p.NetworkID, Name: p.NetworkName}
+			networks[key] = summary
+			networkOrder = append(networkOrder, key)
+		}
+		for _, sum := range []*models.NettingNetworkSummary{summary, &report.Totals} {
+			sum.Partners++
+			sum.Receivable = sum.Receivable.Add(p.Receivable)
+			sum.Payable = sum.Payable.Add(p.Payable)
+			sum.Net = sum.Net.Add(p.Net)
+			sum.Offsettable = sum.Offsettable.Add(p.Offsettable)
+		}
+	}
+	for _, key := range networkOrder {
+		report.Networks = append(report.Networks, *networks[key])
+	}
+	sort.SliceStable(report.Networks, func(i, j int) bool {
+		return report.Networks[i].Offsettable.GreaterThan(report.Networks[j].Offsettable)
+	})
+
+	appLogger.Infof("Encontro de contas (%s) gerado por '%s': %d parceiros, %s compensáveis.",
+		mode, userSession.Username, len(report.Partners), report.Totals.Offsettable.StringFixed(2))
+	return report, nil
+}
+
+// nettingDate formata uma data de vencimento para o extrato.
+func nettingDate(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("02/01/2006")
+}
+
+// nettingStatementInputs monta as planilhas do extrato de um parceiro. Valores vão como "1234.56"
+// para que o exportador XLSX os grave como números; CNPJs vão formatados para permanecerem texto.
+func nettingStatementInputs(p *models.NettingPartner, generatedAt time.Time) ([]utils.DataInput, error) {
+	cnpjs := make([]string, len(p.CNPJs))
+	for i, cnpj := range p.CNPJs {
+		cnpjs[i] = models.FormatCNPJCPF(cnpj)
+	}
+	summary := [][]string{
+		{"Item", "Valor"},
+		{"Parceiro", p.Name},
+		{"CNPJ/CPF", strings.Join(cnpjs, ", ")},
+		{"Rede", p.NetworkName},
+		{"Emitido em", generatedAt.Format("02/01/2006 15:04")},
+		{"Total a receber do parceiro", p.Receivable.StringFixed(2)},
+		{"Total a pagar ao parceiro", p.Payable.StringFixed(2)},
+		{"Saldo líquido (positivo: a receber)", p.Net.StringFixed(2)},
+		{"Valor compensável", p.Offsettable.StringFixed(2)},
+	}
+
+	titleRows := func(titles []models.NettingTitle) [][]string {
+		rows := [][]string{{"CNPJ/CPF", "Título", "NROEMPRESA", "Vencimento", "Saldo em aberto"}}
+		for _, t := range titles {
+			rows = append(rows, []string{models.FormatCNPJCPF(t.CNPJCPF), t.Titulo, strconv.Itoa(t.NumeroEmpresa), nettingDate(t.DataVencimento), t.OpenBalance.StringFixed(2)})
+		}
+		return rows
+	}
+
+	pairs := [][]string{{"Título a receber", "Vencimento", "Título a pagar", "Vencimento", "Valor compensado"}}
+	for _, pair := range p.Pairs {
+		pairs = append(pairs, []string{
+			pair.Direito.Titulo, nettingDate(pair.Direito.DataVencimento),
+			pair.Obrigacao.Titulo, nettingDate(pair.Obrigacao.DataVencimento),
+			pair.Amount.StringFixed(2),
+		})
+	}
+
+	sheets := []struct {
+		name string
+		data [][]string
+	}{
+		{"Resumo", summary},
+		{"A receber do parceiro", titleRows(p.Direitos)},
+		{"A pagar ao parceiro", titleRows(p.Obrigacoes)},
+		{"Compensações", pairs},
+	}
+	inputs := make([]utils.DataInput, 0, len(sheets))
+	for _, sheet := range sheets {
+		input, err := utils.NewSliceDataInput(sheet.data, sheet.name)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs, nil
+}
+
+// ExportNettingStatement exporta o extrato de encontro de contas de um parceiro.
+func (s *nettingServiceImpl) ExportNettingStatement(mode models.NettingMatchMode, partnerKey string, userSession *auth.SessionData) (*models.NettingPartner, string, error) {
+	// O extrato expõe os mesmos títulos do relatório, que exige PermTituloView.
+	if err := s.permManager.CheckPermission(userSession, auth.PermTituloView, nil); err != nil {
+		return nil, "", err
+	}
+	if err := s.permManager.CheckPermission(userSession, auth.PermExportData, nil); err != nil {
+		return nil, "", err
+	}
+	mode = models.NettingMatchMode(strings.ToUpper(strings.TrimSpace(string(mode))))
+	if err := mode.Validate(); err != nil {
+		return nil, "", err
+	}
+	key := mode.PartnerKey(models.CleanCNPJ(partnerKey))
+	if key == "" {
+		return nil, "", fmt.Errorf("%w: CNPJ/CPF do parceiro é obrigatório", appErrors.ErrInvalidInput)
+	}
+
+	partners, _, err := s.collectPartners(mode, key)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(partners) == 0 {
+		return nil, "", fmt.Errorf("%w: parceiro '%s' não possui títulos em aberto a receber e a pagar", appErrors.ErrNotFound, key)
+	}
+	if err := s.resolveNetworkNames(partners); err != nil {
+		return nil, "", err
+	}
+	partner := partners[0]
+
+	generatedAt := time.Now()
+	inputs, err := nettingStatementInputs(partner, generatedAt)
+	if err != nil {
+		return nil, "", err
+	}
+	fileName := fmt.Sprintf("encontro_contas_%s_%s.xlsx", partner.Key, generatedAt.Format("20060102_150405"))
+	exportPath, err := utils.ExportToXLSX(inputs, fileName, s.cfg, nil)
+	if err != nil {
+		appLogger.Errorf("Erro ao exportar o extrato de encontro de contas do parceiro %s: %v", partner.Key, err)
+		return nil, "", err
+	}
+
+	s.auditLogService.LogAction(models.AuditLogEntry{
+		Action: "NETTING_STATEMENT_EXPORT",
+		Description: fmt.Sprintf("Extrato de encontro de contas do parceiro '%s' (%s) exportado para '%s'. Compensável: %s.",
+			partner.Name, partner.Key, exportPath, partner.Offsettable.StringFixed(2)),
+		Severity: "INFO",
+		Metadata: map[string]interface{}{
+			"match_mode":  mode,
+			"partner_key": partner.Key,
+			"export_file": exportPath,
+			"receivable":  partner.Receivable.StringFixed(2),
+			"payable":     partner.Payable.StringFixed(2),
+			"offsettable": partner.Offsettable.StringFixed(2),
+			"pairs":       len(partner.Pairs),
+		},
+	}, userSession)
+	return partner, exportPath, nil
+}
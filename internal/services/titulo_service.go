@@ -0,0 +1,131 @@
+package services
+
+import (
+	"time"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/repositories"
+)
+
+// TituloService define a interface para a consulta dos títulos importados.
+type TituloService interface {
+	// GetTitulosDireitos busca uma página de títulos de direitos que correspondem aos filtros,
+	// com os totais de todo o conjunto filtrado, o vencimento efetivo (próximo dia útil) de cada título
+	// e a multa e os juros de mora na data `filter.ChargesAsOf`.
+	// Exige `auth.PermTituloView`.
+	GetTitulosDireitos(filter models.TituloFilter, userSession *auth.SessionData) (*models.TituloDireitoPage, error)
+
+	// GetTitulosObrigacoes busca uma página de títulos de obrigações que correspondem aos filtros,
+	// com os totais de todo o conjunto filtrado e o vencimento efetivo (próximo dia útil) de cada título.
+	// Exige `auth.PermTituloView`.
+	GetTitulosObrigacoes(filter models.TituloFilter, userSession *auth.SessionData) (*models.TituloObrigacaoPage, error)
+}
+
+// tituloServiceImpl é a implementação de TituloService.
+type tituloServiceImpl struct {
+	direitoRepo    repositories.TituloDireitoRepository
+	obrigacaoRepo  repositories.TituloObrigacaoRepository
+	holidayService HolidayService
+	chargeService  ChargeService
+	permManager    *auth.PermissionManager
+}
+
+// NewTituloService cria uma nova instância de TituloService.
+func NewTituloService(
+	direitoRepo repositories.TituloDireitoRepository,
+	obrigacaoRepo repositories.TituloObrigacaoRepository,
+	holidayService HolidayService,
+	chargeService ChargeService,
+	permManager *auth.PermissionManager,
+) TituloService {
+	if direitoRepo == nil || obrigacaoRepo == nil || holidayService == nil || chargeService == nil || permManager == nil {
+		appLogger.Fatalf("Dependências nulas fornecidas para NewTituloService (direitoRepo, obrigacaoRepo, holidayService, chargeService, permManager)")
+	}
+	return &tituloServiceImpl{
+		direitoRepo:    direitoRepo,
+		obrigacaoRepo:  obrigacaoRepo,
+		holidayService: holidayService,
+		chargeService:  chargeService,
+		permManager:    permManager,
+	}
+}
+
+// prepareFilter verifica a permissão de consulta e normaliza/valida os filtros.
+func (s *tituloServiceImpl) prepareFilter(filter *models.TituloFilter, userSession *auth.SessionData) error {
+	if err := s.permManager.CheckPermission(userSession, auth.PermTituloView, nil); err != nil {
+		return err
+	}
+	filter.Normalize()
+	if err := filter.Validate(); err != nil {
+		appLogger.Warnf("Filtros de consulta de títulos inválidos (usuário '%s'): %v", userSession.Username, err)
+		return err
+	}
+	return nil
+}
+
+// GetTitulosDireitos busca uma página de títulos de direitos.
+func (s *tituloServiceImpl) GetTitulosDireitos(filter models.TituloFilter, userSession *auth.SessionData) (*models.TituloDireitoPage, error) {
+	if err := s.prepareFilter(&filter, userSession); err != nil {
+		return nil, err
+	}
+	dbTitulos, totals, err := s.direitoRepo.GetFiltered(filter)
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	items, err := models.ToTituloDireitoPublicList(dbTitulos)
+	if err != nil {
+		appLogger.Errorf("Erro ao converter títulos de direitos para exibição: %v", err)
+		return nil, appErrors.WrapErrorf(err, "falha ao converter títulos de direitos")
+	}
+	engine, err := s.chargeService.ChargeEngine()
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	calendar, err := s.holidayService.BusinessCalendar()
+	if err != nil {
+		return nil, err
+	}
+	asOf := time.Now()
+	if filter.ChargesAsOf != nil {
+		asOf = *filter.ChargesAsOf
+	}
+	for i, item := range items {
+		item.DataVencimentoEfetiva = calendar.FormatEffectiveDueDate(dbTitulos[i].DataVencimento)
+		if dbTitulos[i].RemovedAt != nil {
+			continue
+		}
+		balance := dbTitulos[i].ToBalance()
+		if item.Encargos, err = engine.Calculate(&balance, asOf); err != nil {
+			appLogger.Errorf("Erro ao calcular encargos do título de direito ID %d: %v", dbTitulos[i].ID, err)
+			return nil, appErrors.WrapErrorf(err, "falha ao calcular encargos dos títulos de direitos")
+		}
+	}
+	return &models.TituloDireitoPage{Items: items, Totals: totals, Limit: filter.Limit, Offset: filter.Offset}, nil
+}
+
+// GetTitulosObrigacoes busca uma página de títulos de obrigações.
+func (s *tituloServiceImpl) GetTitulosObrigacoes(filter models.TituloFilter, userSession *auth.SessionData) (*models.TituloObrigacaoPage, error) {
+	if err := s.prepareFilter(&filter, userSession); err != nil {
+		return nil, err
+	}
+	dbTitulos, totals, err := s.obrigacaoRepo.GetFiltered(filter)
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	items, err := models.ToTituloObrigacaoPublicList(dbTitulos)
+	if err != nil {
+		appLogger.Errorf("Erro ao converter títulos de obrigações para exibição: %v", err)
+		return nil, appErrors.WrapErrorf(err, "falha ao converter títulos de obrigações")
+	}
+	calendar, err := s.holidayService.BusinessCalendar()
+	if err != nil {
+		return nil, err // Erro já logado pelo repo.
+	}
+	for i, item := range items {
+		item.DataVencimentoEfetiva = calendar.FormatEffectiveDueDate(dbTitulos[i].DataVencimento)
+	}
+	return &models.TituloObrigacaoPage{Items: items, Totals: totals, Limit: filter.Limit, Offset: filter.Offset}, nil
+}
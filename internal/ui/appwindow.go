@@ -32,14 +32,20 @@ type AppWindow struct {
 	router *Router         // Gerenciador de navegação entre páginas.
 
 	// Serviços que a AppWindow ou suas páginas podem precisar acessar.
-	authenticator  auth.AuthenticatorInterface
-	sessionManager *auth.SessionManager
-	userService    services.UserService
-	roleService    services.RoleService
-	networkService services.NetworkService
-	cnpjService    services.CNPJService
-	importService  services.ImportService
-	auditService   services.AuditLogService
+	authenticator    auth.AuthenticatorInterface
+	sessionManager   *auth.SessionManager
+	userService      services.UserService
+	roleService      services.RoleService
+	networkService   services.NetworkService
+	cnpjService      services.CNPJService
+	tituloService    services.TituloService
+	agingService     services.AgingReportService
+	nettingService   services.NettingService
+	dashboardService services.DashboardService
+	cashFlowService  services.CashFlowService
+	dueDigestService services.DueDigestService
+	importService    services.ImportService
+	auditService     services.AuditLogService
 
 	// Estado global da UI gerenciado pela AppWindow.
 	globalSpinner   *components.LoadingSpinner // Spinner de carregamento global.
@@ -62,6 +68,12 @@ func NewAppWindow(
 	roleSvc services.RoleService,
 	netSvc services.NetworkService,
 	cnpjSvc services.CNPJService,
+	tituloSvc services.TituloService,
+	agingSvc services.AgingReportService,
+	nettingSvc services.NettingService,
+	dashboardSvc services.DashboardService,
+	cashFlowSvc services.CashFlowService,
+	dueDigestSvc services.DueDigestService,
 	importSvc services.ImportService,
 	auditSvc services.AuditLogService,
 ) *AppWindow {
@@ -78,24 +90,30 @@ func NewAppWindow(
 	)
 
 	aw := &AppWindow{
-		window:         win,
-		th:             th,
-		cfg:            cfg,
-		authenticator:  authN,
-		sessionManager: sessMan,
-		userService:    userSvc,
-		roleService:    roleSvc,
-		networkService: netSvc,
-		cnpjService:    cnpjSvc,
-		importService:  importSvc,
-		auditService:   auditSvc,
-		globalSpinner:  components.NewLoadingSpinner(theme.Colors.Primary), // Spinner global com cor primária.
+		window:           win,
+		th:               th,
+		cfg:              cfg,
+		authenticator:    authN,
+		sessionManager:   sessMan,
+		userService:      userSvc,
+		roleService:      roleSvc,
+		networkService:   netSvc,
+		cnpjService:      cnpjSvc,
+		tituloService:    tituloSvc,
+		agingService:     agingSvc,
+		nettingService:   nettingSvc,
+		dashboardService: dashboardSvc,
+		cashFlowService:  cashFlowSvc,
+		dueDigestService: dueDigestSvc,
+		importService:    importSvc,
+		auditService:     auditSvc,
+		globalSpinner:    components.NewLoadingSpinner(theme.Colors.Primary), // Spinner global com cor primária.
 	}
 
 	// Inicializa o Router, passando `aw` (para callbacks e acesso a serviços/tema)
 	// e todas as dependências de serviço que as páginas podem precisar.
 	// O PermissionManager é obtido globalmente pelo router.
-	aw.router = NewRouter(th, cfg, aw, userSvc, roleSvc, netSvc, cnpjSvc, importSvc, auditSvc, authN, sessMan, auth.GetPermissionManager())
+	aw.router = NewRouter(th, cfg, aw, userSvc, roleSvc, netSvc, cnpjSvc, tituloSvc, agingSvc, nettingSvc, dashboardSvc, cashFlowSvc, dueDigestSvc, importSvc, auditSvc, authN, sessMan, auth.GetPermissionManager())
 
 	// Registra as páginas de nível superior no router.
 	// As páginas recebem o router para navegação e acesso a serviços.
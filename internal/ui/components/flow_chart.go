@@ -0,0 +1,114 @@
+package components
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+)
+
+const (
+	defaultFlowChartHeight   = 220 // dp
+	defaultFlowChartLineSize = 2   // dp
+	flowChartBarGapRatio     = 0.2 // Fração de cada coluna deixada livre entre as barras.
+)
+
+// FlowChart desenha um gráfico de fluxo: para cada período, uma barra de entrada acima do eixo zero,
+// uma barra de saída abaixo dele e uma linha com o saldo acumulado. A escala é comum às três séries.
+type FlowChart struct {
+	Inflows    []float64 // Valores positivos, desenhados acima do eixo.
+	Outflows   []float64 // Valores positivos, desenhados abaixo do eixo.
+	Cumulative []float64 // Saldo acumulado ao fim de cada período (pode ser negativo).
+
+	InflowColor  color.NRGBA
+	OutflowColor color.NRGBA
+	LineColor    color.NRGBA
+	AxisColor    color.NRGBA
+	Height       unit.Dp // Altura do gráfico (padrão: 220dp).
+}
+
+// Layout desenha o gráfico ocupando toda a largura disponível.
+func (c *FlowChart) Layout(gtx layout.Context) layout.Dimensions {
+	height := c.Height
+	if height <= 0 {
+		height = defaultFlowChartHeight
+	}
+	size := image.Pt(gtx.Constraints.Max.X, gtx.Dp(height))
+	n := len(c.Inflows)
+	if n == 0 || size.X <= 0 {
+		return layout.Dimensions{Size: size}
+	}
+	defer clip.Rect{Max: size}.Push(gtx.Ops).Pop()
+
+	// Limites da escala: o eixo zero fica entre o maior valor positivo e o menor negativo.
+	top, bottom := 0.0, 0.0
+	for i := 0; i < n; i++ {
+		top = math.Max(top, c.value(c.Inflows, i))
+		bottom = math.Min(bottom, -c.value(c.Outflows, i))
+		top = math.Max(top, c.value(c.Cumulative, i))
+		bottom = math.Min(bottom, c.value(c.Cumulative, i))
+	}
+	if top == bottom {
+		top = 1
+	}
+	scale := float64(size.Y) / (top - bottom)
+	zeroY := float32(top * scale)
+	yOf := func(v float64) float32 { return zeroY - float32(v*scale) }
+
+	column := float32(size.X) / float32(n)
+	barWidth := column * (1 - flowChartBarGapRatio) / 2
+	gap := column * flowChartBarGapRatio / 2
+	fillBar := func(x, y0, y1 float32, col color.NRGBA) {
+		if y0 > y1 {
+			y0, y1 = y1, y0
+		}
+		rect := image.Rect(int(x), int(y0), int(math.Ceil(float64(x+barWidth))), int(math.Ceil(float64(y1))))
+		paint.FillShape(gtx.Ops, col, clip.Rect(rect).Op())
+	}
+	for i := 0; i < n; i++ {
+		x := float32(i)*column + gap
+		if v := c.value(c.Inflows, i); v > 0 {
+			fillBar(x, zeroY, yOf(v), c.InflowColor)
+		}
+		if v := c.value(c.Outflows, i); v > 0 {
+			fillBar(x+barWidth, zeroY, yOf(-v), c.OutflowColor)
+		}
+	}
+
+	// Eixo zero.
+	axis := image.Rect(0, int(zeroY), size.X, int(zeroY)+1)
+	paint.FillShape(gtx.Ops, c.AxisColor, clip.Rect(axis).Op())
+
+	// Linha do saldo acumulado, ligando o centro de cada coluna.
+	if len(c.Cumulative) > 0 {
+		var path clip.Path
+		path.Begin(gtx.Ops)
+		for i := 0; i < n; i++ {
+			pt := f32.Pt(float32(i)*column+column/2, yOf(c.value(c.Cumulative, i)))
+			if i == 0 {
+				path.MoveTo(pt)
+			} else {
+				path.LineTo(pt)
+			}
+		}
+		paint.StrokeShape(gtx.Ops, c.LineColor, clip.Stroke{
+			Path:  path.End(),
+			Width: float32(gtx.Dp(defaultFlowChartLineSize)),
+		}.Op())
+	}
+
+	return layout.Dimensions{Size: size}
+}
+
+// value retorna o i-ésimo valor da série, ou zero se a série for mais curta.
+func (c *FlowChart) value(series []float64, i int) float64 {
+	if i < len(series) {
+		return series[i]
+	}
+	return 0
+}
@@ -0,0 +1,332 @@
+package pages
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"time"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/config"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/services"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/theme"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/ui/components"
+)
+
+// Pesos das colunas da tabela de aging: grupo, quantidade de títulos, faixas e total.
+const (
+	agingLabelWeight = 0.22
+	agingCountWeight = 0.06
+	agingValueWeight = 0.10
+)
+
+// AgingPage exibe o relatório de aging (saldos em aberto por faixa de atraso) dos títulos
+// de direitos ou obrigações, com exportação para XLSX.
+type AgingPage struct {
+	router         *ui.Router
+	cfg            *core.Config
+	agingService   services.AgingReportService
+	permManager    *auth.PermissionManager
+	sessionManager *auth.SessionManager
+
+	fileTypeEnum  widget.Enum // services.FileTypeDireitos ou services.FileTypeObrigacoes.
+	groupByEnum   widget.Enum // models.AgingGroupBy.
+	refDateInput  widget.Editor
+	generateBtn   widget.Clickable
+	exportBtn     widget.Clickable
+	resultList    widget.List
+	report        *models.AgingReport
+	isLoading     bool
+	statusMessage string
+	messageColor  color.NRGBA
+
+	accessDenied bool
+	spinner      *components.LoadingSpinner
+}
+
+// NewAgingPage cria uma nova instância da página de aging.
+func NewAgingPage(
+	router *ui.Router,
+	cfg *core.Config,
+	agingSvc services.AgingReportService,
+	permMan *auth.PermissionManager,
+	sessMan *auth.SessionManager,
+) *AgingPage {
+	p := &AgingPage{
+		router:         router,
+		cfg:            cfg,
+		agingService:   agingSvc,
+		permManager:    permMan,
+		sessionManager: sessMan,
+		spinner:        components.NewLoadingSpinner(theme.Colors.Primary),
+	}
+	p.fileTypeEnum.Value = string(services.FileTypeDireitos)
+	p.groupByEnum.Value = string(models.AgingGroupByCNPJ)
+	p.refDateInput.SingleLine = true
+	p.refDateInput.Filter = "0123456789/"
+	p.refDateInput.SetText(time.Now().Format("02/01/2006"))
+	p.resultList.Axis = layout.Vertical
+	return p
+}
+
+// OnNavigatedTo é chamado quando a página se torna ativa.
+func (p *AgingPage) OnNavigatedTo(params interface{}) {
+	appLogger.Info("Navegou para AgingPage")
+	currentSession, errSess := p.sessionManager.GetCurrentSession()
+	if errSess != nil || currentSession == nil {
+		p.router.GetAppWindow().HandleLogout()
+		return
+	}
+	if err := p.permManager.CheckPermission(currentSession, auth.PermTituloView, nil); err != nil {
+		p.accessDenied = true
+		p.statusMessage = fmt.Sprintf("Acesso negado ao relatório de aging: %v", err)
+		p.messageColor = theme.Colors.Danger
+		p.router.GetAppWindow().Invalidate()
+		return
+	}
+	p.accessDenied = false
+	if p.report == nil && p.statusMessage == "" {
+		p.statusMessage = "Escolha o tipo, o agrupamento e a data de referência e clique em Gerar."
+		p.messageColor = theme.Colors.TextMuted
+	}
+}
+
+// OnNavigatedFrom é chamado quando o router navega para fora desta página.
+func (p *AgingPage) OnNavigatedFrom() {
+	appLogger.Info("Navegando para fora da AgingPage")
+	p.spinner.Stop(p.router.GetAppWindow().Context())
+}
+
+// readParams lê o tipo de título, o agrupamento e a data de referência do formulário.
+func (p *AgingPage) readParams() (services.FileType, models.AgingGroupBy, time.Time, string) {
+	refDate, err := time.Parse("02/01/2006", strings.TrimSpace(p.refDateInput.Text()))
+	if err != nil {
+		return "", "", time.Time{}, "Data de referência inválida: use DD/MM/AAAA."
+	}
+	return services.FileType(p.fileTypeEnum.Value), models.AgingGroupBy(p.groupByEnum.Value), refDate, ""
+}
+
+// runReport gera o relatório (e, se `export`, exporta para XLSX) em segundo plano.
+func (p *AgingPage) runReport(export bool, currentSession *auth.SessionData) {
+	if p.isLoading || p.accessDenied {
+		return
+	}
+	fileType, groupBy, refDate, feedback := p.readParams()
+	if feedback != "" {
+		p.statusMessage = feedback
+		p.messageColor = theme.Colors.Danger
+		p.router.GetAppWindow().Invalidate()
+		return
+	}
+
+	p.isLoading = true
+	p.statusMessage = "Calculando aging..."
+	if export {
+		p.statusMessage = "Calculando e exportando aging..."
+	}
+	p.messageColor = theme.Colors.TextMuted
+	p.spinner.Start(p.router.GetAppWindow().Context())
+	p.router.GetAppWindow().Invalidate()
+
+	go func(sess *auth.SessionData) {
+		var report *models.AgingReport
+		var exportPath string
+		var err error
+		if export {
+			report, exportPath, err = p.agingService.ExportAgingReport(fileType, groupBy, refDate, sess)
+		} else {
+			report, err = p.agingService.GenerateAgingReport(fileType, groupBy, refDate, sess)
+		}
+
+		p.router.GetAppWindow().Execute(func() {
+			p.isLoading = false
+			p.spinner.Stop(p.router.GetAppWindow().Context())
+			if err != nil {
+				p.statusMessage = fmt.Sprintf("Erro ao gerar o aging: %v", err)
+				p.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao gerar aging de %s por %s: %v", fileType, groupBy, err)
+			} else {
+				p.report = report
+				p.resultList.Position = layout.Position{}
+				p.statusMessage = fmt.Sprintf("%d títulos em aberto em %d grupos, referência %s.",
+					report.Totals.TitleCount, len(report.Rows), report.ReferenceDate.Format("02/01/2006"))
+				p.messageColor = theme.Colors.Success
+				if exportPath != "" {
+					p.statusMessage += " Exportado para: " + exportPath
+				}
+				if report.InvalidTitles > 0 {
+					p.statusMessage += fmt.Sprintf(" %d títulos ignorados por valores inválidos.", report.InvalidTitles)
+					p.messageColor = theme.Colors.Warning
+				}
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(currentSession)
+}
+
+// Layout desenha a página.
+func (p *AgingPage) Layout(gtx layout.Context) layout.Dimensions {
+	th := p.router.GetAppWindow().Theme()
+	currentSession, _ := p.sessionManager.GetCurrentSession()
+
+	if p.generateBtn.Clicked(gtx) {
+		p.runReport(false, currentSession)
+	}
+	if p.exportBtn.Clicked(gtx) {
+		p.runReport(true, currentSession)
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D { return p.layoutForm(gtx, th) }),
+		layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+		layout.Rigid(func(gtx C) D {
+			if p.statusMessage == "" {
+				return D{}
+			}
+			lbl := material.Body2(th, p.statusMessage)
+			lbl.Color = p.messageColor
+			return layout.Inset{Bottom: theme.DefaultVSpacer}.Layout(gtx, lbl.Layout)
+		}),
+		layout.Flexed(1, func(gtx C) D { return p.layoutTable(gtx, th) }),
+	)
+}
+
+// layoutForm desenha o formulário de parâmetros do relatório.
+func (p *AgingPage) layoutForm(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	exportBtn := material.Button(th, &p.exportBtn, "Exportar XLSX")
+	generateBtn := material.Button(th, &p.generateBtn, "Gerar")
+	if p.isLoading || p.accessDenied {
+		exportBtn.Background = theme.Colors.Grey300
+		exportBtn.Color = theme.Colors.TextMuted
+		generateBtn.Background = theme.Colors.Grey300
+		generateBtn.Color = theme.Colors.TextMuted
+	}
+
+	return material.Card(th, theme.Colors.Surface, theme.ElevationSmall, layout.UniformInset(unit.Dp(12)),
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx C) D {
+					return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+						layout.Rigid(material.Body2(th, "Títulos:").Layout),
+						layout.Rigid(material.RadioButton(th, &p.fileTypeEnum, string(services.FileTypeDireitos), "Direitos (a receber)").Layout),
+						layout.Rigid(material.RadioButton(th, &p.fileTypeEnum, string(services.FileTypeObrigacoes), "Obrigações (a pagar)").Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(24)}.Layout),
+						layout.Rigid(material.Body2(th, "Data de referência:").Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+						layout.Rigid(func(gtx C) D {
+							gtx.Constraints.Min.X = gtx.Dp(unit.Dp(110))
+							gtx.Constraints.Max.X = gtx.Constraints.Min.X
+							return material.Editor(th, &p.refDateInput, "DD/MM/AAAA").Layout(gtx)
+						}),
+					)
+				}),
+				layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+				layout.Rigid(func(gtx C) D {
+					return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+						layout.Rigid(material.Body2(th, "Agrupar por:").Layout),
+						layout.Rigid(material.RadioButton(th, &p.groupByEnum, string(models.AgingGroupByCNPJ), models.AgingGroupByCNPJ.Label()).Layout),
+						layout.Rigid(material.RadioButton(th, &p.groupByEnum, string(models.AgingGroupByNetwork), models.AgingGroupByNetwork.Label()).Layout),
+						layout.Rigid(material.RadioButton(th, &p.groupByEnum, string(models.AgingGroupByBuyer), models.AgingGroupByBuyer.Label()).Layout),
+						layout.Rigid(material.RadioButton(th, &p.groupByEnum, string(models.AgingGroupByEmpresa), models.AgingGroupByEmpresa.Label()).Layout),
+						layout.Flexed(1, func(gtx C) D { return D{} }),
+						layout.Rigid(func(gtx C) D {
+							if !p.isLoading {
+								return D{}
+							}
+							return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, p.spinner.Layout)
+						}),
+						layout.Rigid(exportBtn.Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+						layout.Rigid(generateBtn.Layout),
+					)
+				}),
+			)
+		}).Layout(gtx)
+}
+
+// layoutTable desenha a tabela do relatório: uma linha por grupo e a linha de total fixa no rodapé.
+func (p *AgingPage) layoutTable(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	if p.report == nil {
+		return D{}
+	}
+	report := p.report
+
+	// Nos direitos, uma coluna extra mostra o total com multa e juros de mora.
+	valueColumns := models.AgingBucketCount + 1
+	if report.WithCharges {
+		valueColumns++
+	}
+	weights := make([]float32, 2+valueColumns)
+	weights[0], weights[1] = agingLabelWeight, agingCountWeight
+	for i := 2; i < len(weights); i++ {
+		weights[i] = agingValueWeight
+	}
+	rowLayout := func(gtx C, cells []string, bgColor color.NRGBA, bold bool) D {
+		return layoutReportRow(gtx, th, cells, weights, bgColor, bold)
+	}
+	cellsOf := func(row models.AgingReportRow) []string {
+		cells := []string{row.Label, fmt.Sprint(row.TitleCount)}
+		for _, value := range row.Buckets {
+			cells = append(cells, formatMoney(value))
+		}
+		cells = append(cells, formatMoney(row.Total))
+		if report.WithCharges {
+			cells = append(cells, formatMoney(row.UpdatedTotal()))
+		}
+		return cells
+	}
+
+	headers := []string{report.GroupBy.Label(), "Títulos"}
+	headers = append(headers, models.AgingBucketLabels[:]...)
+	headers = append(headers, "Total")
+	if report.WithCharges {
+		headers = append(headers, "Total atualizado")
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D { return rowLayout(gtx, headers, theme.Colors.Grey200, true) }),
+		layout.Flexed(1, func(gtx C) D {
+			if len(report.Rows) == 0 {
+				lbl := material.Body2(th, "Nenhum título com saldo em aberto.")
+				lbl.Color = theme.Colors.Info
+				return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, lbl.Layout)
+			}
+			return material.List(th, &p.resultList).Layout(gtx, len(report.Rows), func(gtx C, index int) D {
+				bgColor := theme.Colors.Surface
+				if index%2 != 0 {
+					bgColor = theme.Colors.BackgroundAlt
+				}
+				return rowLayout(gtx, cellsOf(report.Rows[index]), bgColor, false)
+			})
+		}),
+		layout.Rigid(func(gtx C) D { return rowLayout(gtx, cellsOf(report.Totals), theme.Colors.Grey200, true) }),
+	)
+}
+
+// layoutReportRow desenha uma linha de tabela de relatório com as células distribuídas pelos pesos.
+func layoutReportRow(gtx layout.Context, th *material.Theme, cells []string, weights []float32, bgColor color.NRGBA, bold bool) layout.Dimensions {
+	return layout.Background{Color: bgColor}.Layout(gtx, func(gtx C) D {
+		return layout.Inset{Top: unit.Dp(4), Bottom: unit.Dp(4), Left: unit.Dp(8), Right: unit.Dp(8)}.Layout(gtx, func(gtx C) D {
+			children := make([]layout.FlexChild, len(cells))
+			for i, text := range cells {
+				children[i] = layout.Flexed(weights[i], func(gtx C) D {
+					lbl := material.Body2(th, text)
+					lbl.MaxLines = 1
+					if bold {
+						lbl.Font.Weight = font.Bold
+					}
+					return lbl.Layout(gtx)
+				})
+			}
+			return layout.Flex{}.Layout(gtx, children...)
+		})
+	})
+}
@@ -0,0 +1,383 @@
+package pages
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/config"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/services"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/theme"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/ui/components"
+)
+
+// cashFlowDefaultHorizonDays é o horizonte sugerido ao abrir a página.
+const cashFlowDefaultHorizonDays = 90
+
+// Pesos das colunas da tabela de fluxo de caixa.
+var cashFlowWeights = []float32{0.20, 0.14, 0.08, 0.14, 0.08, 0.18, 0.18}
+
+// CashFlowPage exibe a projeção de fluxo de caixa (entradas pelos direitos e saídas pelas
+// obrigações em aberto) em gráfico e tabela, com exportação para XLSX.
+type CashFlowPage struct {
+	router          *ui.Router
+	cfg             *core.Config
+	cashFlowService services.CashFlowService
+	permManager     *auth.PermissionManager
+	sessionManager  *auth.SessionManager
+
+	granularityEnum widget.Enum // models.CashFlowGranularity.
+	startDateInput  widget.Editor
+	horizonInput    widget.Editor
+	scheduledCheck  widget.Bool // Usar DTAPROGRAMADA no lugar de DTAVENCIMENTO.
+	generateBtn     widget.Clickable
+	exportBtn       widget.Clickable
+	resultList      widget.List
+	projection      *models.CashFlowProjection
+	chart           components.FlowChart
+	isLoading       bool
+	statusMessage   string
+	messageColor    color.NRGBA
+
+	accessDenied bool
+	spinner      *components.LoadingSpinner
+}
+
+// NewCashFlowPage cria uma nova instância da página de fluxo de caixa.
+func NewCashFlowPage(
+	router *ui.Router,
+	cfg *core.Config,
+	cashFlowSvc services.CashFlowService,
+	permMan *auth.PermissionManager,
+	sessMan *auth.SessionManager,
+) *CashFlowPage {
+	p := &CashFlowPage{
+		router:          router,
+		cfg:             cfg,
+		cashFlowService: cashFlowSvc,
+		permManager:     permMan,
+		sessionManager:  sessMan,
+		spinner:         components.NewLoadingSpinner(theme.Colors.Primary),
+		chart: components.FlowChart{
+			InflowColor:  theme.Colors.Success,
+			OutflowColor: theme.Colors.Danger,
+			LineColor:    theme.Colors.Primary,
+			AxisColor:    theme.Colors.Grey300,
+		},
+	}
+	p.granularityEnum.Value = string(models.CashFlowWeekly)
+	p.startDateInput.SingleLine = true
+	p.startDateInput.Filter = "0123456789/"
+	p.startDateInput.SetText(time.Now().Format("02/01/2006"))
+	p.horizonInput.SingleLine = true
+	p.horizonInput.Filter = "0123456789"
+	p.horizonInput.SetText(strconv.Itoa(cashFlowDefaultHorizonDays))
+	p.resultList.Axis = layout.Vertical
+	return p
+}
+
+// OnNavigatedTo é chamado quando a página se torna ativa.
+func (p *CashFlowPage) OnNavigatedTo(params interface{}) {
+	appLogger.Info("Navegou para CashFlowPage")
+	currentSession, errSess := p.sessionManager.GetCurrentSession()
+	if errSess != nil || currentSession == nil {
+		p.router.GetAppWindow().HandleLogout()
+		return
+	}
+	if err := p.permManager.CheckPermission(currentSession, auth.PermTituloView, nil); err != nil {
+		p.accessDenied = true
+		p.statusMessage = fmt.Sprintf("Acesso negado ao fluxo de caixa: %v", err)
+		p.messageColor = theme.Colors.Danger
+		p.router.GetAppWindow().Invalidate()
+		return
+	}
+	p.accessDenied = false
+	if p.projection == nil && p.statusMessage == "" {
+		p.statusMessage = "Escolha a periodicidade, a data inicial e o horizonte e clique em Gerar."
+		p.messageColor = theme.Colors.TextMuted
+	}
+}
+
+// OnNavigatedFrom é chamado quando o router navega para fora desta página.
+func (p *CashFlowPage) OnNavigatedFrom() {
+	appLogger.Info("Navegando para fora da CashFlowPage")
+	p.spinner.Stop(p.router.GetAppWindow().Context())
+}
+
+// readOptions lê os parâmetros da projeção do formulário.
+func (p *CashFlowPage) readOptions() (models.CashFlowOptions, string) {
+	startDate, err := time.Parse("02/01/2006", strings.TrimSpace(p.startDateInput.Text()))
+	if err != nil {
+		return models.CashFlowOptions{}, "Data inicial inválida: use DD/MM/AAAA."
+	}
+	horizon, err := strconv.Atoi(strings.TrimSpace(p.horizonInput.Text()))
+	if err != nil || horizon < 1 || horizon > models.CashFlowMaxHorizonDays {
+		return models.CashFlowOptions{}, fmt.Sprintf("Horizonte inválido: informe de 1 a %d dias.", models.CashFlowMaxHorizonDays)
+	}
+	return models.CashFlowOptions{
+		Granularity:      models.CashFlowGranularity(p.granularityEnum.Value),
+		StartDate:        startDate,
+		HorizonDays:      horizon,
+		UseScheduledDate: p.scheduledCheck.Value,
+	}, ""
+}
+
+// runProjection gera a projeção (e, se `export`, exporta para XLSX) em segundo plano.
+func (p *CashFlowPage) runProjection(export bool, currentSession *auth.SessionData) {
+	if p.isLoading || p.accessDenied {
+		return
+	}
+	options, feedback := p.readOptions()
+	if feedback != "" {
+		p.statusMessage = feedback
+		p.messageColor = theme.Colors.Danger
+		p.router.GetAppWindow().Invalidate()
+		return
+	}
+
+	p.isLoading = true
+	p.statusMessage = "Projetando fluxo de caixa..."
+	if export {
+		p.statusMessage = "Projetando e exportando fluxo de caixa..."
+	}
+	p.messageColor = theme.Colors.TextMuted
+	p.spinner.Start(p.router.GetAppWindow().Context())
+	p.router.GetAppWindow().Invalidate()
+
+	go func(sess *auth.SessionData) {
+		var projection *models.CashFlowProjection
+		var exportPath string
+		var err error
+		if export {
+			projection, exportPath, err = p.cashFlowService.ExportCashFlow(options, sess)
+		} else {
+			projection, err = p.cashFlowService.ProjectCashFlow(options, sess)
+		}
+
+		p.router.GetAppWindow().Execute(func() {
+			p.isLoading = false
+			p.spinner.Stop(p.router.GetAppWindow().Context())
+			if err != nil {
+				p.statusMessage = fmt.Sprintf("Erro ao projetar o fluxo de caixa: %v", err)
+				p.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao projetar fluxo de caixa %s: %v", options.Granularity, err)
+			} else {
+				p.setProjection(projection)
+				p.statusMessage = p.summaryMessage()
+				p.messageColor = theme.Colors.Success
+				if exportPath != "" {
+					p.statusMessage += " Exportado para: " + exportPath
+				}
+				if projection.InvalidTitles > 0 {
+					p.statusMessage += fmt.Sprintf(" %d títulos ignorados por valores inválidos.", projection.InvalidTitles)
+					p.messageColor = theme.Colors.Warning
+				}
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(currentSession)
+}
+
+// setProjection guarda a projeção e monta as séries do gráfico.
+func (p *CashFlowPage) setProjection(projection *models.CashFlowProjection) {
+	p.projection = projection
+	p.resultList.Position = layout.Position{}
+	n := len(projection.Periods)
+	p.chart.Inflows = make([]float64, n)
+	p.chart.Outflows = make([]float64, n)
+	p.chart.Cumulative = make([]float64, n)
+	for i, period := range projection.Periods {
+		p.chart.Inflows[i] = period.Inflow.InexactFloat64()
+		p.chart.Outflows[i] = period.Outflow.InexactFloat64()
+		p.chart.Cumulative[i] = period.Cumulative.InexactFloat64()
+	}
+}
+
+// summaryMessage resume a projeção, incluindo o que ficou fora dos períodos.
+func (p *CashFlowPage) summaryMessage() string {
+	pr := p.projection
+	msg := fmt.Sprintf("%s a %s: entradas %s, saídas %s, saldo %s.",
+		pr.Options.StartDate.Format("02/01/2006"), pr.EndDate.Format("02/01/2006"),
+		formatMoney(pr.Totals.Inflow), formatMoney(pr.Totals.Outflow), formatMoney(pr.Totals.Net))
+	if pr.Overdue.InflowCount+pr.Overdue.OutflowCount > 0 {
+		msg += fmt.Sprintf(" Vencidos antes do início (fora do saldo): a receber %s, a pagar %s.",
+			formatMoney(pr.Overdue.Inflow), formatMoney(pr.Overdue.Outflow))
+	}
+	if pr.Undated.InflowCount+pr.Undated.OutflowCount > 0 {
+		msg += fmt.Sprintf(" %d títulos sem data.", pr.Undated.InflowCount+pr.Undated.OutflowCount)
+	}
+	if pr.BeyondHorizon > 0 {
+		msg += fmt.Sprintf(" %d títulos após o horizonte.", pr.BeyondHorizon)
+	}
+	return msg
+}
+
+// Layout desenha a página.
+func (p *CashFlowPage) Layout(gtx layout.Context) layout.Dimensions {
+	th := p.router.GetAppWindow().Theme()
+	currentSession, _ := p.sessionManager.GetCurrentSession()
+
+	if p.generateBtn.Clicked(gtx) {
+		p.runProjection(false, currentSession)
+	}
+	if p.exportBtn.Clicked(gtx) {
+		p.runProjection(true, currentSession)
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D { return p.layoutForm(gtx, th) }),
+		layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+		layout.Rigid(func(gtx C) D {
+			if p.statusMessage == "" {
+				return D{}
+			}
+			lbl := material.Body2(th, p.statusMessage)
+			lbl.Color = p.messageColor
+			return layout.Inset{Bottom: theme.DefaultVSpacer}.Layout(gtx, lbl.Layout)
+		}),
+		layout.Rigid(func(gtx C) D {
+			if p.projection == nil || len(p.projection.Periods) == 0 {
+				return D{}
+			}
+			return layout.Inset{Bottom: theme.DefaultVSpacer}.Layout(gtx, func(gtx C) D { return p.layoutChart(gtx, th) })
+		}),
+		layout.Flexed(1, func(gtx C) D { return p.layoutTable(gtx, th) }),
+	)
+}
+
+// layoutForm desenha o formulário de parâmetros da projeção.
+func (p *CashFlowPage) layoutForm(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	exportBtn := material.Button(th, &p.exportBtn, "Exportar XLSX")
+	generateBtn := material.Button(th, &p.generateBtn, "Gerar")
+	if p.isLoading || p.accessDenied {
+		exportBtn.Background = theme.Colors.Grey300
+		exportBtn.Color = theme.Colors.TextMuted
+		generateBtn.Background = theme.Colors.Grey300
+		generateBtn.Color = theme.Colors.TextMuted
+	}
+	editor := func(ed *widget.Editor, hint string, width unit.Dp) layout.Widget {
+		return func(gtx C) D {
+			gtx.Constraints.Min.X = gtx.Dp(width)
+			gtx.Constraints.Max.X = gtx.Constraints.Min.X
+			return material.Editor(th, ed, hint).Layout(gtx)
+		}
+	}
+
+	return material.Card(th, theme.Colors.Surface, theme.ElevationSmall, layout.UniformInset(unit.Dp(12)),
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx C) D {
+					return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+						layout.Rigid(material.Body2(th, "Periodicidade:").Layout),
+						layout.Rigid(material.RadioButton(th, &p.granularityEnum, string(models.CashFlowDaily), models.CashFlowDaily.Label()).Layout),
+						layout.Rigid(material.RadioButton(th, &p.granularityEnum, string(models.CashFlowWeekly), models.CashFlowWeekly.Label()).Layout),
+						layout.Rigid(material.RadioButton(th, &p.granularityEnum, string(models.CashFlowMonthly), models.CashFlowMonthly.Label()).Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(24)}.Layout),
+						layout.Rigid(material.Body2(th, "Início:").Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+						layout.Rigid(editor(&p.startDateInput, "DD/MM/AAAA", 110)),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(16)}.Layout),
+						layout.Rigid(material.Body2(th, "Horizonte (dias):").Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+						layout.Rigid(editor(&p.horizonInput, "90", 60)),
+					)
+				}),
+				layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+				layout.Rigid(func(gtx C) D {
+					return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+						layout.Rigid(material.CheckBox(th, &p.scheduledCheck, "Usar a data programada (DTAPROGRAMADA), quando preenchida, no lugar do vencimento").Layout),
+						layout.Flexed(1, func(gtx C) D { return D{} }),
+						layout.Rigid(func(gtx C) D {
+							if !p.isLoading {
+								return D{}
+							}
+							return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, p.spinner.Layout)
+						}),
+						layout.Rigid(exportBtn.Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+						layout.Rigid(generateBtn.Layout),
+					)
+				}),
+			)
+		}).Layout(gtx)
+}
+
+// layoutChart desenha o gráfico de entradas, saídas e saldo acumulado, com a legenda.
+func (p *CashFlowPage) layoutChart(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	legendItem := func(col color.NRGBA, text string) layout.FlexChild {
+		return layout.Rigid(func(gtx C) D {
+			return layout.Inset{Right: unit.Dp(16)}.Layout(gtx, func(gtx C) D {
+				return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(func(gtx C) D {
+						size := gtx.Dp(unit.Dp(10))
+						rect := clip.Rect{Max: image.Pt(size, size)}
+						paint.FillShape(gtx.Ops, col, rect.Op())
+						return D{Size: image.Pt(size, size)}
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(4)}.Layout),
+					layout.Rigid(material.Caption(th, text).Layout),
+				)
+			})
+		})
+	}
+	return material.Card(th, theme.Colors.Surface, theme.ElevationSmall, layout.UniformInset(unit.Dp(12)),
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx C) D {
+					return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+						legendItem(p.chart.InflowColor, "Entradas (a receber)"),
+						legendItem(p.chart.OutflowColor, "Saídas (a pagar)"),
+						legendItem(p.chart.LineColor, "Saldo acumulado"),
+					)
+				}),
+				layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+				layout.Rigid(p.chart.Layout),
+			)
+		}).Layout(gtx)
+}
+
+// layoutTable desenha a tabela da projeção: uma linha por período e a linha de total fixa no rodapé.
+func (p *CashFlowPage) layoutTable(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	if p.projection == nil {
+		return D{}
+	}
+	pr := p.projection
+	headers := []string{"Período", "Entradas", "Títulos", "Saídas", "Títulos", "Saldo do período", "Saldo acumulado"}
+	cellsOf := func(label string, period models.CashFlowPeriod) []string {
+		return []string{label, formatMoney(period.Inflow), fmt.Sprint(period.InflowCount),
+			formatMoney(period.Outflow), fmt.Sprint(period.OutflowCount),
+			formatMoney(period.Net), formatMoney(period.Cumulative)}
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			return layoutReportRow(gtx, th, headers, cashFlowWeights, theme.Colors.Grey200, true)
+		}),
+		layout.Flexed(1, func(gtx C) D {
+			return material.List(th, &p.resultList).Layout(gtx, len(pr.Periods), func(gtx C, index int) D {
+				period := pr.Periods[index]
+				bgColor := theme.Colors.Surface
+				if index%2 != 0 {
+					bgColor = theme.Colors.BackgroundAlt
+				}
+				return layoutReportRow(gtx, th, cellsOf(period.Label(pr.Options.Granularity), period), cashFlowWeights, bgColor, false)
+			})
+		}),
+		layout.Rigid(func(gtx C) D {
+			return layoutReportRow(gtx, th, cellsOf("Total", pr.Totals), cashFlowWeights, theme.Colors.Grey200, true)
+		}),
+	)
+}
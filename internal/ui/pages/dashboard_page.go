@@ -0,0 +1,426 @@
+package pages
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"time"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/shopspring/decimal"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/config"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/navigation"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/services"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/theme"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/ui/components"
+)
+
+// Pesos das colunas das tabelas do painel.
+var (
+	dashboardNetworkWeights = []float32{0.18, 0.14, 0.12, 0.12, 0.12, 0.12, 0.10, 0.10}
+	dashboardCNPJWeights    = []float32{0.17, 0.27, 0.12, 0.15, 0.15, 0.14}
+)
+
+// DashboardPage é o painel de exposição financeira por rede, exibido logo após o login.
+// Clicar em uma rede abre seus CNPJs e maiores títulos; dali é possível abrir a consulta
+// de títulos já filtrada pela rede ou por um CNPJ.
+type DashboardPage struct {
+	router           *ui.Router
+	cfg              *core.Config
+	dashboardService services.DashboardService
+	permManager      *auth.PermissionManager
+	sessionManager   *auth.SessionManager
+	navigateTo       func(moduleID navigation.PageID, params interface{}) // Troca de módulo na MainAppLayout.
+
+	refreshBtn widget.Clickable
+
+	dashboard  *models.NetworkDashboard
+	selected   int // Índice da rede aberta no detalhamento (-1 se nenhuma).
+	list       widget.List
+	rowClicks  []widget.Clickable
+	detailList widget.List
+	cnpjClicks []widget.Clickable
+	backBtn    widget.Clickable
+	titulosBtn widget.Clickable
+	isLoading  bool
+
+	statusMessage string
+	messageColor  color.NRGBA
+	accessDenied  bool
+	spinner       *components.LoadingSpinner
+}
+
+// NewDashboardPage cria uma nova instância do painel de redes.
+func NewDashboardPage(
+	router *ui.Router,
+	cfg *core.Config,
+	dashboardSvc services.DashboardService,
+	permMan *auth.PermissionManager,
+	sessMan *auth.SessionManager,
+	navigateTo func(moduleID navigation.PageID, params interface{}),
+) *DashboardPage {
+	p := &DashboardPage{
+		router:           router,
+		cfg:              cfg,
+		dashboardService: dashboardSvc,
+		permManager:      permMan,
+		sessionManager:   sessMan,
+		navigateTo:       navigateTo,
+		selected:         -1,
+		spinner:          components.NewLoadingSpinner(theme.Colors.Primary),
+	}
+	p.list.Axis = layout.Vertical
+	p.detailList.Axis = layout.Vertical
+	return p
+}
+
+// OnNavigatedTo é chamado quando a página se torna ativa. O painel é sempre recalculado,
+// pois as redes visíveis dependem do usuário logado e os títulos podem ter sido reimportados.
+func (p *DashboardPage) OnNavigatedTo(params interface{}) {
+	appLogger.Info("Navegou para DashboardPage")
+	currentSession, errSess := p.sessionManager.GetCurrentSession()
+	if errSess != nil || currentSession == nil {
+		p.router.GetAppWindow().HandleLogout()
+		return
+	}
+	if err := p.permManager.CheckPermission(currentSession, auth.PermTituloView, nil); err != nil {
+		p.accessDenied = true
+		p.dashboard = nil
+		p.statusMessage = fmt.Sprintf("Acesso negado ao painel de redes: %v", err)
+		p.messageColor = theme.Colors.Danger
+		p.router.GetAppWindow().Invalidate()
+		return
+	}
+	p.accessDenied = false
+	p.load(currentSession)
+}
+
+// OnNavigatedFrom é chamado quando o router navega para fora desta página.
+func (p *DashboardPage) OnNavigatedFrom() {
+	appLogger.Info("Navegando para fora da DashboardPage")
+	p.spinner.Stop(p.router.GetAppWindow().Context())
+}
+
+// load recalcula o painel em segundo plano, mantendo aberta a rede selecionada, se ainda visível.
+func (p *DashboardPage) load(currentSession *auth.SessionData) {
+	if p.isLoading || p.accessDenied {
+		return
+	}
+	p.isLoading = true
+	p.statusMessage = "Calculando exposição por rede..."
+	p.messageColor = theme.Colors.TextMuted
+	p.spinner.Start(p.router.GetAppWindow().Context())
+	p.router.GetAppWindow().Invalidate()
+
+	var selectedID *uint64
+	if p.dashboard != nil && p.selected >= 0 && p.selected < len(p.dashboard.Networks) {
+		id := p.dashboard.Networks[p.selected].NetworkID
+		selectedID = &id
+	}
+
+	go func(sess *auth.SessionData) {
+		dashboard, err := p.dashboardService.GetNetworkDashboard(sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			p.isLoading = false
+			p.spinner.Stop(p.router.GetAppWindow().Context())
+			if err != nil {
+				p.statusMessage = fmt.Sprintf("Erro ao calcular o painel de redes: %v", err)
+				p.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao calcular painel de redes: %v", err)
+				p.router.GetAppWindow().Invalidate()
+				return
+			}
+			p.dashboard = dashboard
+			p.selected = -1
+			for i, network := range dashboard.Networks {
+				if selectedID != nil && network.NetworkID == *selectedID {
+					p.selected = i
+					break
+				}
+			}
+			p.rowClicks = make([]widget.Clickable, len(dashboard.Networks))
+			p.resetDetail()
+			p.statusMessage = p.summaryMessage()
+			p.messageColor = theme.Colors.TextMuted
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(currentSession)
+}
+
+// resetDetail prepara o detalhamento da rede selecionada.
+func (p *DashboardPage) resetDetail() {
+	p.detailList.Position = layout.Position{}
+	p.cnpjClicks = nil
+	if network := p.selectedNetwork(); network != nil {
+		p.cnpjClicks = make([]widget.Clickable, len(network.CNPJs))
+	}
+}
+
+// selectedNetwork retorna a rede aberta no detalhamento, ou nil.
+func (p *DashboardPage) selectedNetwork() *models.NetworkExposure {
+	if p.dashboard == nil || p.selected < 0 || p.selected >= len(p.dashboard.Networks) {
+		return nil
+	}
+	return &p.dashboard.Networks[p.selected]
+}
+
+// summaryMessage descreve o painel carregado: totais, escopo e base da comparação.
+func (p *DashboardPage) summaryMessage() string {
+	d := p.dashboard
+	var receivable, payable decimal.Decimal
+	for _, network := range d.Networks {
+		receivable = receivable.Add(network.Receivable)
+		payable = payable.Add(network.Payable)
+	}
+	msg := fmt.Sprintf("%d redes. A receber: %s. A pagar: %s. Vencidos até %s.",
+		len(d.Networks), formatMoney(receivable), formatMoney(payable), d.ReferenceDate.AddDate(0, 0, -1).Format("02/01/2006"))
+	if d.OwnNetworksOnly {
+		msg += " Exibindo apenas as redes criadas por você."
+	}
+	prev := func(label string, t *time.Time) string {
+		if t == nil {
+			return fmt.Sprintf(" Sem importação anterior de %s para comparar.", label)
+		}
+		return fmt.Sprintf(" Variação de %s desde a importação de %s.", label, t.Local().Format("02/01/2006 15:04"))
+	}
+	return msg + prev("direitos", d.PrevDireitosAt) + prev("obrigações", d.PrevObrigacoesAt)
+}
+
+// openTitulos abre a consulta de títulos filtrada.
+func (p *DashboardPage) openTitulos(filter models.TituloFilter) {
+	if p.navigateTo == nil {
+		return
+	}
+	p.navigateTo(ui.PageTitulos, filter)
+}
+
+// formatTrend formata a variação desde a importação anterior ("-" quando não há comparação).
+func formatTrend(trend *decimal.Decimal) string {
+	if trend == nil {
+		return "-"
+	}
+	if trend.IsPositive() {
+		return "+" + formatMoney(*trend)
+	}
+	return formatMoney(*trend)
+}
+
+// Layout desenha a página.
+func (p *DashboardPage) Layout(gtx layout.Context) layout.Dimensions {
+	th := p.router.GetAppWindow().Theme()
+	currentSession, _ := p.sessionManager.GetCurrentSession()
+
+	if p.refreshBtn.Clicked(gtx) {
+		p.load(currentSession)
+	}
+	for i := range p.rowClicks {
+		if p.rowClicks[i].Clicked(gtx) {
+			p.selected = i
+			p.resetDetail()
+		}
+	}
+	if p.backBtn.Clicked(gtx) {
+		p.selected = -1
+		p.resetDetail()
+	}
+	if network := p.selectedNetwork(); network != nil {
+		if p.titulosBtn.Clicked(gtx) {
+			id := network.NetworkID
+			p.openTitulos(models.TituloFilter{NetworkID: &id})
+		}
+		for i := range p.cnpjClicks {
+			if p.cnpjClicks[i].Clicked(gtx) && i < len(network.CNPJs) {
+				cnpj := network.CNPJs[i].CNPJCPF
+				p.openTitulos(models.TituloFilter{CNPJCPF: &cnpj})
+			}
+		}
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D { return p.layoutHeader(gtx, th) }),
+		layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+		layout.Rigid(func(gtx C) D {
+			if p.statusMessage == "" {
+				return D{}
+			}
+			lbl := material.Body2(th, p.statusMessage)
+			lbl.Color = p.messageColor
+			return layout.Inset{Bottom: theme.DefaultVSpacer}.Layout(gtx, lbl.Layout)
+		}),
+		layout.Flexed(1, func(gtx C) D {
+			if p.dashboard == nil {
+				return D{}
+			}
+			if network := p.selectedNetwork(); network != nil {
+				return p.layoutNetworkDetail(gtx, th, network)
+			}
+			return p.layoutNetworks(gtx, th)
+		}),
+	)
+}
+
+// layoutHeader desenha o título da página e o botão de atualização.
+func (p *DashboardPage) layoutHeader(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	refreshBtn := material.Button(th, &p.refreshBtn, "Atualizar")
+	if p.isLoading || p.accessDenied {
+		refreshBtn.Background = theme.Colors.Grey300
+		refreshBtn.Color = theme.Colors.TextMuted
+	}
+	return material.Card(th, theme.Colors.Surface, theme.ElevationSmall, layout.UniformInset(unit.Dp(12)),
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+				layout.Flexed(1, func(gtx C) D {
+					title := material.H6(th, "Exposição por Rede")
+					title.Font.Weight = font.SemiBold
+					return title.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx C) D {
+					if !p.isLoading {
+						return D{}
+					}
+					return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, p.spinner.Layout)
+				}),
+				layout.Rigid(refreshBtn.Layout),
+			)
+		}).Layout(gtx)
+}
+
+// layoutNetworks desenha a tabela de redes.
+func (p *DashboardPage) layoutNetworks(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	networks := p.dashboard.Networks
+	headers := []string{"Rede", "Comprador", "A receber", "A pagar", "Receber vencido", "Pagar vencido", "Var. receber", "Var. pagar"}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			return layoutReportRow(gtx, th, headers, dashboardNetworkWeights, theme.Colors.Grey200, true)
+		}),
+		layout.Flexed(1, func(gtx C) D {
+			if len(networks) == 0 {
+				lbl := material.Body2(th, "Nenhuma rede disponível para o seu usuário.")
+				lbl.Color = theme.Colors.Info
+				return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, lbl.Layout)
+			}
+			return material.List(th, &p.list).Layout(gtx, len(networks), func(gtx C, index int) D {
+				if index >= len(p.rowClicks) {
+					return D{}
+				}
+				network := networks[index]
+				bgColor := theme.Colors.Surface
+				if index%2 != 0 {
+					bgColor = theme.Colors.BackgroundAlt
+				}
+				name := network.Name
+				if !network.Active {
+					name += " (inativa)"
+				}
+				cells := []string{name, network.Buyer,
+					formatMoney(network.Receivable), formatMoney(network.Payable),
+					formatMoney(network.OverdueReceivable), formatMoney(network.OverduePayable),
+					formatTrend(network.ReceivableTrend()), formatTrend(network.PayableTrend())}
+				return material.Clickable(gtx, &p.rowClicks[index], func(gtx C) D {
+					return layoutReportRow(gtx, th, cells, dashboardNetworkWeights, bgColor, false)
+				})
+			})
+		}),
+	)
+}
+
+// layoutNetworkDetail desenha o detalhamento de uma rede: resumo, CNPJs e maiores títulos.
+func (p *DashboardPage) layoutNetworkDetail(gtx layout.Context, th *material.Theme, network *models.NetworkExposure) layout.Dimensions {
+	cnpjHeaders := []string{"CNPJ/CPF", "Pessoa", "Cadastro", "A receber", "A pagar", "Vencido"}
+	date := func(t *time.Time) string {
+		if t == nil {
+			return "sem vencimento"
+		}
+		return t.Format("02/01/2006")
+	}
+	// Seções do detalhamento, em uma única lista rolável.
+	const (
+		sectionCNPJTitle = iota
+		sectionCNPJHeader
+		sectionFirstCNPJ
+	)
+	titlesStart := sectionFirstCNPJ + len(network.CNPJs)
+	total := titlesStart + 1 + len(network.LargestTitles)
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			title := material.Subtitle1(th, fmt.Sprintf("%s — comprador: %s", network.Name, network.Buyer))
+			title.Font.Weight = font.SemiBold
+			title.MaxLines = 1
+			return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+				layout.Flexed(1, title.Layout),
+				layout.Rigid(material.Button(th, &p.titulosBtn, "Ver títulos da rede").Layout),
+				layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+				layout.Rigid(material.Button(th, &p.backBtn, "Voltar").Layout),
+			)
+		}),
+		layout.Rigid(func(gtx C) D {
+			lbl := material.Body2(th, fmt.Sprintf("A receber: %s em %d títulos (vencido %s, variação %s). A pagar: %s em %d títulos (vencido %s, variação %s).",
+				formatMoney(network.Receivable), network.ReceivableCount, formatMoney(network.OverdueReceivable), formatTrend(network.ReceivableTrend()),
+				formatMoney(network.Payable), network.PayableCount, formatMoney(network.OverduePayable), formatTrend(network.PayableTrend())))
+			lbl.Color = theme.Colors.TextMuted
+			return layout.Inset{Top: unit.Dp(4), Bottom: theme.DefaultVSpacer}.Layout(gtx, lbl.Layout)
+		}),
+		layout.Flexed(1, func(gtx C) D {
+			return material.List(th, &p.detailList).Layout(gtx, total, func(gtx C, index int) D {
+				switch {
+				case index == sectionCNPJTitle:
+					return material.Body1(th, "CNPJs da rede (clique para ver os títulos):").Layout(gtx)
+				case index == sectionCNPJHeader:
+					return layoutReportRow(gtx, th, cnpjHeaders, dashboardCNPJWeights, theme.Colors.Grey200, true)
+				case index < titlesStart:
+					i := index - sectionFirstCNPJ
+					if i >= len(p.cnpjClicks) {
+						return D{}
+					}
+					cnpj := network.CNPJs[i]
+					bgColor := theme.Colors.Surface
+					if i%2 != 0 {
+						bgColor = theme.Colors.BackgroundAlt
+					}
+					registration := "Não cadastrado"
+					if cnpj.Registered {
+						registration = "Ativo"
+						if !cnpj.Active {
+							registration = "Inativo"
+						}
+					}
+					cells := []string{models.FormatCNPJCPF(cnpj.CNPJCPF), cnpj.Pessoa, registration,
+						formatMoney(cnpj.Receivable), formatMoney(cnpj.Payable), formatMoney(cnpj.Overdue)}
+					return material.Clickable(gtx, &p.cnpjClicks[i], func(gtx C) D {
+						return layoutReportRow(gtx, th, cells, dashboardCNPJWeights, bgColor, false)
+					})
+				case index == titlesStart:
+					text := fmt.Sprintf("Maiores títulos em aberto (até %d):", models.NetworkDashboardTopTitles)
+					if len(network.LargestTitles) == 0 {
+						text = "Nenhum título em aberto vinculado à rede."
+					}
+					return layout.Inset{Top: theme.DefaultVSpacer}.Layout(gtx, material.Body1(th, text).Layout)
+				default:
+					title := network.LargestTitles[index-titlesStart-1]
+					kind := "A receber"
+					if title.FileType == string(services.FileTypeObrigacoes) {
+						kind = "A pagar"
+					}
+					lbl := material.Body2(th, fmt.Sprintf("%s  %s  —  %s %s (%s), venc. %s",
+						formatMoney(title.OpenBalance), kind, strings.TrimSpace(title.Titulo),
+						title.Pessoa, models.FormatCNPJCPF(title.CNPJCPF), date(title.DataVencimento)))
+					if title.Overdue {
+						lbl.Color = theme.Colors.Danger
+					}
+					return layout.Inset{Top: unit.Dp(2), Bottom: unit.Dp(2)}.Layout(gtx, lbl.Layout)
+				}
+			})
+		}),
+	)
+}
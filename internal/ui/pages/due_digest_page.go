@@ -0,0 +1,314 @@
+package pages
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/config"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/services"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/theme"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/ui/components"
+)
+
+// DueDigestPage permite ao usuário se inscrever no resumo de vencimentos por e-mail, escolher a
+// frequência e a janela dos títulos a vencer, e pedir o envio imediato do resumo.
+type DueDigestPage struct {
+	router           *ui.Router
+	cfg              *core.Config
+	dueDigestService services.DueDigestService
+	permManager      *auth.PermissionManager
+	sessionManager   *auth.SessionManager
+
+	enabledCheck   widget.Bool
+	frequencyEnum  widget.Enum // models.DueDigestFrequency.
+	daysAheadInput widget.Editor
+	saveBtn        widget.Clickable
+	sendNowBtn     widget.Clickable
+	subscription   *models.DueDigestSubscriptionPublic
+	isLoading      bool
+	statusMessage  string
+	messageColor   color.NRGBA
+
+	accessDenied bool
+	spinner      *components.LoadingSpinner
+}
+
+// NewDueDigestPage cria uma nova instância da página do resumo de vencimentos.
+func NewDueDigestPage(
+	router *ui.Router,
+	cfg *core.Config,
+	dueDigestSvc services.DueDigestService,
+	permMan *auth.PermissionManager,
+	sessMan *auth.SessionManager,
+) *DueDigestPage {
+	p := &DueDigestPage{
+		router:           router,
+		cfg:              cfg,
+		dueDigestService: dueDigestSvc,
+		permManager:      permMan,
+		sessionManager:   sessMan,
+		spinner:          components.NewLoadingSpinner(theme.Colors.Primary),
+	}
+	p.frequencyEnum.Value = string(models.DueDigestDaily)
+	p.daysAheadInput.SingleLine = true
+	p.daysAheadInput.Filter = "0123456789"
+	p.daysAheadInput.SetText(strconv.Itoa(models.DueDigestDefaultDaysAhead))
+	return p
+}
+
+// OnNavigatedTo é chamado quando a página se torna ativa.
+func (p *DueDigestPage) OnNavigatedTo(params interface{}) {
+	appLogger.Info("Navegou para DueDigestPage")
+	currentSession, errSess := p.sessionManager.GetCurrentSession()
+	if errSess != nil || currentSession == nil {
+		p.router.GetAppWindow().HandleLogout()
+		return
+	}
+	if err := p.permManager.CheckPermission(currentSession, auth.PermTituloView, nil); err != nil {
+		p.accessDenied = true
+		p.statusMessage = fmt.Sprintf("Acesso negado ao resumo de vencimentos: %v", err)
+		p.messageColor = theme.Colors.Danger
+		p.router.GetAppWindow().Invalidate()
+		return
+	}
+	p.accessDenied = false
+	p.loadSubscription(currentSession)
+}
+
+// OnNavigatedFrom é chamado quando o router navega para fora desta página.
+func (p *DueDigestPage) OnNavigatedFrom() {
+	appLogger.Info("Navegando para fora da DueDigestPage")
+	p.spinner.Stop(p.router.GetAppWindow().Context())
+}
+
+// startLoading marca a página como ocupada com a mensagem informada.
+func (p *DueDigestPage) startLoading(message string) {
+	p.isLoading = true
+	p.statusMessage = message
+	p.messageColor = theme.Colors.TextMuted
+	p.spinner.Start(p.router.GetAppWindow().Context())
+	p.router.GetAppWindow().Invalidate()
+}
+
+// stopLoading libera a página; chamado na goroutine da UI.
+func (p *DueDigestPage) stopLoading() {
+	p.isLoading = false
+	p.spinner.Stop(p.router.GetAppWindow().Context())
+}
+
+// setSubscription guarda a inscrição e atualiza o formulário.
+func (p *DueDigestPage) setSubscription(sub *models.DueDigestSubscriptionPublic) {
+	p.subscription = sub
+	p.enabledCheck.Value = sub.Enabled
+	p.frequencyEnum.Value = string(sub.Frequency)
+	p.daysAheadInput.SetText(strconv.Itoa(sub.DaysAhead))
+}
+
+// audienceMessage descreve o conteúdo do resumo do usuário.
+func (p *DueDigestPage) audienceMessage() string {
+	sub := p.subscription
+	switch sub.Audience {
+	case models.DueDigestAudienceFinance:
+		return "Você recebe o resumo do financeiro, com os títulos de todas as redes."
+	case models.DueDigestAudienceBuyer:
+		return "Você recebe o resumo como comprador das redes: " + strings.Join(sub.Networks, ", ") + "."
+	default:
+		return "Você não é comprador responsável por nenhuma rede ativa: o resumo não terá títulos até que uma rede seja atribuída a você."
+	}
+}
+
+// loadSubscription carrega a inscrição do usuário em segundo plano.
+func (p *DueDigestPage) loadSubscription(currentSession *auth.SessionData) {
+	if p.isLoading {
+		return
+	}
+	p.startLoading("Carregando inscrição...")
+
+	go func(sess *auth.SessionData) {
+		sub, err := p.dueDigestService.GetMySubscription(sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			p.stopLoading()
+			if err != nil {
+				p.statusMessage = fmt.Sprintf("Erro ao carregar a inscrição no resumo de vencimentos: %v", err)
+				p.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao carregar inscrição no resumo de vencimentos de '%s': %v", sess.Username, err)
+			} else {
+				p.setSubscription(sub)
+				p.statusMessage = p.audienceMessage()
+				p.messageColor = theme.Colors.TextMuted
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(currentSession)
+}
+
+// save grava a inscrição em segundo plano.
+func (p *DueDigestPage) save(currentSession *auth.SessionData) {
+	if p.isLoading || p.accessDenied {
+		return
+	}
+	daysAhead, err := strconv.Atoi(strings.TrimSpace(p.daysAheadInput.Text()))
+	if err != nil || daysAhead < 1 || daysAhead > models.DueDigestMaxDaysAhead {
+		p.statusMessage = fmt.Sprintf("Janela inválida: informe de 1 a %d dias.", models.DueDigestMaxDaysAhead)
+		p.messageColor = theme.Colors.Danger
+		p.router.GetAppWindow().Invalidate()
+		return
+	}
+	data := models.DueDigestSubscriptionUpdate{
+		Enabled:   p.enabledCheck.Value,
+		Frequency: models.DueDigestFrequency(p.frequencyEnum.Value),
+		DaysAhead: daysAhead,
+	}
+	p.startLoading("Salvando inscrição...")
+
+	go func(sess *auth.SessionData) {
+		sub, err := p.dueDigestService.UpdateMySubscription(data, sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			p.stopLoading()
+			if err != nil {
+				p.statusMessage = fmt.Sprintf("Erro ao salvar a inscrição: %v", err)
+				p.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao salvar inscrição no resumo de vencimentos de '%s': %v", sess.Username, err)
+			} else {
+				p.setSubscription(sub)
+				if sub.Enabled {
+					p.statusMessage = fmt.Sprintf("Inscrição salva: resumo %s, títulos a vencer nos próximos %d dias. %s",
+						strings.ToLower(sub.Frequency.Label()), sub.DaysAhead, p.audienceMessage())
+				} else {
+					p.statusMessage = "Inscrição salva: você não receberá o resumo de vencimentos."
+				}
+				p.messageColor = theme.Colors.Success
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(currentSession)
+}
+
+// sendNow envia o resumo imediatamente em segundo plano.
+func (p *DueDigestPage) sendNow(currentSession *auth.SessionData) {
+	if p.isLoading || p.accessDenied {
+		return
+	}
+	p.startLoading("Enviando resumo de vencimentos...")
+
+	go func(sess *auth.SessionData) {
+		digest, err := p.dueDigestService.SendMyDigestNow(sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			p.stopLoading()
+			if err != nil {
+				p.statusMessage = fmt.Sprintf("Erro ao enviar o resumo: %v", err)
+				p.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao enviar resumo de vencimentos a '%s': %v", sess.Username, err)
+			} else {
+				p.statusMessage = fmt.Sprintf("Resumo enviado: %d títulos a vencer (%s) e %d vencidos desde a última importação (%s).",
+					digest.Upcoming.Count, formatMoney(digest.Upcoming.Total), digest.NewlyOverdue.Count, formatMoney(digest.NewlyOverdue.Total))
+				p.messageColor = theme.Colors.Success
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(currentSession)
+}
+
+// Layout desenha a página.
+func (p *DueDigestPage) Layout(gtx layout.Context) layout.Dimensions {
+	th := p.router.GetAppWindow().Theme()
+	currentSession, _ := p.sessionManager.GetCurrentSession()
+
+	if p.saveBtn.Clicked(gtx) {
+		p.save(currentSession)
+	}
+	if p.sendNowBtn.Clicked(gtx) {
+		p.sendNow(currentSession)
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D { return p.layoutForm(gtx, th) }),
+		layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+		layout.Rigid(func(gtx C) D {
+			if p.statusMessage == "" {
+				return D{}
+			}
+			lbl := material.Body2(th, p.statusMessage)
+			lbl.Color = p.messageColor
+			return layout.Inset{Bottom: theme.DefaultVSpacer}.Layout(gtx, lbl.Layout)
+		}),
+		layout.Rigid(func(gtx C) D {
+			if p.subscription == nil || p.subscription.LastSentAt == nil {
+				return D{}
+			}
+			lbl := material.Caption(th, "Último envio agendado: "+p.subscription.LastSentAt.Local().Format("02/01/2006 15:04"))
+			lbl.Color = theme.Colors.TextMuted
+			return lbl.Layout(gtx)
+		}),
+	)
+}
+
+// layoutForm desenha o formulário da inscrição.
+func (p *DueDigestPage) layoutForm(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	saveBtn := material.Button(th, &p.saveBtn, "Salvar")
+	sendNowBtn := material.Button(th, &p.sendNowBtn, "Enviar agora")
+	if p.isLoading || p.accessDenied {
+		saveBtn.Background = theme.Colors.Grey300
+		saveBtn.Color = theme.Colors.TextMuted
+		sendNowBtn.Background = theme.Colors.Grey300
+		sendNowBtn.Color = theme.Colors.TextMuted
+	}
+
+	return material.Card(th, theme.Colors.Surface, theme.ElevationSmall, layout.UniformInset(unit.Dp(12)),
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(material.Subtitle1(th, "Resumo de vencimentos por e-mail").Layout),
+				layout.Rigid(func(gtx C) D {
+					lbl := material.Body2(th, "Títulos em aberto a vencer nos próximos dias e títulos que venceram desde a última importação, "+
+						"enviados em dias úteis para o seu e-mail.")
+					lbl.Color = theme.Colors.TextMuted
+					return layout.Inset{Top: unit.Dp(4), Bottom: theme.DefaultVSpacer}.Layout(gtx, lbl.Layout)
+				}),
+				layout.Rigid(material.CheckBox(th, &p.enabledCheck, "Receber o resumo de vencimentos por e-mail").Layout),
+				layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+				layout.Rigid(func(gtx C) D {
+					return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+						layout.Rigid(material.Body2(th, "Frequência:").Layout),
+						layout.Rigid(material.RadioButton(th, &p.frequencyEnum, string(models.DueDigestDaily), models.DueDigestDaily.Label()).Layout),
+						layout.Rigid(material.RadioButton(th, &p.frequencyEnum, string(models.DueDigestWeekly), models.DueDigestWeekly.Label()).Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(24)}.Layout),
+						layout.Rigid(material.Body2(th, "A vencer nos próximos (dias):").Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+						layout.Rigid(func(gtx C) D {
+							gtx.Constraints.Min.X = gtx.Dp(60)
+							gtx.Constraints.Max.X = gtx.Constraints.Min.X
+							return material.Editor(th, &p.daysAheadInput, strconv.Itoa(models.DueDigestDefaultDaysAhead)).Layout(gtx)
+						}),
+					)
+				}),
+				layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+				layout.Rigid(func(gtx C) D {
+					return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+						layout.Flexed(1, func(gtx C) D { return D{} }),
+						layout.Rigid(func(gtx C) D {
+							if !p.isLoading {
+								return D{}
+							}
+							return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, p.spinner.Layout)
+						}),
+						layout.Rigid(sendNowBtn.Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+						layout.Rigid(saveBtn.Layout),
+					)
+				}),
+			)
+		}).Layout(gtx)
+}
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"image/color"
 	"path/filepath" // Para filepath.Base
+	"sort"
 	"strings"
 	"time" // Para timeout em mensagens de status
 
 	"gioui.org/font"
 	"gioui.org/layout"
+	"gioui.org/op"
 	"gioui.org/unit"
 	"gioui.org/widget"
 	"gioui.org/widget/material"
@@ -31,6 +33,9 @@ type ImportTypeConfig struct {
 	ID          services.FileType // Ex: services.FileTypeDireitos
 	Title       string            // Título para exibição na UI (ex: "Movimento de Títulos - Direitos")
 	AllowedExts []string          // Extensões de arquivo permitidas (ex: ".txt", ".csv")
+	// Registry indica um arquivo de cadastro (redes e CNPJs, feriados) em vez de títulos: é importado
+	// diretamente, sem pré-visualização, modo incremental, versões anteriores ou verificação de CNPJs.
+	Registry bool
 	// Description string         // Descrição opcional sobre o formato do arquivo
 }
 
@@ -42,7 +47,55 @@ type ImportSectionState struct {
 	SelectedFileName string           // Apenas o nome do arquivo para exibição na UI
 
 	SelectFileBtn widget.Clickable // Botão para abrir o diálogo de seleção de arquivo
-	ImportBtn     widget.Clickable // Botão para iniciar a importação do arquivo selecionado
+	ImportBtn     widget.Clickable // Botão para validar (pré-visualizar) o arquivo selecionado antes de importar
+
+	// Pré-visualização (dry-run): o arquivo é validado sem gravar no banco e o relatório é
+	// exibido no card. A importação real só é feita após a confirmação do usuário.
+	Preview          *models.ImportPreviewReport // Relatório da última pré-visualização (nil se não houver)
+	PreviewFilePath  string                      // Arquivo ao qual `Preview` se refere
+	IsPreviewing     bool                        // True enquanto a pré-visualização está em andamento
+	PreviewIssueList widget.List                 // Lista rolável de problemas do relatório
+	ConfirmImportBtn widget.Clickable            // Confirma a importação após a pré-visualização
+	CancelPreviewBtn widget.Clickable            // Descarta a pré-visualização
+
+	// IncrementalMode, se marcado, aplica o arquivo de forma incremental (upsert pela chave natural)
+	// em vez de substituir todos os títulos do tipo.
+	IncrementalMode widget.Bool
+	// CompanyScope, se marcado, restringe a importação às empresas (NROEMPRESA) presentes no arquivo:
+	// os títulos das demais empresas não são substituídos nem removidos.
+	CompanyScope widget.Bool
+
+	// Planilhas do arquivo XLSX selecionado (vazio para arquivos de texto). `SheetChoice` guarda
+	// a planilha escolhida pelo usuário ("" para seleção automática pelo cabeçalho).
+	SheetNames  []string
+	SheetChoice widget.Enum
+
+	// Encoding e delimitador forçados pelo usuário para arquivos de texto ("" para detecção automática).
+	// `DelimiterChoice` guarda o próprio caractere, ou "TAB" para tabulação.
+	EncodingChoice  widget.Enum
+	DelimiterChoice widget.Enum
+
+	// Perfis de mapeamento de colunas do tipo de arquivo. `ProfileChoice` guarda o ID do perfil
+	// escolhido ("" para o layout padrão).
+	Profiles          []*models.ImportProfilePublic
+	ProfileChoice     widget.Enum
+	ManageProfilesBtn widget.Clickable
+	ProfileEditor     *importProfileEditor // Editor de perfis aberto na seção (nil se fechado)
+	profilesLoaded    bool                 // True após a primeira carga dos perfis (pré-seleção do padrão)
+
+	// Versões anteriores (snapshots) dos títulos do tipo, com a ação de restaurar.
+	SnapshotsBtn  widget.Clickable
+	SnapshotPanel *importSnapshotPanel // Painel de snapshots aberto na seção (nil se fechado)
+
+	// Exportação dos CNPJs dos títulos não cadastrados, inativos ou inválidos.
+	ExportCNPJCheckBtn widget.Clickable
+	IsCheckingCNPJs    bool
+
+	// Job de importação em segundo plano da seção (0 se nenhum). O andamento é consultado a cada quadro
+	// enquanto o job estiver ativo, inclusive ao sair e voltar para a página.
+	JobID        uint64
+	Job          *services.ImportJobStatus // Último estado consultado do job
+	CancelJobBtn widget.Clickable
 
 	IsImporting   bool        // True se este tipo específico estiver sendo importado no momento
 	StatusMessage string      // Mensagem de status específica para esta seção (ex: "Importando...", "Sucesso!")
@@ -72,8 +125,26 @@ type ImportPage struct {
 	spinner *components.LoadingSpinner // Spinner de carregamento global para a página
 
 	firstLoadDone bool // Controla o carregamento inicial de dados
+
+	// Histórico de execuções de importação (uma linha por execução).
+	historyRuns         []*models.ImportRunPublic
+	historyTotal        int64
+	historyOffset       int
+	historyTypeFilter   widget.Enum // "" para todos, ou o FileType
+	historyStatusFilter widget.Enum // "" para todos, ou um models.ImportRunStatus*
+	historyList         widget.List
+	historyPrevBtn      widget.Clickable
+	historyNextBtn      widget.Clickable
+	isLoadingHistory    bool
+	historyMessage      string
 }
 
+// importHistoryPageSize é o número de execuções exibidas por página no histórico.
+const importHistoryPageSize = 20
+
+// importJobPollInterval é o intervalo entre as consultas ao andamento dos jobs de importação ativos.
+const importJobPollInterval = 300 * time.Millisecond
+
 // NewImportPage cria uma nova instância da página de importação.
 func NewImportPage(
 	router *ui.Router,
@@ -94,17 +165,23 @@ func NewImportPage(
 	// Define as configurações para cada tipo de importação que a página suportará.
 	// A ordem aqui define a ordem de exibição na UI.
 	supportedImportTypes := []ImportTypeConfig{
-		{ID: services.FileTypeDireitos, Title: "Importar Títulos de Direitos", AllowedExts: []string{".txt", ".csv"}},
-		{ID: services.FileTypeObrigacoes, Title: "Importar Títulos de Obrigações", AllowedExts: []string{".txt", ".csv"}},
+		{ID: services.FileTypeDireitos, Title: "Importar Títulos de Direitos", AllowedExts: []string{".txt", ".csv", ".xlsx"}},
+		{ID: services.FileTypeObrigacoes, Title: "Importar Títulos de Obrigações", AllowedExts: []string{".txt", ".csv", ".xlsx"}},
+		{ID: services.FileTypeRedesCNPJs, Title: "Importar Redes e CNPJs (REDE, COMPRADOR, CNPJ)", AllowedExts: []string{".txt", ".csv", ".xlsx"}, Registry: true},
+		{ID: services.FileTypeFeriados, Title: "Importar Feriados Locais (DATA, NOME, ABRANGENCIA, LOCAL, ANUAL)", AllowedExts: []string{".txt", ".csv", ".xlsx"}, Registry: true},
 		// Adicionar outros tipos de importação aqui conforme necessário.
 	}
 
+	p.historyList.Axis = layout.Vertical
+
 	p.importSections = make([]*ImportSectionState, 0, len(supportedImportTypes))
 	for _, importCfg := range supportedImportTypes {
-		p.importSections = append(p.importSections, &ImportSectionState{
+		section := &ImportSectionState{
 			Config:         importCfg,
 			LastUpdateText: "Última atualização: <i>Carregando...</i>", // Placeholder inicial
-		})
+		}
+		section.PreviewIssueList.Axis = layout.Vertical
+		p.importSections = append(p.importSections, section)
 	}
 	return p
 }
@@ -139,14 +216,26 @@ func (p *ImportPage) OnNavigatedTo(params interface{}) {
 		// Recarrega os status ao revisitar a página para ter dados frescos.
 		p.loadAllImportStatuses(currentSession)
 	}
+	p.historyOffset = 0
+	p.loadImportHistory(currentSession)
+	p.attachRunningImportJobs(currentSession)
+	if canExecute, _ := p.permManager.HasPermission(currentSession, auth.PermImportExecute, nil); canExecute {
+		for _, section := range p.importSections {
+			p.loadSectionProfiles(section, 0, currentSession)
+		}
+	}
 }
 
 // OnNavigatedFrom é chamado quando o router navega para fora desta página.
 func (p *ImportPage) OnNavigatedFrom() {
 	appLogger.Info("Navegando para fora da ImportPage")
-	// Para qualquer importação em andamento ou spinners.
+	// Para os spinners. Os jobs de importação continuam em segundo plano e são retomados ao voltar
+	// para a página (o JobID da seção é mantido).
 	for _, section := range p.importSections {
-		section.IsImporting = false // Cancela a flag de importação (a goroutine pode continuar, mas a UI não mostrará)
+		if section.JobID == 0 {
+			section.IsImporting = false
+		}
+		section.IsPreviewing = false
 	}
 	p.isLoadingGlobal = false
 	p.spinner.Stop(p.router.GetAppWindow().Context())
@@ -210,6 +299,9 @@ func (p *ImportPage) updateSectionsWithStatus(allStatuses []models.ImportMetadat
 			if status.RecordCount != nil {
 				countStr = fmt.Sprintf(" %d registros.", *status.RecordCount)
 			}
+			if status.Companies != nil && *status.Companies != "" {
+				countStr += fmt.Sprintf(" Empresas: %s.", *status.Companies)
+			}
 			// Usar \n para quebras de linha em vez de HTML, pois Label não renderiza HTML.
 			section.LastUpdateText = fmt.Sprintf("Última atualização: %s%s\n%s", updateTimeStr, countStr, fileNameStr)
 		} else {
@@ -232,6 +324,7 @@ func (p *ImportPage) clearAllSectionStatus() {
 func (p *ImportPage) Layout(gtx layout.Context) layout.Dimensions {
 	th := p.router.GetAppWindow().Theme()
 	currentSession, _ := p.sessionManager.GetCurrentSession() // Para verificações de permissão
+	p.pollImportJobs(gtx, currentSession)
 
 	// Processar cliques nos botões (Seleção de arquivo e Importação)
 	for _, section := range p.importSections {
@@ -242,11 +335,57 @@ func (p *ImportPage) Layout(gtx layout.Context) layout.Dimensions {
 			p.handleSelectFile(currentSection)
 		}
 		if currentSection.ImportBtn.Clicked(gtx) {
+			if currentSection.Config.Registry { // Cadastro: importa direto, sem pré-visualização.
+				p.handleImportFile(currentSection, currentSession)
+			} else {
+				p.handlePreviewFile(currentSection, currentSession)
+			}
+		}
+		if currentSection.ConfirmImportBtn.Clicked(gtx) {
 			p.handleImportFile(currentSection, currentSession)
 		}
+		if currentSection.SheetChoice.Update(gtx) { // Outra planilha exige uma nova pré-visualização.
+			currentSection.clearPreview()
+		}
+		if currentSection.ProfileChoice.Update(gtx) { // Outro perfil também.
+			currentSection.clearPreview()
+		}
+		encodingChanged := currentSection.EncodingChoice.Update(gtx)
+		if delimiterChanged := currentSection.DelimiterChoice.Update(gtx); encodingChanged || delimiterChanged {
+			currentSection.clearPreview() // Assim como outro encoding ou delimitador.
+		}
+		if currentSection.CancelPreviewBtn.Clicked(gtx) && !currentSection.IsImporting {
+			currentSection.clearPreview()
+		}
+		if currentSection.CancelJobBtn.Clicked(gtx) && currentSection.JobID != 0 {
+			p.handleCancelImportJob(currentSection, currentSession)
+		}
+		p.handleProfileEditorEvents(gtx, currentSection, currentSession)
+		p.handleSnapshotPanelEvents(gtx, currentSection, currentSession)
+		if currentSection.ExportCNPJCheckBtn.Clicked(gtx) && !currentSection.Config.Registry && !currentSection.IsCheckingCNPJs && !currentSection.IsImporting {
+			p.handleExportCNPJCheck(currentSection, currentSession)
+		}
 	}
 	if p.refreshStatusBtn.Clicked(gtx) && !p.isLoadingGlobal {
 		p.loadAllImportStatuses(currentSession)
+		p.loadImportHistory(currentSession)
+	}
+	typeFilterChanged := p.historyTypeFilter.Update(gtx)
+	statusFilterChanged := p.historyStatusFilter.Update(gtx)
+	if typeFilterChanged || statusFilterChanged {
+		p.historyOffset = 0
+		p.loadImportHistory(currentSession)
+	}
+	if p.historyPrevBtn.Clicked(gtx) && p.historyOffset > 0 && !p.isLoadingHistory {
+		p.historyOffset -= importHistoryPageSize
+		if p.historyOffset < 0 {
+			p.historyOffset = 0
+		}
+		p.loadImportHistory(currentSession)
+	}
+	if p.historyNextBtn.Clicked(gtx) && int64(p.historyOffset+importHistoryPageSize) < p.historyTotal && !p.isLoadingHistory {
+		p.historyOffset += importHistoryPageSize
+		p.loadImportHistory(currentSession)
 	}
 
 
@@ -272,6 +411,11 @@ func (p *ImportPage) Layout(gtx layout.Context) layout.Dimensions {
 			})
 		}),
 
+		// Histórico de execuções de importação
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			return p.layoutImportHistory(gtx, th)
+		}),
+
 		// Mensagem de Status Global da Página
 		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 			if p.statusMessageGlobal != "" && !p.isLoadingGlobal {
@@ -294,6 +438,9 @@ func (p *ImportPage) layoutImportSectionCard(gtx layout.Context, th *material.Th
 
 	// Verifica permissão para executar a importação (para habilitar/desabilitar botão Importar)
 	canExecuteImport, _ := p.permManager.HasPermission(currentSession, auth.PermImportExecute, nil)
+	canManageProfiles, _ := p.permManager.HasPermission(currentSession, auth.PermImportManageProfiles, nil)
+	canRestoreSnapshots, _ := p.permManager.HasPermission(currentSession, auth.PermImportRestoreSnapshot, nil)
+	canExportData, _ := p.permManager.HasPermission(currentSession, auth.PermExportData, nil)
 
 	return material.Card(th, theme.Colors.Surface, theme.ElevationSmall, layout.UniformInset(unit.Dp(16)),
 		func(gtx layout.Context) layout.Dimensions {
@@ -328,7 +475,7 @@ func (p *ImportPage) layoutImportSectionCard(gtx layout.Context, th *material.Th
 						layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
 						layout.Rigid(func(gtx C) D { // Botão Selecionar Arquivo
 							btn := material.Button(th, §ion.SelectFileBtn, "Selecionar...")
-							if section.IsImporting || p.isLoadingGlobal || !canExecuteImport { // Desabilita se importando, carregando globalmente ou sem permissão
+							if section.IsImporting || section.IsPreviewing || p.isLoadingGlobal || !canExecuteImport { // Desabilita se importando, carregando globalmente ou sem permissão
 								btn.Style.TextColor = theme.Colors.TextMuted
 								btn.Style.Background = theme.Colors.Grey300
 							}
@@ -336,8 +483,12 @@ func (p *ImportPage) layoutImportSectionCard(gtx layout.Context, th *material.Th
 						}),
 						layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
 						layout.Rigid(func(gtx layout.Context) layout.Dimensions { // Botão Importar
-							importButton := material.Button(th, §ion.ImportBtn, "Importar Arquivo")
-							if section.SelectedFilePath == "" || section.IsImporting || p.isLoadingGlobal || !canExecuteImport {
+							importLabel := "Validar Arquivo"
+							if section.Config.Registry {
+								importLabel = "Importar Cadastro"
+							}
+							importButton := material.Button(th, §ion.ImportBtn, importLabel)
+							if section.SelectedFilePath == "" || section.IsImporting || section.IsPreviewing || p.isLoadingGlobal || !canExecuteImport {
 								importButton.Style.TextColor = theme.Colors.TextMuted
 								importButton.Style.Background = theme.Colors.Grey300
 							} else {
@@ -347,6 +498,100 @@ func (p *ImportPage) layoutImportSectionCard(gtx layout.Context, th *material.Th
 						}),
 					)
 				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions { // Modo de importação
+					if section.Config.Registry { // O cadastro é sempre aplicado linha a linha.
+						return layout.Dimensions{}
+					}
+					checkBox := material.CheckBox(th, &section.IncrementalMode, "Importação incremental (atualiza títulos existentes em vez de substituir todos)")
+					if section.IsImporting || !canExecuteImport {
+						checkBox.Color = theme.Colors.TextMuted
+						gtx = gtx.Disabled()
+					}
+					return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, checkBox.Layout)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions { // Escopo por empresa
+					if section.Config.Registry {
+						return layout.Dimensions{}
+					}
+					checkBox := material.CheckBox(th, &section.CompanyScope, "Apenas as empresas do arquivo (mantém os títulos das demais empresas / NROEMPRESA)")
+					if section.IsImporting || !canExecuteImport {
+						checkBox.Color = theme.Colors.TextMuted
+						gtx = gtx.Disabled()
+					}
+					return checkBox.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions { // Planilha (apenas arquivos XLSX com mais de uma)
+					if len(section.SheetNames) < 2 {
+						return layout.Dimensions{}
+					}
+					if section.IsImporting || section.IsPreviewing || !canExecuteImport {
+						gtx = gtx.Disabled()
+					}
+					children := []layout.FlexChild{
+						layout.Rigid(material.Body2(th, "Planilha:").Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+						layout.Rigid(material.RadioButton(th, &section.SheetChoice, "", "Automática").Layout),
+					}
+					for _, sheetName := range section.SheetNames {
+						children = append(children, layout.Rigid(material.RadioButton(th, &section.SheetChoice, sheetName, sheetName).Layout))
+					}
+					return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx C) D {
+						return layout.Flex{Alignment: layout.Middle}.Layout(gtx, children...)
+					})
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions { // Encoding e delimitador (apenas arquivos de texto)
+					return p.layoutTextFormatSelector(gtx, th, section, canExecuteImport)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions { // Perfil de mapeamento de colunas
+					return p.layoutProfileSelector(gtx, th, section, canExecuteImport, canManageProfiles)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions { // Editor de perfis
+					if section.ProfileEditor == nil || !canManageProfiles {
+						return layout.Dimensions{}
+					}
+					return layout.Inset{Top: theme.DefaultVSpacer}.Layout(gtx, func(gtx C) D {
+						return p.layoutProfileEditor(gtx, th, section)
+					})
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions { // Versões anteriores (snapshots)
+					if section.Config.Registry {
+						return layout.Dimensions{}
+					}
+					btnLabel := "Versões Anteriores"
+					if section.SnapshotPanel != nil {
+						btnLabel = "Ocultar Versões"
+					}
+					return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx C) D {
+						return layout.Flex{Axis: layout.Vertical, Alignment: layout.End}.Layout(gtx,
+							layout.Rigid(func(gtx C) D {
+								return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+									layout.Rigid(func(gtx C) D {
+										if !canExportData {
+											return D{}
+										}
+										btn := material.Button(th, &section.ExportCNPJCheckBtn, "Exportar CNPJs Pendentes")
+										if section.IsCheckingCNPJs || section.IsImporting {
+											btn.Background = theme.Colors.Grey300
+											btn.Color = theme.Colors.TextMuted
+											gtx = gtx.Disabled()
+										}
+										return btn.Layout(gtx)
+									}),
+									layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+									layout.Rigid(material.Button(th, &section.SnapshotsBtn, btnLabel).Layout),
+								)
+							}),
+							layout.Rigid(func(gtx C) D {
+								if section.SnapshotPanel == nil {
+									return D{}
+								}
+								return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx C) D {
+									return p.layoutSnapshotPanel(gtx, th, section, canRestoreSnapshots)
+								})
+							}),
+						)
+					})
+				}),
 				layout.Rigid(func(gtx layout.Context) layout.Dimensions { // Status específico da seção
 					if section.StatusMessage != "" {
 						lbl := material.Body2(th, section.StatusMessage)
@@ -355,8 +600,24 @@ func (p *ImportPage) layoutImportSectionCard(gtx layout.Context, th *material.Th
 					}
 					return layout.Dimensions{}
 				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions { // Andamento do job de importação
+					if section.JobID == 0 || section.Job == nil {
+						return layout.Dimensions{}
+					}
+					return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx C) D {
+						return p.layoutImportJobProgress(gtx, th, section, canExecuteImport)
+					})
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions { // Relatório da pré-visualização
+					if section.Preview == nil {
+						return layout.Dimensions{}
+					}
+					return layout.Inset{Top: theme.DefaultVSpacer}.Layout(gtx, func(gtx C) D {
+						return p.layoutPreviewPanel(gtx, th, section, canExecuteImport)
+					})
+				}),
 				layout.Rigid(func(gtx C)D { // Spinner local à seção
-				    if section.IsImporting {
+				    if section.IsImporting || section.IsPreviewing {
 				        return layout.Center.Layout(gtx, p.spinner.Layout) // Reutiliza spinner global ou um específico da seção
 				    }
 				    return D{}
@@ -367,9 +628,13 @@ func (p *ImportPage) layoutImportSectionCard(gtx layout.Context, th *material.Th
 
 // handleSelectFile abre o diálogo de seleção de arquivo para a seção especificada.
 func (p *ImportPage) handleSelectFile(section *ImportSectionState) {
-	if section.IsImporting || p.isLoadingGlobal { return } // Não permite selecionar se já estiver ocupado
+	if section.IsImporting || section.IsPreviewing || p.isLoadingGlobal { return } // Não permite selecionar se já estiver ocupado
 
 	section.StatusMessage = ""      // Limpa mensagem de status anterior da seção
+	section.Preview = nil           // Uma nova seleção exige uma nova pré-visualização
+	section.PreviewFilePath = ""
+	section.SheetNames = nil
+	section.SheetChoice.Value = ""
 	p.statusMessageGlobal = ""      // Limpa mensagem global
 	p.router.GetAppWindow().Invalidate()
 
@@ -386,6 +651,14 @@ func (p *ImportPage) handleSelectFile(section *ImportSectionState) {
 		filePath := "/simulado/caminho/para/" + strings.ToLower(string(sec.Config.ID)) + ".txt"
 		var err error // = nil para sucesso simulado
 
+		// Para planilhas XLSX, lista as abas para que o usuário possa escolher qual importar.
+		var sheetNames []string
+		if err == nil && strings.EqualFold(filepath.Ext(filePath), ".xlsx") {
+			if sess, errSess := p.sessionManager.GetCurrentSession(); errSess == nil && sess != nil {
+				sheetNames, err = p.importService.ListImportSheets(filePath, sess)
+			}
+		}
+
 		p.router.GetAppWindow().Execute(func() {
 			if err != nil {
 				// if errors.Is(err, filedialog.ErrCancelled) {
@@ -398,6 +671,9 @@ func (p *ImportPage) handleSelectFile(section *ImportSectionState) {
 			} else if filePath != "" {
 				sec.SelectedFilePath = filePath
 				sec.SelectedFileName = filepath.Base(filePath) // Extrai apenas o nome do arquivo
+				sec.SheetNames = sheetNames
+				sec.EncodingChoice.Value = "" // Um novo arquivo volta à detecção automática.
+				sec.DelimiterChoice.Value = ""
 				sec.StatusMessage = fmt.Sprintf("Arquivo '%s' selecionado.", sec.SelectedFileName)
 				sec.MessageColor = theme.Colors.Info
 			} else {
@@ -411,11 +687,130 @@ func (p *ImportPage) handleSelectFile(section *ImportSectionState) {
 	}(section)
 }
 
-// handleImportFile inicia o processo de importação para o arquivo selecionado na seção.
+// importDelimiterTab é o valor de `DelimiterChoice` para a tabulação.
+const importDelimiterTab = "TAB"
+
+// applyTextFormat aplica às opções o encoding e o delimitador escolhidos na seção, se houver.
+func (section *ImportSectionState) applyTextFormat(opts *services.ImportOptions) {
+	opts.Encoding = services.ImportEncoding(section.EncodingChoice.Value)
+	switch value := section.DelimiterChoice.Value; value {
+	case "":
+		opts.Delimiter = 0
+	case importDelimiterTab:
+		opts.Delimiter = '\t'
+	default:
+		opts.Delimiter = []rune(value)[0]
+	}
+}
+
+// layoutTextFormatSelector desenha a escolha do encoding e do delimitador de um arquivo de texto
+// selecionado. "Automático" usa a detecção, cujo resultado aparece na pré-visualização.
+func (p *ImportPage) layoutTextFormatSelector(gtx layout.Context, th *material.Theme, section *ImportSectionState, canExecuteImport bool) layout.Dimensions {
+	if section.SelectedFilePath == "" || strings.EqualFold(filepath.Ext(section.SelectedFilePath), ".xlsx") {
+		return layout.Dimensions{}
+	}
+	if section.IsImporting || section.IsPreviewing || !canExecuteImport {
+		gtx = gtx.Disabled()
+	}
+
+	encodingChildren := []layout.FlexChild{
+		layout.Rigid(material.Body2(th, "Encoding:").Layout),
+		layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+		layout.Rigid(material.RadioButton(th, &section.EncodingChoice, "", "Automático").Layout),
+	}
+	for _, enc := range services.ImportEncodings {
+		encodingChildren = append(encodingChildren, layout.Rigid(material.RadioButton(th, &section.EncodingChoice, string(enc), string(enc)).Layout))
+	}
+
+	delimiterChildren := []layout.FlexChild{
+		layout.Rigid(material.Body2(th, "Delimitador:").Layout),
+		layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+		layout.Rigid(material.RadioButton(th, &section.DelimiterChoice, "", "Automático").Layout),
+	}
+	for _, delimiter := range services.ImportDelimiters {
+		value := string(delimiter)
+		if delimiter == '\t' {
+			value = importDelimiterTab
+		}
+		delimiterChildren = append(delimiterChildren, layout.Rigid(material.RadioButton(th, &section.DelimiterChoice, value, services.ImportDelimiterLabel(delimiter)).Layout))
+	}
+
+	return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{Alignment: layout.Middle}.Layout(gtx, encodingChildren...)
+			}),
+			layout.Rigid(func(gtx C) D {
+				return layout.Flex{Alignment: layout.Middle}.Layout(gtx, delimiterChildren...)
+			}),
+		)
+	})
+}
+
+// handlePreviewFile valida o arquivo selecionado sem gravar no banco (dry-run) e exibe o relatório
+// no card. A importação real é iniciada apenas pelo botão de confirmação do relatório.
+func (p *ImportPage) handlePreviewFile(section *ImportSectionState, currentSession *auth.SessionData) {
+	if section.SelectedFilePath == "" || section.IsImporting || section.IsPreviewing || p.isLoadingGlobal {
+		return
+	}
+	if errPerm := p.permManager.CheckPermission(currentSession, auth.PermImportExecute, nil); errPerm != nil {
+		section.StatusMessage = "Você não tem permissão para executar importações."
+		section.MessageColor = theme.Colors.Danger
+		p.router.GetAppWindow().Invalidate()
+		return
+	}
+
+	section.IsPreviewing = true
+	section.Preview = nil
+	section.PreviewFilePath = ""
+	section.StatusMessage = "Validando arquivo (nenhum dado será gravado)..."
+	section.MessageColor = theme.Colors.TextMuted
+	p.spinner.Start(p.router.GetAppWindow().Context())
+	p.router.GetAppWindow().Invalidate()
+
+	opts := services.ImportOptions{SheetName: section.SheetChoice.Value, ProfileID: section.selectedProfileID()}
+	section.applyTextFormat(&opts)
+
+	go func(sec *ImportSectionState, fp string, opts services.ImportOptions, sess *auth.SessionData) {
+		report, previewErr := p.importService.PreviewImport(fp, sec.Config.ID, opts, sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			sec.IsPreviewing = false
+			if !p.anySectionIsImporting() && !p.isLoadingGlobal {
+				p.spinner.Stop(p.router.GetAppWindow().Context())
+			}
+			if previewErr != nil {
+				errMsg := fmt.Sprintf("Arquivo inválido: %v", previewErr)
+				var valErr *appErrors.ValidationError
+				if errors.As(previewErr, &valErr) {
+					errMsg = fmt.Sprintf("Arquivo inválido: %s", valErr.Message)
+				}
+				sec.StatusMessage = errMsg
+				sec.MessageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro na pré-visualização do arquivo tipo %s (%s): %v", sec.Config.ID, fp, previewErr)
+			} else if fp == sec.SelectedFilePath { // Ignora o resultado se outro arquivo foi selecionado nesse meio tempo.
+				sec.Preview = report
+				sec.PreviewFilePath = fp
+				sec.StatusMessage = "Revise o relatório abaixo e confirme a importação."
+				sec.MessageColor = theme.Colors.Info
+				if report.TotalIssues() > 0 {
+					sec.MessageColor = theme.Colors.Warning
+				}
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(section, section.SelectedFilePath, opts, currentSession)
+}
+
+// handleImportFile inicia o processo de importação para o arquivo selecionado na seção,
+// após a pré-visualização ter sido revisada e confirmada pelo usuário.
 func (p *ImportPage) handleImportFile(section *ImportSectionState, currentSession *auth.SessionData) {
-	if section.SelectedFilePath == "" || section.IsImporting || p.isLoadingGlobal {
+	if section.SelectedFilePath == "" || section.IsImporting || section.IsPreviewing || p.isLoadingGlobal {
 		return // Não faz nada se nenhum arquivo selecionado ou já importando/carregando
 	}
+	if !section.Config.Registry && (section.Preview == nil || section.PreviewFilePath != section.SelectedFilePath) {
+		return // A importação de títulos só é feita após a pré-visualização do arquivo selecionado.
+	}
 	// Permissão já verificada para habilitar o botão, mas checar novamente é seguro
 	if errPerm := p.permManager.CheckPermission(currentSession, auth.PermImportExecute, nil); errPerm != nil {
 		section.StatusMessage = "Você não tem permissão para executar importações."
@@ -425,66 +820,335 @@ func (p *ImportPage) handleImportFile(section *ImportSectionState, currentSessio
 	}
 
 
-	// Idealmente, mostrar um diálogo de confirmação: "Tem certeza que deseja importar X, substituindo dados existentes?"
-	// Por agora, prossegue diretamente.
-
+	// A confirmação do usuário é o botão "Confirmar Importação" do relatório de pré-visualização.
+	section.Preview = nil
+	section.PreviewFilePath = ""
 	section.IsImporting = true
 	section.StatusMessage = "Importando arquivo, por favor aguarde..."
 	section.MessageColor = theme.Colors.TextMuted
 	p.spinner.Start(p.router.GetAppWindow().Context()) // Spinner global ou local
 	p.router.GetAppWindow().Invalidate()
 
-	filePathToImport := section.SelectedFilePath // Copia para a goroutine
+	filePathToImport := section.SelectedFilePath
+	importOpts := services.ImportOptions{
+		Mode:      services.ImportModeReplace,
+		SheetName: section.SheetChoice.Value,
+		ProfileID: section.selectedProfileID(),
+	}
+	if section.IncrementalMode.Value {
+		importOpts.Mode = services.ImportModeIncremental
+	}
+	if section.CompanyScope.Value && !section.Config.Registry {
+		importOpts.Scope = services.ImportScopeCompanies
+	}
+	section.applyTextFormat(&importOpts)
+
+	job, errStart := p.importService.StartImportJob(filePathToImport, section.Config.ID, importOpts, currentSession)
+	if errStart != nil {
+		section.IsImporting = false
+		if !p.anySectionIsImporting() && !p.isLoadingGlobal {
+			p.spinner.Stop(p.router.GetAppWindow().Context())
+		}
+		section.StatusMessage = fmt.Sprintf("Falha ao iniciar a importação: %v", errStart)
+		section.MessageColor = theme.Colors.Danger
+		if msg, locked := importLockedMessage(errStart); locked {
+			section.StatusMessage = msg
+			section.MessageColor = theme.Colors.Warning
+		}
+		appLogger.Errorf("Erro ao iniciar job de importação tipo %s (%s): %v", section.Config.ID, filePathToImport, errStart)
+		p.router.GetAppWindow().Invalidate()
+		return
+	}
+	// O andamento e o resultado são acompanhados por pollImportJobs a cada quadro.
+	section.JobID = job.ID
+	section.Job = job
+	p.router.GetAppWindow().Invalidate()
+}
+
+// attachRunningImportJobs associa às seções os jobs de importação ainda em andamento (ex: iniciados
+// antes de o usuário sair da página), para que o andamento volte a ser exibido.
+func (p *ImportPage) attachRunningImportJobs(currentSession *auth.SessionData) {
+	jobs, err := p.importService.GetImportJobs(currentSession)
+	if err != nil {
+		appLogger.Warnf("Erro ao buscar jobs de importação em andamento: %v", err)
+		return
+	}
+	for _, job := range jobs {
+		if job.Done() {
+			continue
+		}
+		for _, section := range p.importSections {
+			if section.Config.ID != job.FileType || section.JobID != 0 {
+				continue
+			}
+			section.JobID = job.ID
+			section.Job = job
+			section.IsImporting = true
+			section.StatusMessage = fmt.Sprintf("Importando arquivo '%s' (iniciado por %s às %s)...",
+				job.FileName, job.StartedBy, job.StartedAt.Local().Format("15:04:05"))
+			section.MessageColor = theme.Colors.TextMuted
+		}
+	}
+	if p.anySectionIsImporting() {
+		p.spinner.Start(p.router.GetAppWindow().Context())
+	}
+	p.router.GetAppWindow().Invalidate()
+}
 
-	go func(sec *ImportSectionState, fp string, sess *auth.SessionData) {
-		var importResult map[string]interface{}
-		var importErr error
+// pollImportJobs atualiza o andamento dos jobs de importação das seções e aplica o resultado dos
+// que terminaram. Enquanto houver job ativo, agenda um novo quadro para o próximo intervalo.
+func (p *ImportPage) pollImportJobs(gtx layout.Context, currentSession *auth.SessionData) {
+	if currentSession == nil {
+		return
+	}
+	active := false
+	for _, section := range p.importSections {
+		if section.JobID == 0 {
+			continue
+		}
+		job, err := p.importService.GetImportJob(section.JobID, currentSession)
+		if err != nil {
+			appLogger.Warnf("Erro ao consultar job de importação #%d: %v", section.JobID, err)
+			section.JobID = 0
+			section.Job = nil
+			section.IsImporting = false
+			section.StatusMessage = fmt.Sprintf("Não foi possível acompanhar a importação: %v", err)
+			section.MessageColor = theme.Colors.Danger
+			continue
+		}
+		section.Job = job
+		if !job.Done() {
+			active = true
+			continue
+		}
+		section.JobID = 0
+		section.IsImporting = false
+		p.applyImportJobResult(section, job, currentSession)
+	}
+	if active {
+		gtx.Execute(op.InvalidateCmd{At: gtx.Now.Add(importJobPollInterval)})
+	} else if !p.anySectionIsImporting() && !p.isLoadingGlobal {
+		p.spinner.Stop(p.router.GetAppWindow().Context())
+	}
+}
 
-		importResult, importErr = s.importService.ImportFile(fp, sec.Config.ID, sess)
+// applyImportJobResult exibe na seção o resultado de um job de importação finalizado e recarrega
+// o status e o histórico.
+func (p *ImportPage) applyImportJobResult(sec *ImportSectionState, job *services.ImportJobStatus, sess *auth.SessionData) {
+	importResult, importErr := job.Result, job.Err
+	fp := job.FileName
 
-		p.router.GetAppWindow().Execute(func() {
-			sec.IsImporting = false // Atualiza o estado da seção específica
-			// Parar o spinner global se nenhuma outra seção estiver importando E não houver carregamento global
-			if !p.anySectionIsImporting() && !p.isLoadingGlobal {
-				p.spinner.Stop(p.router.GetAppWindow().Context())
+	switch {
+	case job.State == services.ImportJobCancelled:
+		sec.StatusMessage = fmt.Sprintf("Importação cancelada por %s após %d linhas lidas. Nenhuma alteração foi gravada.",
+			job.CancelledBy, job.Progress.RowsProcessed)
+		if sec.Config.Registry { // O cadastro é aplicado linha a linha, sem transação única.
+			sec.StatusMessage = fmt.Sprintf("Importação cancelada por %s: %v", job.CancelledBy, importErr)
+		}
+		sec.MessageColor = theme.Colors.Warning
+		appLogger.Infof("Importação do arquivo tipo %s (%s) cancelada por %s.", sec.Config.ID, fp, job.CancelledBy)
+	case errors.As(importErr, new(*models.ImportLockedError)):
+		sec.StatusMessage, _ = importLockedMessage(importErr)
+		sec.MessageColor = theme.Colors.Warning
+	case importErr != nil:
+		errMsg := fmt.Sprintf("Falha na importação: %v", importErr)
+		// Tenta extrair mensagem mais amigável de ValidationError
+		var valErr *appErrors.ValidationError
+		if errors.As(importErr, &valErr) {
+			errMsg = fmt.Sprintf("Falha na importação: %s", valErr.Message)
+			if len(valErr.Fields) > 0 {
+				errMsg += fmt.Sprintf(" (Detalhes: %v)", valErr.Fields)
 			}
+		}
+		sec.StatusMessage = errMsg
+		sec.MessageColor = theme.Colors.Danger
+		appLogger.Errorf("Erro ao importar arquivo tipo %s (%s): %v", sec.Config.ID, fp, importErr)
+	default:
+		processed := 0
+		if proc, ok := importResult["records_processed"].(int); ok { processed = proc }
+		skippedParse := 0
+		if skipP, ok := importResult["records_skipped_parsing"].(int); ok { skippedParse = skipP }
+		skippedRepo := 0
+		if skipR, ok := importResult["records_skipped_repo"].(int); ok { skippedRepo = skipR }
 
-			if importErr != nil {
-				errMsg := fmt.Sprintf("Falha na importação: %v", importErr)
-				// Tenta extrair mensagem mais amigável de ValidationError
-				var valErr *appErrors.ValidationError
-				if errors.As(importErr, &valErr) {
-					errMsg = fmt.Sprintf("Falha na importação: %s", valErr.Message)
-					if len(valErr.Fields) > 0 {
-						errMsg += fmt.Sprintf(" (Detalhes: %v)", valErr.Fields)
+		sec.StatusMessage = fmt.Sprintf("Importação concluída! %d registros processados. %d pulados (parsing), %d pulados (repositório).",
+			processed, skippedParse, skippedRepo)
+		if report, _ := importResult["network_cnpj_report"].(*models.NetworkCNPJImportReport); report != nil {
+			sec.StatusMessage = networkCNPJReportMessage(report)
+		} else if message, _ := importResult["message"].(string); sec.Config.ID == services.FileTypeFeriados && message != "" {
+			sec.StatusMessage = message
+		} else if job.Mode == services.ImportModeIncremental {
+			inserted, _ := importResult["records_inserted"].(int)
+			updated, _ := importResult["records_updated"].(int)
+			unchanged, _ := importResult["records_unchanged"].(int)
+			removed, _ := importResult["records_removed"].(int)
+			sec.StatusMessage = fmt.Sprintf("Importação incremental concluída! Inseridos: %d, atualizados: %d, inalterados: %d, removidos: %d. %d pulados (parsing).",
+				inserted, updated, unchanged, removed, skippedParse)
+		}
+		if scope, _ := importResult["import_scope"].(string); scope == string(services.ImportScopeCompanies) {
+			companies, _ := importResult["companies"].([]int)
+			sec.StatusMessage += fmt.Sprintf("\nEmpresas afetadas (NROEMPRESA): %s. As demais empresas não foram alteradas.", formatCompanies(companies))
+		}
+		if quarantineFile, _ := importResult["quarantine_file"].(string); quarantineFile != "" {
+			quarantined, _ := importResult["records_quarantined"].(int)
+			sec.StatusMessage += fmt.Sprintf("\n%d linhas rejeitadas ou corrigidas foram gravadas em: %s", quarantined, quarantineFile)
+		}
+		if cnpjCheck, _ := importResult["cnpj_check"].(*models.TituloCNPJCheckReport); cnpjCheck != nil && len(cnpjCheck.Issues) > 0 {
+			sec.StatusMessage += fmt.Sprintf("\n%d de %d títulos vinculados a redes. %d CNPJs pendentes (não cadastrados, inativos ou inválidos); use \"Exportar CNPJs Pendentes\" para a lista.",
+				cnpjCheck.LinkedTitles, cnpjCheck.TotalTitles, len(cnpjCheck.Issues))
+		}
+		if snapshotID, _ := importResult["snapshot_id"].(uint64); snapshotID != 0 {
+			sec.StatusMessage += fmt.Sprintf("\nVersão #%d guardada para eventual restauração.", snapshotID)
+		}
+		sec.MessageColor = theme.Colors.Success
+		if report, _ := importResult["network_cnpj_report"].(*models.NetworkCNPJImportReport); report != nil && report.RejectedRows > 0 {
+			sec.MessageColor = theme.Colors.Warning
+		}
+		appLogger.Infof("Arquivo tipo %s (%s) importado. Processados: %d, Pulados Parsing: %d, Pulados Repo: %d.",
+			sec.Config.ID, fp, processed, skippedParse, skippedRepo)
+
+		// Atualiza o "Última atualização" para esta seção.
+		p.updateSpecificSectionStatus(sec, sess)
+		p.loadSectionSnapshots(sec, sess) // Sem efeito se o painel de versões estiver fechado.
+	}
+	// A execução (com sucesso, falha ou cancelamento) é registrada no histórico.
+	p.historyOffset = 0
+	p.loadImportHistory(sess)
+	// Limpar seleção de arquivo após tentativa de importação.
+	sec.SelectedFilePath = ""
+	sec.SelectedFileName = ""
+	sec.SheetNames = nil
+	sec.SheetChoice.Value = ""
+	p.router.GetAppWindow().Invalidate()
+}
+
+// formatCompanies formata a lista de empresas (NROEMPRESA) para as mensagens da página.
+func formatCompanies(companies []int) string {
+	if len(companies) == 0 {
+		return "nenhuma"
+	}
+	parts := make([]string, len(companies))
+	for i, company := range companies {
+		parts[i] = fmt.Sprint(company)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// importLockedMessage retorna a mensagem para uma importação recusada porque outra importação do
+// mesmo tipo está em andamento (em outra sessão ou máquina). O segundo retorno é false para outros erros.
+func importLockedMessage(err error) (string, bool) {
+	var lockedErr *models.ImportLockedError
+	if !errors.As(err, &lockedErr) {
+		return "", false
+	}
+	return fmt.Sprintf("Importação não iniciada: %v. Aguarde a conclusão e tente novamente.", lockedErr), true
+}
+
+// handleCancelImportJob pede o cancelamento do job de importação da seção. O resultado (transação
+// desfeita) é exibido quando o job terminar.
+func (p *ImportPage) handleCancelImportJob(section *ImportSectionState, currentSession *auth.SessionData) {
+	if err := p.importService.CancelImportJob(section.JobID, currentSession); err != nil {
+		section.StatusMessage = fmt.Sprintf("Não foi possível cancelar a importação: %v", err)
+		section.MessageColor = theme.Colors.Danger
+		appLogger.Warnf("Erro ao cancelar job de importação #%d: %v", section.JobID, err)
+	} else {
+		section.StatusMessage = "Cancelando a importação..."
+		section.MessageColor = theme.Colors.Warning
+	}
+	p.router.GetAppWindow().Invalidate()
+}
+
+// importPhaseLabel retorna a descrição da fase de importação para exibição.
+func importPhaseLabel(phase services.ImportPhase) string {
+	switch phase {
+	case services.ImportPhaseReading:
+		return "Lendo arquivo"
+	case services.ImportPhaseDecoding:
+		return "Decodificando e validando cabeçalho"
+	case services.ImportPhaseMapping:
+		return "Lendo e mapeando linhas"
+	case services.ImportPhasePersisting:
+		return "Gravando dados"
+	case services.ImportPhaseFinished:
+		return "Finalizada"
+	default:
+		return string(phase)
+	}
+}
+
+// layoutImportJobProgress desenha a fase, as linhas lidas, a barra de progresso (quando o tamanho do
+// arquivo é conhecido) e o botão de cancelamento do job de importação da seção.
+func (p *ImportPage) layoutImportJobProgress(gtx layout.Context, th *material.Theme, section *ImportSectionState, canExecuteImport bool) layout.Dimensions {
+	job := section.Job
+	progressText := fmt.Sprintf("%s — %d linhas lidas", importPhaseLabel(job.Progress.Phase), job.Progress.RowsProcessed)
+	if job.CancelRequested {
+		progressText += " (cancelamento solicitado)"
+	}
+	fraction := job.Progress.Fraction()
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+				layout.Flexed(1, material.Body2(th, progressText).Layout),
+				layout.Rigid(func(gtx C) D {
+					btn := material.Button(th, &section.CancelJobBtn, "Cancelar Importação")
+					btn.Background = theme.Colors.Danger
+					if job.CancelRequested || job.Progress.Phase == services.ImportPhasePersisting || !canExecuteImport {
+						btn.Background = theme.Colors.Grey300
+						btn.Color = theme.Colors.TextMuted
+						gtx = gtx.Disabled()
 					}
-				}
-				sec.StatusMessage = errMsg
+					return btn.Layout(gtx)
+				}),
+			)
+		}),
+		layout.Rigid(func(gtx C) D {
+			if fraction < 0 {
+				return D{}
+			}
+			return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, material.ProgressBar(th, fraction).Layout)
+		}),
+	)
+}
+
+// networkCNPJReportMessage resume o resultado da importação de redes e CNPJs para a mensagem da seção.
+func networkCNPJReportMessage(report *models.NetworkCNPJImportReport) string {
+	msg := fmt.Sprintf("Cadastro importado! Redes criadas: %d. CNPJs cadastrados: %d, atualizados: %d, inalterados: %d. Linhas rejeitadas: %d, repetidas: %d.",
+		report.NetworksCreated, report.CNPJsCreated, report.CNPJsUpdated, report.CNPJsUnchanged, report.RejectedRows, report.DuplicateRows)
+	if report.ReportFile != "" {
+		msg += fmt.Sprintf("\nResultado de cada linha gravado em: %s", report.ReportFile)
+	}
+	return msg
+}
+
+// handleExportCNPJCheck refaz o vínculo dos títulos da seção com os CNPJs cadastrados e exporta
+// a lista de CNPJs pendentes (não cadastrados, inativos ou inválidos) para CSV.
+func (p *ImportPage) handleExportCNPJCheck(section *ImportSectionState, currentSession *auth.SessionData) {
+	section.IsCheckingCNPJs = true
+	section.StatusMessage = "Verificando os CNPJs dos títulos..."
+	section.MessageColor = theme.Colors.TextMuted
+	p.router.GetAppWindow().Invalidate()
+
+	go func(sec *ImportSectionState, sess *auth.SessionData) {
+		report, exportPath, err := p.importService.ExportTitleCNPJCheck(sec.Config.ID, sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			sec.IsCheckingCNPJs = false
+			if err != nil {
+				sec.StatusMessage = fmt.Sprintf("Falha ao exportar a verificação de CNPJs: %v", err)
 				sec.MessageColor = theme.Colors.Danger
-				appLogger.Errorf("Erro ao importar arquivo tipo %s (%s): %v", sec.Config.ID, fp, importErr)
+				appLogger.Errorf("Erro ao exportar verificação de CNPJs para %s: %v", sec.Config.ID, err)
 			} else {
-				processed := 0
-				if proc, ok := importResult["records_processed"].(int); ok { processed = proc }
-				skippedParse := 0
-				if skipP, ok := importResult["records_skipped_parsing"].(int); ok { skippedParse = skipP }
-				skippedRepo := 0
-				if skipR, ok := importResult["records_skipped_repo"].(int); ok { skippedRepo = skipR }
-
-				sec.StatusMessage = fmt.Sprintf("Importação concluída! %d registros processados. %d pulados (parsing), %d pulados (repositório).",
-					processed, skippedParse, skippedRepo)
+				sec.StatusMessage = fmt.Sprintf("%d de %d títulos vinculados a redes. %d CNPJs pendentes exportados para: %s",
+					report.LinkedTitles, report.TotalTitles, len(report.Issues), exportPath)
 				sec.MessageColor = theme.Colors.Success
-				appLogger.Infof("Arquivo tipo %s (%s) importado. Processados: %d, Pulados Parsing: %d, Pulados Repo: %d.",
-					sec.Config.ID, fp, processed, skippedParse, skippedRepo)
-				
-				// Atualiza o "Última atualização" para esta seção.
-				p.updateSpecificSectionStatus(sec, sess)
+				if len(report.Issues) > 0 {
+					sec.MessageColor = theme.Colors.Warning
+				}
 			}
-			// Limpar seleção de arquivo após tentativa de importação.
-			sec.SelectedFilePath = ""
-			sec.SelectedFileName = ""
 			p.router.GetAppWindow().Invalidate()
 		})
-	}(section, filePathToImport, currentSession)
+	}(section, currentSession)
 }
 
 // updateSpecificSectionStatus busca e atualiza o label de "Última atualização" para uma seção.
@@ -509,6 +1173,9 @@ func (p *ImportPage) updateSpecificSectionStatus(section *ImportSectionState, cu
 			if status.RecordCount != nil {
 				countStr = fmt.Sprintf(" %d registros.", *status.RecordCount)
 			}
+			if status.Companies != nil && *status.Companies != "" {
+				countStr += fmt.Sprintf(" Empresas: %s.", *status.Companies)
+			}
 			newStatusText = fmt.Sprintf("Última atualização: %s%s\n%s", updateTimeStr, countStr, fileNameStr)
 		} else {
 			newStatusText = "Última atualização: Não encontrado."
@@ -521,12 +1188,394 @@ func (p *ImportPage) updateSpecificSectionStatus(section *ImportSectionState, cu
 	}(fileTypeID, section, currentSession)
 }
 
-// anySectionIsImporting verifica se alguma das seções de importação está atualmente em processo.
+// anySectionIsImporting verifica se alguma das seções de importação está atualmente em processo
+// (importação ou pré-visualização).
 func (p *ImportPage) anySectionIsImporting() bool {
 	for _, section := range p.importSections {
-		if section.IsImporting {
+		if section.IsImporting || section.IsPreviewing {
 			return true
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+
+// --- Pré-visualização (Dry-run) ---
+
+// importPreviewMaxListHeight limita a altura das listas do relatório de pré-visualização dentro do card.
+const importPreviewMaxListHeight = 180
+
+// importPreviewColumnSummary formata a contagem de problemas por coluna (ex: "VLRNOMINAL: 3, DTAVENCIMENTO: 1"),
+// da coluna com mais problemas para a com menos.
+func importPreviewColumnSummary(counts map[string]int) string {
+	columns := make([]string, 0, len(counts))
+	for column := range counts {
+		columns = append(columns, column)
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		if counts[columns[i]] != counts[columns[j]] {
+			return counts[columns[i]] > counts[columns[j]]
+		}
+		return columns[i] < columns[j]
+	})
+	parts := make([]string, len(columns))
+	for i, column := range columns {
+		parts[i] = fmt.Sprintf("%s: %d", column, counts[column])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// importPreviewSampleLines formata a amostra de registros mapeados do relatório, uma linha por registro.
+func importPreviewSampleLines(report *models.ImportPreviewReport) []string {
+	orDash := func(val *string) string {
+		if val == nil || *val == "" {
+			return "-"
+		}
+		return *val
+	}
+	lines := make([]string, 0, len(report.SampleDireitos)+len(report.SampleObrigacoes))
+	for _, td := range report.SampleDireitos {
+		lines = append(lines, fmt.Sprintf("%s | Empresa %d | Título %s | Venc. %s | Nominal %s | %s",
+			td.CNPJCPF, td.NumeroEmpresa, td.Titulo, orDash(td.DataVencimento), td.ValorNominal.StringFixed(2), orDash(td.Pessoa)))
+	}
+	for _, to := range report.SampleObrigacoes {
+		lines = append(lines, fmt.Sprintf("%s | Empresa %d | Título %s | Venc. %s | Nominal %s | %s",
+			to.CNPJCPF, to.NumeroEmpresa, to.IdentificadorObrigacao, orDash(to.DataVencimento), to.ValorNominalObrigacao.StringFixed(2), orDash(to.Pessoa)))
+	}
+	return lines
+}
+
+// layoutPreviewPanel desenha o relatório da pré-visualização de uma seção: resumo, problemas por
+// coluna e por linha, amostra dos registros mapeados e os botões para confirmar ou descartar a importação.
+func (p *ImportPage) layoutPreviewPanel(gtx layout.Context, th *material.Theme, section *ImportSectionState, canExecuteImport bool) layout.Dimensions {
+	report := section.Preview
+	sampleLines := importPreviewSampleLines(report)
+
+	confirmBtn := material.Button(th, &section.ConfirmImportBtn, "Confirmar Importação")
+	if report.ValidRows == 0 || section.IsImporting || p.isLoadingGlobal || !canExecuteImport {
+		confirmBtn.Background = theme.Colors.Grey300
+		confirmBtn.Color = theme.Colors.TextMuted
+	} else {
+		confirmBtn.Background = theme.Colors.Primary
+	}
+	cancelBtn := material.Button(th, &section.CancelPreviewBtn, "Cancelar")
+	cancelBtn.Background = theme.Colors.Grey300
+	cancelBtn.Color = theme.Colors.Text
+
+	// boundedList limita a altura de uma lista, já que o card fica dentro de uma lista vertical sem limite de altura.
+	boundedList := func(gtx C, w layout.Widget) D {
+		gtx.Constraints.Max.Y = gtx.Dp(unit.Dp(importPreviewMaxListHeight))
+		gtx.Constraints.Min.Y = 0
+		return w(gtx)
+	}
+
+	border := widget.Border{Color: theme.Colors.Border, CornerRadius: theme.CornerRadius, Width: theme.BorderWidthDefault}
+	return border.Layout(gtx, func(gtx C) D {
+		return layout.UniformInset(unit.Dp(8)).Layout(gtx, func(gtx C) D {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx C) D {
+					titleText := fmt.Sprintf("Pré-visualização de '%s' (encoding: %s)", report.OriginalFilename, report.EncodingDetected)
+					if report.DelimiterDetected != "" {
+						titleText = fmt.Sprintf("Pré-visualização de '%s' (encoding: %s · delimitador: %s)", report.OriginalFilename, report.EncodingDetected, report.DelimiterDetected)
+					}
+					if report.ProfileName != "" {
+						titleText += fmt.Sprintf(" · perfil: %s", report.ProfileName)
+					}
+					title := material.Body1(th, titleText)
+					title.Font.Weight = font.SemiBold
+					return title.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx C) D { // Resumo
+					summary := fmt.Sprintf("%d linhas de dados: %d seriam importadas, %d seriam ignoradas, %d usariam valores padrão (placeholders), %d com problemas.",
+						report.TotalDataRows, report.ValidRows, report.RowsSkippedParsing, report.RowsWithPlaceholders, report.RowsWithIssues)
+					return material.Body2(th, summary).Layout(gtx)
+				}),
+				layout.Rigid(func(gtx C) D { // Problemas por coluna
+					if len(report.IssueCountByColumn) == 0 {
+						lbl := material.Body2(th, "Nenhum problema encontrado.")
+						lbl.Color = theme.Colors.Success
+						return lbl.Layout(gtx)
+					}
+					lbl := material.Body2(th, "Problemas por coluna: "+importPreviewColumnSummary(report.IssueCountByColumn))
+					lbl.Color = theme.Colors.Warning
+					return lbl.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx C) D { // Problemas por linha
+					if len(report.Issues) == 0 {
+						return D{}
+					}
+					return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx C) D {
+						return boundedList(gtx, func(gtx C) D {
+							return material.List(th, &section.PreviewIssueList).Layout(gtx, len(report.Issues), func(gtx C, index int) D {
+								if index < 0 || index >= len(report.Issues) {
+									return D{}
+								}
+								issue := report.Issues[index]
+								text := fmt.Sprintf("Linha %d · %s: %s (valor: '%s')", issue.LineNum, issue.Column, issue.Message, issue.RawValue)
+								lbl := material.Caption(th, text)
+								lbl.MaxLines = 1
+								lbl.Color = theme.Colors.TextMuted
+								if issue.UsedPlaceholder || issue.Column == models.ImportRowIssueColumnRow {
+									lbl.Color = theme.Colors.Danger
+								}
+								return lbl.Layout(gtx)
+							})
+						})
+					})
+				}),
+				layout.Rigid(func(gtx C) D {
+					if !report.IssuesTruncated {
+						return D{}
+					}
+					lbl := material.Caption(th, fmt.Sprintf("Exibindo os primeiros %d de %d problemas.", len(report.Issues), report.TotalIssues()))
+					lbl.Color = theme.Colors.TextMuted
+					return lbl.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx C) D { // Amostra dos registros mapeados
+					if len(sampleLines) == 0 {
+						return D{}
+					}
+					children := []layout.FlexChild{
+						layout.Rigid(func(gtx C) D {
+							lbl := material.Body2(th, fmt.Sprintf("Amostra (primeiros %d registros, como seriam gravados):", len(sampleLines)))
+							lbl.Font.Weight = font.SemiBold
+							return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, lbl.Layout)
+						}),
+					}
+					for _, line := range sampleLines {
+						lbl := material.Caption(th, line)
+						lbl.MaxLines = 1
+						children = append(children, layout.Rigid(lbl.Layout))
+					}
+					return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+				}),
+				layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+				layout.Rigid(func(gtx C) D { // Ações
+					return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+						layout.Flexed(1, func(gtx C) D { return D{} }),
+						layout.Rigid(cancelBtn.Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+						layout.Rigid(confirmBtn.Layout),
+					)
+				}),
+			)
+		})
+	})
+}
+
+// --- Histórico de Importações ---
+
+// loadImportHistory carrega a página atual do histórico de execuções, aplicando os filtros selecionados.
+func (p *ImportPage) loadImportHistory(currentSession *auth.SessionData) {
+	if p.isLoadingHistory {
+		return
+	}
+	p.isLoadingHistory = true
+	p.historyMessage = "Carregando histórico..."
+	p.router.GetAppWindow().Invalidate()
+
+	filter := models.ImportRunFilter{Limit: importHistoryPageSize, Offset: p.historyOffset}
+	if typeFilter := p.historyTypeFilter.Value; typeFilter != "" {
+		filter.FileType = &typeFilter
+	}
+	if statusFilter := p.historyStatusFilter.Value; statusFilter != "" {
+		filter.Status = &statusFilter
+	}
+
+	go func(f models.ImportRunFilter, sess *auth.SessionData) {
+		runs, total, err := p.importService.GetImportRuns(f, sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			p.isLoadingHistory = false
+			if err != nil {
+				p.historyMessage = fmt.Sprintf("Erro ao carregar histórico: %v", err)
+				appLogger.Errorf("Erro ao carregar histórico de importações: %v", err)
+			} else {
+				p.historyRuns = runs
+				p.historyTotal = total
+				p.historyMessage = ""
+				if total == 0 {
+					p.historyMessage = "Nenhuma importação encontrada."
+				}
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(filter, currentSession)
+}
+
+// importRunStatusLabel retorna o texto e a cor de exibição de um status de execução.
+func importRunStatusLabel(status string) (string, color.NRGBA) {
+	switch status {
+	case models.ImportRunStatusRunning:
+		return "Em andamento", theme.Colors.Info
+	case models.ImportRunStatusSuccess:
+		return "Sucesso", theme.Colors.Success
+	case models.ImportRunStatusSuccessEmpty:
+		return "Sem dados", theme.Colors.Warning
+	case models.ImportRunStatusFailed:
+		return "Falhou", theme.Colors.Danger
+	case models.ImportRunStatusCancelled:
+		return "Cancelada", theme.Colors.Warning
+	default:
+		return status, theme.Colors.Text
+	}
+}
+
+// layoutImportHistory desenha o card com o histórico de execuções de importação.
+func (p *ImportPage) layoutImportHistory(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	headers := []string{"Início", "Tipo", "Modo", "Arquivo", "Usuário", "Status", "Linhas", "Ins./Atu./Rem.", "Duração"}
+	colWeights := []float32{0.13, 0.10, 0.09, 0.20, 0.10, 0.10, 0.07, 0.12, 0.09}
+
+	cell := func(text string, weight float32, textColor color.NRGBA, bold bool) layout.FlexChild {
+		return layout.Flexed(weight, func(gtx C) D {
+			lbl := material.Body2(th, text)
+			lbl.Color = textColor
+			lbl.MaxLines = 1
+			if bold {
+				lbl.Font.Weight = font.Bold
+			}
+			return lbl.Layout(gtx)
+		})
+	}
+
+	rowLayout := func(gtx C, index int, run *models.ImportRunPublic) D {
+		bgColor := theme.Colors.Surface
+		if index%2 != 0 {
+			bgColor = theme.Colors.BackgroundAlt
+		}
+		statusText, statusColor := importRunStatusLabel(run.Status)
+		duration := "-"
+		if run.FinishedAt != nil {
+			duration = run.Duration().Round(time.Second).String()
+		}
+		changes := fmt.Sprintf("%d/%d/%d", run.RecordsInserted, run.RecordsUpdated, run.RecordsRemoved)
+
+		return layout.Background{Color: bgColor}.Layout(gtx, func(gtx C) D {
+			return layout.Inset{Top: unit.Dp(4), Bottom: unit.Dp(4), Left: unit.Dp(8), Right: unit.Dp(8)}.Layout(gtx, func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(func(gtx C) D {
+						return layout.Flex{}.Layout(gtx,
+							cell(run.StartedAt.Local().Format("02/01/06 15:04:05"), colWeights[0], theme.Colors.Text, false),
+							cell(run.FileType, colWeights[1], theme.Colors.Text, false),
+							cell(run.ImportMode, colWeights[2], theme.Colors.Text, false),
+							cell(run.OriginalFilename, colWeights[3], theme.Colors.Text, false),
+							cell(run.ImportedBy, colWeights[4], theme.Colors.Text, false),
+							cell(statusText, colWeights[5], statusColor, true),
+							cell(fmt.Sprint(run.TotalDataRows), colWeights[6], theme.Colors.Text, false),
+							cell(changes, colWeights[7], theme.Colors.Text, false),
+							cell(duration, colWeights[8], theme.Colors.Text, false),
+						)
+					}),
+					layout.Rigid(func(gtx C) D { // Detalhes do arquivo e erro (se houver)
+						details := ""
+						if run.FileSHA256 != nil {
+							details = "SHA-256: " + *run.FileSHA256
+						}
+						if run.FileSizeBytes != nil {
+							details += fmt.Sprintf("  Tamanho: %d bytes", *run.FileSizeBytes)
+						}
+						if run.EncodingDetected != nil {
+							details += "  Encoding: " + *run.EncodingDetected
+						}
+						if run.DelimiterDetected != nil {
+							details += "  Delimitador: " + *run.DelimiterDetected
+						}
+						if run.ImportScope == string(services.ImportScopeCompanies) && run.Companies != nil {
+							details += "  Apenas empresas: " + *run.Companies
+						} else if run.Companies != nil {
+							details += "  Empresas: " + *run.Companies
+						}
+						lbl := material.Caption(th, details)
+						lbl.Color = theme.Colors.TextMuted
+						if run.ErrorMessage != nil && *run.ErrorMessage != "" {
+							lbl = material.Caption(th, "Erro: "+*run.ErrorMessage)
+							lbl.Color = theme.Colors.Danger
+						}
+						lbl.MaxLines = 2
+						return lbl.Layout(gtx)
+					}),
+				)
+			})
+		})
+	}
+
+	prevBtn := material.Button(th, &p.historyPrevBtn, "Anterior")
+	if p.historyOffset == 0 || p.isLoadingHistory {
+		prevBtn.Background = theme.Colors.Grey300
+		prevBtn.Color = theme.Colors.TextMuted
+	}
+	nextBtn := material.Button(th, &p.historyNextBtn, "Próxima")
+	if int64(p.historyOffset+importHistoryPageSize) >= p.historyTotal || p.isLoadingHistory {
+		nextBtn.Background = theme.Colors.Grey300
+		nextBtn.Color = theme.Colors.TextMuted
+	}
+
+	return material.Card(th, theme.Colors.Surface, theme.ElevationSmall, layout.UniformInset(unit.Dp(16)),
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx C) D {
+					title := material.Subtitle1(th, "Histórico de Importações")
+					title.Font.Weight = font.SemiBold
+					return title.Layout(gtx)
+				}),
+				layout.Rigid(layout.Spacer{Height: unit.Dp(4)}.Layout),
+				layout.Rigid(func(gtx C) D { // Filtros
+					children := []layout.FlexChild{
+						layout.Rigid(material.Body2(th, "Tipo:").Layout),
+						layout.Rigid(material.RadioButton(th, &p.historyTypeFilter, "", "Todos").Layout),
+					}
+					for _, section := range p.importSections {
+						children = append(children, layout.Rigid(
+							material.RadioButton(th, &p.historyTypeFilter, string(section.Config.ID), string(section.Config.ID)).Layout))
+					}
+					children = append(children,
+						layout.Rigid(layout.Spacer{Width: unit.Dp(16)}.Layout),
+						layout.Rigid(material.Body2(th, "Status:").Layout),
+						layout.Rigid(material.RadioButton(th, &p.historyStatusFilter, "", "Todos").Layout),
+						layout.Rigid(material.RadioButton(th, &p.historyStatusFilter, models.ImportRunStatusSuccess, "Sucesso").Layout),
+						layout.Rigid(material.RadioButton(th, &p.historyStatusFilter, models.ImportRunStatusFailed, "Falhou").Layout),
+						layout.Rigid(material.RadioButton(th, &p.historyStatusFilter, models.ImportRunStatusCancelled, "Cancelada").Layout),
+					)
+					return layout.Flex{Alignment: layout.Middle}.Layout(gtx, children...)
+				}),
+				layout.Rigid(layout.Spacer{Height: unit.Dp(4)}.Layout),
+				layout.Rigid(func(gtx C) D { // Cabeçalho da tabela
+					return layout.Background{Color: theme.Colors.Grey200}.Layout(gtx, func(gtx C) D {
+						return layout.Inset{Top: unit.Dp(6), Bottom: unit.Dp(6), Left: unit.Dp(8), Right: unit.Dp(8)}.Layout(gtx, func(gtx C) D {
+							children := make([]layout.FlexChild, len(headers))
+							for i, h := range headers {
+								children[i] = cell(h, colWeights[i], theme.Colors.Text, true)
+							}
+							return layout.Flex{}.Layout(gtx, children...)
+						})
+					})
+				}),
+				layout.Flexed(1, func(gtx C) D { // Linhas
+					if len(p.historyRuns) == 0 || p.historyMessage != "" {
+						lbl := material.Body2(th, p.historyMessage)
+						lbl.Color = theme.Colors.TextMuted
+						return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, lbl.Layout)
+					}
+					return material.List(th, &p.historyList).Layout(gtx, len(p.historyRuns), func(gtx C, index int) D {
+						if index < 0 || index >= len(p.historyRuns) {
+							return D{}
+						}
+						return rowLayout(gtx, index, p.historyRuns[index])
+					})
+				}),
+				layout.Rigid(func(gtx C) D { // Paginação
+					from, to := 0, 0
+					if p.historyTotal > 0 {
+						from = p.historyOffset + 1
+						to = p.historyOffset + len(p.historyRuns)
+					}
+					return layout.Flex{Alignment: layout.Middle, Spacing: layout.SpaceBetween}.Layout(gtx,
+						layout.Rigid(material.Caption(th, fmt.Sprintf("%d–%d de %d execuções", from, to, p.historyTotal)).Layout),
+						layout.Flexed(1, func(gtx C) D { return D{} }),
+						layout.Rigid(prevBtn.Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+						layout.Rigid(nextBtn.Layout),
+					)
+				}),
+			)
+		}).Layout(gtx)
+}
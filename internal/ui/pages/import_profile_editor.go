@@ -0,0 +1,437 @@
+package pages
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/services"
+)
+
+// importProfileEditorMaxColumnsHeight limita a altura da lista de colunas do editor de perfis.
+const importProfileEditorMaxColumnsHeight = 240
+
+// importProfileColumnEditor é a linha do editor de perfis para uma coluna esperada.
+type importProfileColumnEditor struct {
+	field        string
+	required     bool          // Coluna que não pode ser marcada como opcional.
+	aliasesInput widget.Editor // Aliases separados por ";".
+	optional     widget.Bool
+}
+
+// importProfileEditor guarda o estado do editor de perfis de mapeamento de colunas de uma seção.
+type importProfileEditor struct {
+	profileID uint64 // Perfil em edição (0 para um novo perfil).
+
+	nameInput        widget.Editor
+	decimalSep       widget.Enum   // "," ou "."
+	dateFormatsInput widget.Editor // Formatos separados por ";" (ex: "DD/MM/AAAA;AAAA-MM-DD").
+	isDefault        widget.Bool
+	columns          []*importProfileColumnEditor
+	columnList       widget.List
+
+	saveBtn   widget.Clickable
+	newBtn    widget.Clickable
+	deleteBtn widget.Clickable
+	closeBtn  widget.Clickable
+
+	isSaving     bool
+	message      string
+	messageColor color.NRGBA
+}
+
+// newImportProfileEditor cria o editor preenchido com `profile` (nil para um novo perfil).
+func newImportProfileEditor(fileType services.FileType, profile *models.ImportProfilePublic) *importProfileEditor {
+	ed := &importProfileEditor{}
+	ed.nameInput.SingleLine = true
+	ed.dateFormatsInput.SingleLine = true
+	ed.columnList.Axis = layout.Vertical
+	ed.decimalSep.Value = ","
+
+	if profile != nil {
+		ed.profileID = profile.ID
+		ed.nameInput.SetText(profile.Name)
+		if profile.DecimalSeparator != "" {
+			ed.decimalSep.Value = profile.DecimalSeparator
+		}
+		ed.dateFormatsInput.SetText(strings.Join(profile.DateFormats, models.ImportProfileListSeparator))
+		ed.isDefault.Value = profile.IsDefault
+	}
+
+	for _, field := range services.ExpectedImportHeaders(fileType) {
+		col := &importProfileColumnEditor{field: field}
+		col.aliasesInput.SingleLine = true
+		for _, required := range models.ImportProfileRequiredFields {
+			if strings.EqualFold(required, field) {
+				col.required = true
+			}
+		}
+		if colCfg := profile.Column(field); colCfg != nil {
+			col.aliasesInput.SetText(strings.Join(colCfg.Aliases, models.ImportProfileListSeparator))
+			col.optional.Value = colCfg.Optional && !col.required
+		}
+		ed.columns = append(ed.columns, col)
+	}
+	return ed
+}
+
+// toUpsert monta os dados do perfil a partir dos campos do editor.
+func (ed *importProfileEditor) toUpsert(fileType services.FileType) models.ImportProfileUpsert {
+	data := models.ImportProfileUpsert{
+		Name:             ed.nameInput.Text(),
+		FileType:         string(fileType),
+		DecimalSeparator: ed.decimalSep.Value,
+		DateFormats:      models.SplitImportProfileList(ed.dateFormatsInput.Text()),
+		IsDefault:        ed.isDefault.Value,
+	}
+	for _, col := range ed.columns {
+		data.Columns = append(data.Columns, models.ImportProfileColumnPublic{
+			Field:    col.field,
+			Aliases:  models.SplitImportProfileList(col.aliasesInput.Text()),
+			Optional: col.optional.Value && !col.required,
+		})
+	}
+	return data
+}
+
+// selectedProfileID retorna o ID do perfil escolhido na seção (0 para o layout padrão).
+func (section *ImportSectionState) selectedProfileID() uint64 {
+	id, err := strconv.ParseUint(section.ProfileChoice.Value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// selectedProfile retorna o perfil escolhido na seção (nil para o layout padrão).
+func (section *ImportSectionState) selectedProfile() *models.ImportProfilePublic {
+	id := section.selectedProfileID()
+	for _, profile := range section.Profiles {
+		if profile.ID == id {
+			return profile
+		}
+	}
+	return nil
+}
+
+// clearPreview descarta a pré-visualização da seção (ex: após mudar a planilha ou o perfil).
+func (section *ImportSectionState) clearPreview() {
+	section.Preview = nil
+	section.PreviewFilePath = ""
+	section.StatusMessage = ""
+}
+
+// loadSectionProfiles carrega os perfis de importação do tipo da seção. Na primeira carga, o perfil
+// padrão do tipo (se houver) é pré-selecionado; depois, `selectID` (se não zero) é selecionado.
+func (p *ImportPage) loadSectionProfiles(section *ImportSectionState, selectID uint64, currentSession *auth.SessionData) {
+	go func(sec *ImportSectionState, sess *auth.SessionData) {
+		profiles, err := p.importService.GetImportProfiles(sec.Config.ID, sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			if err != nil {
+				appLogger.Errorf("Erro ao carregar perfis de importação para %s: %v", sec.Config.ID, err)
+				sec.Profiles = nil
+				sec.ProfileChoice.Value = ""
+				p.router.GetAppWindow().Invalidate()
+				return
+			}
+			previousID := sec.selectedProfileID()
+			sec.Profiles = profiles
+			switch {
+			case selectID != 0:
+				sec.ProfileChoice.Value = strconv.FormatUint(selectID, 10)
+			case !sec.profilesLoaded:
+				for _, profile := range profiles {
+					if profile.IsDefault {
+						sec.ProfileChoice.Value = strconv.FormatUint(profile.ID, 10)
+					}
+				}
+			}
+			sec.profilesLoaded = true
+			if sec.selectedProfile() == nil {
+				sec.ProfileChoice.Value = "" // O perfil escolhido foi excluído.
+			}
+			if sec.selectedProfileID() != previousID {
+				sec.clearPreview()
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(section, currentSession)
+}
+
+// handleProfileEditorEvents processa os botões do editor de perfis de uma seção.
+func (p *ImportPage) handleProfileEditorEvents(gtx layout.Context, section *ImportSectionState, currentSession *auth.SessionData) {
+	if section.ManageProfilesBtn.Clicked(gtx) {
+		if section.ProfileEditor != nil {
+			section.ProfileEditor = nil
+		} else if canManage, _ := p.permManager.HasPermission(currentSession, auth.PermImportManageProfiles, nil); canManage {
+			section.ProfileEditor = newImportProfileEditor(section.Config.ID, section.selectedProfile())
+		}
+	}
+	ed := section.ProfileEditor
+	if ed == nil {
+		return
+	}
+	if ed.closeBtn.Clicked(gtx) {
+		section.ProfileEditor = nil
+		return
+	}
+	if ed.newBtn.Clicked(gtx) && !ed.isSaving {
+		section.ProfileEditor = newImportProfileEditor(section.Config.ID, nil)
+		return
+	}
+	if ed.saveBtn.Clicked(gtx) && !ed.isSaving {
+		p.handleSaveProfile(section, ed, currentSession)
+	}
+	if ed.deleteBtn.Clicked(gtx) && !ed.isSaving && ed.profileID != 0 {
+		p.handleDeleteProfile(section, ed, currentSession)
+	}
+}
+
+// handleSaveProfile grava o perfil do editor e seleciona-o na seção.
+func (p *ImportPage) handleSaveProfile(section *ImportSectionState, ed *importProfileEditor, currentSession *auth.SessionData) {
+	data := ed.toUpsert(section.Config.ID)
+	ed.isSaving = true
+	ed.message = "Salvando perfil..."
+	ed.messageColor = theme.Colors.TextMuted
+	p.router.GetAppWindow().Invalidate()
+
+	go func(sec *ImportSectionState, profileID uint64, data models.ImportProfileUpsert, sess *auth.SessionData) {
+		saved, err := p.importService.SaveImportProfile(profileID, data, sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			ed.isSaving = false
+			if err != nil {
+				ed.message = importProfileErrorMessage("Falha ao salvar perfil", err)
+				ed.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao salvar perfil de importação '%s' (%s): %v", data.Name, sec.Config.ID, err)
+			} else {
+				ed.profileID = saved.ID
+				ed.message = fmt.Sprintf("Perfil '%s' salvo.", saved.Name)
+				ed.messageColor = theme.Colors.Success
+				p.loadSectionProfiles(sec, saved.ID, sess)
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(section, ed.profileID, data, currentSession)
+}
+
+// handleDeleteProfile exclui o perfil do editor.
+func (p *ImportPage) handleDeleteProfile(section *ImportSectionState, ed *importProfileEditor, currentSession *auth.SessionData) {
+	ed.isSaving = true
+	ed.message = "Excluindo perfil..."
+	ed.messageColor = theme.Colors.TextMuted
+	p.router.GetAppWindow().Invalidate()
+
+	go func(sec *ImportSectionState, profileID uint64, sess *auth.SessionData) {
+		err := p.importService.DeleteImportProfile(profileID, sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			ed.isSaving = false
+			if err != nil {
+				ed.message = importProfileErrorMessage("Falha ao excluir perfil", err)
+				ed.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao excluir perfil de importação ID %d (%s): %v", profileID, sec.Config.ID, err)
+			} else {
+				sec.ProfileEditor = newImportProfileEditor(sec.Config.ID, nil)
+				sec.ProfileEditor.message = "Perfil excluído."
+				sec.ProfileEditor.messageColor = theme.Colors.Success
+				p.loadSectionProfiles(sec, 0, sess)
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(section, ed.profileID, currentSession)
+}
+
+// importProfileErrorMessage formata um erro do serviço de perfis, usando a mensagem e os campos
+// de um ValidationError quando houver.
+func importProfileErrorMessage(prefix string, err error) string {
+	var valErr *appErrors.ValidationError
+	if errors.As(err, &valErr) {
+		msg := fmt.Sprintf("%s: %s", prefix, valErr.Message)
+		if len(valErr.Fields) > 0 {
+			msg += fmt.Sprintf(" (Detalhes: %v)", valErr.Fields)
+		}
+		return msg
+	}
+	return fmt.Sprintf("%s: %v", prefix, err)
+}
+
+// layoutProfileSelector desenha a escolha do perfil de mapeamento de colunas da seção e o botão
+// para abrir o editor de perfis (apenas para quem pode gerenciá-los).
+func (p *ImportPage) layoutProfileSelector(gtx layout.Context, th *material.Theme, section *ImportSectionState, canExecuteImport, canManageProfiles bool) layout.Dimensions {
+	if len(section.Profiles) == 0 && !canManageProfiles {
+		return layout.Dimensions{}
+	}
+	selectorGtx := gtx
+	if section.IsImporting || section.IsPreviewing || !canExecuteImport {
+		selectorGtx = gtx.Disabled()
+	}
+	children := []layout.FlexChild{
+		layout.Rigid(material.Body2(th, "Perfil:").Layout),
+		layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+		layout.Rigid(func(gtx C) D {
+			return material.RadioButton(th, &section.ProfileChoice, "", "Layout padrão").Layout(selectorGtx)
+		}),
+	}
+	for _, profile := range section.Profiles {
+		label := profile.Name
+		if profile.IsDefault {
+			label += " (padrão)"
+		}
+		key := strconv.FormatUint(profile.ID, 10)
+		children = append(children, layout.Rigid(func(gtx C) D {
+			return material.RadioButton(th, &section.ProfileChoice, key, label).Layout(selectorGtx)
+		}))
+	}
+	if canManageProfiles {
+		btnLabel := "Gerenciar Perfis"
+		if section.ProfileEditor != nil {
+			btnLabel = "Fechar Editor"
+		}
+		children = append(children,
+			layout.Flexed(1, func(gtx C) D { return D{} }),
+			layout.Rigid(material.Button(th, &section.ManageProfilesBtn, btnLabel).Layout),
+		)
+	}
+	return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Alignment: layout.Middle}.Layout(gtx, children...)
+	})
+}
+
+// layoutProfileEditor desenha o editor de perfis de mapeamento de colunas de uma seção.
+func (p *ImportPage) layoutProfileEditor(gtx layout.Context, th *material.Theme, section *ImportSectionState) layout.Dimensions {
+	ed := section.ProfileEditor
+	title := "Novo perfil de importação"
+	if ed.profileID != 0 {
+		title = fmt.Sprintf("Editando perfil '%s'", ed.nameInput.Text())
+	}
+
+	saveBtn := material.Button(th, &ed.saveBtn, "Salvar Perfil")
+	saveBtn.Background = theme.Colors.Primary
+	newBtn := material.Button(th, &ed.newBtn, "Novo")
+	newBtn.Background = theme.Colors.Grey300
+	newBtn.Color = theme.Colors.Text
+	deleteBtn := material.Button(th, &ed.deleteBtn, "Excluir")
+	deleteBtn.Background = theme.Colors.Danger
+	closeBtn := material.Button(th, &ed.closeBtn, "Fechar")
+	closeBtn.Background = theme.Colors.Grey300
+	closeBtn.Color = theme.Colors.Text
+	if ed.isSaving {
+		gtx = gtx.Disabled()
+	}
+
+	labeled := func(label string, w layout.Widget) layout.FlexChild {
+		return layout.Rigid(func(gtx C) D {
+			return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx C) D {
+				return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(func(gtx C) D {
+						gtx.Constraints.Min.X = gtx.Dp(unit.Dp(150))
+						return material.Body2(th, label).Layout(gtx)
+					}),
+					layout.Flexed(1, w),
+				)
+			})
+		})
+	}
+	editorBox := func(editor *widget.Editor, hint string) layout.Widget {
+		return func(gtx C) D {
+			border := widget.Border{Color: theme.Colors.Border, CornerRadius: theme.CornerRadius, Width: theme.BorderWidthDefault}
+			return border.Layout(gtx, func(gtx C) D {
+				return layout.UniformInset(unit.Dp(4)).Layout(gtx, material.Editor(th, editor, hint).Layout)
+			})
+		}
+	}
+
+	border := widget.Border{Color: theme.Colors.Border, CornerRadius: theme.CornerRadius, Width: theme.BorderWidthDefault}
+	return border.Layout(gtx, func(gtx C) D {
+		return layout.UniformInset(unit.Dp(8)).Layout(gtx, func(gtx C) D {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx C) D {
+					lbl := material.Body1(th, title)
+					lbl.Font.Weight = font.SemiBold
+					return lbl.Layout(gtx)
+				}),
+				labeled("Nome:*", editorBox(&ed.nameInput, "Ex: ERP Filial Sul")),
+				labeled("Separador decimal:", func(gtx C) D {
+					return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+						layout.Rigid(material.RadioButton(th, &ed.decimalSep, ",", "Vírgula (1.234,56)").Layout),
+						layout.Rigid(material.RadioButton(th, &ed.decimalSep, ".", "Ponto (1,234.56)").Layout),
+					)
+				}),
+				labeled("Formatos de data:", editorBox(&ed.dateFormatsInput, "Ex: DD/MM/AAAA;AAAA-MM-DD (vazio usa o padrão)")),
+				layout.Rigid(func(gtx C) D {
+					return layout.Inset{Top: unit.Dp(4)}.Layout(gtx,
+						material.CheckBox(th, &ed.isDefault, "Perfil padrão deste tipo de arquivo (pré-selecionado na importação)").Layout)
+				}),
+				layout.Rigid(func(gtx C) D {
+					lbl := material.Caption(th, "Colunas: informe outros nomes aceitos no cabeçalho do arquivo (separados por ';'). Colunas extras do arquivo são ignoradas.")
+					lbl.Color = theme.Colors.TextMuted
+					return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, lbl.Layout)
+				}),
+				layout.Rigid(func(gtx C) D {
+					gtx.Constraints.Max.Y = gtx.Dp(unit.Dp(importProfileEditorMaxColumnsHeight))
+					gtx.Constraints.Min.Y = 0
+					return material.List(th, &ed.columnList).Layout(gtx, len(ed.columns), func(gtx C, index int) D {
+						col := ed.columns[index]
+						return layout.Inset{Top: unit.Dp(2), Bottom: unit.Dp(2)}.Layout(gtx, func(gtx C) D {
+							return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+								layout.Rigid(func(gtx C) D {
+									gtx.Constraints.Min.X = gtx.Dp(unit.Dp(150))
+									return material.Body2(th, col.field).Layout(gtx)
+								}),
+								layout.Flexed(1, editorBox(&col.aliasesInput, "Aliases")),
+								layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+								layout.Rigid(func(gtx C) D {
+									if col.required {
+										lbl := material.Caption(th, "Obrigatória")
+										lbl.Color = theme.Colors.TextMuted
+										return lbl.Layout(gtx)
+									}
+									return material.CheckBox(th, &col.optional, "Opcional").Layout(gtx)
+								}),
+							)
+						})
+					})
+				}),
+				layout.Rigid(func(gtx C) D {
+					if ed.message == "" {
+						return D{}
+					}
+					lbl := material.Body2(th, ed.message)
+					lbl.Color = ed.messageColor
+					return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, lbl.Layout)
+				}),
+				layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+				layout.Rigid(func(gtx C) D {
+					return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+						layout.Rigid(func(gtx C) D {
+							if ed.profileID == 0 {
+								return D{}
+							}
+							return deleteBtn.Layout(gtx)
+						}),
+						layout.Flexed(1, func(gtx C) D { return D{} }),
+						layout.Rigid(closeBtn.Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+						layout.Rigid(newBtn.Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+						layout.Rigid(saveBtn.Layout),
+					)
+				}),
+			)
+		})
+	})
+}
@@ -0,0 +1,221 @@
+package pages
+
+import (
+	"fmt"
+	"image/color"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+)
+
+// importSnapshotPanel guarda o estado do painel de snapshots (versões anteriores dos títulos) de uma seção.
+type importSnapshotPanel struct {
+	snapshots   []*models.ImportSnapshotPublic
+	restoreBtns []widget.Clickable // Um botão "Restaurar" por snapshot.
+	list        widget.List
+
+	confirmID  uint64 // Snapshot aguardando confirmação da restauração (0 se nenhum).
+	confirmBtn widget.Clickable
+	cancelBtn  widget.Clickable
+
+	isLoading    bool
+	isRestoring  bool
+	message      string
+	messageColor color.NRGBA
+}
+
+// loadSectionSnapshots carrega os snapshots do tipo da seção.
+func (p *ImportPage) loadSectionSnapshots(section *ImportSectionState, currentSession *auth.SessionData) {
+	panel := section.SnapshotPanel
+	if panel == nil || panel.isLoading {
+		return
+	}
+	panel.isLoading = true
+	p.router.GetAppWindow().Invalidate()
+
+	go func(sec *ImportSectionState, sess *auth.SessionData) {
+		snapshots, err := p.importService.GetImportSnapshots(sec.Config.ID, sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			panel.isLoading = false
+			if err != nil {
+				panel.message = fmt.Sprintf("Erro ao carregar versões anteriores: %v", err)
+				panel.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao carregar snapshots de importação para %s: %v", sec.Config.ID, err)
+			} else {
+				panel.snapshots = snapshots
+				panel.restoreBtns = make([]widget.Clickable, len(snapshots))
+				panel.confirmID = 0
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(section, currentSession)
+}
+
+// handleSnapshotPanelEvents processa os botões do painel de snapshots de uma seção.
+func (p *ImportPage) handleSnapshotPanelEvents(gtx layout.Context, section *ImportSectionState, currentSession *auth.SessionData) {
+	if section.SnapshotsBtn.Clicked(gtx) {
+		if section.SnapshotPanel != nil {
+			section.SnapshotPanel = nil
+		} else {
+			section.SnapshotPanel = &importSnapshotPanel{}
+			section.SnapshotPanel.list.Axis = layout.Vertical
+			p.loadSectionSnapshots(section, currentSession)
+		}
+	}
+	panel := section.SnapshotPanel
+	if panel == nil {
+		return
+	}
+	for i := range panel.restoreBtns {
+		if panel.restoreBtns[i].Clicked(gtx) && !panel.isRestoring && i < len(panel.snapshots) {
+			panel.confirmID = panel.snapshots[i].ID
+			panel.message = ""
+		}
+	}
+	if panel.cancelBtn.Clicked(gtx) {
+		panel.confirmID = 0
+	}
+	if panel.confirmBtn.Clicked(gtx) && panel.confirmID != 0 && !panel.isRestoring && !section.IsImporting {
+		p.handleRestoreSnapshot(section, panel.confirmID, currentSession)
+	}
+}
+
+// handleRestoreSnapshot restaura o snapshot confirmado pelo usuário.
+func (p *ImportPage) handleRestoreSnapshot(section *ImportSectionState, snapshotID uint64, currentSession *auth.SessionData) {
+	panel := section.SnapshotPanel
+	if errPerm := p.permManager.CheckPermission(currentSession, auth.PermImportRestoreSnapshot, nil); errPerm != nil {
+		panel.message = "Você não tem permissão para restaurar versões anteriores."
+		panel.messageColor = theme.Colors.Danger
+		p.router.GetAppWindow().Invalidate()
+		return
+	}
+
+	panel.isRestoring = true
+	panel.confirmID = 0
+	panel.message = fmt.Sprintf("Restaurando a versão #%d...", snapshotID)
+	panel.messageColor = theme.Colors.TextMuted
+	p.router.GetAppWindow().Invalidate()
+
+	go func(sec *ImportSectionState, id uint64, sess *auth.SessionData) {
+		snapshot, restoredCount, err := p.importService.RestoreImportSnapshot(id, sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			panel.isRestoring = false
+			if err != nil {
+				panel.message = fmt.Sprintf("Falha ao restaurar a versão #%d: %v", id, err)
+				panel.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao restaurar snapshot de importação ID %d (%s): %v", id, sec.Config.ID, err)
+			} else {
+				panel.message = fmt.Sprintf("Versão #%d ('%s') restaurada: %d títulos.", snapshot.ID, snapshot.OriginalFilename, restoredCount)
+				panel.messageColor = theme.Colors.Success
+				p.updateSpecificSectionStatus(sec, sess)
+				p.loadSectionSnapshots(sec, sess)
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(section, snapshotID, currentSession)
+}
+
+// layoutSnapshotPanel desenha a lista de snapshots de uma seção, com a ação de restaurar.
+func (p *ImportPage) layoutSnapshotPanel(gtx layout.Context, th *material.Theme, section *ImportSectionState, canRestore bool) layout.Dimensions {
+	panel := section.SnapshotPanel
+	border := widget.Border{Color: theme.Colors.Border, CornerRadius: theme.CornerRadius, Width: theme.BorderWidthDefault}
+	return border.Layout(gtx, func(gtx C) D {
+		return layout.UniformInset(unit.Dp(8)).Layout(gtx, func(gtx C) D {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx C) D {
+					lbl := material.Body1(th, "Versões anteriores (snapshots após cada importação)")
+					lbl.Font.Weight = font.SemiBold
+					return lbl.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx C) D {
+					switch {
+					case panel.isLoading:
+						return material.Body2(th, "Carregando...").Layout(gtx)
+					case len(panel.snapshots) == 0:
+						emptyText := "Nenhuma versão guardada para este tipo. Uma versão é guardada a cada importação concluída."
+						if !p.cfg.ImportSnapshotEnabled {
+							emptyText = "Nenhuma versão guardada: os snapshots de importação estão desabilitados (APP_IMPORT_SNAPSHOT_ENABLED=false)."
+						}
+						lbl := material.Body2(th, emptyText)
+						lbl.Color = theme.Colors.TextMuted
+						return lbl.Layout(gtx)
+					}
+					gtx.Constraints.Max.Y = gtx.Dp(unit.Dp(importPreviewMaxListHeight))
+					gtx.Constraints.Min.Y = 0
+					return material.List(th, &panel.list).Layout(gtx, len(panel.snapshots), func(gtx C, index int) D {
+						if index < 0 || index >= len(panel.snapshots) || index >= len(panel.restoreBtns) {
+							return D{}
+						}
+						snap := panel.snapshots[index]
+						text := fmt.Sprintf("#%d · %s · %s · %d títulos · %s por %s",
+							snap.ID, snap.OriginalFilename, snap.ImportMode, snap.RecordCount,
+							snap.CreatedAt.Local().Format("02/01/2006 15:04:05"), snap.CreatedBy)
+						if snap.RestoredAt != nil && snap.RestoredBy != nil {
+							text += fmt.Sprintf(" (restaurada em %s por %s)", snap.RestoredAt.Local().Format("02/01/2006 15:04"), *snap.RestoredBy)
+						}
+						return layout.Inset{Top: unit.Dp(2), Bottom: unit.Dp(2)}.Layout(gtx, func(gtx C) D {
+							return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+								layout.Flexed(1, func(gtx C) D {
+									lbl := material.Caption(th, text)
+									lbl.MaxLines = 1
+									return lbl.Layout(gtx)
+								}),
+								layout.Rigid(func(gtx C) D {
+									if !canRestore {
+										return D{}
+									}
+									btn := material.Button(th, &panel.restoreBtns[index], "Restaurar")
+									if panel.isRestoring || section.IsImporting {
+										btn.Background = theme.Colors.Grey300
+										btn.Color = theme.Colors.TextMuted
+										gtx = gtx.Disabled()
+									}
+									return btn.Layout(gtx)
+								}),
+							)
+						})
+					})
+				}),
+				layout.Rigid(func(gtx C) D { // Confirmação da restauração
+					if panel.confirmID == 0 {
+						return D{}
+					}
+					confirmBtn := material.Button(th, &panel.confirmBtn, "Confirmar Restauração")
+					confirmBtn.Background = theme.Colors.Danger
+					cancelBtn := material.Button(th, &panel.cancelBtn, "Cancelar")
+					cancelBtn.Background = theme.Colors.Grey300
+					cancelBtn.Color = theme.Colors.Text
+					return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx C) D {
+						return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+							layout.Flexed(1, func(gtx C) D {
+								lbl := material.Body2(th, fmt.Sprintf("Todos os títulos atuais serão substituídos pela versão #%d. Confirma?", panel.confirmID))
+								lbl.Color = theme.Colors.Warning
+								return lbl.Layout(gtx)
+							}),
+							layout.Rigid(cancelBtn.Layout),
+							layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+							layout.Rigid(confirmBtn.Layout),
+						)
+					})
+				}),
+				layout.Rigid(func(gtx C) D {
+					if panel.message == "" {
+						return D{}
+					}
+					lbl := material.Body2(th, panel.message)
+					lbl.Color = panel.messageColor
+					return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, lbl.Layout)
+				}),
+			)
+		})
+	})
+}
@@ -39,14 +39,20 @@ type MainAppLayout struct {
 	router *ui.Router
 	cfg    *core.Config
 
-	userService    services.UserService
-	roleService    services.RoleService
-	networkService services.NetworkService
-	cnpjService    services.CNPJService
-	importService  services.ImportService
-	auditService   services.AuditLogService
-	permManager    *auth.PermissionManager
-	sessionManager *auth.SessionManager
+	userService      services.UserService
+	roleService      services.RoleService
+	networkService   services.NetworkService
+	cnpjService      services.CNPJService
+	tituloService    services.TituloService
+	agingService     services.AgingReportService
+	nettingService   services.NettingService
+	dashboardService services.DashboardService
+	cashFlowService  services.CashFlowService
+	dueDigestService services.DueDigestService
+	importService    services.ImportService
+	auditService     services.AuditLogService
+	permManager      *auth.PermissionManager
+	sessionManager   *auth.SessionManager
 
 	currentModuleID navigation.PageID
 	sidebarModules  []ModuleConfig
@@ -75,24 +81,36 @@ func NewMainAppLayout(
 	sessMan *auth.SessionManager,
 ) *MainAppLayout {
 	ml := &MainAppLayout{
-		router:         router,
-		cfg:            cfg,
-		userService:    userSvc,
-		roleService:    roleSvc,
-		networkService: netSvc,
-		cnpjService:    cnpjSvc,
-		importService:  importSvc,
-		auditService:   router.AuditLogService(),
-		permManager:    permMan,
-		sessionManager: sessMan,
-		modulePages:    make(map[navigation.PageID]ui.Page),
+		router:           router,
+		cfg:              cfg,
+		userService:      userSvc,
+		roleService:      roleSvc,
+		networkService:   netSvc,
+		cnpjService:      cnpjSvc,
+		tituloService:    router.TituloService(),
+		agingService:     router.AgingReportService(),
+		nettingService:   router.NettingService(),
+		dashboardService: router.DashboardService(),
+		cashFlowService:  router.CashFlowService(),
+		dueDigestService: router.DueDigestService(),
+		importService:    importSvc,
+		auditService:     router.AuditLogService(),
+		permManager:      permMan,
+		sessionManager:   sessMan,
+		modulePages:      make(map[navigation.PageID]ui.Page),
 	}
 
+	ml.modulePages[ui.PageNetworks] = NewNetworksPage(ml.router, ml.cfg, ml.networkService, ml.cnpjService, ml.permManager, ml.sessionManager)
 	ml.modulePages[ui.PageCNPJ] = NewCNPJPage(ml.router, ml.cfg, ml.cnpjService, ml.networkService, ml.permManager, ml.sessionManager)
 	ml.modulePages[ui.PageAdminPermissions] = NewAdminPermissionsPage(ml.router, ml.cfg, ml.userService, ml.roleService, ml.auditService, ml.permManager, ml.sessionManager)
 	ml.modulePages[ui.PageRoleManagement] = NewRoleManagementPage(ml.router, ml.cfg, ml.roleService, ml.auditService, ml.permManager, ml.sessionManager)
+	ml.modulePages[ui.PageTitulos] = NewTitulosPage(ml.router, ml.cfg, ml.tituloService, ml.networkService, ml.permManager, ml.sessionManager)
+	ml.modulePages[ui.PageAging] = NewAgingPage(ml.router, ml.cfg, ml.agingService, ml.permManager, ml.sessionManager)
+	ml.modulePages[ui.PageNetting] = NewNettingPage(ml.router, ml.cfg, ml.nettingService, ml.permManager, ml.sessionManager)
+	ml.modulePages[ui.PageDashboard] = NewDashboardPage(ml.router, ml.cfg, ml.dashboardService, ml.permManager, ml.sessionManager, ml.navigateToModule)
+	ml.modulePages[ui.PageCashFlow] = NewCashFlowPage(ml.router, ml.cfg, ml.cashFlowService, ml.permManager, ml.sessionManager)
+	ml.modulePages[ui.PageDueDigest] = NewDueDigestPage(ml.router, ml.cfg, ml.dueDigestService, ml.permManager, ml.sessionManager)
 	ml.modulePages[ui.PageImport] = NewImportPage(ml.router, ml.cfg, ml.importService, ml.permManager, ml.sessionManager)
-	ml.modulePages[ui.PageNetworks] = &PlaceholderPage{Title: "Gerenciamento de Redes"}
 
 	return ml
 }
@@ -115,10 +133,16 @@ func (ml *MainAppLayout) loadSidebarModules() {
 		Cfg      ModuleConfig
 	}
 	allModuleDefs := []moduleDef{
+		{IconData: icons.ActionDashboard, Cfg: ModuleConfig{ID: ui.PageDashboard, Title: "Painel", RequiredPermission: auth.PermTituloView}},
 		{IconData: icons.ActionList, Cfg: ModuleConfig{ID: ui.PageNetworks, Title: "Redes", RequiredPermission: auth.PermNetworkView}},
 		{IconData: icons.ActionVerifiedUser, Cfg: ModuleConfig{ID: ui.PageCNPJ, Title: "CNPJs", RequiredPermission: auth.PermCNPJView}},
 		{IconData: icons.ActionSupervisorAccount, Cfg: ModuleConfig{ID: ui.PageAdminPermissions, Title: "Usuários", RequiredPermission: auth.PermUserRead}},
 		{IconData: icons.ActionLockOpen, Cfg: ModuleConfig{ID: ui.PageRoleManagement, Title: "Perfis", RequiredPermission: auth.PermRoleManage}},
+		{IconData: icons.ActionReceipt, Cfg: ModuleConfig{ID: ui.PageTitulos, Title: "Títulos", RequiredPermission: auth.PermTituloView}},
+		{IconData: icons.ActionAssessment, Cfg: ModuleConfig{ID: ui.PageAging, Title: "Aging", RequiredPermission: auth.PermTituloView}},
+		{IconData: icons.ActionCompareArrows, Cfg: ModuleConfig{ID: ui.PageNetting, Title: "Encontro de Contas", RequiredPermission: auth.PermTituloView}},
+		{IconData: icons.EditorShowChart, Cfg: ModuleConfig{ID: ui.PageCashFlow, Title: "Fluxo de Caixa", RequiredPermission: auth.PermTituloView}},
+		{IconData: icons.CommunicationEmail, Cfg: ModuleConfig{ID: ui.PageDueDigest, Title: "Resumo por E-mail", RequiredPermission: auth.PermTituloView}},
 		{IconData: icons.FileFileUpload, Cfg: ModuleConfig{ID: ui.PageImport, Title: "Importar Dados", RequiredPermission: auth.PermImportExecute}},
 	}
 
@@ -170,12 +194,18 @@ func (ml *MainAppLayout) OnNavigatedTo(params interface{}) {
 		return
 	}
 	ml.currentUserData = userData
+	// Cada login começa pelo painel de redes; se o usuário não tiver acesso a ele,
+	// loadSidebarModules escolhe o primeiro módulo acessível.
+	ml.currentModuleID = ui.PageDashboard
 	ml.loadSidebarModules()
 
 	currentPage := ml.getModulePage(ml.currentModuleID)
 	if currentPage == nil && ml.currentModuleID != ui.PageNone {
 		appLogger.Errorf("Falha ao carregar o módulo ID %v em MainAppLayout.", ml.currentModuleID)
 	}
+	if page, ok := ml.modulePages[ml.currentModuleID]; ok && page != nil {
+		page.OnNavigatedTo(nil)
+	}
 }
 
 func (ml *MainAppLayout) OnNavigatedFrom() {
@@ -186,6 +216,34 @@ func (ml *MainAppLayout) OnNavigatedFrom() {
 	ml.currentUserData = nil
 }
 
+// navigateToModule troca o módulo exibido na área de conteúdo, repassando `params` ao
+// OnNavigatedTo da nova página. Módulos fora da sidebar do usuário são ignorados.
+func (ml *MainAppLayout) navigateToModule(moduleID navigation.PageID, params interface{}) {
+	title := ""
+	for _, modCfg := range ml.sidebarModules {
+		if modCfg.ID == moduleID {
+			title = modCfg.Title
+			break
+		}
+	}
+	if title == "" {
+		appLogger.Warnf("Navegação para o módulo ID %v ignorada: módulo não disponível para o usuário.", moduleID)
+		return
+	}
+
+	if oldPage, ok := ml.modulePages[ml.currentModuleID]; ok && oldPage != nil {
+		oldPage.OnNavigatedFrom()
+	}
+	ml.currentModuleID = moduleID
+	appLogger.Infof("Módulo da MainAppLayout alterado para: %s (ID: %v)", title, moduleID)
+	if newPage, ok := ml.modulePages[ml.currentModuleID]; ok && newPage != nil {
+		newPage.OnNavigatedTo(params)
+	} else {
+		appLogger.Errorf("Tentativa de navegar para módulo ID %v que não tem página associada.", ml.currentModuleID)
+	}
+	ml.router.GetAppWindow().Invalidate()
+}
+
 func (ml *MainAppLayout) getModulePage(moduleID navigation.PageID) ui.Page {
 	if moduleID == ui.PageNone {
 		return &PlaceholderPage{Title: "Nenhum Módulo Selecionado"}
@@ -213,16 +271,7 @@ func (ml *MainAppLayout) Layout(gtx layout.Context) layout.Dimensions {
 		if ml.sidebarClicks[i].Clicked(gtx) {
 			selectedModuleID := ml.sidebarModules[i].ID
 			if ml.currentModuleID != selectedModuleID {
-				if oldPage, ok := ml.modulePages[ml.currentModuleID]; ok && oldPage != nil {
-					oldPage.OnNavigatedFrom()
-				}
-				ml.currentModuleID = selectedModuleID
-				appLogger.Infof("Módulo da MainAppLayout alterado para: %s (ID: %v)", ml.sidebarModules[i].Title, selectedModuleID)
-				if newPage, ok := ml.modulePages[ml.currentModuleID]; ok && newPage != nil {
-					newPage.OnNavigatedTo(nil)
-				} else {
-					appLogger.Errorf("Tentativa de navegar para módulo ID %v que não tem página associada.", ml.currentModuleID)
-				}
+				ml.navigateToModule(selectedModuleID, nil)
 			}
 		}
 	}
@@ -0,0 +1,406 @@
+package pages
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"time"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/config"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/services"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/theme"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/ui/components"
+)
+
+// Visões da página de encontro de contas.
+const (
+	nettingViewPartners = "PARTNERS"
+	nettingViewNetworks = "NETWORKS"
+)
+
+// Pesos das colunas das tabelas de parceiros e de redes.
+var (
+	nettingPartnerWeights = []float32{0.22, 0.13, 0.13, 0.11, 0.11, 0.11, 0.11}
+	nettingNetworkWeights = []float32{0.30, 0.10, 0.15, 0.15, 0.15, 0.15}
+)
+
+// NettingPage exibe o encontro de contas entre direitos e obrigações por parceiro e por rede,
+// com as compensações propostas e a exportação do extrato do parceiro.
+type NettingPage struct {
+	router         *ui.Router
+	cfg            *core.Config
+	nettingService services.NettingService
+	permManager    *auth.PermissionManager
+	sessionManager *auth.SessionManager
+
+	modeEnum    widget.Enum // models.NettingMatchMode.
+	viewEnum    widget.Enum // nettingViewPartners ou nettingViewNetworks.
+	generateBtn widget.Clickable
+
+	report      *models.NettingReport
+	selected    int // Índice do parceiro aberto na gaveta de compensações (-1 se nenhum).
+	list        widget.List
+	rowClicks   []widget.Clickable
+	pairList    widget.List
+	exportBtn   widget.Clickable
+	closeBtn    widget.Clickable
+	isLoading   bool
+	isExporting bool
+
+	statusMessage string
+	messageColor  color.NRGBA
+	accessDenied  bool
+	spinner       *components.LoadingSpinner
+}
+
+// NewNettingPage cria uma nova instância da página de encontro de contas.
+func NewNettingPage(
+	router *ui.Router,
+	cfg *core.Config,
+	nettingSvc services.NettingService,
+	permMan *auth.PermissionManager,
+	sessMan *auth.SessionManager,
+) *NettingPage {
+	p := &NettingPage{
+		router:         router,
+		cfg:            cfg,
+		nettingService: nettingSvc,
+		permManager:    permMan,
+		sessionManager: sessMan,
+		selected:       -1,
+		spinner:        components.NewLoadingSpinner(theme.Colors.Primary),
+	}
+	p.modeEnum.Value = string(models.NettingMatchCNPJ)
+	p.viewEnum.Value = nettingViewPartners
+	p.list.Axis = layout.Vertical
+	p.pairList.Axis = layout.Vertical
+	return p
+}
+
+// OnNavigatedTo é chamado quando a página se torna ativa.
+func (p *NettingPage) OnNavigatedTo(params interface{}) {
+	appLogger.Info("Navegou para NettingPage")
+	currentSession, errSess := p.sessionManager.GetCurrentSession()
+	if errSess != nil || currentSession == nil {
+		p.router.GetAppWindow().HandleLogout()
+		return
+	}
+	if err := p.permManager.CheckPermission(currentSession, auth.PermTituloView, nil); err != nil {
+		p.accessDenied = true
+		p.statusMessage = fmt.Sprintf("Acesso negado ao encontro de contas: %v", err)
+		p.messageColor = theme.Colors.Danger
+		p.router.GetAppWindow().Invalidate()
+		return
+	}
+	p.accessDenied = false
+	if p.report == nil {
+		p.generate(currentSession)
+	}
+}
+
+// OnNavigatedFrom é chamado quando o router navega para fora desta página.
+func (p *NettingPage) OnNavigatedFrom() {
+	appLogger.Info("Navegando para fora da NettingPage")
+	p.spinner.Stop(p.router.GetAppWindow().Context())
+}
+
+// generate recalcula o encontro de contas em segundo plano.
+func (p *NettingPage) generate(currentSession *auth.SessionData) {
+	if p.isLoading || p.accessDenied {
+		return
+	}
+	p.isLoading = true
+	p.statusMessage = "Calculando encontro de contas..."
+	p.messageColor = theme.Colors.TextMuted
+	p.spinner.Start(p.router.GetAppWindow().Context())
+	p.router.GetAppWindow().Invalidate()
+
+	mode := models.NettingMatchMode(p.modeEnum.Value)
+	go func(sess *auth.SessionData) {
+		report, err := p.nettingService.GenerateNettingReport(mode, sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			p.isLoading = false
+			p.spinner.Stop(p.router.GetAppWindow().Context())
+			if err != nil {
+				p.statusMessage = fmt.Sprintf("Erro ao calcular o encontro de contas: %v", err)
+				p.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao calcular encontro de contas (%s): %v", mode, err)
+			} else {
+				p.report = report
+				p.selected = -1
+				p.rowClicks = make([]widget.Clickable, len(report.Partners))
+				p.list.Position = layout.Position{}
+				p.statusMessage = fmt.Sprintf("%d parceiros com valores a receber e a pagar. Valor compensável: %s.",
+					len(report.Partners), formatMoney(report.Totals.Offsettable))
+				p.messageColor = theme.Colors.Success
+				if report.InvalidTitles > 0 {
+					p.statusMessage += fmt.Sprintf(" %d títulos ignorados por valores inválidos.", report.InvalidTitles)
+					p.messageColor = theme.Colors.Warning
+				}
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(currentSession)
+}
+
+// exportStatement exporta o extrato do parceiro selecionado.
+func (p *NettingPage) exportStatement(currentSession *auth.SessionData) {
+	if p.isExporting || p.report == nil || p.selected < 0 || p.selected >= len(p.report.Partners) {
+		return
+	}
+	partner := p.report.Partners[p.selected]
+	mode := p.report.MatchMode
+	p.isExporting = true
+	p.statusMessage = fmt.Sprintf("Exportando extrato de '%s'...", partner.Name)
+	p.messageColor = theme.Colors.TextMuted
+	p.router.GetAppWindow().Invalidate()
+
+	go func(sess *auth.SessionData) {
+		_, exportPath, err := p.nettingService.ExportNettingStatement(mode, partner.Key, sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			p.isExporting = false
+			if err != nil {
+				p.statusMessage = fmt.Sprintf("Falha ao exportar o extrato de '%s': %v", partner.Name, err)
+				p.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao exportar extrato de encontro de contas de %s: %v", partner.Key, err)
+			} else {
+				p.statusMessage = fmt.Sprintf("Extrato de '%s' exportado para: %s", partner.Name, exportPath)
+				p.messageColor = theme.Colors.Success
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(currentSession)
+}
+
+// Layout desenha a página.
+func (p *NettingPage) Layout(gtx layout.Context) layout.Dimensions {
+	th := p.router.GetAppWindow().Theme()
+	currentSession, _ := p.sessionManager.GetCurrentSession()
+
+	if p.generateBtn.Clicked(gtx) {
+		p.generate(currentSession)
+	}
+	for i := range p.rowClicks {
+		if p.rowClicks[i].Clicked(gtx) {
+			p.selected = i
+			p.pairList.Position = layout.Position{}
+		}
+	}
+	if p.closeBtn.Clicked(gtx) {
+		p.selected = -1
+	}
+	if p.exportBtn.Clicked(gtx) {
+		p.exportStatement(currentSession)
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D { return p.layoutForm(gtx, th) }),
+		layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+		layout.Rigid(func(gtx C) D {
+			if p.statusMessage == "" {
+				return D{}
+			}
+			lbl := material.Body2(th, p.statusMessage)
+			lbl.Color = p.messageColor
+			return layout.Inset{Bottom: theme.DefaultVSpacer}.Layout(gtx, lbl.Layout)
+		}),
+		layout.Flexed(1, func(gtx C) D {
+			if p.report == nil {
+				return D{}
+			}
+			if p.viewEnum.Value == nettingViewNetworks {
+				return p.layoutNetworks(gtx, th)
+			}
+			if p.selected < 0 || p.selected >= len(p.report.Partners) {
+				return p.layoutPartners(gtx, th)
+			}
+			return layout.Flex{}.Layout(gtx,
+				layout.Flexed(1, func(gtx C) D { return p.layoutPartners(gtx, th) }),
+				layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+				layout.Rigid(func(gtx C) D { return p.layoutPairsDrawer(gtx, th) }),
+			)
+		}),
+	)
+}
+
+// layoutForm desenha as opções de associação e de visão.
+func (p *NettingPage) layoutForm(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	generateBtn := material.Button(th, &p.generateBtn, "Recalcular")
+	if p.isLoading || p.accessDenied {
+		generateBtn.Background = theme.Colors.Grey300
+		generateBtn.Color = theme.Colors.TextMuted
+	}
+	return material.Card(th, theme.Colors.Surface, theme.ElevationSmall, layout.UniformInset(unit.Dp(12)),
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(material.Body2(th, "Associar por:").Layout),
+				layout.Rigid(material.RadioButton(th, &p.modeEnum, string(models.NettingMatchCNPJ), "CNPJ/CPF").Layout),
+				layout.Rigid(material.RadioButton(th, &p.modeEnum, string(models.NettingMatchRoot), "Raiz do CNPJ").Layout),
+				layout.Rigid(layout.Spacer{Width: unit.Dp(24)}.Layout),
+				layout.Rigid(material.Body2(th, "Exibir:").Layout),
+				layout.Rigid(material.RadioButton(th, &p.viewEnum, nettingViewPartners, "Parceiros").Layout),
+				layout.Rigid(material.RadioButton(th, &p.viewEnum, nettingViewNetworks, "Redes").Layout),
+				layout.Flexed(1, func(gtx C) D { return D{} }),
+				layout.Rigid(func(gtx C) D {
+					if !p.isLoading {
+						return D{}
+					}
+					return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, p.spinner.Layout)
+				}),
+				layout.Rigid(generateBtn.Layout),
+			)
+		}).Layout(gtx)
+}
+
+// layoutPartners desenha a tabela de parceiros, com a linha de total no rodapé.
+func (p *NettingPage) layoutPartners(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	report := p.report
+	keyTitle := "CNPJ/CPF"
+	if report.MatchMode == models.NettingMatchRoot {
+		keyTitle = "Raiz do CNPJ"
+	}
+	headers := []string{"Parceiro", keyTitle, "Rede", "A receber", "A pagar", "Líquido", "Compensável"}
+	totals := []string{"Total", fmt.Sprintf("%d parceiros", report.Totals.Partners), "",
+		formatMoney(report.Totals.Receivable), formatMoney(report.Totals.Payable),
+		formatMoney(report.Totals.Net), formatMoney(report.Totals.Offsettable)}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			return layoutReportRow(gtx, th, headers, nettingPartnerWeights, theme.Colors.Grey200, true)
+		}),
+		layout.Flexed(1, func(gtx C) D {
+			if len(report.Partners) == 0 {
+				lbl := material.Body2(th, "Nenhum parceiro com valores a receber e a pagar em aberto.")
+				lbl.Color = theme.Colors.Info
+				return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, lbl.Layout)
+			}
+			return material.List(th, &p.list).Layout(gtx, len(report.Partners), func(gtx C, index int) D {
+				if index >= len(p.rowClicks) {
+					return D{}
+				}
+				partner := report.Partners[index]
+				bgColor := theme.Colors.Surface
+				if index%2 != 0 {
+					bgColor = theme.Colors.BackgroundAlt
+				}
+				if index == p.selected {
+					bgColor = theme.Colors.PrimaryLight
+				}
+				key := partner.Key
+				if report.MatchMode == models.NettingMatchCNPJ {
+					key = models.FormatCNPJCPF(key)
+				}
+				cells := []string{partner.Name, key, partner.NetworkName,
+					formatMoney(partner.Receivable), formatMoney(partner.Payable),
+					formatMoney(partner.Net), formatMoney(partner.Offsettable)}
+				return material.Clickable(gtx, &p.rowClicks[index], func(gtx C) D {
+					return layoutReportRow(gtx, th, cells, nettingPartnerWeights, bgColor, false)
+				})
+			})
+		}),
+		layout.Rigid(func(gtx C) D {
+			return layoutReportRow(gtx, th, totals, nettingPartnerWeights, theme.Colors.Grey200, true)
+		}),
+	)
+}
+
+// layoutNetworks desenha a consolidação por rede.
+func (p *NettingPage) layoutNetworks(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	report := p.report
+	headers := []string{"Rede", "Parceiros", "A receber", "A pagar", "Líquido", "Compensável"}
+	cellsOf := func(s models.NettingNetworkSummary) []string {
+		return []string{s.Name, fmt.Sprint(s.Partners), formatMoney(s.Receivable), formatMoney(s.Payable),
+			formatMoney(s.Net), formatMoney(s.Offsettable)}
+	}
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			return layoutReportRow(gtx, th, headers, nettingNetworkWeights, theme.Colors.Grey200, true)
+		}),
+		layout.Flexed(1, func(gtx C) D {
+			return material.List(th, &p.list).Layout(gtx, len(report.Networks), func(gtx C, index int) D {
+				bgColor := theme.Colors.Surface
+				if index%2 != 0 {
+					bgColor = theme.Colors.BackgroundAlt
+				}
+				return layoutReportRow(gtx, th, cellsOf(report.Networks[index]), nettingNetworkWeights, bgColor, false)
+			})
+		}),
+		layout.Rigid(func(gtx C) D {
+			return layoutReportRow(gtx, th, cellsOf(report.Totals), nettingNetworkWeights, theme.Colors.Grey200, true)
+		}),
+	)
+}
+
+// layoutPairsDrawer desenha a gaveta com as compensações propostas para o parceiro selecionado.
+func (p *NettingPage) layoutPairsDrawer(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	partner := p.report.Partners[p.selected]
+	width := gtx.Dp(unit.Dp(titulosDrawerWidth))
+	gtx.Constraints.Min.X = width
+	gtx.Constraints.Max.X = width
+
+	date := func(t *time.Time) string {
+		if t == nil {
+			return "sem vencimento"
+		}
+		return t.Format("02/01/2006")
+	}
+	exportBtn := material.Button(th, &p.exportBtn, "Exportar extrato")
+	if p.isExporting {
+		exportBtn.Background = theme.Colors.Grey300
+		exportBtn.Color = theme.Colors.TextMuted
+	}
+
+	return material.Card(th, theme.Colors.Surface, theme.ElevationMedium, layout.UniformInset(unit.Dp(12)),
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx C) D {
+					title := material.Subtitle1(th, partner.Name)
+					title.Font.Weight = font.SemiBold
+					title.MaxLines = 1
+					return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+						layout.Flexed(1, title.Layout),
+						layout.Rigid(material.Button(th, &p.closeBtn, "Fechar").Layout),
+					)
+				}),
+				layout.Rigid(func(gtx C) D {
+					lbl := material.Caption(th, fmt.Sprintf("%d a receber, %d a pagar. Líquido: %s",
+						len(partner.Direitos), len(partner.Obrigacoes), formatMoney(partner.Net)))
+					lbl.Color = theme.Colors.TextMuted
+					return lbl.Layout(gtx)
+				}),
+				layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+				layout.Rigid(material.Body2(th, "Compensações propostas (por vencimento):").Layout),
+				layout.Flexed(1, func(gtx C) D {
+					return material.List(th, &p.pairList).Layout(gtx, len(partner.Pairs), func(gtx C, index int) D {
+						pair := partner.Pairs[index]
+						return layout.Inset{Top: unit.Dp(4), Bottom: unit.Dp(4)}.Layout(gtx, func(gtx C) D {
+							return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+								layout.Rigid(func(gtx C) D {
+									lbl := material.Body2(th, formatMoney(pair.Amount))
+									lbl.Font.Weight = font.SemiBold
+									return lbl.Layout(gtx)
+								}),
+								layout.Rigid(material.Caption(th, fmt.Sprintf("Receber: %s (venc. %s)",
+									strings.TrimSpace(pair.Direito.Titulo), date(pair.Direito.DataVencimento))).Layout),
+								layout.Rigid(material.Caption(th, fmt.Sprintf("Pagar: %s (venc. %s)",
+									strings.TrimSpace(pair.Obrigacao.Titulo), date(pair.Obrigacao.DataVencimento))).Layout),
+							)
+						})
+					})
+				}),
+				layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+				layout.Rigid(exportBtn.Layout),
+			)
+		}).Layout(gtx)
+}
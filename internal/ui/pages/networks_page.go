@@ -0,0 +1,760 @@
+package pages
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/config"
+	appErrors "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/errors"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/services"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/theme"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/ui/components"
+)
+
+// Valores do filtro de status da lista de redes.
+const (
+	networkFilterAll      = "ALL"
+	networkFilterActive   = "ACTIVE"
+	networkFilterInactive = "INACTIVE"
+)
+
+// Pesos das colunas da tabela de redes (seleção, nome, comprador, status, atualização).
+var networkWeights = []float32{0.08, 0.30, 0.30, 0.12, 0.20}
+
+// networkRow é uma linha da tabela de redes, com a caixa de seleção para a exclusão em massa.
+type networkRow struct {
+	network  *models.NetworkPublic
+	selected widget.Bool
+	click    widget.Clickable
+}
+
+// NetworksPage gerencia as redes: busca por nome ou comprador, filtro de status, cadastro e edição,
+// ativação/desativação, exclusão em massa e consulta dos CNPJs de cada rede.
+type NetworksPage struct {
+	router         *ui.Router
+	cfg            *core.Config
+	networkService services.NetworkService
+	cnpjService    services.CNPJService
+	permManager    *auth.PermissionManager
+	sessionManager *auth.SessionManager
+
+	// Busca e filtro.
+	searchInput  widget.Editor
+	statusFilter widget.Enum
+	searchBtn    widget.Clickable
+	networks     []*models.NetworkPublic // Resultado da busca (ativas e inativas).
+	rows         []*networkRow           // Redes exibidas, após o filtro de status.
+	list         widget.List
+
+	// Exclusão em massa.
+	deleteBtn        widget.Clickable
+	confirmDelete    bool
+	confirmDeleteBtn widget.Clickable
+	cancelDeleteBtn  widget.Clickable
+
+	// Formulário de cadastro/edição da rede selecionada.
+	selected      *models.NetworkPublic // nil no cadastro de uma nova rede.
+	nameInput     widget.Editor
+	buyerInput    widget.Editor
+	nameFeedback  string
+	buyerFeedback string
+	saveBtn       widget.Clickable
+	clearBtn      widget.Clickable
+	toggleBtn     widget.Clickable
+	cnpjs         []*models.CNPJPublic // CNPJs da rede selecionada.
+	cnpjMessage   string
+	cnpjList      widget.List
+
+	isLoading     bool
+	statusMessage string
+	messageColor  color.NRGBA
+
+	accessDenied bool
+	spinner      *components.LoadingSpinner
+}
+
+// NewNetworksPage cria uma nova instância da página de gerenciamento de redes.
+func NewNetworksPage(
+	router *ui.Router,
+	cfg *core.Config,
+	netSvc services.NetworkService,
+	cnpjSvc services.CNPJService,
+	permMan *auth.PermissionManager,
+	sessMan *auth.SessionManager,
+) *NetworksPage {
+	p := &NetworksPage{
+		router:         router,
+		cfg:            cfg,
+		networkService: netSvc,
+		cnpjService:    cnpjSvc,
+		permManager:    permMan,
+		sessionManager: sessMan,
+		spinner:        components.NewLoadingSpinner(theme.Colors.Primary),
+	}
+	p.searchInput.SingleLine = true
+	p.nameInput.SingleLine = true
+	p.buyerInput.SingleLine = true
+	p.statusFilter.Value = networkFilterAll
+	p.list.Axis = layout.Vertical
+	p.cnpjList.Axis = layout.Vertical
+	return p
+}
+
+// OnNavigatedTo é chamado quando a página se torna ativa.
+func (p *NetworksPage) OnNavigatedTo(params interface{}) {
+	appLogger.Info("Navegou para NetworksPage")
+	currentSession, errSess := p.sessionManager.GetCurrentSession()
+	if errSess != nil || currentSession == nil {
+		p.router.GetAppWindow().HandleLogout()
+		return
+	}
+	if err := p.permManager.CheckPermission(currentSession, auth.PermNetworkView, nil); err != nil {
+		p.accessDenied = true
+		p.statusMessage = fmt.Sprintf("Acesso negado às redes: %v", err)
+		p.messageColor = theme.Colors.Danger
+		p.networks = nil
+		p.applyFilter()
+		p.router.GetAppWindow().Invalidate()
+		return
+	}
+	p.accessDenied = false
+	p.clearForm()
+	p.loadNetworks(currentSession, "")
+}
+
+// OnNavigatedFrom é chamado quando o router navega para fora desta página.
+func (p *NetworksPage) OnNavigatedFrom() {
+	appLogger.Info("Navegando para fora da NetworksPage")
+	p.confirmDelete = false
+	p.spinner.Stop(p.router.GetAppWindow().Context())
+}
+
+// hasPermission verifica uma permissão da sessão atual, para habilitar os botões.
+func (p *NetworksPage) hasPermission(session *auth.SessionData, perm auth.Permission) bool {
+	ok, _ := p.permManager.HasPermission(session, perm, nil)
+	return ok
+}
+
+// startLoading marca a página como ocupada com a mensagem informada.
+func (p *NetworksPage) startLoading(message string) {
+	p.isLoading = true
+	p.statusMessage = message
+	p.messageColor = theme.Colors.TextMuted
+	p.spinner.Start(p.router.GetAppWindow().Context())
+	p.router.GetAppWindow().Invalidate()
+}
+
+// stopLoading libera a página; chamado na goroutine da UI.
+func (p *NetworksPage) stopLoading() {
+	p.isLoading = false
+	p.spinner.Stop(p.router.GetAppWindow().Context())
+}
+
+// loadNetworks busca as redes pelo termo informado (nome ou comprador) em segundo plano.
+// As inativas são sempre carregadas; o filtro de status é aplicado na página.
+// `notice`, se informado, substitui a contagem na mensagem de status (resultado da ação anterior).
+func (p *NetworksPage) loadNetworks(currentSession *auth.SessionData, notice string) {
+	if p.isLoading || p.accessDenied {
+		return
+	}
+	term := strings.TrimSpace(p.searchInput.Text())
+	p.startLoading("Carregando redes...")
+
+	go func(sess *auth.SessionData) {
+		networks, err := p.networkService.SearchNetworks(term, nil, true, sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			p.stopLoading()
+			if err != nil {
+				p.statusMessage = fmt.Sprintf("Erro ao carregar redes: %v", err)
+				p.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao carregar redes (termo '%s'): %v", term, err)
+			} else {
+				p.networks = networks
+				p.applyFilter()
+				p.refreshSelected()
+				p.statusMessage = fmt.Sprintf("%d redes encontradas.", len(p.rows))
+				p.messageColor = theme.Colors.TextMuted
+				if notice != "" {
+					p.statusMessage = notice
+					p.messageColor = theme.Colors.Success
+				}
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(currentSession)
+}
+
+// applyFilter monta as linhas da tabela com as redes do filtro de status, ativas primeiro.
+// A seleção para exclusão é descartada.
+func (p *NetworksPage) applyFilter() {
+	p.confirmDelete = false
+	p.rows = p.rows[:0]
+	for _, network := range p.networks {
+		if (p.statusFilter.Value == networkFilterActive && !network.Status) ||
+			(p.statusFilter.Value == networkFilterInactive && network.Status) {
+			continue
+		}
+		p.rows = append(p.rows, &networkRow{network: network})
+	}
+	sort.SliceStable(p.rows, func(i, j int) bool {
+		return p.rows[i].network.Status && !p.rows[j].network.Status
+	})
+	p.list.Position = layout.Position{}
+}
+
+// selectedIDs retorna os IDs das redes marcadas para exclusão.
+func (p *NetworksPage) selectedIDs() []uint64 {
+	var ids []uint64
+	for _, row := range p.rows {
+		if row.selected.Value {
+			ids = append(ids, row.network.ID)
+		}
+	}
+	return ids
+}
+
+// refreshSelected atualiza a rede do formulário com os dados recarregados. Sem termo de busca, uma
+// rede ausente não existe mais e o formulário é limpo; com termo, ela pode só estar fora do resultado.
+func (p *NetworksPage) refreshSelected() {
+	if p.selected == nil {
+		return
+	}
+	for _, network := range p.networks {
+		if network.ID == p.selected.ID {
+			p.selected = network
+			return
+		}
+	}
+	if strings.TrimSpace(p.searchInput.Text()) == "" {
+		p.clearForm()
+	}
+}
+
+// clearForm volta o formulário para o cadastro de uma nova rede.
+func (p *NetworksPage) clearForm() {
+	p.selected = nil
+	p.nameInput.SetText("")
+	p.buyerInput.SetText("")
+	p.nameFeedback = ""
+	p.buyerFeedback = ""
+	p.cnpjs = nil
+	p.cnpjMessage = ""
+}
+
+// selectNetwork carrega a rede no formulário de edição e busca seus CNPJs.
+func (p *NetworksPage) selectNetwork(network *models.NetworkPublic, sess *auth.SessionData) {
+	p.selected = network
+	p.nameInput.SetText(network.Name)
+	p.buyerInput.SetText(network.Buyer)
+	p.nameFeedback = ""
+	p.buyerFeedback = ""
+	p.loadCNPJs(network.ID, sess)
+}
+
+// loadCNPJs busca os CNPJs (ativos e inativos) da rede em segundo plano.
+func (p *NetworksPage) loadCNPJs(networkID uint64, currentSession *auth.SessionData) {
+	p.cnpjs = nil
+	p.cnpjList.Position = layout.Position{}
+	if !p.hasPermission(currentSession, auth.PermCNPJView) {
+		p.cnpjMessage = "Você não tem permissão para visualizar CNPJs."
+		return
+	}
+	p.cnpjMessage = "Carregando CNPJs..."
+
+	go func(sess *auth.SessionData) {
+		cnpjs, err := p.cnpjService.GetCNPJsByNetwork(networkID, true, sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			if p.selected == nil || p.selected.ID != networkID {
+				return // Outra rede foi selecionada enquanto carregava.
+			}
+			switch {
+			case err != nil:
+				p.cnpjMessage = fmt.Sprintf("Erro ao carregar CNPJs: %v", err)
+				appLogger.Errorf("Erro ao carregar CNPJs da rede ID %d: %v", networkID, err)
+			case len(cnpjs) == 0:
+				p.cnpjMessage = "Nenhum CNPJ cadastrado nesta rede."
+			default:
+				sort.Slice(cnpjs, func(i, j int) bool { return cnpjs[i].CNPJ < cnpjs[j].CNPJ })
+				p.cnpjs = cnpjs
+				p.cnpjMessage = fmt.Sprintf("%d CNPJs nesta rede.", len(cnpjs))
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(currentSession)
+}
+
+// setFieldFeedback exibe nos campos as mensagens de um erro de validação.
+func (p *NetworksPage) setFieldFeedback(err error) {
+	var valErr *appErrors.ValidationError
+	if errors.As(err, &valErr) {
+		p.nameFeedback = valErr.Fields["name"]
+		p.buyerFeedback = valErr.Fields["buyer"]
+		if p.nameFeedback == "" && p.buyerFeedback == "" {
+			p.nameFeedback = valErr.Message
+		}
+		return
+	}
+	if errors.Is(err, appErrors.ErrConflict) {
+		p.nameFeedback = "Já existe uma rede com este nome."
+	}
+}
+
+// save cadastra a nova rede ou grava a edição da rede selecionada em segundo plano.
+// Os dados são validados antes do envio com as mesmas regras do serviço.
+func (p *NetworksPage) save(currentSession *auth.SessionData) {
+	if p.isLoading || p.accessDenied {
+		return
+	}
+	p.nameFeedback, p.buyerFeedback = "", ""
+	name, buyer := p.nameInput.Text(), p.buyerInput.Text()
+
+	var validationErr error
+	if p.selected == nil {
+		createData := models.NetworkCreate{Name: name, Buyer: buyer}
+		validationErr = createData.CleanAndValidate()
+	} else {
+		updateData := models.NetworkUpdate{Name: &name, Buyer: &buyer}
+		validationErr = updateData.CleanAndValidate()
+	}
+	if validationErr != nil {
+		p.setFieldFeedback(validationErr)
+		p.statusMessage = "Corrija os erros no formulário."
+		p.messageColor = theme.Colors.Warning
+		p.router.GetAppWindow().Invalidate()
+		return
+	}
+
+	editing := p.selected
+	p.startLoading("Salvando rede...")
+
+	go func(sess *auth.SessionData) {
+		var saved *models.NetworkPublic
+		var err error
+		if editing == nil {
+			saved, err = p.networkService.CreateNetwork(models.NetworkCreate{Name: name, Buyer: buyer}, sess)
+		} else {
+			saved, err = p.networkService.UpdateNetwork(editing.ID, models.NetworkUpdate{Name: &name, Buyer: &buyer}, sess)
+		}
+
+		p.router.GetAppWindow().Execute(func() {
+			p.stopLoading()
+			if err != nil {
+				p.setFieldFeedback(err)
+				p.statusMessage = fmt.Sprintf("Erro ao salvar a rede: %v", err)
+				p.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao salvar rede '%s': %v", name, err)
+				p.router.GetAppWindow().Invalidate()
+				return
+			}
+			notice := fmt.Sprintf("Rede '%s' atualizada.", saved.Name)
+			if editing == nil {
+				p.clearForm()
+				notice = fmt.Sprintf("Rede '%s' cadastrada (comprador: %s).", saved.Name, saved.Buyer)
+			} else {
+				p.selected = saved
+			}
+			p.loadNetworks(sess, notice)
+		})
+	}(currentSession)
+}
+
+// toggleStatus ativa ou desativa a rede selecionada em segundo plano.
+func (p *NetworksPage) toggleStatus(currentSession *auth.SessionData) {
+	if p.isLoading || p.accessDenied || p.selected == nil {
+		return
+	}
+	network := p.selected
+	p.startLoading("Alterando status da rede...")
+
+	go func(sess *auth.SessionData) {
+		updated, err := p.networkService.ToggleNetworkStatus(network.ID, sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			p.stopLoading()
+			if err != nil {
+				p.statusMessage = fmt.Sprintf("Erro ao alterar o status da rede '%s': %v", network.Name, err)
+				p.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao alterar status da rede ID %d: %v", network.ID, err)
+				p.router.GetAppWindow().Invalidate()
+				return
+			}
+			p.selected = updated
+			p.loadNetworks(sess, fmt.Sprintf("Rede '%s' %s.", updated.Name, boolToString(updated.Status, "ativada", "desativada")))
+		})
+	}(currentSession)
+}
+
+// deleteSelected exclui as redes marcadas em segundo plano. Chamado após a confirmação.
+func (p *NetworksPage) deleteSelected(currentSession *auth.SessionData) {
+	p.confirmDelete = false
+	ids := p.selectedIDs()
+	if p.isLoading || p.accessDenied || len(ids) == 0 {
+		return
+	}
+	p.startLoading(fmt.Sprintf("Excluindo %d redes...", len(ids)))
+
+	go func(sess *auth.SessionData) {
+		deleted, err := p.networkService.DeleteNetworks(ids, sess)
+
+		p.router.GetAppWindow().Execute(func() {
+			p.stopLoading()
+			if err != nil {
+				p.statusMessage = fmt.Sprintf("Erro ao excluir redes: %v", err)
+				if errors.Is(err, appErrors.ErrConflict) {
+					p.statusMessage = fmt.Sprintf("Não foi possível excluir: alguma rede selecionada ainda tem CNPJs ou títulos vinculados (%v).", err)
+				}
+				p.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao excluir %d redes: %v", len(ids), err)
+				p.router.GetAppWindow().Invalidate()
+				return
+			}
+			for _, id := range ids {
+				if p.selected != nil && p.selected.ID == id {
+					p.clearForm()
+					break
+				}
+			}
+			p.loadNetworks(sess, fmt.Sprintf("%d de %d redes excluídas.", deleted, len(ids)))
+		})
+	}(currentSession)
+}
+
+// Layout desenha a página.
+func (p *NetworksPage) Layout(gtx layout.Context) layout.Dimensions {
+	th := p.router.GetAppWindow().Theme()
+	currentSession, _ := p.sessionManager.GetCurrentSession()
+
+	if p.searchBtn.Clicked(gtx) {
+		p.loadNetworks(currentSession, "")
+	}
+	if p.statusFilter.Update(gtx) {
+		p.applyFilter()
+	}
+	for _, row := range p.rows {
+		if row.click.Clicked(gtx) {
+			p.selectNetwork(row.network, currentSession)
+		}
+		if row.selected.Update(gtx) {
+			p.confirmDelete = false
+		}
+	}
+	if p.deleteBtn.Clicked(gtx) && !p.isLoading && len(p.selectedIDs()) > 0 && p.hasPermission(currentSession, auth.PermNetworkDelete) {
+		p.confirmDelete = true
+	}
+	if p.confirmDeleteBtn.Clicked(gtx) {
+		p.deleteSelected(currentSession)
+	}
+	if p.cancelDeleteBtn.Clicked(gtx) {
+		p.confirmDelete = false
+	}
+	if p.saveBtn.Clicked(gtx) {
+		p.save(currentSession)
+	}
+	if p.clearBtn.Clicked(gtx) {
+		p.clearForm()
+	}
+	if p.toggleBtn.Clicked(gtx) {
+		p.toggleStatus(currentSession)
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D { return p.layoutFilters(gtx, th) }),
+		layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+		layout.Rigid(func(gtx C) D {
+			if p.statusMessage == "" {
+				return D{}
+			}
+			lbl := material.Body2(th, p.statusMessage)
+			lbl.Color = p.messageColor
+			return layout.Inset{Bottom: theme.DefaultVSpacer}.Layout(gtx, lbl.Layout)
+		}),
+		layout.Flexed(1, func(gtx C) D {
+			return layout.Flex{}.Layout(gtx,
+				layout.Flexed(0.6, func(gtx C) D { return p.layoutTable(gtx, th, currentSession) }),
+				layout.Rigid(layout.Spacer{Width: unit.Dp(12)}.Layout),
+				layout.Flexed(0.4, func(gtx C) D { return p.layoutDetails(gtx, th, currentSession) }),
+			)
+		}),
+	)
+}
+
+// layoutFilters desenha a busca por nome ou comprador e o filtro de status.
+func (p *NetworksPage) layoutFilters(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	searchBtn := material.Button(th, &p.searchBtn, "Buscar")
+	if p.isLoading || p.accessDenied {
+		searchBtn.Background = theme.Colors.Grey300
+		searchBtn.Color = theme.Colors.TextMuted
+	}
+
+	return material.Card(th, theme.Colors.Surface, theme.ElevationSmall, layout.UniformInset(unit.Dp(12)),
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(material.Body2(th, "Nome ou comprador:").Layout),
+				layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+				layout.Flexed(1, material.Editor(th, &p.searchInput, "Parte do nome da rede ou do comprador").Layout),
+				layout.Rigid(layout.Spacer{Width: unit.Dp(16)}.Layout),
+				layout.Rigid(material.Body2(th, "Status:").Layout),
+				layout.Rigid(material.RadioButton(th, &p.statusFilter, networkFilterAll, "Todas").Layout),
+				layout.Rigid(material.RadioButton(th, &p.statusFilter, networkFilterActive, "Ativas").Layout),
+				layout.Rigid(material.RadioButton(th, &p.statusFilter, networkFilterInactive, "Inativas").Layout),
+				layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+				layout.Rigid(func(gtx C) D {
+					if !p.isLoading {
+						return D{}
+					}
+					return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, p.spinner.Layout)
+				}),
+				layout.Rigid(searchBtn.Layout),
+			)
+		}).Layout(gtx)
+}
+
+// layoutTable desenha a tabela de redes, com a exclusão em massa das selecionadas.
+func (p *NetworksPage) layoutTable(gtx layout.Context, th *material.Theme, currentSession *auth.SessionData) layout.Dimensions {
+	header := func(text string) layout.Widget {
+		return func(gtx C) D {
+			lbl := material.Body2(th, text)
+			lbl.Font.Weight = font.Bold
+			return lbl.Layout(gtx)
+		}
+	}
+	selectedCount := len(p.selectedIDs())
+	canDelete := p.hasPermission(currentSession, auth.PermNetworkDelete)
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D {
+			return layout.Background{Color: theme.Colors.Grey200}.Layout(gtx, func(gtx C) D {
+				return layout.UniformInset(unit.Dp(8)).Layout(gtx, func(gtx C) D {
+					return layout.Flex{}.Layout(gtx,
+						layout.Flexed(networkWeights[0], header("")),
+						layout.Flexed(networkWeights[1], header("Rede")),
+						layout.Flexed(networkWeights[2], header("Comprador")),
+						layout.Flexed(networkWeights[3], header("Status")),
+						layout.Flexed(networkWeights[4], header("Atualizada em")),
+					)
+				})
+			})
+		}),
+		layout.Flexed(1, func(gtx C) D {
+			if len(p.rows) == 0 {
+				lbl := material.Body2(th, "Nenhuma rede para exibir.")
+				lbl.Color = theme.Colors.TextMuted
+				return layout.UniformInset(unit.Dp(8)).Layout(gtx, lbl.Layout)
+			}
+			return material.List(th, &p.list).Layout(gtx, len(p.rows), func(gtx C, index int) D {
+				if index < 0 || index >= len(p.rows) {
+					return D{}
+				}
+				return p.layoutRow(gtx, th, index, canDelete)
+			})
+		}),
+		layout.Rigid(func(gtx C) D { // Exclusão das redes selecionadas
+			if !canDelete {
+				return D{}
+			}
+			deleteBtn := material.Button(th, &p.deleteBtn, fmt.Sprintf("Excluir selecionadas (%d)", selectedCount))
+			deleteBtn.Background = theme.Colors.Danger
+			if selectedCount == 0 || p.isLoading || p.confirmDelete {
+				deleteBtn.Background = theme.Colors.Grey300
+				deleteBtn.Color = theme.Colors.TextMuted
+			}
+			return layout.Inset{Top: theme.DefaultVSpacer}.Layout(gtx, func(gtx C) D {
+				if !p.confirmDelete {
+					return deleteBtn.Layout(gtx)
+				}
+				confirmBtn := material.Button(th, &p.confirmDeleteBtn, "Confirmar Exclusão")
+				confirmBtn.Background = theme.Colors.Danger
+				cancelBtn := material.Button(th, &p.cancelDeleteBtn, "Cancelar")
+				cancelBtn.Background = theme.Colors.Grey300
+				cancelBtn.Color = theme.Colors.Text
+				return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+					layout.Flexed(1, func(gtx C) D {
+						lbl := material.Body2(th, fmt.Sprintf("%d redes serão excluídas definitivamente. Confirma?", selectedCount))
+						lbl.Color = theme.Colors.Warning
+						return lbl.Layout(gtx)
+					}),
+					layout.Rigid(cancelBtn.Layout),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+					layout.Rigid(confirmBtn.Layout),
+				)
+			})
+		}),
+	)
+}
+
+// layoutRow desenha uma linha da tabela de redes.
+func (p *NetworksPage) layoutRow(gtx layout.Context, th *material.Theme, index int, canDelete bool) layout.Dimensions {
+	row := p.rows[index]
+	network := row.network
+	isSelected := p.selected != nil && p.selected.ID == network.ID
+
+	bgColor := theme.Colors.Surface
+	if index%2 != 0 {
+		bgColor = theme.Colors.BackgroundAlt
+	}
+	textColor := theme.Colors.Text
+	if !network.Status {
+		bgColor = theme.Colors.Grey100
+		textColor = theme.Colors.TextMuted
+	}
+	if isSelected {
+		bgColor = theme.Colors.PrimaryLight
+		textColor = theme.Colors.PrimaryText
+	}
+	cell := func(text string) layout.Widget {
+		return func(gtx C) D {
+			lbl := material.Body2(th, text)
+			lbl.Color = textColor
+			lbl.MaxLines = 1
+			return lbl.Layout(gtx)
+		}
+	}
+
+	return layout.Background{Color: bgColor}.Layout(gtx, func(gtx C) D {
+		return layout.Inset{Left: unit.Dp(8), Right: unit.Dp(8)}.Layout(gtx, func(gtx C) D {
+			return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+				layout.Flexed(networkWeights[0], func(gtx C) D {
+					if !canDelete {
+						return D{}
+					}
+					return material.CheckBox(th, &row.selected, "").Layout(gtx)
+				}),
+				layout.Flexed(1-networkWeights[0], func(gtx C) D {
+					return material.Clickable(gtx, &row.click, func(gtx C) D {
+						return layout.Inset{Top: unit.Dp(6), Bottom: unit.Dp(6)}.Layout(gtx, func(gtx C) D {
+							// Pesos relativos à área clicável (sem a coluna de seleção).
+							rest := 1 - networkWeights[0]
+							return layout.Flex{}.Layout(gtx,
+								layout.Flexed(networkWeights[1]/rest, cell(network.Name)),
+								layout.Flexed(networkWeights[2]/rest, cell(network.Buyer)),
+								layout.Flexed(networkWeights[3]/rest, cell(boolToString(network.Status, "Ativa", "Inativa"))),
+								layout.Flexed(networkWeights[4]/rest, cell(network.UpdatedAt.Local().Format("02/01/2006 15:04"))),
+							)
+						})
+					})
+				}),
+			)
+		})
+	})
+}
+
+// layoutDetails desenha o formulário de cadastro/edição e os CNPJs da rede selecionada.
+func (p *NetworksPage) layoutDetails(gtx layout.Context, th *material.Theme, currentSession *auth.SessionData) layout.Dimensions {
+	editing := p.selected != nil
+	title, saveText := "Nova Rede", "Cadastrar"
+	canSave := p.hasPermission(currentSession, auth.PermNetworkCreate)
+	if editing {
+		title, saveText = fmt.Sprintf("Editar Rede #%d", p.selected.ID), "Salvar Alterações"
+		canSave = p.hasPermission(currentSession, auth.PermNetworkUpdate)
+	}
+	canToggle := editing && p.hasPermission(currentSession, auth.PermNetworkStatus)
+
+	saveBtn := material.Button(th, &p.saveBtn, saveText)
+	if !canSave || p.isLoading || p.accessDenied {
+		saveBtn.Background = theme.Colors.Grey300
+		saveBtn.Color = theme.Colors.TextMuted
+	}
+	clearBtn := material.Button(th, &p.clearBtn, "Nova Rede")
+	clearBtn.Background = theme.Colors.Grey300
+	clearBtn.Color = theme.Colors.Text
+
+	field := func(label string, editor *widget.Editor, hint, feedback string) layout.FlexChild {
+		return layout.Rigid(func(gtx C) D {
+			return layout.Inset{Bottom: theme.DefaultVSpacer}.Layout(gtx, func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(material.Body2(th, label).Layout),
+					layout.Rigid(material.Editor(th, editor, hint).Layout),
+					layout.Rigid(func(gtx C) D {
+						if feedback == "" {
+							return D{}
+						}
+						lbl := material.Caption(th, feedback)
+						lbl.Color = theme.Colors.Danger
+						return lbl.Layout(gtx)
+					}),
+				)
+			})
+		})
+	}
+
+	return material.Card(th, theme.Colors.Surface, theme.ElevationSmall, layout.UniformInset(unit.Dp(12)),
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(material.Subtitle1(th, title).Layout),
+				layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+				field("Nome da rede:*", &p.nameInput, "3 a 50 caracteres: letras, números, espaço, '_' ou '-'", p.nameFeedback),
+				field("Comprador responsável:*", &p.buyerInput, "Nome do comprador", p.buyerFeedback),
+				layout.Rigid(func(gtx C) D {
+					if !editing {
+						return D{}
+					}
+					lbl := material.Caption(th, fmt.Sprintf("Status: %s · cadastrada em %s",
+						boolToString(p.selected.Status, "Ativa", "Inativa"), p.selected.CreatedAt.Local().Format("02/01/2006")))
+					lbl.Color = theme.Colors.TextMuted
+					return layout.Inset{Bottom: theme.DefaultVSpacer}.Layout(gtx, lbl.Layout)
+				}),
+				layout.Rigid(func(gtx C) D {
+					children := []layout.FlexChild{
+						layout.Rigid(clearBtn.Layout),
+						layout.Flexed(1, func(gtx C) D { return D{} }),
+					}
+					if canToggle {
+						toggleBtn := material.Button(th, &p.toggleBtn, boolToString(p.selected.Status, "Desativar", "Ativar"))
+						toggleBtn.Background = theme.Colors.Warning
+						if p.isLoading {
+							toggleBtn.Background = theme.Colors.Grey300
+							toggleBtn.Color = theme.Colors.TextMuted
+						}
+						children = append(children, layout.Rigid(toggleBtn.Layout), layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout))
+					}
+					children = append(children, layout.Rigid(saveBtn.Layout))
+					return layout.Flex{Alignment: layout.Middle}.Layout(gtx, children...)
+				}),
+				layout.Rigid(func(gtx C) D {
+					if !editing {
+						return D{}
+					}
+					return layout.Inset{Top: theme.LargeVSpacer}.Layout(gtx, material.Subtitle1(th, "CNPJs da rede").Layout)
+				}),
+				layout.Rigid(func(gtx C) D {
+					if !editing || p.cnpjMessage == "" {
+						return D{}
+					}
+					lbl := material.Caption(th, p.cnpjMessage)
+					lbl.Color = theme.Colors.TextMuted
+					return layout.Inset{Top: unit.Dp(4), Bottom: unit.Dp(4)}.Layout(gtx, lbl.Layout)
+				}),
+				layout.Flexed(1, func(gtx C) D {
+					if !editing || len(p.cnpjs) == 0 {
+						return D{}
+					}
+					return material.List(th, &p.cnpjList).Layout(gtx, len(p.cnpjs), func(gtx C, index int) D {
+						if index < 0 || index >= len(p.cnpjs) {
+							return D{}
+						}
+						cnpj := p.cnpjs[index]
+						lbl := material.Body2(th, fmt.Sprintf("%s · %s · desde %s",
+							cnpj.FormatCNPJ(), boolToString(cnpj.Active, "Ativo", "Inativo"), cnpj.RegistrationDate.Local().Format("02/01/2006")))
+						if !cnpj.Active {
+							lbl.Color = theme.Colors.TextMuted
+						}
+						return layout.Inset{Top: unit.Dp(2), Bottom: unit.Dp(2)}.Layout(gtx, lbl.Layout)
+					})
+				}),
+			)
+		}).Layout(gtx)
+}
@@ -0,0 +1,796 @@
+package pages
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+	"time"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/shopspring/decimal"
+
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/auth"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/config"
+	appLogger "github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/core/logger"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/data/models"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/services"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/theme"
+	"github.com/Dukorsa/APP_RIOGRANDENSE_GO/internal/ui/components"
+)
+
+// titulosPageSize é o número de títulos buscados por página. A lista é virtualizada,
+// então apenas as linhas visíveis são desenhadas.
+const titulosPageSize = 200
+
+// titulosDrawerWidth é a largura da gaveta de detalhes do título selecionado.
+const titulosDrawerWidth = 380
+
+// Abas da página de títulos.
+const (
+	titulosTabDireitos   = "DIREITOS"
+	titulosTabObrigacoes = "OBRIGACOES"
+)
+
+// tituloColumn define uma coluna da tabela de títulos.
+type tituloColumn struct {
+	Title  string
+	Weight float32
+	SortBy models.TituloSortField // Vazio se a coluna não for ordenável.
+}
+
+// tituloTableColumns são as colunas da tabela, comuns às duas abas.
+var tituloTableColumns = []tituloColumn{
+	{Title: "Vencimento", Weight: 0.09, SortBy: models.TituloSortDueDate},
+	{Title: "CNPJ/CPF", Weight: 0.14, SortBy: models.TituloSortCNPJCPF},
+	{Title: "Pessoa", Weight: 0.20},
+	{Title: "Empresa", Weight: 0.07, SortBy: models.TituloSortNumeroEmpresa},
+	{Title: "Título", Weight: 0.12, SortBy: models.TituloSortTitulo},
+	{Title: "Espécie", Weight: 0.07},
+	{Title: "Valor Nominal", Weight: 0.11, SortBy: models.TituloSortNominalValue},
+	{Title: "Valor Pago", Weight: 0.10},
+	{Title: "Quitação", Weight: 0.10, SortBy: models.TituloSortSettledDate},
+}
+
+// tituloDetail é um campo exibido na gaveta de detalhes.
+type tituloDetail struct {
+	Label string
+	Value string
+}
+
+// tituloRow é uma linha da tabela, comum a direitos e obrigações.
+type tituloRow struct {
+	Cells   []string       // Na ordem de tituloTableColumns.
+	Details []tituloDetail // Os 20 campos do arquivo importado, mais o vencimento efetivo, o vínculo com a rede e os encargos.
+}
+
+// tituloFields reúne os campos de um título de direito ou de obrigação para montar a linha da tabela.
+type tituloFields struct {
+	Pessoa                *string
+	CNPJCPF               string
+	NumeroEmpresa         int
+	Titulo                string
+	CodigoEspecie         *string
+	DataVencimento        *string
+	DataVencimentoEfetiva *string // Próximo dia útil, se o vencimento cair em fim de semana ou feriado.
+	DataQuitacao          *string
+	ValorNominal          *decimal.Decimal
+	ValorPago             *decimal.Decimal
+	Operacao              *string
+	DataOperacao          *string
+	DataContabiliza       *string
+	DataAlteracaoCSV      *string
+	Observacao            *string
+	ValorOperacao         *decimal.Decimal
+	UsuarioAlteracao      *string
+	EspecieAbatcomp       *string
+	ObsTitulo             *string
+	ContasQuitacao        *string
+	DataProgramada        *string
+	NetworkID             *uint64
+	Encargos              *models.ChargeCalculation // Só nos direitos; nil nos títulos removidos.
+}
+
+// newTituloRow monta a linha da tabela e os detalhes (rotulados pelos cabeçalhos do arquivo).
+func newTituloRow(f tituloFields) tituloRow {
+	text := func(val *string) string {
+		if val == nil || strings.TrimSpace(*val) == "" {
+			return "-"
+		}
+		return *val
+	}
+	money := func(val *decimal.Decimal) string {
+		if val == nil {
+			return "-"
+		}
+		return formatMoney(*val)
+	}
+	network := "Não vinculado"
+	if f.NetworkID != nil {
+		network = fmt.Sprint(*f.NetworkID)
+	}
+	empresa := fmt.Sprint(f.NumeroEmpresa)
+
+	row := tituloRow{
+		Cells: []string{
+			text(f.DataVencimento), f.CNPJCPF, text(f.Pessoa), empresa, f.Titulo,
+			text(f.CodigoEspecie), money(f.ValorNominal), money(f.ValorPago), text(f.DataQuitacao),
+		},
+		Details: []tituloDetail{
+			{"PESSOA", text(f.Pessoa)},
+			{"CNPJ/CPF", f.CNPJCPF},
+			{"NROEMPRESA", empresa},
+			{"TÍTULO", f.Titulo},
+			{"CODESPÉCIE", text(f.CodigoEspecie)},
+			{"DTAVENCIMENTO", text(f.DataVencimento)},
+			{"Vencimento efetivo (dia útil)", text(f.DataVencimentoEfetiva)},
+			{"DTAQUITAÇÃO", text(f.DataQuitacao)},
+			{"VLRNOMINAL", money(f.ValorNominal)},
+			{"VLRPAGO", money(f.ValorPago)},
+			{"OPERAÇÃO", text(f.Operacao)},
+			{"DTAOPERAÇÃO", text(f.DataOperacao)},
+			{"DTACONTABILIZA", text(f.DataContabiliza)},
+			{"DTAALTERAÇÃO", text(f.DataAlteracaoCSV)},
+			{"OBSERVAÇÃO", text(f.Observacao)},
+			{"VLROPERAÇÃO", money(f.ValorOperacao)},
+			{"USUALTERAÇÃO", text(f.UsuarioAlteracao)},
+			{"ESPECIEABATCOMP", text(f.EspecieAbatcomp)},
+			{"OBSTÍTULO", text(f.ObsTitulo)},
+			{"CONTASQUITAÇÃO", text(f.ContasQuitacao)},
+			{"DTAPROGRAMADA", text(f.DataProgramada)},
+			{"Rede (ID)", network},
+		},
+	}
+	if f.Encargos != nil && f.Encargos.DaysOverdue > 0 {
+		row.Details = append(row.Details, chargeDetails(f.Encargos)...)
+	}
+	return row
+}
+
+// chargeDetails descreve a multa e os juros de mora de um título vencido.
+func chargeDetails(calc *models.ChargeCalculation) []tituloDetail {
+	rule := "Nenhuma regra de encargos aplicável"
+	if calc.Rule != nil {
+		rule = fmt.Sprintf("%s: %s", calc.Rule.Name, calc.Rule.Describe())
+	}
+	details := []tituloDetail{
+		{"Dias de atraso", fmt.Sprint(calc.DaysOverdue)},
+		{"Regra de encargos", rule},
+	}
+	if calc.InGracePeriod {
+		return append(details, tituloDetail{"Encargos", "Em carência, sem multa e juros"})
+	}
+	return append(details,
+		tituloDetail{"Multa", formatMoney(calc.Fine)},
+		tituloDetail{"Juros de mora", formatMoney(calc.Interest)},
+		tituloDetail{fmt.Sprintf("Valor atualizado em %s", calc.AsOf.Format("02/01/2006")), formatMoney(calc.UpdatedAmount)},
+	)
+}
+
+// tituloDireitoRows converte os títulos de direitos para linhas da tabela.
+func tituloDireitoRows(items []*models.TituloDireitoPublic) []tituloRow {
+	rows := make([]tituloRow, 0, len(items))
+	for _, t := range items {
+		rows = append(rows, newTituloRow(tituloFields{
+			Pessoa: t.Pessoa, CNPJCPF: t.CNPJCPF, NumeroEmpresa: t.NumeroEmpresa, Titulo: t.Titulo,
+			CodigoEspecie: t.CodigoEspecie, DataVencimento: t.DataVencimento, DataVencimentoEfetiva: t.DataVencimentoEfetiva,
+			DataQuitacao: t.DataQuitacao, ValorNominal: t.ValorNominal, ValorPago: t.ValorPago, Operacao: t.Operacao,
+			DataOperacao: t.DataOperacao, DataContabiliza: t.DataContabiliza, DataAlteracaoCSV: t.DataAlteracaoCSV,
+			Observacao: t.Observacao, ValorOperacao: t.ValorOperacao, UsuarioAlteracao: t.UsuarioAlteracao,
+			EspecieAbatcomp: t.EspecieAbatcomp, ObsTitulo: t.ObsTitulo, ContasQuitacao: t.ContasQuitacao,
+			DataProgramada: t.DataProgramada, NetworkID: t.NetworkID, Encargos: t.Encargos,
+		}))
+	}
+	return rows
+}
+
+// tituloObrigacaoRows converte os títulos de obrigações para linhas da tabela.
+func tituloObrigacaoRows(items []*models.TituloObrigacaoPublic) []tituloRow {
+	rows := make([]tituloRow, 0, len(items))
+	for _, t := range items {
+		rows = append(rows, newTituloRow(tituloFields{
+			Pessoa: t.Pessoa, CNPJCPF: t.CNPJCPF, NumeroEmpresa: t.NumeroEmpresa, Titulo: t.IdentificadorObrigacao,
+			CodigoEspecie: t.CodigoEspecie, DataVencimento: t.DataVencimento, DataVencimentoEfetiva: t.DataVencimentoEfetiva,
+			DataQuitacao: t.DataQuitacao, ValorNominal: t.ValorNominalObrigacao, ValorPago: t.ValorPago, Operacao: t.Operacao,
+			DataOperacao: t.DataOperacao, DataContabiliza: t.DataContabiliza, DataAlteracaoCSV: t.DataAlteracaoCSV,
+			Observacao: t.Observacao, ValorOperacao: t.ValorOperacao, UsuarioAlteracao: t.UsuarioAlteracao,
+			EspecieAbatcomp: t.EspecieAbatcomp, ObsTitulo: t.ObsTitulo, ContasQuitacao: t.ContasQuitacao,
+			DataProgramada: t.DataProgramada, NetworkID: t.NetworkID,
+		}))
+	}
+	return rows
+}
+
+// formatMoney formata um valor no padrão brasileiro (ex: "R$ 1.234,56").
+func formatMoney(value decimal.Decimal) string {
+	return models.FormatMoneyBRL(value)
+}
+
+// titulosTabState guarda o estado de uma aba (direitos ou obrigações).
+type titulosTabState struct {
+	FileType string // titulosTabDireitos ou titulosTabObrigacoes.
+	Title    string
+
+	rows     []tituloRow
+	totals   models.TituloTotals
+	offset   int
+	sortBy   models.TituloSortField
+	sortDesc bool
+	selected int // Índice da linha aberta na gaveta de detalhes (-1 se nenhuma).
+
+	isLoading    bool
+	loaded       bool // False força nova consulta ao exibir a aba (ex: filtros alterados).
+	message      string
+	messageColor color.NRGBA
+
+	tabBtn         widget.Clickable
+	list           widget.List
+	rowClicks      []widget.Clickable
+	headerClicks   []widget.Clickable
+	prevBtn        widget.Clickable
+	nextBtn        widget.Clickable
+	detailList     widget.List
+	closeDetailBtn widget.Clickable
+}
+
+// TitulosPage exibe os títulos importados (direitos e obrigações) com filtros, ordenação e totais.
+type TitulosPage struct {
+	router         *ui.Router
+	cfg            *core.Config
+	tituloService  services.TituloService
+	networkService services.NetworkService
+	permManager    *auth.PermissionManager
+	sessionManager *auth.SessionManager
+
+	tabs      []*titulosTabState
+	activeTab *titulosTabState
+
+	// Painel de filtros (compartilhado pelas abas).
+	cnpjInput      widget.Editor
+	networkInput   widget.Editor // ID numérico ou nome da rede.
+	dueFromInput   widget.Editor
+	dueToInput     widget.Editor
+	statusFilter   widget.Enum // "" (todos), models.TituloStatusOpen ou models.TituloStatusSettled.
+	applyFilterBtn widget.Clickable
+	clearFilterBtn widget.Clickable
+	filterFeedback string
+
+	// Filtros aplicados na última consulta. A rede informada por nome é resolvida na consulta.
+	appliedFilter      models.TituloFilter
+	appliedNetworkName *string
+
+	accessDenied bool
+	spinner      *components.LoadingSpinner
+}
+
+// NewTitulosPage cria uma nova instância da página de consulta de títulos.
+func NewTitulosPage(
+	router *ui.Router,
+	cfg *core.Config,
+	tituloSvc services.TituloService,
+	netSvc services.NetworkService,
+	permMan *auth.PermissionManager,
+	sessMan *auth.SessionManager,
+) *TitulosPage {
+	p := &TitulosPage{
+		router:         router,
+		cfg:            cfg,
+		tituloService:  tituloSvc,
+		networkService: netSvc,
+		permManager:    permMan,
+		sessionManager: sessMan,
+		spinner:        components.NewLoadingSpinner(theme.Colors.Primary),
+	}
+	p.tabs = []*titulosTabState{
+		{FileType: titulosTabDireitos, Title: "Direitos"},
+		{FileType: titulosTabObrigacoes, Title: "Obrigações"},
+	}
+	for _, tab := range p.tabs {
+		tab.sortBy = models.TituloSortDueDate
+		tab.selected = -1
+		tab.list.Axis = layout.Vertical
+		tab.detailList.Axis = layout.Vertical
+		tab.headerClicks = make([]widget.Clickable, len(tituloTableColumns))
+	}
+	p.activeTab = p.tabs[0]
+
+	p.cnpjInput.SingleLine = true
+	p.networkInput.SingleLine = true
+	p.dueFromInput.SingleLine = true
+	p.dueFromInput.Filter = "0123456789/"
+	p.dueToInput.SingleLine = true
+	p.dueToInput.Filter = "0123456789/"
+	return p
+}
+
+// OnNavigatedTo é chamado quando a página se torna ativa.
+func (p *TitulosPage) OnNavigatedTo(params interface{}) {
+	appLogger.Info("Navegou para TitulosPage")
+	currentSession, errSess := p.sessionManager.GetCurrentSession()
+	if errSess != nil || currentSession == nil {
+		p.router.GetAppWindow().HandleLogout()
+		return
+	}
+	if err := p.permManager.CheckPermission(currentSession, auth.PermTituloView, nil); err != nil {
+		p.accessDenied = true
+		p.activeTab.message = fmt.Sprintf("Acesso negado à consulta de títulos: %v", err)
+		p.activeTab.messageColor = theme.Colors.Danger
+		p.router.GetAppWindow().Invalidate()
+		return
+	}
+	p.accessDenied = false
+	// Outros módulos (ex: o painel de redes) podem abrir a página já filtrada.
+	if preset, ok := params.(models.TituloFilter); ok {
+		p.applyPresetFilter(preset, currentSession)
+		return
+	}
+	// Recarrega para exibir os dados de importações feitas enquanto a página estava fechada.
+	for _, tab := range p.tabs {
+		tab.loaded = false
+	}
+	p.loadTab(p.activeTab, currentSession)
+}
+
+// OnNavigatedFrom é chamado quando o router navega para fora desta página.
+func (p *TitulosPage) OnNavigatedFrom() {
+	appLogger.Info("Navegando para fora da TitulosPage")
+	p.spinner.Stop(p.router.GetAppWindow().Context())
+}
+
+// buildFilter lê o painel de filtros. Retorna o filtro e o nome da rede (se a rede foi informada
+// por nome), ou uma mensagem de erro de preenchimento.
+func (p *TitulosPage) buildFilter() (models.TituloFilter, *string, string) {
+	var filter models.TituloFilter
+	var networkName *string
+
+	if cnpj := strings.TrimSpace(p.cnpjInput.Text()); cnpj != "" {
+		filter.CNPJCPF = &cnpj
+	}
+	if network := strings.TrimSpace(p.networkInput.Text()); network != "" {
+		if id, err := strconv.ParseUint(network, 10, 64); err == nil {
+			filter.NetworkID = &id
+		} else {
+			networkName = &network
+		}
+	}
+	parseDate := func(editor *widget.Editor, label string) (*time.Time, string) {
+		text := strings.TrimSpace(editor.Text())
+		if text == "" {
+			return nil, ""
+		}
+		t, err := time.Parse("02/01/2006", text)
+		if err != nil {
+			return nil, fmt.Sprintf("%s inválido: use DD/MM/AAAA.", label)
+		}
+		return &t, ""
+	}
+	var feedback string
+	if filter.DueFrom, feedback = parseDate(&p.dueFromInput, "Vencimento inicial"); feedback != "" {
+		return filter, nil, feedback
+	}
+	if filter.DueTo, feedback = parseDate(&p.dueToInput, "Vencimento final"); feedback != "" {
+		return filter, nil, feedback
+	}
+	if filter.DueFrom != nil && filter.DueTo != nil && filter.DueTo.Before(*filter.DueFrom) {
+		return filter, nil, "Vencimento final anterior ao inicial."
+	}
+	filter.Status = models.TituloStatus(p.statusFilter.Value)
+	return filter, networkName, ""
+}
+
+// handleApplyFilter aplica o painel de filtros às duas abas e recarrega a aba ativa.
+func (p *TitulosPage) handleApplyFilter(currentSession *auth.SessionData) {
+	filter, networkName, feedback := p.buildFilter()
+	p.filterFeedback = feedback
+	if feedback != "" {
+		p.router.GetAppWindow().Invalidate()
+		return
+	}
+	p.appliedFilter = filter
+	p.appliedNetworkName = networkName
+	for _, tab := range p.tabs {
+		tab.offset = 0
+		tab.selected = -1
+		tab.loaded = false
+	}
+	p.loadTab(p.activeTab, currentSession)
+}
+
+// applyPresetFilter preenche o painel de filtros com o CNPJ/CPF e a rede de `preset`
+// (os demais filtros são limpos) e recarrega a aba ativa.
+func (p *TitulosPage) applyPresetFilter(preset models.TituloFilter, currentSession *auth.SessionData) {
+	p.cnpjInput.SetText("")
+	if preset.CNPJCPF != nil {
+		p.cnpjInput.SetText(*preset.CNPJCPF)
+	}
+	p.networkInput.SetText("")
+	if preset.NetworkID != nil {
+		p.networkInput.SetText(strconv.FormatUint(*preset.NetworkID, 10))
+	}
+	p.dueFromInput.SetText("")
+	p.dueToInput.SetText("")
+	p.statusFilter.Value = ""
+	p.handleApplyFilter(currentSession)
+}
+
+// handleClearFilter limpa o painel de filtros e recarrega a aba ativa.
+func (p *TitulosPage) handleClearFilter(currentSession *auth.SessionData) {
+	p.cnpjInput.SetText("")
+	p.networkInput.SetText("")
+	p.dueFromInput.SetText("")
+	p.dueToInput.SetText("")
+	p.statusFilter.Value = ""
+	p.handleApplyFilter(currentSession)
+}
+
+// loadTab consulta a página atual da aba com os filtros aplicados e a ordenação da aba.
+func (p *TitulosPage) loadTab(tab *titulosTabState, currentSession *auth.SessionData) {
+	if tab.isLoading || p.accessDenied {
+		return
+	}
+	tab.isLoading = true
+	tab.message = "Carregando títulos..."
+	tab.messageColor = theme.Colors.TextMuted
+	p.spinner.Start(p.router.GetAppWindow().Context())
+	p.router.GetAppWindow().Invalidate()
+
+	filter := p.appliedFilter
+	filter.SortBy = tab.sortBy
+	filter.SortDesc = tab.sortDesc
+	filter.Limit = titulosPageSize
+	filter.Offset = tab.offset
+	networkName := p.appliedNetworkName
+
+	go func(f models.TituloFilter, sess *auth.SessionData) {
+		var rows []tituloRow
+		var totals models.TituloTotals
+		var loadErr error
+
+		if networkName != nil {
+			network, err := p.networkService.GetNetworkByName(*networkName, sess)
+			if err != nil {
+				loadErr = fmt.Errorf("rede '%s': %w", *networkName, err)
+			} else {
+				f.NetworkID = &network.ID
+			}
+		}
+		if loadErr == nil {
+			if tab.FileType == titulosTabDireitos {
+				page, err := p.tituloService.GetTitulosDireitos(f, sess)
+				if loadErr = err; err == nil {
+					rows, totals = tituloDireitoRows(page.Items), page.Totals
+				}
+			} else {
+				page, err := p.tituloService.GetTitulosObrigacoes(f, sess)
+				if loadErr = err; err == nil {
+					rows, totals = tituloObrigacaoRows(page.Items), page.Totals
+				}
+			}
+		}
+
+		p.router.GetAppWindow().Execute(func() {
+			tab.isLoading = false
+			p.spinner.Stop(p.router.GetAppWindow().Context())
+			if loadErr != nil {
+				tab.message = fmt.Sprintf("Erro ao carregar títulos: %v", loadErr)
+				tab.messageColor = theme.Colors.Danger
+				appLogger.Errorf("Erro ao carregar títulos de %s: %v", tab.FileType, loadErr)
+			} else {
+				tab.rows = rows
+				tab.totals = totals
+				tab.loaded = true
+				tab.selected = -1
+				tab.rowClicks = make([]widget.Clickable, len(rows))
+				tab.message = ""
+				if totals.Count == 0 {
+					tab.message = "Nenhum título encontrado para os filtros informados."
+					tab.messageColor = theme.Colors.Info
+				}
+			}
+			p.router.GetAppWindow().Invalidate()
+		})
+	}(filter, currentSession)
+}
+
+// Layout desenha a página.
+func (p *TitulosPage) Layout(gtx layout.Context) layout.Dimensions {
+	th := p.router.GetAppWindow().Theme()
+	currentSession, _ := p.sessionManager.GetCurrentSession()
+	tab := p.activeTab
+
+	for _, t := range p.tabs {
+		if t.tabBtn.Clicked(gtx) && t != p.activeTab {
+			p.activeTab = t
+			tab = t
+			if !t.loaded {
+				p.loadTab(t, currentSession)
+			}
+		}
+	}
+	if p.applyFilterBtn.Clicked(gtx) {
+		p.handleApplyFilter(currentSession)
+	}
+	if p.clearFilterBtn.Clicked(gtx) {
+		p.handleClearFilter(currentSession)
+	}
+	for i, col := range tituloTableColumns {
+		if tab.headerClicks[i].Clicked(gtx) && col.SortBy != "" && !tab.isLoading {
+			if tab.sortBy == col.SortBy {
+				tab.sortDesc = !tab.sortDesc
+			} else {
+				tab.sortBy = col.SortBy
+				tab.sortDesc = false
+			}
+			tab.offset = 0
+			p.loadTab(tab, currentSession)
+		}
+	}
+	for i := range tab.rowClicks {
+		if tab.rowClicks[i].Clicked(gtx) {
+			tab.selected = i
+			tab.detailList.Position = layout.Position{}
+		}
+	}
+	if tab.closeDetailBtn.Clicked(gtx) {
+		tab.selected = -1
+	}
+	if tab.prevBtn.Clicked(gtx) && tab.offset > 0 && !tab.isLoading {
+		tab.offset -= titulosPageSize
+		if tab.offset < 0 {
+			tab.offset = 0
+		}
+		p.loadTab(tab, currentSession)
+	}
+	if tab.nextBtn.Clicked(gtx) && int64(tab.offset+titulosPageSize) < tab.totals.Count && !tab.isLoading {
+		tab.offset += titulosPageSize
+		p.loadTab(tab, currentSession)
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx C) D { return p.layoutTabs(gtx, th) }),
+		layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+		layout.Rigid(func(gtx C) D { return p.layoutFilterPanel(gtx, th) }),
+		layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+		layout.Flexed(1, func(gtx C) D {
+			if tab.selected < 0 || tab.selected >= len(tab.rows) {
+				return p.layoutTable(gtx, th, tab)
+			}
+			return layout.Flex{}.Layout(gtx,
+				layout.Flexed(1, func(gtx C) D { return p.layoutTable(gtx, th, tab) }),
+				layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+				layout.Rigid(func(gtx C) D { return p.layoutDetailDrawer(gtx, th, tab) }),
+			)
+		}),
+		layout.Rigid(func(gtx C) D { return p.layoutFooter(gtx, th, tab) }),
+	)
+}
+
+// layoutTabs desenha os botões das abas Direitos/Obrigações.
+func (p *TitulosPage) layoutTabs(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	children := make([]layout.FlexChild, 0, len(p.tabs)*2)
+	for _, t := range p.tabs {
+		btn := material.Button(th, &t.tabBtn, t.Title)
+		btn.CornerRadius = theme.CornerRadius
+		if t == p.activeTab {
+			btn.Background = theme.Colors.Primary
+			btn.Color = theme.Colors.PrimaryText
+		} else {
+			btn.Background = theme.Colors.Grey200
+			btn.Color = theme.Colors.Text
+		}
+		children = append(children, layout.Rigid(btn.Layout), layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout))
+	}
+	return layout.Flex{Alignment: layout.Middle}.Layout(gtx, children...)
+}
+
+// layoutFilterPanel desenha o painel de filtros.
+func (p *TitulosPage) layoutFilterPanel(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	field := func(label string, editor *widget.Editor, hint string) layout.FlexChild {
+		return layout.Flexed(1, func(gtx C) D {
+			return layout.Inset{Right: unit.Dp(12)}.Layout(gtx, func(gtx C) D {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(material.Caption(th, label).Layout),
+					layout.Rigid(material.Editor(th, editor, hint).Layout),
+				)
+			})
+		})
+	}
+
+	return material.Card(th, theme.Colors.Surface, theme.ElevationSmall, layout.UniformInset(unit.Dp(12)),
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx C) D {
+					return layout.Flex{Alignment: layout.End}.Layout(gtx,
+						field("CNPJ/CPF (ou raiz)", &p.cnpjInput, "Somente números ou formatado"),
+						field("Rede (ID ou nome)", &p.networkInput, "Ex: 12 ou rede sul"),
+						field("Vencimento de", &p.dueFromInput, "DD/MM/AAAA"),
+						field("Vencimento até", &p.dueToInput, "DD/MM/AAAA"),
+					)
+				}),
+				layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+				layout.Rigid(func(gtx C) D {
+					return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+						layout.Rigid(material.Body2(th, "Situação:").Layout),
+						layout.Rigid(material.RadioButton(th, &p.statusFilter, "", "Todos").Layout),
+						layout.Rigid(material.RadioButton(th, &p.statusFilter, string(models.TituloStatusOpen), "Em aberto").Layout),
+						layout.Rigid(material.RadioButton(th, &p.statusFilter, string(models.TituloStatusSettled), "Quitados").Layout),
+						layout.Flexed(1, func(gtx C) D {
+							if p.filterFeedback == "" {
+								return D{}
+							}
+							lbl := material.Body2(th, p.filterFeedback)
+							lbl.Color = theme.Colors.Danger
+							return layout.Inset{Left: unit.Dp(12)}.Layout(gtx, lbl.Layout)
+						}),
+						layout.Rigid(material.Button(th, &p.clearFilterBtn, "Limpar").Layout),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+						layout.Rigid(material.Button(th, &p.applyFilterBtn, "Filtrar").Layout),
+					)
+				}),
+			)
+		}).Layout(gtx)
+}
+
+// layoutTable desenha o cabeçalho ordenável e a lista virtualizada de títulos da aba.
+func (p *TitulosPage) layoutTable(gtx layout.Context, th *material.Theme, tab *titulosTabState) layout.Dimensions {
+	cell := func(text string, weight float32, textColor color.NRGBA, bold bool) layout.FlexChild {
+		return layout.Flexed(weight, func(gtx C) D {
+			lbl := material.Body2(th, text)
+			lbl.Color = textColor
+			lbl.MaxLines = 1
+			if bold {
+				lbl.Font.Weight = font.Bold
+			}
+			return lbl.Layout(gtx)
+		})
+	}
+
+	header := func(gtx C) D {
+		children := make([]layout.FlexChild, len(tituloTableColumns))
+		for i, col := range tituloTableColumns {
+			title := col.Title
+			if col.SortBy != "" && col.SortBy == tab.sortBy {
+				if tab.sortDesc {
+					title += " ▼"
+				} else {
+					title += " ▲"
+				}
+			}
+			children[i] = layout.Flexed(col.Weight, func(gtx C) D {
+				lbl := material.Body2(th, title)
+				lbl.Font.Weight = font.Bold
+				lbl.MaxLines = 1
+				if col.SortBy == "" {
+					return lbl.Layout(gtx)
+				}
+				return material.Clickable(gtx, &tab.headerClicks[i], lbl.Layout)
+			})
+		}
+		return layout.Background{Color: theme.Colors.Grey200}.Layout(gtx, func(gtx C) D {
+			return layout.Inset{Top: unit.Dp(6), Bottom: unit.Dp(6), Left: unit.Dp(8), Right: unit.Dp(8)}.Layout(gtx, func(gtx C) D {
+				return layout.Flex{}.Layout(gtx, children...)
+			})
+		})
+	}
+
+	rowLayout := func(gtx C, index int) D {
+		row := tab.rows[index]
+		bgColor := theme.Colors.Surface
+		if index%2 != 0 {
+			bgColor = theme.Colors.BackgroundAlt
+		}
+		textColor := theme.Colors.Text
+		if index == tab.selected {
+			bgColor = theme.Colors.PrimaryLight
+			textColor = theme.Colors.PrimaryText
+		}
+		return material.Clickable(gtx, &tab.rowClicks[index], func(gtx C) D {
+			return layout.Background{Color: bgColor}.Layout(gtx, func(gtx C) D {
+				return layout.Inset{Top: unit.Dp(4), Bottom: unit.Dp(4), Left: unit.Dp(8), Right: unit.Dp(8)}.Layout(gtx, func(gtx C) D {
+					children := make([]layout.FlexChild, len(tituloTableColumns))
+					for i, col := range tituloTableColumns {
+						children[i] = cell(row.Cells[i], col.Weight, textColor, false)
+					}
+					return layout.Flex{}.Layout(gtx, children...)
+				})
+			})
+		})
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(header),
+		layout.Flexed(1, func(gtx C) D {
+			if len(tab.rows) == 0 || tab.message != "" {
+				lbl := material.Body2(th, tab.message)
+				lbl.Color = tab.messageColor
+				return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, lbl.Layout)
+			}
+			return material.List(th, &tab.list).Layout(gtx, len(tab.rows), func(gtx C, index int) D {
+				if index < 0 || index >= len(tab.rows) || index >= len(tab.rowClicks) {
+					return D{}
+				}
+				return rowLayout(gtx, index)
+			})
+		}),
+	)
+}
+
+// layoutDetailDrawer desenha a gaveta lateral com todos os campos importados do título selecionado.
+func (p *TitulosPage) layoutDetailDrawer(gtx layout.Context, th *material.Theme, tab *titulosTabState) layout.Dimensions {
+	details := tab.rows[tab.selected].Details
+	width := gtx.Dp(unit.Dp(titulosDrawerWidth))
+	gtx.Constraints.Min.X = width
+	gtx.Constraints.Max.X = width
+
+	return material.Card(th, theme.Colors.Surface, theme.ElevationMedium, layout.UniformInset(unit.Dp(12)),
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx C) D {
+					title := material.Subtitle1(th, "Detalhes do Título")
+					title.Font.Weight = font.SemiBold
+					return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+						layout.Flexed(1, title.Layout),
+						layout.Rigid(material.Button(th, &tab.closeDetailBtn, "Fechar").Layout),
+					)
+				}),
+				layout.Rigid(layout.Spacer{Height: theme.DefaultVSpacer}.Layout),
+				layout.Flexed(1, func(gtx C) D {
+					return material.List(th, &tab.detailList).Layout(gtx, len(details), func(gtx C, index int) D {
+						detail := details[index]
+						return layout.Inset{Bottom: unit.Dp(6)}.Layout(gtx, func(gtx C) D {
+							return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+								layout.Rigid(func(gtx C) D {
+									lbl := material.Caption(th, detail.Label)
+									lbl.Color = theme.Colors.TextMuted
+									return lbl.Layout(gtx)
+								}),
+								layout.Rigid(material.Body2(th, detail.Value).Layout),
+							)
+						})
+					})
+				}),
+			)
+		}).Layout(gtx)
+}
+
+// layoutFooter desenha os totais do conjunto filtrado e a paginação.
+func (p *TitulosPage) layoutFooter(gtx layout.Context, th *material.Theme, tab *titulosTabState) layout.Dimensions {
+	prevBtn := material.Button(th, &tab.prevBtn, "Anterior")
+	if tab.offset == 0 || tab.isLoading {
+		prevBtn.Background = theme.Colors.Grey300
+		prevBtn.Color = theme.Colors.TextMuted
+	}
+	nextBtn := material.Button(th, &tab.nextBtn, "Próxima")
+	if int64(tab.offset+titulosPageSize) >= tab.totals.Count || tab.isLoading {
+		nextBtn.Background = theme.Colors.Grey300
+		nextBtn.Color = theme.Colors.TextMuted
+	}
+
+	from, to := 0, 0
+	if tab.totals.Count > 0 {
+		from = tab.offset + 1
+		to = tab.offset + len(tab.rows)
+	}
+	totalsText := fmt.Sprintf("%d títulos   Valor nominal: %s   Valor pago: %s",
+		tab.totals.Count, formatMoney(tab.totals.ValorNominal), formatMoney(tab.totals.ValorPago))
+
+	return layout.Inset{Top: theme.DefaultVSpacer}.Layout(gtx, func(gtx C) D {
+		return layout.Flex{Alignment: layout.Middle}.Layout(gtx,
+			layout.Rigid(func(gtx C) D {
+				lbl := material.Body1(th, totalsText)
+				lbl.Font.Weight = font.SemiBold
+				return lbl.Layout(gtx)
+			}),
+			layout.Flexed(1, func(gtx C) D { return D{} }),
+			layout.Rigid(material.Caption(th, fmt.Sprintf("%d–%d de %d", from, to, tab.totals.Count)).Layout),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			layout.Rigid(prevBtn.Layout),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			layout.Rigid(nextBtn.Layout),
+		)
+	})
+}
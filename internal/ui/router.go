@@ -39,6 +39,12 @@ const (
 	PageAdminPermissions // Módulo de Gerenciamento de Usuários e Permissões de Admin.
 	PageRoleManagement   // Módulo de Gerenciamento de Perfis (Roles).
 	PageImport           // Módulo de Importação de Dados.
+	PageTitulos          // Módulo de Consulta de Títulos (Direitos e Obrigações).
+	PageAging            // Módulo de Aging de Títulos (saldos em aberto por faixa de atraso).
+	PageNetting          // Módulo de Encontro de Contas (Direitos × Obrigações).
+	PageDashboard        // Painel de exposição por rede (módulo inicial após o login).
+	PageCashFlow         // Módulo de Projeção de Fluxo de Caixa (entradas e saídas previstas).
+	PageDueDigest        // Inscrição do usuário no resumo de vencimentos por e-mail.
 	// PageAuditLogs     // Exemplo: Módulo para visualização de Logs de Auditoria.
 )
 
@@ -75,15 +81,21 @@ type Router struct {
 	// Serviços centralizados para acesso pelas páginas através do router,
 	// ou as páginas podem obtê-los da AppWindow se o router não os expor.
 	// Expor aqui pode simplificar a passagem de dependências para as páginas.
-	userService    services.UserService
-	roleService    services.RoleService
-	networkService services.NetworkService
-	cnpjService    services.CNPJService
-	importService  services.ImportService
-	auditService   services.AuditLogService
-	authenticator  auth.AuthenticatorInterface
-	sessionManager *auth.SessionManager
-	permManager    *auth.PermissionManager
+	userService      services.UserService
+	roleService      services.RoleService
+	networkService   services.NetworkService
+	cnpjService      services.CNPJService
+	tituloService    services.TituloService
+	agingService     services.AgingReportService
+	nettingService   services.NettingService
+	dashboardService services.DashboardService
+	cashFlowService  services.CashFlowService
+	dueDigestService services.DueDigestService
+	importService    services.ImportService
+	auditService     services.AuditLogService
+	authenticator    auth.AuthenticatorInterface
+	sessionManager   *auth.SessionManager
+	permManager      *auth.PermissionManager
 }
 
 // NewRouter cria uma nova instância do Router.
@@ -96,6 +108,12 @@ func NewRouter(
 	roleSvc services.RoleService,
 	netSvc services.NetworkService,
 	cnpjSvc services.CNPJService,
+	tituloSvc services.TituloService,
+	agingSvc services.AgingReportService,
+	nettingSvc services.NettingService,
+	dashboardSvc services.DashboardService,
+	cashFlowSvc services.CashFlowService,
+	dueDigestSvc services.DueDigestService,
 	importSvc services.ImportService,
 	auditSvc services.AuditLogService,
 	authN auth.AuthenticatorInterface,
@@ -104,7 +122,7 @@ func NewRouter(
 ) *Router {
 	// Validação de dependências críticas.
 	if th == nil || cfg == nil || aw == nil || userSvc == nil || roleSvc == nil ||
-		netSvc == nil || cnpjSvc == nil || importSvc == nil || auditSvc == nil ||
+		netSvc == nil || cnpjSvc == nil || tituloSvc == nil || agingSvc == nil || nettingSvc == nil || dashboardSvc == nil || cashFlowSvc == nil || dueDigestSvc == nil || importSvc == nil || auditSvc == nil ||
 		authN == nil || sessMan == nil || permMan == nil {
 		appLogger.Fatalf("Dependências nulas fornecidas ao criar NewRouter. Verifique a inicialização.")
 	}
@@ -118,15 +136,21 @@ func NewRouter(
 		previousPageID: PageNone,
 
 		// Atribui os serviços.
-		userService:    userSvc,
-		roleService:    roleSvc,
-		networkService: netSvc,
-		cnpjService:    cnpjSvc,
-		importService:  importSvc,
-		auditService:   auditSvc,
-		authenticator:  authN,
-		sessionManager: sessMan,
-		permManager:    permMan,
+		userService:      userSvc,
+		roleService:      roleSvc,
+		networkService:   netSvc,
+		cnpjService:      cnpjSvc,
+		tituloService:    tituloSvc,
+		agingService:     agingSvc,
+		nettingService:   nettingSvc,
+		dashboardService: dashboardSvc,
+		cashFlowService:  cashFlowSvc,
+		dueDigestService: dueDigestSvc,
+		importService:    importSvc,
+		auditService:     auditSvc,
+		authenticator:    authN,
+		sessionManager:   sessMan,
+		permManager:      permMan,
 	}
 }
 
@@ -257,12 +281,18 @@ func (r *Router) GetTheme() *material.Theme { return r.th }
 func (r *Router) GetConfig() *core.Config { return r.cfg }
 
 // --- Getters para acesso aos serviços (as páginas podem chamar estes) ---
-func (r *Router) UserService() services.UserService          { return r.userService }
-func (r *Router) RoleService() services.RoleService          { return r.roleService }
-func (r *Router) NetworkService() services.NetworkService    { return r.networkService }
-func (r *Router) CNPJService() services.CNPJService          { return r.cnpjService }
-func (r *Router) ImportService() services.ImportService      { return r.importService }
-func (r *Router) AuditLogService() services.AuditLogService  { return r.auditService }
-func (r *Router) Authenticator() auth.AuthenticatorInterface { return r.authenticator }
-func (r *Router) SessionManager() *auth.SessionManager       { return r.sessionManager }
-func (r *Router) PermissionManager() *auth.PermissionManager { return r.permManager }
+func (r *Router) UserService() services.UserService               { return r.userService }
+func (r *Router) RoleService() services.RoleService               { return r.roleService }
+func (r *Router) NetworkService() services.NetworkService         { return r.networkService }
+func (r *Router) CNPJService() services.CNPJService               { return r.cnpjService }
+func (r *Router) TituloService() services.TituloService           { return r.tituloService }
+func (r *Router) AgingReportService() services.AgingReportService { return r.agingService }
+func (r *Router) NettingService() services.NettingService         { return r.nettingService }
+func (r *Router) DashboardService() services.DashboardService     { return r.dashboardService }
+func (r *Router) CashFlowService() services.CashFlowService       { return r.cashFlowService }
+func (r *Router) DueDigestService() services.DueDigestService     { return r.dueDigestService }
+func (r *Router) ImportService() services.ImportService           { return r.importService }
+func (r *Router) AuditLogService() services.AuditLogService       { return r.auditService }
+func (r *Router) Authenticator() auth.AuthenticatorInterface      { return r.authenticator }
+func (r *Router) SessionManager() *auth.SessionManager            { return r.sessionManager }
+func (r *Router) PermissionManager() *auth.PermissionManager      { return r.permManager }